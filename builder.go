@@ -17,8 +17,11 @@ import (
 )
 
 var (
-	cStateOffsets    = []uint32{c3Residency, c6Residency, c7Residency, maxFreqClockCount, actualFreqClockCount, timestampCounter}
-	cStatePerfEvents = []string{c01.String(), c02.String(), c0Wait.String(), thread.String()}
+	cStateOffsets     = []uint32{c3Residency, c6Residency, c7Residency, maxFreqClockCount, actualFreqClockCount, timestampCounter}
+	cStatePerfEvents  = []string{c01.String(), c02.String(), c0Wait.String(), thread.String()}
+	imcCASEvents      = []string{imcCASCountRead, imcCASCountWrite}
+	fixedPerfEvents   = []string{instRetired, thread.String(), refCycles}
+	topdownPerfEvents = []string{topdownSlots, topdownRetiring, topdownBadSpeculation, topdownFrontendBound, topdownBackendBound}
 )
 
 // PowerTelemetry enables monitoring platform metrics.
@@ -29,9 +32,20 @@ type PowerTelemetry struct {
 	rapl       raplReader
 	cpuFreq    cpuFreqReader
 	perf       perfReaderWithStorage
-
-	busClock float64
-	cpus     []int
+	perfUncore uncorePerfReaderWithStorage
+	cpuIdle    cpuIdleReader
+	throttle   thermalThrottleReader
+	cstatePerf pmuCounterReader
+	msrPerf    pmuCounterReader
+	coreTemp   coreTempReader
+	thermal    thermalZoneReader
+	msrWrite   *msrWriteGuard
+
+	busClock          float64
+	cpus              []int
+	uncoreRatioLimits map[int]uint64 // initial MSR_UNCORE_RATIO_LIMIT values per package ID
+	updateWorkers     int            // maximum number of CPU IDs updated concurrently by UpdateAllCPUMetrics
+	perfCumulative    bool           // perf ratio metrics are calculated on values accumulated since activation
 }
 
 // powerBuilder enables piecewise builds of PowerTelemetry instances. Implements functional options pattern.
@@ -43,9 +57,19 @@ type powerBuilder struct {
 	coreFreq   *coreFreqBuilder
 	uncoreFreq *uncoreFreqBuilder
 	perf       *perfBuilder
-
-	includedCPUs []int
-	excludedCPUs []int
+	perfUncore *perfUncoreBuilder
+	cpuIdle    *cpuIdleBuilder
+	throttle   *thermalThrottleBuilder
+	cstatePerf *perfCStateBuilder
+	msrPerf    *perfMsrBuilder
+	coreTemp   *coreTempBuilder
+	thermal    *thermalZoneBuilder
+	msrWrite   *msrWriteGuard
+
+	includedCPUs   []int
+	excludedCPUs   []int
+	updateWorkers  int
+	perfCumulative bool
 }
 
 type Option func(*powerBuilder)
@@ -66,6 +90,14 @@ func WithIncludedCPUs(cpuIDs []int) Option {
 	}
 }
 
+// WithUpdateWorkers returns a function closure that sets the maximum number of CPU IDs updated
+// concurrently by UpdateAllCPUMetrics. If not set, or set to a non-positive value, GOMAXPROCS is used.
+func WithUpdateWorkers(workers int) Option {
+	return func(b *powerBuilder) {
+		b.updateWorkers = workers
+	}
+}
+
 // WithMsr returns a function closure that initializes the msrBuilder struct of a builder with the default configuration.
 func WithMsr() Option {
 	return func(b *powerBuilder) {
@@ -96,6 +128,61 @@ func WithMsrTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithMsrSafe returns a function closure that initializes the msrBuilder struct of a builder to access MSRs via
+// msr_safe driver, with the default allowlist and batch device paths. A read timeout previously set by
+// WithMsrTimeout and counters previously set by WithMsrCounters are preserved.
+func WithMsrSafe() Option {
+	return func(b *powerBuilder) {
+		var timeout time.Duration
+		var counters []MsrCounter
+		if b.msr != nil {
+			timeout = b.msr.timeout
+			counters = b.msr.counters
+		}
+		b.msr = &msrBuilder{
+			msrReaderWithStorage: &msrDataWithStorage{
+				msrOffsets:    cStateOffsets,
+				msrPath:       defaultMsrBasePath,
+				msrSafe:       true,
+				allowlistPath: defaultMsrAllowlistPath,
+				batchPath:     defaultMsrBatchPath,
+			},
+			timeout:  timeout,
+			counters: counters,
+		}
+	}
+}
+
+// WithMsrCounters returns a function closure that initializes the msrBuilder struct of a builder with the default
+// configuration, if not initialized yet, and sets user-defined MSR counters to be tracked along with the MSR offsets
+// read by msr storage.
+func WithMsrCounters(counters []MsrCounter) Option {
+	return func(b *powerBuilder) {
+		if b.msr == nil {
+			b.msr = &msrBuilder{
+				msrReaderWithStorage: &msrDataWithStorage{
+					msrOffsets: cStateOffsets,
+					msrPath:    defaultMsrBasePath,
+				},
+			}
+		}
+		b.msr.counters = counters
+	}
+}
+
+// WithMsrWrites returns a function closure that enables write operations to MSR offsets via WriteMSR method. The allowlist
+// holds the MSR offsets allowed to be written, together with the mask of their writable bits. Every write operation is
+// recorded in the journal file at journalPath, which allows restoring the original values of written offsets.
+func WithMsrWrites(allowlist map[uint32]uint64, journalPath string) Option {
+	return func(b *powerBuilder) {
+		b.msrWrite = &msrWriteGuard{
+			allowWritesPath: defaultMsrAllowWritesPath,
+			allowlist:       allowlist,
+			journalPath:     journalPath,
+		}
+	}
+}
+
 // WithRapl returns a function closure that initializes the raplBuilder struct of a builder with the default configuration.
 func WithRapl(basePath ...string) Option {
 	var path string
@@ -148,15 +235,198 @@ func WithUncoreFrequency(basePath ...string) Option {
 }
 
 // WithPerf takes a file path with perf event definition in JSON format. It returns a function closure
-// that initializes a perfBuilder struct with the given JSON event definition file.
+// that initializes a perfBuilder struct with the given JSON event definition file. If the path is empty,
+// the event definitions embedded in the library for the processor model are used. On hybrid processors,
+// events of both core types are resolved against the given file, see WithPerfHybrid to use a file per core
+// type. Unless set by WithPerfEvents option, the events to be activated depend on the processor model,
+// see defaultPerfEvents.
 func WithPerf(jsonFile string) Option {
 	return func(b *powerBuilder) {
+		var events []string
+		if b.perf != nil {
+			events = b.perf.events
+		}
+		b.perf = &perfBuilder{
+			perfReaderWithStorage: &perfWithStorage{
+				perfReader: newPerf(),
+			},
+			jsonPath: jsonFile,
+			events:   events,
+		}
+	}
+}
+
+// WithPerfHybrid takes file paths with perf event definitions in JSON format, specific to the performance and efficient
+// cores of a hybrid processor respectively. It returns a function closure that initializes a perfBuilder struct with
+// the given JSON event definition files, instead of the file given to WithPerf option. Events are activated only on
+// CPUs of the core type they are resolved for. An empty path excludes CPUs of the corresponding core type.
+func WithPerfHybrid(coreJSONFile, atomJSONFile string) Option {
+	return func(b *powerBuilder) {
+		var events []string
+		if b.perf != nil {
+			events = b.perf.events
+		}
+
+		jsonPaths := make(map[CoreType]string, len(coreTypes))
+		if len(coreJSONFile) != 0 {
+			jsonPaths[CoreTypeCore] = coreJSONFile
+		}
+		if len(atomJSONFile) != 0 {
+			jsonPaths[CoreTypeAtom] = atomJSONFile
+		}
+
 		b.perf = &perfBuilder{
 			perfReaderWithStorage: &perfWithStorage{
 				perfReader: newPerf(),
 			},
+			hybridJSONPaths: jsonPaths,
+			events:          events,
+		}
+	}
+}
+
+// WithPerfEvents takes a slice of core event names, defined in the JSON file given to WithPerf option. It returns
+// a function closure that sets the events to be activated, in groups which fit the counters of the processor, instead
+// of the events required by C0 substate metrics. Values of the events can be retrieved via GetPerfEventValue method.
+func WithPerfEvents(events []string) Option {
+	return func(b *powerBuilder) {
+		if b.perf == nil {
+			b.perf = &perfBuilder{
+				perfReaderWithStorage: &perfWithStorage{
+					perfReader: newPerf(),
+				},
+			}
+		}
+		b.perf.events = events
+	}
+}
+
+// WithPerfCumulativeRatios returns a function closure that sets perf ratio metrics, such as C0 substate percentages
+// or IPC, to be calculated on values accumulated since event activation. By default, they are calculated on the
+// increase of values between the two latest ReadPerfEvents method calls.
+func WithPerfCumulativeRatios() Option {
+	return func(b *powerBuilder) {
+		b.perfCumulative = true
+	}
+}
+
+// WithPerfUncore takes a file path with uncore perf event definition in JSON format. It returns a function closure
+// that initializes a perfUncoreBuilder struct with the given JSON event definition file. Uncore events required by
+// memory bandwidth metrics are activated on all units of each package.
+func WithPerfUncore(jsonFile string) Option {
+	return func(b *powerBuilder) {
+		b.perfUncore = &perfUncoreBuilder{
+			uncorePerfReaderWithStorage: &uncorePerfWithStorage{
+				uncorePerfReader: newUncorePerf(),
+			},
 			jsonPath: jsonFile,
-			events:   cStatePerfEvents,
+			events:   imcCASEvents,
+		}
+	}
+}
+
+// WithCPUIdle returns a function closure that initializes the cpuIdleBuilder struct of a builder with the default configuration.
+func WithCPUIdle(basePath ...string) Option {
+	var path string
+	if len(basePath) != 0 {
+		path = basePath[0]
+	} else {
+		path = defaultCPUIdleBasePath
+	}
+	return func(b *powerBuilder) {
+		b.cpuIdle = &cpuIdleBuilder{
+			cpuIdleReader: &cpuIdleData{
+				basePath:      path,
+				intelIdlePath: defaultIntelIdleParamsPath,
+			},
+		}
+	}
+}
+
+// WithThermalThrottle returns a function closure that initializes the thermalThrottleBuilder struct of a builder
+// with the default configuration.
+func WithThermalThrottle(basePath ...string) Option {
+	var path string
+	if len(basePath) != 0 {
+		path = basePath[0]
+	} else {
+		path = defaultThermalThrottleBasePath
+	}
+	return func(b *powerBuilder) {
+		b.throttle = &thermalThrottleBuilder{
+			thermalThrottleReader: &thermalThrottleData{
+				basePath: path,
+			},
+		}
+	}
+}
+
+// WithPerfCStates returns a function closure that initializes the perfCStateBuilder struct of a builder with
+// the default configuration. C-state residencies are read from cstate_core and cstate_pkg PMUs of `perf_events`
+// kernel interface, whose events are discovered from the base path of PMUs.
+func WithPerfCStates(basePath ...string) Option {
+	var path string
+	if len(basePath) != 0 {
+		path = basePath[0]
+	} else {
+		path = pmuDevicesPath
+	}
+	return func(b *powerBuilder) {
+		b.cstatePerf = &perfCStateBuilder{
+			pmuCounterReader: newPMUCounterData(path, cstatePMUs),
+		}
+	}
+}
+
+// WithPerfMsr returns a function closure that initializes the perfMsrBuilder struct of a builder with the default
+// configuration. Free-running MSR counters, such as TSC, APERF and MPERF, are read from msr PMU of `perf_events`
+// kernel interface, whose events are discovered from the base path of PMUs.
+func WithPerfMsr(basePath ...string) Option {
+	var path string
+	if len(basePath) != 0 {
+		path = basePath[0]
+	} else {
+		path = pmuDevicesPath
+	}
+	return func(b *powerBuilder) {
+		b.msrPerf = &perfMsrBuilder{
+			pmuCounterReader: newPMUCounterData(path, msrPMUs),
+		}
+	}
+}
+
+// WithCoreTemp returns a function closure that initializes the coreTempBuilder struct of a builder
+// with the default configuration.
+func WithCoreTemp(basePath ...string) Option {
+	var path string
+	if len(basePath) != 0 {
+		path = basePath[0]
+	} else {
+		path = defaultHwmonBasePath
+	}
+	return func(b *powerBuilder) {
+		b.coreTemp = &coreTempBuilder{
+			coreTempReader: &coreTempData{
+				basePath: path,
+			},
+		}
+	}
+}
+
+// WithThermalZones returns a function closure that initializes the thermalZoneBuilder struct of a builder
+// with the default configuration.
+func WithThermalZones(basePath ...string) Option {
+	var path string
+	if len(basePath) != 0 {
+		path = basePath[0]
+	} else {
+		path = defaultThermalBasePath
+	}
+	return func(b *powerBuilder) {
+		b.thermal = &thermalZoneBuilder{
+			thermalZoneReader: &thermalZoneData{
+				basePath: path,
+			},
 		}
 	}
 }
@@ -186,7 +456,10 @@ func New(opts ...Option) (*PowerTelemetry, error) {
 		opt(b)
 	}
 
-	pt := &PowerTelemetry{}
+	pt := &PowerTelemetry{
+		updateWorkers:  b.updateWorkers,
+		perfCumulative: b.perfCumulative,
+	}
 
 	// initialize topology
 	if err := b.topology.initTopology(); err != nil {
@@ -226,6 +499,18 @@ func New(opts ...Option) (*PowerTelemetry, error) {
 	if err != nil {
 		multiErr.add(fmt.Sprintf("failed to initialize msr: %v", err))
 	}
+	if pt.msr != nil {
+		logPackagePPINs(pt)
+	}
+
+	// initialize msr writes
+	pt.msrWrite, err = b.initMsrWrite(pt.msr)
+	if err != nil {
+		multiErr.add(fmt.Sprintf("failed to initialize msr writes: %v", err))
+	}
+	if pt.msr != nil {
+		pt.uncoreRatioLimits = pt.readUncoreRatioLimits()
+	}
 
 	// initialize rapl
 	pt.rapl, err = b.initRapl()
@@ -248,11 +533,53 @@ func New(opts ...Option) (*PowerTelemetry, error) {
 	}
 
 	// initialize perf
-	pt.perf, err = b.initPerf(cStatePerfEvents, cpus)
+	pt.perf, err = b.initPerf(cpus)
 	if err != nil {
 		multiErr.add(fmt.Sprintf("failed to initialize perf: %v", err))
 	}
 
+	// initialize perf uncore
+	pt.perfUncore, err = b.initPerfUncore()
+	if err != nil {
+		multiErr.add(fmt.Sprintf("failed to initialize perf uncore: %v", err))
+	}
+
+	// initialize cpu idle
+	pt.cpuIdle, err = b.initCPUIdle(cpus)
+	if err != nil {
+		multiErr.add(fmt.Sprintf("failed to initialize cpu idle: %v", err))
+	}
+
+	// initialize thermal throttle
+	pt.throttle, err = b.initThermalThrottle(cpus)
+	if err != nil {
+		multiErr.add(fmt.Sprintf("failed to initialize thermal throttle: %v", err))
+	}
+
+	// initialize perf cstates
+	pt.cstatePerf, err = b.initPerfCStates(cpus)
+	if err != nil {
+		multiErr.add(fmt.Sprintf("failed to initialize perf cstates: %v", err))
+	}
+
+	// initialize perf msr
+	pt.msrPerf, err = b.initPerfMsr(cpus)
+	if err != nil {
+		multiErr.add(fmt.Sprintf("failed to initialize perf msr: %v", err))
+	}
+
+	// initialize coretemp
+	pt.coreTemp, err = b.initCoreTemp()
+	if err != nil {
+		multiErr.add(fmt.Sprintf("failed to initialize coretemp: %v", err))
+	}
+
+	// initialize thermal zones
+	pt.thermal, err = b.initThermalZones()
+	if err != nil {
+		multiErr.add(fmt.Sprintf("failed to initialize thermal zones: %v", err))
+	}
+
 	// TODO: Remove this optimization. Call to bus clock should be done only when needed.
 	// TODO: Getting model can be done inside getBusClock, pt.topology.getModel()
 	model := b.topology.getCPUModel()
@@ -276,7 +603,8 @@ type topologyBuilder struct {
 type msrBuilder struct {
 	msrReaderWithStorage
 
-	timeout time.Duration
+	timeout  time.Duration
+	counters []MsrCounter
 }
 
 // raplBuilder enables configuration and initialization of rapl subsystem for PowerTelemetry instances.
@@ -298,10 +626,49 @@ type uncoreFreqBuilder struct {
 type perfBuilder struct {
 	perfReaderWithStorage
 
+	jsonPath        string
+	hybridJSONPaths map[CoreType]string // JSON files per core type of hybrid processors
+	events          []string
+}
+
+// perfUncoreBuilder enables configuration and initialization of perf uncore subsystem for PowerTelemetry instances.
+type perfUncoreBuilder struct {
+	uncorePerfReaderWithStorage
+
 	jsonPath string
 	events   []string
 }
 
+// cpuIdleBuilder enables configuration and initialization of cpuIdle subsystem for PowerTelemetry instances.
+type cpuIdleBuilder struct {
+	cpuIdleReader
+}
+
+// thermalThrottleBuilder enables configuration and initialization of thermal throttle subsystem for PowerTelemetry instances.
+type thermalThrottleBuilder struct {
+	thermalThrottleReader
+}
+
+// perfCStateBuilder enables configuration and initialization of perf cstate subsystem for PowerTelemetry instances.
+type perfCStateBuilder struct {
+	pmuCounterReader
+}
+
+// perfMsrBuilder enables configuration and initialization of perf msr subsystem for PowerTelemetry instances.
+type perfMsrBuilder struct {
+	pmuCounterReader
+}
+
+// coreTempBuilder enables configuration and initialization of coretemp subsystem for PowerTelemetry instances.
+type coreTempBuilder struct {
+	coreTempReader
+}
+
+// thermalZoneBuilder enables configuration and initialization of thermal zone subsystem for PowerTelemetry instances.
+type thermalZoneBuilder struct {
+	thermalZoneReader
+}
+
 // getAvailableCPUs returns a slice with available CPU IDs which can be accessed to get metrics from.
 func (b *powerBuilder) getAvailableCPUs() ([]int, error) {
 	if len(b.excludedCPUs) != 0 && len(b.includedCPUs) != 0 {
@@ -344,10 +711,18 @@ func validateFromRange(nums []int, low, high int) error {
 }
 
 // initMsr takes a slice of CPU IDs and initializes the msrReaderWithStorage from the receiver's msrBuilder configuration.
-// If successfully initialized, it returns an msrReaderWithStorage. Otherwise, returns
-// an error.
+// MSR files are opened for writing only if WithMsrWrites option is present. If successfully initialized, it returns
+// an msrReaderWithStorage. Otherwise, returns an error.
 func (b *powerBuilder) initMsr(cpus []int) (msrReaderWithStorage, error) {
 	if b.msr != nil {
+		if len(b.msr.counters) != 0 {
+			if err := b.msr.initCounters(b.msr.counters); err != nil {
+				return nil, fmt.Errorf("invalid MSR counters: %w", err)
+			}
+		}
+		if b.msrWrite != nil {
+			b.msr.enableWrites()
+		}
 		if err := b.msr.initMsrMap(cpus, b.msr.timeout); err != nil {
 			return nil, err
 		}
@@ -392,19 +767,29 @@ func (b *powerBuilder) initUncoreFreq() (uncoreFreqReader, error) {
 	return nil, nil
 }
 
-// initPerf takes a slice of perf events and a slice of CPU IDs. It initializes the perfReaderWithStorage
-// from the receiver's perfBuilder configuration. If successfully initialized, it returns an perfReaderWithStorage.
+// initPerf takes a slice of CPU IDs. It initializes the perfReaderWithStorage from the receiver's perfBuilder
+// configuration, activating its perf events. If successfully initialized, it returns an perfReaderWithStorage.
 // Otherwise, returns an error.
-func (b *powerBuilder) initPerf(events []string, cpus []int) (perfReaderWithStorage, error) {
+func (b *powerBuilder) initPerf(cpus []int) (perfReaderWithStorage, error) {
 	if b.perf != nil {
-		// check if processor supports perf hardware events for cstates
+		events := b.perf.events
+		if len(events) != 0 {
+			if err := validatePerfEvents(events); err != nil {
+				return nil, err
+			}
+		}
+
 		model := b.topology.getCPUModel()
-		if !isPerfAllowed(model) {
-			return nil, fmt.Errorf("perf based metrics are not supported for processor model: 0x%X", model)
+		if len(events) == 0 {
+			var err error
+			events, err = defaultPerfEvents(model)
+			if err != nil {
+				return nil, err
+			}
 		}
 
-		if err := b.perf.initResolver(b.perf.jsonPath); err != nil {
-			return nil, fmt.Errorf("failed to init resolver: %w", err)
+		if err := b.initPerfResolvers(model); err != nil {
+			return nil, err
 		}
 
 		if err := b.perf.activate(events, cpus); err != nil {
@@ -415,20 +800,207 @@ func (b *powerBuilder) initPerf(events []string, cpus []int) (perfReaderWithStor
 	return nil, nil
 }
 
+// initPerfResolvers takes a processor model and initializes the resolvers of the receiver's perfBuilder. Unless perf
+// event definition files are provided, the files embedded in the library for the processor model are used.
+func (b *powerBuilder) initPerfResolvers(model int) error {
+	hybridJSONPaths := b.perf.hybridJSONPaths
+	if len(hybridJSONPaths) == 0 && isHybridModel(model) {
+		if len(b.perf.jsonPath) != 0 {
+			// The JSON file given to WithPerf comprises the event definitions of both core types.
+			hybridJSONPaths = map[CoreType]string{
+				CoreTypeCore: b.perf.jsonPath,
+				CoreTypeAtom: b.perf.jsonPath,
+			}
+		} else {
+			hybridJSONPaths = embeddedHybridPerfEvents(model)
+		}
+	}
+
+	if len(hybridJSONPaths) != 0 {
+		if err := b.perf.initHybridResolvers(hybridJSONPaths); err != nil {
+			return fmt.Errorf("failed to init hybrid resolvers: %w", err)
+		}
+		return nil
+	}
+
+	jsonPath := b.perf.jsonPath
+	if len(jsonPath) == 0 {
+		jsonPath = embeddedPerfEvents(model)
+	}
+	if err := b.perf.initResolver(jsonPath); err != nil {
+		return fmt.Errorf("failed to init resolver: %w", err)
+	}
+	return nil
+}
+
+// initPerfUncore initializes the uncorePerfReaderWithStorage from the receiver's perfUncoreBuilder configuration,
+// activating its uncore events for each package ID. If successfully initialized, it returns an uncorePerfReaderWithStorage.
+// Otherwise, returns an error.
+func (b *powerBuilder) initPerfUncore() (uncorePerfReaderWithStorage, error) {
+	if b.perfUncore != nil {
+		if err := b.perfUncore.initResolver(b.perfUncore.jsonPath); err != nil {
+			return nil, fmt.Errorf("failed to init resolver: %w", err)
+		}
+
+		if err := b.perfUncore.activate(b.perfUncore.events, b.topology.getPackageIDs()); err != nil {
+			return nil, fmt.Errorf("failed to activate events: %w", err)
+		}
+		return b.perfUncore.uncorePerfReaderWithStorage, nil
+	}
+	return nil, nil
+}
+
+// initCPUIdle takes a slice of CPU IDs and initializes the cpuIdleReader from the receiver's cpuIdleBuilder configuration.
+// If successfully initialized, it returns a cpuIdleReader. Otherwise, returns an error.
+func (b *powerBuilder) initCPUIdle(cpus []int) (cpuIdleReader, error) {
+	if b.cpuIdle != nil {
+		if err := b.cpuIdle.initCPUIdleMap(cpus); err != nil {
+			return nil, err
+		}
+		return b.cpuIdle.cpuIdleReader, nil
+	}
+	return nil, nil
+}
+
+// initThermalThrottle takes a slice of CPU IDs and initializes the thermalThrottleReader from the receiver's
+// thermalThrottleBuilder configuration. If successfully initialized, it returns a thermalThrottleReader.
+// Otherwise, returns an error.
+func (b *powerBuilder) initThermalThrottle(cpus []int) (thermalThrottleReader, error) {
+	if b.throttle != nil {
+		if err := b.throttle.initThrottleMap(cpus); err != nil {
+			return nil, err
+		}
+		return b.throttle.thermalThrottleReader, nil
+	}
+	return nil, nil
+}
+
+// initPerfCStates takes a slice of CPU IDs and initializes the pmuCounterReader from the receiver's perfCStateBuilder
+// configuration. Package C-state residencies are counted on the first CPU ID of each package. If successfully
+// initialized, it returns a pmuCounterReader. Otherwise, returns an error.
+func (b *powerBuilder) initPerfCStates(cpus []int) (pmuCounterReader, error) {
+	if b.cstatePerf != nil {
+		packageCPUs, err := b.getPackageCPUs(cpus)
+		if err != nil {
+			return nil, err
+		}
+		if err := b.cstatePerf.initCounterMap(cpus, packageCPUs); err != nil {
+			return nil, err
+		}
+		return b.cstatePerf.pmuCounterReader, nil
+	}
+	return nil, nil
+}
+
+// initPerfMsr takes a slice of CPU IDs and initializes the pmuCounterReader from the receiver's perfMsrBuilder
+// configuration. If successfully initialized, it returns a pmuCounterReader. Otherwise, returns an error.
+func (b *powerBuilder) initPerfMsr(cpus []int) (pmuCounterReader, error) {
+	if b.msrPerf != nil {
+		if err := b.msrPerf.initCounterMap(cpus, nil); err != nil {
+			return nil, err
+		}
+		return b.msrPerf.pmuCounterReader, nil
+	}
+	return nil, nil
+}
+
+// getPackageCPUs takes a slice of CPU IDs and returns the first CPU ID of each package, in the order of the slice.
+func (b *powerBuilder) getPackageCPUs(cpus []int) ([]int, error) {
+	packageCPUs := make([]int, 0)
+	seen := make(map[int]bool)
+	for _, cpuID := range cpus {
+		packageID, err := b.topology.getCPUPackageID(cpuID)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving package ID for CPU ID %v: %w", cpuID, err)
+		}
+		if seen[packageID] {
+			continue
+		}
+		seen[packageID] = true
+		packageCPUs = append(packageCPUs, cpuID)
+	}
+	return packageCPUs, nil
+}
+
+// initCoreTemp initializes the coreTempReader from the receiver's coreTempBuilder configuration.
+// If successfully initialized, it returns a coreTempReader. Otherwise, returns an error.
+func (b *powerBuilder) initCoreTemp() (coreTempReader, error) {
+	if b.coreTemp != nil {
+		if err := b.coreTemp.initCoreTempMap(); err != nil {
+			return nil, err
+		}
+		return b.coreTemp.coreTempReader, nil
+	}
+	return nil, nil
+}
+
+// initThermalZones initializes the thermalZoneReader from the receiver's thermalZoneBuilder configuration.
+// If successfully initialized, it returns a thermalZoneReader. Otherwise, returns an error.
+func (b *powerBuilder) initThermalZones() (thermalZoneReader, error) {
+	if b.thermal != nil {
+		if err := b.thermal.initThermalZones(); err != nil {
+			return nil, err
+		}
+		return b.thermal.thermalZoneReader, nil
+	}
+	return nil, nil
+}
+
+// defaultPerfEvents takes a processor model and returns the perf events to be activated when no events are set by
+// WithPerfEvents option. These comprise the events required by C0 substate metrics, if supported by the processor model,
+// the events of architectural fixed-function counters, if the processor has them, and the events required by topdown
+// metrics, if the processor model supports the perf metrics feature. If none of them are supported, an error is returned.
+func defaultPerfEvents(model int) ([]string, error) {
+	events := make([]string, 0, len(cStatePerfEvents)+len(fixedPerfEvents)+len(topdownPerfEvents))
+	if isPerfAllowed(model) {
+		events = append(events, cStatePerfEvents...)
+	}
+	if archFixedCounters() >= len(fixedPerfEvents) {
+		for _, event := range fixedPerfEvents {
+			if !slices.Contains(events, event) {
+				events = append(events, event)
+			}
+		}
+	}
+	if isTopdownAllowed(model) {
+		events = append(events, topdownPerfEvents...)
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("perf based metrics are not supported for processor model: 0x%X", model)
+	}
+	return events, nil
+}
+
+// validatePerfEvents takes a slice of user-defined perf event names and returns an error if any name is empty
+// or duplicated. Event names are case-insensitive.
+func validatePerfEvents(events []string) error {
+	seen := make(map[string]struct{}, len(events))
+	for _, event := range events {
+		name := strings.ToUpper(strings.TrimSpace(event))
+		if len(name) == 0 {
+			return errors.New("perf event name cannot be empty")
+		}
+		if _, ok := seen[name]; ok {
+			return fmt.Errorf("duplicated perf event %q", event)
+		}
+		seen[name] = struct{}{}
+	}
+	return nil
+}
+
 // isPerfAllowed is helper function that returns true if the processor model supports hardware
 // perf events specific to cstate residency metrics.
 func isPerfAllowed(model int) bool {
 	switch model {
 	case cpumodel.INTEL_FAM6_SAPPHIRERAPIDS_X:
 	case cpumodel.INTEL_FAM6_EMERALDRAPIDS_X:
-		//TODO: Hybrid models are not supported right now
-	//case cpumodel.INTEL_FAM6_ALDERLAKE:
-	//case cpumodel.INTEL_FAM6_ALDERLAKE_L:
-	//case cpumodel.INTEL_FAM6_RAPTORLAKE:
-	//case cpumodel.INTEL_FAM6_RAPTORLAKE_P:
-	//case cpumodel.INTEL_FAM6_RAPTORLAKE_S:
-	//case cpumodel.INTEL_FAM6_METEORLAKE:
-	//case cpumodel.INTEL_FAM6_METEORLAKE_L:
+	case cpumodel.INTEL_FAM6_ALDERLAKE:
+	case cpumodel.INTEL_FAM6_ALDERLAKE_L:
+	case cpumodel.INTEL_FAM6_RAPTORLAKE:
+	case cpumodel.INTEL_FAM6_RAPTORLAKE_P:
+	case cpumodel.INTEL_FAM6_RAPTORLAKE_S:
+	case cpumodel.INTEL_FAM6_METEORLAKE:
+	case cpumodel.INTEL_FAM6_METEORLAKE_L:
 	//Above list should be updated in the future with new processors supporting the required events.
 	default:
 		return false
@@ -436,6 +1008,51 @@ func isPerfAllowed(model int) bool {
 	return true
 }
 
+// isTopdownAllowed is helper function that returns true if the processor model supports the perf metrics
+// feature, which provides the events specific to topdown metrics.
+func isTopdownAllowed(model int) bool {
+	switch model {
+	case cpumodel.INTEL_FAM6_ICELAKE:
+	case cpumodel.INTEL_FAM6_ICELAKE_L:
+	case cpumodel.INTEL_FAM6_ICELAKE_X:
+	case cpumodel.INTEL_FAM6_ICELAKE_D:
+	case cpumodel.INTEL_FAM6_TIGERLAKE:
+	case cpumodel.INTEL_FAM6_TIGERLAKE_L:
+	case cpumodel.INTEL_FAM6_ROCKETLAKE:
+	case cpumodel.INTEL_FAM6_SAPPHIRERAPIDS_X:
+	case cpumodel.INTEL_FAM6_EMERALDRAPIDS_X:
+	case cpumodel.INTEL_FAM6_GRANITERAPIDS_X:
+	case cpumodel.INTEL_FAM6_GRANITERAPIDS_D:
+	case cpumodel.INTEL_FAM6_ALDERLAKE:
+	case cpumodel.INTEL_FAM6_ALDERLAKE_L:
+	case cpumodel.INTEL_FAM6_RAPTORLAKE:
+	case cpumodel.INTEL_FAM6_RAPTORLAKE_P:
+	case cpumodel.INTEL_FAM6_RAPTORLAKE_S:
+	case cpumodel.INTEL_FAM6_METEORLAKE:
+	case cpumodel.INTEL_FAM6_METEORLAKE_L:
+	default:
+		return false
+	}
+	return true
+}
+
+// isHybridModel is helper function that returns true if the processor model is a hybrid processor, with separate
+// core PMUs for its performance and efficient cores.
+func isHybridModel(model int) bool {
+	switch model {
+	case cpumodel.INTEL_FAM6_ALDERLAKE:
+	case cpumodel.INTEL_FAM6_ALDERLAKE_L:
+	case cpumodel.INTEL_FAM6_RAPTORLAKE:
+	case cpumodel.INTEL_FAM6_RAPTORLAKE_P:
+	case cpumodel.INTEL_FAM6_RAPTORLAKE_S:
+	case cpumodel.INTEL_FAM6_METEORLAKE:
+	case cpumodel.INTEL_FAM6_METEORLAKE_L:
+	default:
+		return false
+	}
+	return true
+}
+
 // logTopologyDetails logs topology details such as CPU: vendor ID, family and model.
 // It also logs core ID, package ID and die ID for every CPU ID.
 func logTopologyDetails(t topologyReader) {
@@ -480,3 +1097,55 @@ func logTopologyDetails(t topologyReader) {
 
 	log.Debugf(sb.String())
 }
+
+// logPackagePPINs logs, at info level, the Protected Processor Inventory Number (PPIN) for every package ID
+// of the host. Nothing is logged if PPIN is not supported by the CPU model.
+func logPackagePPINs(pt *PowerTelemetry) {
+	if err := CheckIfPackagePPINSupported(pt.topology.getCPUModel()); err != nil {
+		return
+	}
+
+	ppins, err := pt.GetPackagePPINs()
+
+	var sb strings.Builder
+
+	sb.WriteString("Package inventory details:\n")
+	for _, packageID := range pt.topology.getPackageIDs() {
+		ppin, ok := ppins[packageID]
+		if !ok {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("    Package ID: %2d, PPIN: 0x%016X\n", packageID, ppin))
+	}
+	if err != nil {
+		sb.WriteString(fmt.Sprintf("    Errors: %v\n", err))
+	}
+
+	log.Info(sb.String())
+}
+
+// initMsrWrite takes an msr reader and initializes the guard of MSR write operations. It returns an error if msr
+// is not initialized or the journal could not be opened. If the journal holds entries of a previous session,
+// a warning is logged, since original values of the offsets were not restored.
+func (b *powerBuilder) initMsrWrite(msr msrReaderWithStorage) (*msrWriteGuard, error) {
+	if b.msrWrite == nil {
+		return nil, nil
+	}
+	if msr == nil {
+		return nil, errors.New("msr is not initialized")
+	}
+	if err := b.msrWrite.init(); err != nil {
+		return nil, err
+	}
+
+	entries, err := b.msrWrite.journal.entries()
+	if err != nil {
+		_ = b.msrWrite.close()
+		return nil, err
+	}
+	if len(entries) != 0 {
+		log.Warnf("MSR write journal %q holds %v entries which were not restored, call ReplayMsrJournal to restore them",
+			b.msrWrite.journalPath, len(entries))
+	}
+	return b.msrWrite, nil
+}
@@ -8,6 +8,8 @@ package powertelemetry
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
@@ -65,6 +67,63 @@ func withPerfMock(m *perfMock) Option {
 	}
 }
 
+func withPerfUncoreMock(m *perfUncoreMock) Option {
+	return func(b *powerBuilder) {
+		b.perfUncore = &perfUncoreBuilder{
+			uncorePerfReaderWithStorage: m,
+			events:                      imcCASEvents,
+		}
+	}
+}
+
+func withThermalThrottleMock(m *thermalThrottleMock) Option {
+	return func(b *powerBuilder) {
+		b.throttle = &thermalThrottleBuilder{
+			thermalThrottleReader: m,
+		}
+	}
+}
+
+func withPerfCStatesMock(m *pmuCounterMock) Option {
+	return func(b *powerBuilder) {
+		b.cstatePerf = &perfCStateBuilder{
+			pmuCounterReader: m,
+		}
+	}
+}
+
+func withPerfMsrMock(m *pmuCounterMock) Option {
+	return func(b *powerBuilder) {
+		b.msrPerf = &perfMsrBuilder{
+			pmuCounterReader: m,
+		}
+	}
+}
+
+func withCoreTempMock(m *coreTempMock) Option {
+	return func(b *powerBuilder) {
+		b.coreTemp = &coreTempBuilder{
+			coreTempReader: m,
+		}
+	}
+}
+
+func withThermalZoneMock(m *thermalZoneMock) Option {
+	return func(b *powerBuilder) {
+		b.thermal = &thermalZoneBuilder{
+			thermalZoneReader: m,
+		}
+	}
+}
+
+func withCPUIdleMock(m *cpuIdleMock) Option {
+	return func(b *powerBuilder) {
+		b.cpuIdle = &cpuIdleBuilder{
+			cpuIdleReader: m,
+		}
+	}
+}
+
 func TestWithExcludedCPUs(t *testing.T) {
 	cpus := []int{0, 1, 2, 3}
 	exp := &powerBuilder{
@@ -91,6 +150,30 @@ func TestWithIncludedCPUs(t *testing.T) {
 	require.Equal(t, exp, b)
 }
 
+func TestWithUpdateWorkers(t *testing.T) {
+	exp := &powerBuilder{
+		updateWorkers: 8,
+	}
+
+	b := &powerBuilder{}
+	f := WithUpdateWorkers(8)
+	f(b)
+
+	require.Equal(t, exp, b)
+}
+
+func TestWithPerfCumulativeRatios(t *testing.T) {
+	exp := &powerBuilder{
+		perfCumulative: true,
+	}
+
+	b := &powerBuilder{}
+	f := WithPerfCumulativeRatios()
+	f(b)
+
+	require.Equal(t, exp, b)
+}
+
 func TestWithMsr(t *testing.T) {
 	exp := &powerBuilder{
 		msr: &msrBuilder{
@@ -126,6 +209,97 @@ func TestWithMsrTimeout(t *testing.T) {
 	require.Equal(t, exp, b)
 }
 
+func TestWithMsrSafe(t *testing.T) {
+	exp := &powerBuilder{
+		msr: &msrBuilder{
+			msrReaderWithStorage: &msrDataWithStorage{
+				msrOffsets:    cStateOffsets,
+				msrPath:       defaultMsrBasePath,
+				msrSafe:       true,
+				allowlistPath: defaultMsrAllowlistPath,
+				batchPath:     defaultMsrBatchPath,
+			},
+			timeout: time.Minute,
+		},
+	}
+
+	t.Run("TimeoutFirst", func(t *testing.T) {
+		b := &powerBuilder{}
+		WithMsrTimeout(time.Minute)(b)
+		WithMsrSafe()(b)
+
+		require.Equal(t, exp, b)
+	})
+
+	t.Run("TimeoutLast", func(t *testing.T) {
+		b := &powerBuilder{}
+		WithMsrSafe()(b)
+		WithMsrTimeout(time.Minute)(b)
+
+		require.Equal(t, exp, b)
+	})
+}
+
+func TestWithMsrCounters(t *testing.T) {
+	counters := []MsrCounter{
+		{Name: "fixed_ctr0", Offset: 0x309, Width: 48},
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		exp := &powerBuilder{
+			msr: &msrBuilder{
+				msrReaderWithStorage: &msrDataWithStorage{
+					msrOffsets: cStateOffsets,
+					msrPath:    defaultMsrBasePath,
+				},
+				counters: counters,
+			},
+		}
+
+		b := &powerBuilder{}
+		WithMsrCounters(counters)(b)
+
+		require.Equal(t, exp, b)
+	})
+
+	t.Run("WithMsrSafe", func(t *testing.T) {
+		exp := &powerBuilder{
+			msr: &msrBuilder{
+				msrReaderWithStorage: &msrDataWithStorage{
+					msrOffsets:    cStateOffsets,
+					msrPath:       defaultMsrBasePath,
+					msrSafe:       true,
+					allowlistPath: defaultMsrAllowlistPath,
+					batchPath:     defaultMsrBatchPath,
+				},
+				counters: counters,
+			},
+		}
+
+		b := &powerBuilder{}
+		WithMsrCounters(counters)(b)
+		WithMsrSafe()(b)
+
+		require.Equal(t, exp, b)
+	})
+}
+
+func TestWithMsrWrites(t *testing.T) {
+	allowlist := map[uint32]uint64{0x1A0: 0x1}
+	exp := &powerBuilder{
+		msrWrite: &msrWriteGuard{
+			allowWritesPath: defaultMsrAllowWritesPath,
+			allowlist:       allowlist,
+			journalPath:     "/var/lib/powertelemetry/msr_journal",
+		},
+	}
+
+	b := &powerBuilder{}
+	WithMsrWrites(allowlist, "/var/lib/powertelemetry/msr_journal")(b)
+
+	require.Equal(t, exp, b)
+}
+
 func TestWithRapl(t *testing.T) {
 	t.Run("DefaultBasePath", func(t *testing.T) {
 		exp := &powerBuilder{
@@ -231,128 +405,514 @@ func TestWithUncoreFrequency(t *testing.T) {
 	})
 }
 
-func TestWithPerf(t *testing.T) {
-	jsonFile := "testdata/sapphirerapids_core.json"
+func TestWithCPUIdle(t *testing.T) {
+	t.Run("DefaultBasePath", func(t *testing.T) {
+		exp := &powerBuilder{
+			cpuIdle: &cpuIdleBuilder{
+				cpuIdleReader: &cpuIdleData{
+					basePath:      defaultCPUIdleBasePath,
+					intelIdlePath: defaultIntelIdleParamsPath,
+				},
+			},
+		}
 
-	b := &powerBuilder{}
-	f := WithPerf(jsonFile)
-	f(b)
+		b := &powerBuilder{}
+		f := WithCPUIdle()
+		f(b)
 
-	require.NotNil(t, b.perf)
-	require.NotNil(t, b.perf.perfReaderWithStorage)
-	require.Equal(t, jsonFile, b.perf.jsonPath)
-	require.Equal(t, cStatePerfEvents, b.perf.events)
-}
+		require.Equal(t, exp, b)
+	})
 
-func TestGetAvailableCPUs(t *testing.T) {
-	t.Run("WithAllCPUs", func(t *testing.T) {
-		mTopo := &topologyMock{}
+	t.Run("CustomBasePath", func(t *testing.T) {
+		customPath := "custom/cpu"
+		exp := &powerBuilder{
+			cpuIdle: &cpuIdleBuilder{
+				cpuIdleReader: &cpuIdleData{
+					basePath:      customPath,
+					intelIdlePath: defaultIntelIdleParamsPath,
+				},
+			},
+		}
 
-		// mock getting number of CPUs from powerBuilder.getAvailableCPUs
-		mTopo.On("getCPUsNumber").Return(10).Once()
+		b := &powerBuilder{}
+		f := WithCPUIdle(customPath)
+		f(b)
 
-		cpusExp := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+		require.Equal(t, exp, b)
+	})
+}
 
-		b := &powerBuilder{
-			topology: &topologyBuilder{
-				topologyReader: mTopo,
+func TestWithThermalThrottle(t *testing.T) {
+	t.Run("DefaultBasePath", func(t *testing.T) {
+		exp := &powerBuilder{
+			throttle: &thermalThrottleBuilder{
+				thermalThrottleReader: &thermalThrottleData{
+					basePath: defaultThermalThrottleBasePath,
+				},
 			},
 		}
 
-		cpusOut, err := b.getAvailableCPUs()
-		require.NoError(t, err)
-		require.Equal(t, cpusExp, cpusOut)
+		b := &powerBuilder{}
+		f := WithThermalThrottle()
+		f(b)
+
+		require.Equal(t, exp, b)
 	})
 
-	t.Run("WithIncludedCPUs", func(t *testing.T) {
-		mTopo := &topologyMock{}
+	t.Run("CustomBasePath", func(t *testing.T) {
+		customPath := "custom/cpu"
+		exp := &powerBuilder{
+			throttle: &thermalThrottleBuilder{
+				thermalThrottleReader: &thermalThrottleData{
+					basePath: customPath,
+				},
+			},
+		}
 
-		// mock getting number of CPUs from powerBuilder.getAvailableCPUs
-		// valid CPU IDs within the range [0, 19]
-		mTopo.On("getCPUsNumber").Return(20)
+		b := &powerBuilder{}
+		f := WithThermalThrottle(customPath)
+		f(b)
 
-		t.Run("OutOfBounds", func(t *testing.T) {
-			includedCPUs := []int{15, 16, 17, 18, 19, 20}
+		require.Equal(t, exp, b)
+	})
+}
 
-			b := &powerBuilder{
-				topology: &topologyBuilder{
-					topologyReader: mTopo,
-				},
-			}
-			f := WithIncludedCPUs(includedCPUs)
-			f(b)
+func TestWithPerfCStates(t *testing.T) {
+	// sysfsPMU values hold functions, hence the PMUs are compared by name.
+	requirePerfCStates := func(t *testing.T, expPath string, b *powerBuilder) {
+		t.Helper()
+
+		require.NotNil(t, b.cstatePerf)
+		data, ok := b.cstatePerf.pmuCounterReader.(*pmuCounterData)
+		require.True(t, ok)
+		require.Equal(t, expPath, data.basePath)
+		require.Equal(t, &pmuEventActivatorImpl{}, data.activator)
+		require.Equal(t, &pmuValuesReaderImpl{}, data.valuesReader)
+		require.Nil(t, data.cpuMap)
+
+		names := make([]string, 0, len(data.pmus))
+		for _, pmu := range data.pmus {
+			names = append(names, pmu.name)
+		}
+		require.Equal(t, []string{cstateCorePMU, cstatePkgPMU}, names)
+	}
 
-			cpusOut, err := b.getAvailableCPUs()
-			require.ErrorContains(t, err, "failed to validate included CPU IDs")
-			require.ErrorContains(t, err, "20 is out of bounds [0, 19]")
-			require.Nil(t, cpusOut)
-			mTopo.AssertExpectations(t)
-		})
+	t.Run("DefaultBasePath", func(t *testing.T) {
+		b := &powerBuilder{}
+		f := WithPerfCStates()
+		f(b)
 
-		t.Run("NilCPUsSlice", func(t *testing.T) {
-			b := &powerBuilder{
-				topology: &topologyBuilder{
-					topologyReader: mTopo,
-				},
-			}
-			f := WithIncludedCPUs(nil)
-			f(b)
+		requirePerfCStates(t, pmuDevicesPath, b)
+	})
 
-			cpusOut, err := b.getAvailableCPUs()
-			require.NoError(t, err)
-			require.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19}, cpusOut)
-			mTopo.AssertExpectations(t)
-		})
+	t.Run("CustomBasePath", func(t *testing.T) {
+		customPath := "custom/devices"
 
-		t.Run("EmptyCPUsSlice", func(t *testing.T) {
-			b := &powerBuilder{
-				topology: &topologyBuilder{
-					topologyReader: mTopo,
-				},
-			}
-			f := WithIncludedCPUs([]int{})
-			f(b)
+		b := &powerBuilder{}
+		f := WithPerfCStates(customPath)
+		f(b)
 
-			cpusOut, err := b.getAvailableCPUs()
-			require.NoError(t, err)
-			require.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19}, cpusOut)
-			mTopo.AssertExpectations(t)
-		})
+		requirePerfCStates(t, customPath, b)
+	})
+}
 
-		t.Run("PartialCPUsIncluded", func(t *testing.T) {
-			cpusExp := []int{0, 1, 2, 3}
+func TestWithPerfMsr(t *testing.T) {
+	// sysfsPMU values hold functions, hence the PMUs are compared by name.
+	requirePerfMsr := func(t *testing.T, expPath string, b *powerBuilder) {
+		t.Helper()
+
+		require.NotNil(t, b.msrPerf)
+		data, ok := b.msrPerf.pmuCounterReader.(*pmuCounterData)
+		require.True(t, ok)
+		require.Equal(t, expPath, data.basePath)
+		require.Equal(t, &pmuEventActivatorImpl{}, data.activator)
+		require.Equal(t, &pmuValuesReaderImpl{}, data.valuesReader)
+		require.Nil(t, data.cpuMap)
+		require.Len(t, data.pmus, 1)
+		require.Equal(t, msrPMU, data.pmus[0].name)
+		require.False(t, data.pmus[0].perPackage)
+	}
 
-			b := &powerBuilder{
-				topology: &topologyBuilder{
-					topologyReader: mTopo,
-				},
-			}
-			f := WithIncludedCPUs(cpusExp)
-			f(b)
+	t.Run("DefaultBasePath", func(t *testing.T) {
+		b := &powerBuilder{}
+		f := WithPerfMsr()
+		f(b)
 
-			cpusOut, err := b.getAvailableCPUs()
-			require.NoError(t, err)
-			require.Equal(t, cpusExp, cpusOut)
-			mTopo.AssertExpectations(t)
-		})
+		requirePerfMsr(t, pmuDevicesPath, b)
 	})
 
-	t.Run("WithExcludedCPUs", func(t *testing.T) {
-		mTopo := &topologyMock{}
+	t.Run("CustomBasePath", func(t *testing.T) {
+		customPath := "custom/devices"
 
-		// mock getting number of CPUs from powerBuilder.getAvailableCPUs
-		mTopo.On("getCPUsNumber").Return(10)
+		b := &powerBuilder{}
+		f := WithPerfMsr(customPath)
+		f(b)
 
-		t.Run("OutOfBounds", func(t *testing.T) {
-			excludedCPUs := []int{2, 1, 0, 12, 13}
+		requirePerfMsr(t, customPath, b)
+	})
+}
 
-			b := &powerBuilder{
-				topology: &topologyBuilder{
-					topologyReader: mTopo,
+func TestWithCoreTemp(t *testing.T) {
+	t.Run("DefaultBasePath", func(t *testing.T) {
+		exp := &powerBuilder{
+			coreTemp: &coreTempBuilder{
+				coreTempReader: &coreTempData{
+					basePath: defaultHwmonBasePath,
 				},
-			}
-			f := WithExcludedCPUs(excludedCPUs)
-			f(b)
+			},
+		}
+
+		b := &powerBuilder{}
+		f := WithCoreTemp()
+		f(b)
+
+		require.Equal(t, exp, b)
+	})
+
+	t.Run("CustomBasePath", func(t *testing.T) {
+		customPath := "custom/hwmon"
+		exp := &powerBuilder{
+			coreTemp: &coreTempBuilder{
+				coreTempReader: &coreTempData{
+					basePath: customPath,
+				},
+			},
+		}
+
+		b := &powerBuilder{}
+		f := WithCoreTemp(customPath)
+		f(b)
+
+		require.Equal(t, exp, b)
+	})
+}
+
+func TestWithThermalZones(t *testing.T) {
+	t.Run("DefaultBasePath", func(t *testing.T) {
+		exp := &powerBuilder{
+			thermal: &thermalZoneBuilder{
+				thermalZoneReader: &thermalZoneData{
+					basePath: defaultThermalBasePath,
+				},
+			},
+		}
+
+		b := &powerBuilder{}
+		f := WithThermalZones()
+		f(b)
+
+		require.Equal(t, exp, b)
+	})
+
+	t.Run("CustomBasePath", func(t *testing.T) {
+		customPath := "custom/thermal"
+		exp := &powerBuilder{
+			thermal: &thermalZoneBuilder{
+				thermalZoneReader: &thermalZoneData{
+					basePath: customPath,
+				},
+			},
+		}
+
+		b := &powerBuilder{}
+		f := WithThermalZones(customPath)
+		f(b)
+
+		require.Equal(t, exp, b)
+	})
+}
+
+func TestWithPerf(t *testing.T) {
+	jsonFile := "testdata/sapphirerapids_core.json"
+
+	b := &powerBuilder{}
+	f := WithPerf(jsonFile)
+	f(b)
+
+	require.NotNil(t, b.perf)
+	require.NotNil(t, b.perf.perfReaderWithStorage)
+	require.Equal(t, jsonFile, b.perf.jsonPath)
+	require.Nil(t, b.perf.events)
+}
+
+// setArchFixedCounters takes a number of architectural fixed-function counters to be reported by archFixedCounters
+// function, which is restored once the test completes.
+func setArchFixedCounters(t *testing.T, n int) {
+	t.Helper()
+
+	orig := archFixedCounters
+	archFixedCounters = func() int {
+		return n
+	}
+	t.Cleanup(func() {
+		archFixedCounters = orig
+	})
+}
+
+func TestDefaultPerfEvents(t *testing.T) {
+	t.Run("NotSupported", func(t *testing.T) {
+		setArchFixedCounters(t, 0)
+
+		events, err := defaultPerfEvents(cpumodel.INTEL_FAM6_SKYLAKE_X)
+		require.ErrorContains(t, err, fmt.Sprintf("perf based metrics are not supported for processor model: 0x%X", cpumodel.INTEL_FAM6_SKYLAKE_X))
+		require.Nil(t, events)
+	})
+
+	t.Run("NotEnoughFixedCounters", func(t *testing.T) {
+		setArchFixedCounters(t, 2)
+
+		events, err := defaultPerfEvents(cpumodel.INTEL_FAM6_SKYLAKE_X)
+		require.Error(t, err)
+		require.Nil(t, events)
+	})
+
+	t.Run("FixedCountersOnly", func(t *testing.T) {
+		setArchFixedCounters(t, 3)
+
+		events, err := defaultPerfEvents(cpumodel.INTEL_FAM6_SKYLAKE_X)
+		require.NoError(t, err)
+		require.Equal(t, []string{"INST_RETIRED.ANY", "CPU_CLK_UNHALTED.THREAD", "CPU_CLK_UNHALTED.REF_TSC"}, events)
+	})
+
+	t.Run("FixedCountersAndTopdown", func(t *testing.T) {
+		setArchFixedCounters(t, 4)
+
+		events, err := defaultPerfEvents(cpumodel.INTEL_FAM6_ICELAKE_X)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"INST_RETIRED.ANY",
+			"CPU_CLK_UNHALTED.THREAD",
+			"CPU_CLK_UNHALTED.REF_TSC",
+			"TOPDOWN.SLOTS",
+			"PERF_METRICS.RETIRING",
+			"PERF_METRICS.BAD_SPECULATION",
+			"PERF_METRICS.FRONTEND_BOUND",
+			"PERF_METRICS.BACKEND_BOUND",
+		}, events)
+	})
+
+	t.Run("C0SubstatesAndTopdown", func(t *testing.T) {
+		setArchFixedCounters(t, 0)
+
+		events, err := defaultPerfEvents(cpumodel.INTEL_FAM6_SAPPHIRERAPIDS_X)
+		require.NoError(t, err)
+		require.Equal(t, append(append([]string{}, cStatePerfEvents...), topdownPerfEvents...), events)
+	})
+
+	t.Run("AllSupported", func(t *testing.T) {
+		setArchFixedCounters(t, 4)
+
+		events, err := defaultPerfEvents(cpumodel.INTEL_FAM6_EMERALDRAPIDS_X)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"CPU_CLK_UNHALTED.C01",
+			"CPU_CLK_UNHALTED.C02",
+			"CPU_CLK_UNHALTED.C0_WAIT",
+			"CPU_CLK_UNHALTED.THREAD",
+			"INST_RETIRED.ANY",
+			"CPU_CLK_UNHALTED.REF_TSC",
+			"TOPDOWN.SLOTS",
+			"PERF_METRICS.RETIRING",
+			"PERF_METRICS.BAD_SPECULATION",
+			"PERF_METRICS.FRONTEND_BOUND",
+			"PERF_METRICS.BACKEND_BOUND",
+		}, events)
+	})
+}
+
+func TestWithPerfUncore(t *testing.T) {
+	jsonFile := "testdata/sapphirerapids_uncore.json"
+
+	b := &powerBuilder{}
+	f := WithPerfUncore(jsonFile)
+	f(b)
+
+	require.NotNil(t, b.perfUncore)
+	require.NotNil(t, b.perfUncore.uncorePerfReaderWithStorage)
+	require.Equal(t, jsonFile, b.perfUncore.jsonPath)
+	require.Equal(t, imcCASEvents, b.perfUncore.events)
+}
+
+func TestWithPerfEvents(t *testing.T) {
+	jsonFile := "testdata/sapphirerapids_core.json"
+	events := []string{"INST_RETIRED.ANY", "CPU_CLK_UNHALTED.THREAD"}
+
+	t.Run("AfterWithPerf", func(t *testing.T) {
+		b := &powerBuilder{}
+		WithPerf(jsonFile)(b)
+		WithPerfEvents(events)(b)
+
+		require.NotNil(t, b.perf)
+		require.NotNil(t, b.perf.perfReaderWithStorage)
+		require.Equal(t, jsonFile, b.perf.jsonPath)
+		require.Equal(t, events, b.perf.events)
+	})
+
+	t.Run("BeforeWithPerf", func(t *testing.T) {
+		b := &powerBuilder{}
+		WithPerfEvents(events)(b)
+		WithPerf(jsonFile)(b)
+
+		require.NotNil(t, b.perf)
+		require.NotNil(t, b.perf.perfReaderWithStorage)
+		require.Equal(t, jsonFile, b.perf.jsonPath)
+		require.Equal(t, events, b.perf.events)
+	})
+}
+
+func TestWithPerfHybrid(t *testing.T) {
+	coreJSONFile := "alderlake_goldencove_core.json"
+	atomJSONFile := "alderlake_gracemont_core.json"
+	events := []string{"INST_RETIRED.ANY", "CPU_CLK_UNHALTED.THREAD"}
+
+	t.Run("BothCoreTypes", func(t *testing.T) {
+		b := &powerBuilder{}
+		WithPerfEvents(events)(b)
+		WithPerfHybrid(coreJSONFile, atomJSONFile)(b)
+
+		require.NotNil(t, b.perf)
+		require.NotNil(t, b.perf.perfReaderWithStorage)
+		require.Empty(t, b.perf.jsonPath)
+		require.Equal(t, map[CoreType]string{
+			CoreTypeCore: coreJSONFile,
+			CoreTypeAtom: atomJSONFile,
+		}, b.perf.hybridJSONPaths)
+		require.Equal(t, events, b.perf.events)
+	})
+
+	t.Run("CoreOnly", func(t *testing.T) {
+		b := &powerBuilder{}
+		WithPerfHybrid(coreJSONFile, "")(b)
+
+		require.NotNil(t, b.perf)
+		require.Equal(t, map[CoreType]string{CoreTypeCore: coreJSONFile}, b.perf.hybridJSONPaths)
+		require.Nil(t, b.perf.events)
+	})
+}
+
+func TestValidatePerfEvents(t *testing.T) {
+	t.Run("EmptyName", func(t *testing.T) {
+		require.ErrorContains(t, validatePerfEvents([]string{"INST_RETIRED.ANY", " "}), "perf event name cannot be empty")
+	})
+
+	t.Run("Duplicated", func(t *testing.T) {
+		require.ErrorContains(t, validatePerfEvents([]string{"INST_RETIRED.ANY", "inst_retired.any"}), "duplicated perf event \"inst_retired.any\"")
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		require.NoError(t, validatePerfEvents([]string{"INST_RETIRED.ANY", "CPU_CLK_UNHALTED.THREAD"}))
+	})
+}
+
+func TestGetAvailableCPUs(t *testing.T) {
+	t.Run("WithAllCPUs", func(t *testing.T) {
+		mTopo := &topologyMock{}
+
+		// mock getting number of CPUs from powerBuilder.getAvailableCPUs
+		mTopo.On("getCPUsNumber").Return(10).Once()
+
+		cpusExp := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+
+		b := &powerBuilder{
+			topology: &topologyBuilder{
+				topologyReader: mTopo,
+			},
+		}
+
+		cpusOut, err := b.getAvailableCPUs()
+		require.NoError(t, err)
+		require.Equal(t, cpusExp, cpusOut)
+	})
+
+	t.Run("WithIncludedCPUs", func(t *testing.T) {
+		mTopo := &topologyMock{}
+
+		// mock getting number of CPUs from powerBuilder.getAvailableCPUs
+		// valid CPU IDs within the range [0, 19]
+		mTopo.On("getCPUsNumber").Return(20)
+
+		t.Run("OutOfBounds", func(t *testing.T) {
+			includedCPUs := []int{15, 16, 17, 18, 19, 20}
+
+			b := &powerBuilder{
+				topology: &topologyBuilder{
+					topologyReader: mTopo,
+				},
+			}
+			f := WithIncludedCPUs(includedCPUs)
+			f(b)
+
+			cpusOut, err := b.getAvailableCPUs()
+			require.ErrorContains(t, err, "failed to validate included CPU IDs")
+			require.ErrorContains(t, err, "20 is out of bounds [0, 19]")
+			require.Nil(t, cpusOut)
+			mTopo.AssertExpectations(t)
+		})
+
+		t.Run("NilCPUsSlice", func(t *testing.T) {
+			b := &powerBuilder{
+				topology: &topologyBuilder{
+					topologyReader: mTopo,
+				},
+			}
+			f := WithIncludedCPUs(nil)
+			f(b)
+
+			cpusOut, err := b.getAvailableCPUs()
+			require.NoError(t, err)
+			require.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19}, cpusOut)
+			mTopo.AssertExpectations(t)
+		})
+
+		t.Run("EmptyCPUsSlice", func(t *testing.T) {
+			b := &powerBuilder{
+				topology: &topologyBuilder{
+					topologyReader: mTopo,
+				},
+			}
+			f := WithIncludedCPUs([]int{})
+			f(b)
+
+			cpusOut, err := b.getAvailableCPUs()
+			require.NoError(t, err)
+			require.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19}, cpusOut)
+			mTopo.AssertExpectations(t)
+		})
+
+		t.Run("PartialCPUsIncluded", func(t *testing.T) {
+			cpusExp := []int{0, 1, 2, 3}
+
+			b := &powerBuilder{
+				topology: &topologyBuilder{
+					topologyReader: mTopo,
+				},
+			}
+			f := WithIncludedCPUs(cpusExp)
+			f(b)
+
+			cpusOut, err := b.getAvailableCPUs()
+			require.NoError(t, err)
+			require.Equal(t, cpusExp, cpusOut)
+			mTopo.AssertExpectations(t)
+		})
+	})
+
+	t.Run("WithExcludedCPUs", func(t *testing.T) {
+		mTopo := &topologyMock{}
+
+		// mock getting number of CPUs from powerBuilder.getAvailableCPUs
+		mTopo.On("getCPUsNumber").Return(10)
+
+		t.Run("OutOfBounds", func(t *testing.T) {
+			excludedCPUs := []int{2, 1, 0, 12, 13}
+
+			b := &powerBuilder{
+				topology: &topologyBuilder{
+					topologyReader: mTopo,
+				},
+			}
+			f := WithExcludedCPUs(excludedCPUs)
+			f(b)
 
 			cpusOut, err := b.getAvailableCPUs()
 			require.ErrorContains(t, err, "failed to validate excluded CPU IDs")
@@ -586,217 +1146,624 @@ func TestNew(t *testing.T) {
 	t.Run("With", func(t *testing.T) {
 		cpus := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
 
-		mTopology := &topologyMock{}
+		mTopology := &topologyMock{}
+
+		// mock initializing topology map
+		mTopology.On("initTopology").Return(nil)
+
+		// mock getting CPU family and vendor from isCPUSupported
+		mTopology.On("getCPUFamily", 0).Return("6", nil)
+		mTopology.On("getCPUVendor", 0).Return("GenuineIntel", nil)
+
+		// mock getting model to calculate bus clock
+		mTopology.On("getCPUModel").Return(cpumodel.INTEL_FAM6_ICELAKE)
+
+		// mock getting number of CPU IDs from powerBuilder.getAvailableCPUs
+		mTopology.On("getCPUsNumber").Return(len(cpus))
+
+		// mock getting topology CPU data from logTopologyDetails
+		mTopology.On("getCPUCoreID", mock.AnythingOfType("int")).Return(0, nil)
+		mTopology.On("getCPUPackageID", mock.AnythingOfType("int")).Return(0, nil)
+		mTopology.On("getCPUDieID", mock.AnythingOfType("int")).Return(0, nil)
+
+		t.Run("Msr", func(t *testing.T) {
+			t.Run("FailedToInitMsrMap", func(t *testing.T) {
+				mMsr := &msrMock{}
+
+				// mock initializing msr map from powerBuilder.initMsr
+				mMsr.On("initMsrMap", cpus, time.Duration(0)).Return(mError).Once()
+
+				pt, err := New(
+					withTopologyMock(mTopology),
+					withMsrMock(mMsr),
+				)
+
+				require.ErrorContains(t, err, "failed to initialize msr")
+				require.NotNil(t, pt)
+				require.Nil(t, pt.msr)
+				require.Nil(t, pt.GetRaplPackageIDs())
+				require.Equal(t, cpus, pt.GetMsrCPUIDs())
+
+				mTopology.AssertExpectations(t)
+				mMsr.AssertExpectations(t)
+			})
+
+			t.Run("FailedToInitCounters", func(t *testing.T) {
+				counters := []MsrCounter{{Offset: 0x309}}
+
+				mMsr := &msrMock{}
+
+				// mock initializing msr counters from powerBuilder.initMsr
+				mMsr.On("initCounters", counters).Return(mError).Once()
+
+				pt, err := New(
+					withTopologyMock(mTopology),
+					withMsrMock(mMsr),
+
+					WithMsrCounters(counters),
+				)
+
+				require.ErrorContains(t, err, "failed to initialize msr")
+				require.ErrorContains(t, err, "invalid MSR counters")
+				require.NotNil(t, pt)
+				require.Nil(t, pt.msr)
+
+				mTopology.AssertExpectations(t)
+				mMsr.AssertExpectations(t)
+			})
+
+			t.Run("Counters", func(t *testing.T) {
+				counters := []MsrCounter{{Name: "fixed_ctr0", Offset: 0x309, Width: 48}}
+
+				mMsr := &msrMock{}
+
+				// mock initializing msr counters and msr map from powerBuilder.initMsr
+				mMsr.On("initCounters", counters).Return(nil).Once()
+				mMsr.On("initMsrMap", cpus, time.Duration(0)).Return(nil).Once()
+
+				pt, err := New(
+					withTopologyMock(mTopology),
+					withMsrMock(mMsr),
+
+					WithMsrCounters(counters),
+				)
+
+				require.NoError(t, err)
+				require.NotNil(t, pt)
+				require.NotNil(t, pt.msr)
+
+				mTopology.AssertExpectations(t)
+				mMsr.AssertExpectations(t)
+			})
+
+			t.Run("IncludedCPUs", func(t *testing.T) {
+				includedCPUs := []int{0, 1, 2, 3, 4}
+
+				mMsr := &msrMock{}
+
+				// mock initializing msr map from powerBuilder.initMsr
+				mMsr.On("initMsrMap", includedCPUs, time.Duration(0)).Return(nil).Once()
+
+				pt, err := New(
+					withTopologyMock(mTopology),
+					withMsrMock(mMsr),
+
+					WithIncludedCPUs(includedCPUs),
+				)
+
+				require.NoError(t, err)
+				require.NotNil(t, pt)
+				require.NotNil(t, pt.msr)
+				require.Nil(t, pt.GetRaplPackageIDs())
+				require.Equal(t, includedCPUs, pt.GetMsrCPUIDs())
+
+				mTopology.AssertExpectations(t)
+				mMsr.AssertExpectations(t)
+			})
+
+			t.Run("ExcludedCPUs", func(t *testing.T) {
+				excludedCPUs := []int{0, 1, 2, 3, 4}
+				availableCPUs := []int{5, 6, 7, 8, 9}
+
+				mMsr := &msrMock{}
+
+				// mock initializing msr map from powerBuilder.initMsr
+				mMsr.On("initMsrMap", availableCPUs, time.Duration(0)).Return(nil).Once()
+
+				pt, err := New(
+					withTopologyMock(mTopology),
+					withMsrMock(mMsr),
+
+					WithExcludedCPUs(excludedCPUs),
+				)
+
+				require.NoError(t, err)
+				require.NotNil(t, pt)
+				require.NotNil(t, pt.msr)
+				require.Nil(t, pt.GetRaplPackageIDs())
+				require.Equal(t, availableCPUs, pt.GetMsrCPUIDs())
+
+				mTopology.AssertExpectations(t)
+				mMsr.AssertExpectations(t)
+			})
+		})
+
+		t.Run("Rapl", func(t *testing.T) {
+			t.Run("FailedToInitZoneMap", func(t *testing.T) {
+				mRapl := &raplMock{}
+
+				// mock initializing rapl zone map from powerBuilder.initRapl
+				mRapl.On("initZoneMap").Return(mError).Once()
+
+				pt, err := New(
+					withTopologyMock(mTopology),
+					withRaplMock(mRapl),
+				)
+
+				require.ErrorContains(t, err, "failed to initialize rapl")
+				require.NotNil(t, pt)
+				require.Nil(t, pt.rapl)
+				require.Nil(t, pt.GetRaplPackageIDs())
+				require.Equal(t, cpus, pt.GetMsrCPUIDs())
+
+				mTopology.AssertExpectations(t)
+				mRapl.AssertExpectations(t)
+			})
+
+			t.Run("Ok", func(t *testing.T) {
+				pt, err := New(
+					withTopologyMock(mTopology),
+					WithRapl(makeTestDataPath("testdata/intel-rapl")),
+				)
+
+				require.NoError(t, err)
+				require.NotNil(t, pt)
+				require.NotNil(t, pt.rapl)
+				require.Equal(t, []int{0, 1, 2, 3}, pt.GetRaplPackageIDs())
+				require.Equal(t, cpus, pt.GetMsrCPUIDs())
+
+				mTopology.AssertExpectations(t)
+			})
+		})
+
+		t.Run("CoreFrequency", func(t *testing.T) {
+			// TODO: Consider to remove
+			t.Run("FailedToInit", func(t *testing.T) {
+				mCoreFreq := &coreFreqMock{}
+
+				// mock initializing core frequency from powerBuilder.initCoreFreq
+				mCoreFreq.On("init").Return(mError).Once()
+
+				pt, err := New(
+					withTopologyMock(mTopology),
+					withCoreFrequencyMock(mCoreFreq),
+				)
+
+				require.ErrorContains(t, err, "failed to initialize core freq")
+				require.NotNil(t, pt)
+				require.Nil(t, pt.cpuFreq)
+				require.Nil(t, pt.GetRaplPackageIDs())
+				require.Equal(t, cpus, pt.GetMsrCPUIDs())
+
+				mTopology.AssertExpectations(t)
+				mCoreFreq.AssertExpectations(t)
+			})
+
+			t.Run("Ok", func(t *testing.T) {
+				pt, err := New(
+					withTopologyMock(mTopology),
+					WithCoreFrequency("testdata/cpu-freq"),
+				)
+
+				require.NoError(t, err)
+				require.NotNil(t, pt)
+				require.NotNil(t, pt.cpuFreq)
+				require.Nil(t, pt.GetRaplPackageIDs())
+				require.Equal(t, cpus, pt.GetMsrCPUIDs())
+
+				mTopology.AssertExpectations(t)
+			})
+		})
+
+		t.Run("UncoreFrequency", func(t *testing.T) {
+			// TODO: Consider to remove
+			t.Run("FailedToInit", func(t *testing.T) {
+				mUncoreFreq := &uncoreFreqMock{}
+
+				// mock initializing uncore frequency from powerBuilder.initUncoreFreq
+				mUncoreFreq.On("init").Return(mError).Once()
+
+				pt, err := New(
+					withTopologyMock(mTopology),
+					withUncoreFrequencyMock(mUncoreFreq),
+				)
+
+				require.ErrorContains(t, err, "failed to initialize uncore freq")
+				require.NotNil(t, pt)
+				require.Nil(t, pt.cpuFreq)
+				require.Nil(t, pt.GetRaplPackageIDs())
+				require.Equal(t, cpus, pt.GetMsrCPUIDs())
+
+				mTopology.AssertExpectations(t)
+				mUncoreFreq.AssertExpectations(t)
+			})
+
+			t.Run("Ok", func(t *testing.T) {
+				pt, err := New(
+					withTopologyMock(mTopology),
+					WithUncoreFrequency("testdata/intel_uncore_frequency"),
+				)
+
+				require.NoError(t, err)
+				require.NotNil(t, pt)
+				require.NotNil(t, pt.uncoreFreq)
+				require.Nil(t, pt.GetRaplPackageIDs())
+				require.Equal(t, cpus, pt.GetMsrCPUIDs())
+
+				mTopology.AssertExpectations(t)
+			})
+		})
+
+		t.Run("CPUIdle", func(t *testing.T) {
+			t.Run("FailedToInitCPUIdleMap", func(t *testing.T) {
+				mCPUIdle := &cpuIdleMock{}
+
+				// mock initializing cpu idle map from powerBuilder.initCPUIdle
+				mCPUIdle.On("initCPUIdleMap", cpus).Return(mError).Once()
+
+				pt, err := New(
+					withTopologyMock(mTopology),
+					withCPUIdleMock(mCPUIdle),
+				)
+
+				require.ErrorContains(t, err, "failed to initialize cpu idle")
+				require.NotNil(t, pt)
+				require.Nil(t, pt.cpuIdle)
+
+				mTopology.AssertExpectations(t)
+				mCPUIdle.AssertExpectations(t)
+			})
+
+			t.Run("Ok", func(t *testing.T) {
+				mCPUIdle := &cpuIdleMock{}
+
+				// mock initializing cpu idle map from powerBuilder.initCPUIdle
+				mCPUIdle.On("initCPUIdleMap", cpus).Return(nil).Once()
+
+				pt, err := New(
+					withTopologyMock(mTopology),
+					withCPUIdleMock(mCPUIdle),
+				)
+
+				require.NoError(t, err)
+				require.NotNil(t, pt)
+				require.Equal(t, mCPUIdle, pt.cpuIdle)
+
+				mTopology.AssertExpectations(t)
+				mCPUIdle.AssertExpectations(t)
+			})
+		})
+
+		t.Run("ThermalThrottle", func(t *testing.T) {
+			t.Run("FailedToInitThrottleMap", func(t *testing.T) {
+				mThrottle := &thermalThrottleMock{}
+
+				// mock initializing thermal throttle map from powerBuilder.initThermalThrottle
+				mThrottle.On("initThrottleMap", cpus).Return(mError).Once()
+
+				pt, err := New(
+					withTopologyMock(mTopology),
+					withThermalThrottleMock(mThrottle),
+				)
+
+				require.ErrorContains(t, err, "failed to initialize thermal throttle")
+				require.NotNil(t, pt)
+				require.Nil(t, pt.throttle)
+
+				mTopology.AssertExpectations(t)
+				mThrottle.AssertExpectations(t)
+			})
+
+			t.Run("Ok", func(t *testing.T) {
+				mThrottle := &thermalThrottleMock{}
+
+				// mock initializing thermal throttle map from powerBuilder.initThermalThrottle
+				mThrottle.On("initThrottleMap", cpus).Return(nil).Once()
+
+				pt, err := New(
+					withTopologyMock(mTopology),
+					withThermalThrottleMock(mThrottle),
+				)
+
+				require.NoError(t, err)
+				require.NotNil(t, pt)
+				require.Equal(t, mThrottle, pt.throttle)
+
+				mTopology.AssertExpectations(t)
+				mThrottle.AssertExpectations(t)
+			})
+		})
+
+		t.Run("PerfCStates", func(t *testing.T) {
+			t.Run("FailedToInitCounterMap", func(t *testing.T) {
+				mPerfCStates := &pmuCounterMock{}
+
+				// mock initializing counter map from powerBuilder.initPerfCStates
+				mPerfCStates.On("initCounterMap", cpus, []int{0}).Return(mError).Once()
+
+				pt, err := New(
+					withTopologyMock(mTopology),
+					withPerfCStatesMock(mPerfCStates),
+				)
+
+				require.ErrorContains(t, err, "failed to initialize perf cstates")
+				require.NotNil(t, pt)
+				require.Nil(t, pt.cstatePerf)
+
+				mTopology.AssertExpectations(t)
+				mPerfCStates.AssertExpectations(t)
+			})
+
+			t.Run("Ok", func(t *testing.T) {
+				mPerfCStates := &pmuCounterMock{}
+
+				// mock initializing counter map from powerBuilder.initPerfCStates, with package events
+				// counted on the first CPU ID of the package
+				mPerfCStates.On("initCounterMap", cpus, []int{0}).Return(nil).Once()
+
+				pt, err := New(
+					withTopologyMock(mTopology),
+					withPerfCStatesMock(mPerfCStates),
+				)
+
+				require.NoError(t, err)
+				require.NotNil(t, pt)
+				require.Equal(t, mPerfCStates, pt.cstatePerf)
+
+				mTopology.AssertExpectations(t)
+				mPerfCStates.AssertExpectations(t)
+			})
+		})
 
-		// mock initializing topology map
-		mTopology.On("initTopology").Return(nil)
+		t.Run("PerfMsr", func(t *testing.T) {
+			t.Run("FailedToInitCounterMap", func(t *testing.T) {
+				mPerfMsr := &pmuCounterMock{}
 
-		// mock getting CPU family and vendor from isCPUSupported
-		mTopology.On("getCPUFamily", 0).Return("6", nil)
-		mTopology.On("getCPUVendor", 0).Return("GenuineIntel", nil)
+				// mock initializing counter map from powerBuilder.initPerfMsr
+				mPerfMsr.On("initCounterMap", cpus, []int(nil)).Return(mError).Once()
 
-		// mock getting model to calculate bus clock
-		mTopology.On("getCPUModel").Return(cpumodel.INTEL_FAM6_ICELAKE)
+				pt, err := New(
+					withTopologyMock(mTopology),
+					withPerfMsrMock(mPerfMsr),
+				)
 
-		// mock getting number of CPU IDs from powerBuilder.getAvailableCPUs
-		mTopology.On("getCPUsNumber").Return(len(cpus))
+				require.ErrorContains(t, err, "failed to initialize perf msr")
+				require.NotNil(t, pt)
+				require.Nil(t, pt.msrPerf)
 
-		// mock getting topology CPU data from logTopologyDetails
-		mTopology.On("getCPUCoreID", mock.AnythingOfType("int")).Return(0, nil)
-		mTopology.On("getCPUPackageID", mock.AnythingOfType("int")).Return(0, nil)
-		mTopology.On("getCPUDieID", mock.AnythingOfType("int")).Return(0, nil)
+				mTopology.AssertExpectations(t)
+				mPerfMsr.AssertExpectations(t)
+			})
 
-		t.Run("Msr", func(t *testing.T) {
-			t.Run("FailedToInitMsrMap", func(t *testing.T) {
-				mMsr := &msrMock{}
+			t.Run("Ok", func(t *testing.T) {
+				mPerfMsr := &pmuCounterMock{}
 
-				// mock initializing msr map from powerBuilder.initMsr
-				mMsr.On("initMsrMap", cpus, time.Duration(0)).Return(mError).Once()
+				// mock initializing counter map from powerBuilder.initPerfMsr
+				mPerfMsr.On("initCounterMap", cpus, []int(nil)).Return(nil).Once()
 
 				pt, err := New(
 					withTopologyMock(mTopology),
-					withMsrMock(mMsr),
+					withPerfMsrMock(mPerfMsr),
 				)
 
-				require.ErrorContains(t, err, "failed to initialize msr")
+				require.NoError(t, err)
 				require.NotNil(t, pt)
-				require.Nil(t, pt.msr)
-				require.Nil(t, pt.GetRaplPackageIDs())
-				require.Equal(t, cpus, pt.GetMsrCPUIDs())
+				require.Equal(t, mPerfMsr, pt.msrPerf)
 
 				mTopology.AssertExpectations(t)
-				mMsr.AssertExpectations(t)
+				mPerfMsr.AssertExpectations(t)
 			})
+		})
 
-			t.Run("IncludedCPUs", func(t *testing.T) {
-				includedCPUs := []int{0, 1, 2, 3, 4}
-
-				mMsr := &msrMock{}
+		t.Run("CoreTemp", func(t *testing.T) {
+			t.Run("FailedToInitCoreTempMap", func(t *testing.T) {
+				mCoreTemp := &coreTempMock{}
 
-				// mock initializing msr map from powerBuilder.initMsr
-				mMsr.On("initMsrMap", includedCPUs, time.Duration(0)).Return(nil).Once()
+				// mock initializing coretemp map from powerBuilder.initCoreTemp
+				mCoreTemp.On("initCoreTempMap").Return(mError).Once()
 
 				pt, err := New(
 					withTopologyMock(mTopology),
-					withMsrMock(mMsr),
-
-					WithIncludedCPUs(includedCPUs),
+					withCoreTempMock(mCoreTemp),
 				)
 
-				require.NoError(t, err)
+				require.ErrorContains(t, err, "failed to initialize coretemp")
 				require.NotNil(t, pt)
-				require.NotNil(t, pt.msr)
-				require.Nil(t, pt.GetRaplPackageIDs())
-				require.Equal(t, includedCPUs, pt.GetMsrCPUIDs())
+				require.Nil(t, pt.coreTemp)
 
 				mTopology.AssertExpectations(t)
-				mMsr.AssertExpectations(t)
+				mCoreTemp.AssertExpectations(t)
 			})
 
-			t.Run("ExcludedCPUs", func(t *testing.T) {
-				excludedCPUs := []int{0, 1, 2, 3, 4}
-				availableCPUs := []int{5, 6, 7, 8, 9}
-
-				mMsr := &msrMock{}
+			t.Run("Ok", func(t *testing.T) {
+				mCoreTemp := &coreTempMock{}
 
-				// mock initializing msr map from powerBuilder.initMsr
-				mMsr.On("initMsrMap", availableCPUs, time.Duration(0)).Return(nil).Once()
+				// mock initializing coretemp map from powerBuilder.initCoreTemp
+				mCoreTemp.On("initCoreTempMap").Return(nil).Once()
 
 				pt, err := New(
 					withTopologyMock(mTopology),
-					withMsrMock(mMsr),
-
-					WithExcludedCPUs(excludedCPUs),
+					withCoreTempMock(mCoreTemp),
 				)
 
 				require.NoError(t, err)
 				require.NotNil(t, pt)
-				require.NotNil(t, pt.msr)
-				require.Nil(t, pt.GetRaplPackageIDs())
-				require.Equal(t, availableCPUs, pt.GetMsrCPUIDs())
+				require.Equal(t, mCoreTemp, pt.coreTemp)
 
 				mTopology.AssertExpectations(t)
-				mMsr.AssertExpectations(t)
+				mCoreTemp.AssertExpectations(t)
 			})
 		})
 
-		t.Run("Rapl", func(t *testing.T) {
-			t.Run("FailedToInitZoneMap", func(t *testing.T) {
-				mRapl := &raplMock{}
+		t.Run("ThermalZones", func(t *testing.T) {
+			t.Run("FailedToInitThermalZones", func(t *testing.T) {
+				mThermal := &thermalZoneMock{}
 
-				// mock initializing rapl zone map from powerBuilder.initRapl
-				mRapl.On("initZoneMap").Return(mError).Once()
+				// mock initializing thermal zones from powerBuilder.initThermalZones
+				mThermal.On("initThermalZones").Return(mError).Once()
 
 				pt, err := New(
 					withTopologyMock(mTopology),
-					withRaplMock(mRapl),
+					withThermalZoneMock(mThermal),
 				)
 
-				require.ErrorContains(t, err, "failed to initialize rapl")
+				require.ErrorContains(t, err, "failed to initialize thermal zones")
 				require.NotNil(t, pt)
-				require.Nil(t, pt.rapl)
-				require.Nil(t, pt.GetRaplPackageIDs())
-				require.Equal(t, cpus, pt.GetMsrCPUIDs())
+				require.Nil(t, pt.thermal)
 
 				mTopology.AssertExpectations(t)
-				mRapl.AssertExpectations(t)
+				mThermal.AssertExpectations(t)
 			})
 
 			t.Run("Ok", func(t *testing.T) {
+				mThermal := &thermalZoneMock{}
+
+				// mock initializing thermal zones from powerBuilder.initThermalZones
+				mThermal.On("initThermalZones").Return(nil).Once()
+
 				pt, err := New(
 					withTopologyMock(mTopology),
-					WithRapl(makeTestDataPath("testdata/intel-rapl")),
+					withThermalZoneMock(mThermal),
 				)
 
 				require.NoError(t, err)
 				require.NotNil(t, pt)
-				require.NotNil(t, pt.rapl)
-				require.Equal(t, []int{0, 1, 2, 3}, pt.GetRaplPackageIDs())
-				require.Equal(t, cpus, pt.GetMsrCPUIDs())
+				require.Equal(t, mThermal, pt.thermal)
 
 				mTopology.AssertExpectations(t)
+				mThermal.AssertExpectations(t)
 			})
 		})
 
-		t.Run("CoreFrequency", func(t *testing.T) {
-			// TODO: Consider to remove
-			t.Run("FailedToInit", func(t *testing.T) {
-				mCoreFreq := &coreFreqMock{}
+		t.Run("PerfUncore", func(t *testing.T) {
+			packageIDs := []int{0, 1}
 
-				// mock initializing core frequency from powerBuilder.initCoreFreq
-				mCoreFreq.On("init").Return(mError).Once()
+			t.Run("FailedToInitResolver", func(t *testing.T) {
+				mPerfUncore := &perfUncoreMock{}
+
+				// mock initializing perf uncore resolver from powerBuilder.initPerfUncore
+				mPerfUncore.On("initResolver", mock.AnythingOfType("string")).Return(mError).Once()
 
 				pt, err := New(
 					withTopologyMock(mTopology),
-					withCoreFrequencyMock(mCoreFreq),
+					withPerfUncoreMock(mPerfUncore),
 				)
 
-				require.ErrorContains(t, err, "failed to initialize core freq")
+				require.ErrorContains(t, err, "failed to initialize perf uncore: failed to init resolver")
 				require.NotNil(t, pt)
-				require.Nil(t, pt.cpuFreq)
-				require.Nil(t, pt.GetRaplPackageIDs())
-				require.Equal(t, cpus, pt.GetMsrCPUIDs())
+				require.Nil(t, pt.perfUncore)
 
 				mTopology.AssertExpectations(t)
-				mCoreFreq.AssertExpectations(t)
+				mPerfUncore.AssertExpectations(t)
+			})
+
+			t.Run("FailedToActivate", func(t *testing.T) {
+				// mock getting package IDs from powerBuilder.initPerfUncore
+				mTopology.On("getPackageIDs").Return(packageIDs).Once()
+
+				mPerfUncore := &perfUncoreMock{}
+
+				// mock initializing perf uncore resolver and event activation from powerBuilder.initPerfUncore
+				mPerfUncore.On("initResolver", mock.AnythingOfType("string")).Return(nil).Once()
+				mPerfUncore.On("activate", imcCASEvents, packageIDs).Return(mError).Once()
+
+				pt, err := New(
+					withTopologyMock(mTopology),
+					withPerfUncoreMock(mPerfUncore),
+				)
+
+				require.ErrorContains(t, err, "failed to initialize perf uncore: failed to activate events")
+				require.NotNil(t, pt)
+				require.Nil(t, pt.perfUncore)
+
+				mTopology.AssertExpectations(t)
+				mPerfUncore.AssertExpectations(t)
 			})
 
 			t.Run("Ok", func(t *testing.T) {
+				// mock getting package IDs from powerBuilder.initPerfUncore
+				mTopology.On("getPackageIDs").Return(packageIDs).Once()
+
+				mPerfUncore := &perfUncoreMock{}
+
+				// mock initializing perf uncore resolver and event activation from powerBuilder.initPerfUncore
+				mPerfUncore.On("initResolver", mock.AnythingOfType("string")).Return(nil).Once()
+				mPerfUncore.On("activate", imcCASEvents, packageIDs).Return(nil).Once()
+
 				pt, err := New(
 					withTopologyMock(mTopology),
-					WithCoreFrequency("testdata/cpu-freq"),
+					withPerfUncoreMock(mPerfUncore),
 				)
 
 				require.NoError(t, err)
 				require.NotNil(t, pt)
-				require.NotNil(t, pt.cpuFreq)
-				require.Nil(t, pt.GetRaplPackageIDs())
-				require.Equal(t, cpus, pt.GetMsrCPUIDs())
+				require.Equal(t, mPerfUncore, pt.perfUncore)
 
 				mTopology.AssertExpectations(t)
+				mPerfUncore.AssertExpectations(t)
 			})
 		})
 
-		t.Run("UncoreFrequency", func(t *testing.T) {
-			// TODO: Consider to remove
-			t.Run("FailedToInit", func(t *testing.T) {
-				mUncoreFreq := &uncoreFreqMock{}
-
-				// mock initializing uncore frequency from powerBuilder.initUncoreFreq
-				mUncoreFreq.On("init").Return(mError).Once()
+		t.Run("MsrWrites", func(t *testing.T) {
+			allowlist := map[uint32]uint64{0x1A0: 0x1}
 
+			t.Run("MsrIsNil", func(t *testing.T) {
 				pt, err := New(
 					withTopologyMock(mTopology),
-					withUncoreFrequencyMock(mUncoreFreq),
+					WithMsrWrites(allowlist, filepath.Join(t.TempDir(), "msr_journal")),
 				)
 
-				require.ErrorContains(t, err, "failed to initialize uncore freq")
+				require.ErrorContains(t, err, "failed to initialize msr writes: msr is not initialized")
 				require.NotNil(t, pt)
-				require.Nil(t, pt.cpuFreq)
-				require.Nil(t, pt.GetRaplPackageIDs())
-				require.Equal(t, cpus, pt.GetMsrCPUIDs())
+				require.Nil(t, pt.msrWrite)
 
 				mTopology.AssertExpectations(t)
-				mUncoreFreq.AssertExpectations(t)
 			})
 
 			t.Run("Ok", func(t *testing.T) {
+				journalPath := filepath.Join(t.TempDir(), "msr_journal")
+
+				mMsr := &msrMock{}
+
+				// mock enabling msr writes and initializing msr map from powerBuilder.initMsr
+				mMsr.On("enableWrites").Once()
+				mMsr.On("initMsrMap", cpus, time.Duration(0)).Return(nil).Once()
+
 				pt, err := New(
 					withTopologyMock(mTopology),
-					WithUncoreFrequency("testdata/intel_uncore_frequency"),
+					withMsrMock(mMsr),
+					WithMsrWrites(allowlist, journalPath),
 				)
 
 				require.NoError(t, err)
 				require.NotNil(t, pt)
-				require.NotNil(t, pt.uncoreFreq)
-				require.Nil(t, pt.GetRaplPackageIDs())
-				require.Equal(t, cpus, pt.GetMsrCPUIDs())
+				require.NotNil(t, pt.msrWrite)
+				require.Equal(t, allowlist, pt.msrWrite.allowlist)
+				require.FileExists(t, journalPath)
+				require.NoError(t, pt.msrWrite.close())
 
 				mTopology.AssertExpectations(t)
+				mMsr.AssertExpectations(t)
 			})
 		})
 
 		t.Run("Perf", func(t *testing.T) {
+			// processor without architectural fixed-function counters, unless set otherwise by a subtest
+			setArchFixedCounters(t, 0)
+
+			// default events of processor models supporting C0 substate and topdown metrics
+			defaultEvents := append(slices.Clone(cStatePerfEvents), topdownPerfEvents...)
+
 			// Reset mock object for perf
 			mTopology = &topologyMock{}
 
@@ -833,13 +1800,13 @@ func TestNew(t *testing.T) {
 				require.Nil(t, pt.perf)
 				require.ErrorContains(t, err, "failed to initialize perf: perf based metrics are not supported for processor model: 0x0")
 				require.Nil(t, pt.GetRaplPackageIDs())
-				require.Equal(t, cpus, pt.GetPerfCPUIDs())
+				require.Nil(t, pt.GetPerfCPUIDs())
 
 				mTopology.AssertExpectations(t)
 			})
 
 			t.Run("CPUModelNotAllowed", func(t *testing.T) {
-				model := cpumodel.INTEL_FAM6_ICELAKE
+				model := cpumodel.INTEL_FAM6_SKYLAKE_X
 
 				// mock getting model from isCPUSupported, powerBuilder.initPerf and logTopologyDetails
 				mTopology.On("getCPUModel").Return(model).Times(3)
@@ -854,7 +1821,7 @@ func TestNew(t *testing.T) {
 				require.ErrorContains(t, err, "failed to initialize perf")
 				require.ErrorContains(t, err, fmt.Sprintf("perf based metrics are not supported for processor model: 0x%X", model))
 				require.Nil(t, pt.GetRaplPackageIDs())
-				require.Equal(t, cpus, pt.GetPerfCPUIDs())
+				require.Nil(t, pt.GetPerfCPUIDs())
 
 				mTopology.AssertExpectations(t)
 			})
@@ -878,7 +1845,7 @@ func TestNew(t *testing.T) {
 				require.ErrorContains(t, err, "failed to initialize perf")
 				require.ErrorContains(t, err, "failed to init resolver")
 				require.Nil(t, pt.GetRaplPackageIDs())
-				require.Equal(t, cpus, pt.GetPerfCPUIDs())
+				require.Nil(t, pt.GetPerfCPUIDs())
 
 				mTopology.AssertExpectations(t)
 				mPerf.AssertExpectations(t)
@@ -892,7 +1859,7 @@ func TestNew(t *testing.T) {
 
 				// mock initializing perf resolver and event activation from powerBuilder.initPerf
 				mPerf.On("initResolver", mock.AnythingOfType("string")).Return(nil).Once()
-				mPerf.On("activate", cStatePerfEvents, cpus).Return(mError).Once()
+				mPerf.On("activate", defaultEvents, cpus).Return(mError).Once()
 
 				pt, err := New(
 					withTopologyMock(mTopology),
@@ -904,7 +1871,7 @@ func TestNew(t *testing.T) {
 				require.ErrorContains(t, err, "failed to initialize perf")
 				require.ErrorContains(t, err, "failed to activate events")
 				require.Nil(t, pt.GetRaplPackageIDs())
-				require.Equal(t, cpus, pt.GetPerfCPUIDs())
+				require.Nil(t, pt.GetPerfCPUIDs())
 
 				mTopology.AssertExpectations(t)
 				mPerf.AssertExpectations(t)
@@ -920,7 +1887,8 @@ func TestNew(t *testing.T) {
 
 				// mock initializing perf resolver and event activation from powerBuilder.initPerf
 				mPerf.On("initResolver", mock.AnythingOfType("string")).Return(nil).Once()
-				mPerf.On("activate", cStatePerfEvents, includedCPUs).Return(nil).Once()
+				mPerf.On("activate", defaultEvents, includedCPUs).Return(nil).Once()
+				mPerf.On("activeCPUIDs").Return(includedCPUs).Once()
 
 				pt, err := New(
 					withTopologyMock(mTopology),
@@ -950,7 +1918,8 @@ func TestNew(t *testing.T) {
 
 				// mock initializing perf resolver and event activation from powerBuilder.initPerf
 				mPerf.On("initResolver", mock.AnythingOfType("string")).Return(nil).Once()
-				mPerf.On("activate", cStatePerfEvents, availableCPUs).Return(nil).Once()
+				mPerf.On("activate", defaultEvents, availableCPUs).Return(nil).Once()
+				mPerf.On("activeCPUIDs").Return(availableCPUs).Once()
 
 				pt, err := New(
 					withTopologyMock(mTopology),
@@ -968,11 +1937,204 @@ func TestNew(t *testing.T) {
 				mTopology.AssertExpectations(t)
 				mPerf.AssertExpectations(t)
 			})
+
+			t.Run("FixedCounters", func(t *testing.T) {
+				setArchFixedCounters(t, 3)
+
+				// mock getting model from isCPUSupported, powerBuilder.initPerf and logTopologyDetails
+				mTopology.On("getCPUModel").Return(cpumodel.INTEL_FAM6_ICELAKE_X).Times(3)
+
+				mPerf := &perfMock{}
+
+				// mock initializing perf resolver and event activation from powerBuilder.initPerf
+				mPerf.On("initResolver", mock.AnythingOfType("string")).Return(nil).Once()
+				mPerf.On("activate", append(slices.Clone(fixedPerfEvents), topdownPerfEvents...), cpus).Return(nil).Once()
+
+				pt, err := New(
+					withTopologyMock(mTopology),
+					withPerfMock(mPerf),
+				)
+
+				require.NotNil(t, pt)
+				require.NotNil(t, pt.perf)
+				require.NoError(t, err)
+
+				mTopology.AssertExpectations(t)
+				mPerf.AssertExpectations(t)
+			})
+
+			t.Run("HybridWithSingleJSON", func(t *testing.T) {
+				jsonPath := "alderlake_goldencove_core.json"
+
+				// mock getting model from isCPUSupported, powerBuilder.initPerf and logTopologyDetails
+				mTopology.On("getCPUModel").Return(cpumodel.INTEL_FAM6_ALDERLAKE).Times(3)
+
+				mPerf := &perfMock{}
+
+				// mock initializing perf resolvers of both core types with the JSON file given to WithPerf,
+				// and event activation from powerBuilder.initPerf
+				mPerf.On("initHybridResolvers", map[CoreType]string{
+					CoreTypeCore: jsonPath,
+					CoreTypeAtom: jsonPath,
+				}).Return(nil).Once()
+				mPerf.On("activate", append(slices.Clone(cStatePerfEvents), topdownPerfEvents...), cpus).Return(nil).Once()
+
+				pt, err := New(
+					withTopologyMock(mTopology),
+					withPerfMock(mPerf),
+					func(b *powerBuilder) {
+						b.perf.jsonPath = jsonPath
+					},
+				)
+
+				require.NotNil(t, pt)
+				require.NotNil(t, pt.perf)
+				require.NoError(t, err)
+
+				mTopology.AssertExpectations(t)
+				mPerf.AssertExpectations(t)
+				mPerf.AssertNotCalled(t, "initResolver", mock.Anything)
+			})
+
+			t.Run("HybridEmbedded", func(t *testing.T) {
+				// mock getting model from isCPUSupported, powerBuilder.initPerf and logTopologyDetails
+				mTopology.On("getCPUModel").Return(cpumodel.INTEL_FAM6_ALDERLAKE).Times(3)
+
+				mPerf := &perfMock{}
+
+				// mock initializing perf resolvers per core type, with embedded event definitions, and event activation
+				// from powerBuilder.initPerf
+				mPerf.On("initHybridResolvers", map[CoreType]string{
+					CoreTypeCore: "embedded:goldencove_core.json",
+					CoreTypeAtom: "embedded:architectural_core.json",
+				}).Return(nil).Once()
+				mPerf.On("activate", append(slices.Clone(cStatePerfEvents), topdownPerfEvents...), cpus).Return(nil).Once()
+
+				pt, err := New(
+					withTopologyMock(mTopology),
+					withPerfMock(mPerf),
+				)
+
+				require.NotNil(t, pt)
+				require.NotNil(t, pt.perf)
+				require.NoError(t, err)
+
+				mTopology.AssertExpectations(t)
+				mPerf.AssertExpectations(t)
+			})
+
+			t.Run("Hybrid", func(t *testing.T) {
+				jsonPaths := map[CoreType]string{
+					CoreTypeCore: "alderlake_goldencove_core.json",
+					CoreTypeAtom: "alderlake_gracemont_core.json",
+				}
+				withHybridJSONPaths := func(b *powerBuilder) {
+					b.perf.hybridJSONPaths = jsonPaths
+				}
+
+				t.Run("FailedToInitHybridResolvers", func(t *testing.T) {
+					// mock getting model from isCPUSupported, powerBuilder.initPerf and logTopologyDetails
+					mTopology.On("getCPUModel").Return(cpumodel.INTEL_FAM6_ALDERLAKE).Times(3)
+
+					mPerf := &perfMock{}
+
+					// mock initializing perf resolvers per core type from powerBuilder.initPerf
+					mPerf.On("initHybridResolvers", jsonPaths).Return(mError).Once()
+
+					pt, err := New(
+						withTopologyMock(mTopology),
+						withPerfMock(mPerf),
+						withHybridJSONPaths,
+					)
+
+					require.NotNil(t, pt)
+					require.Nil(t, pt.perf)
+					require.ErrorContains(t, err, "failed to init hybrid resolvers")
+
+					mTopology.AssertExpectations(t)
+					mPerf.AssertExpectations(t)
+				})
+
+				t.Run("Valid", func(t *testing.T) {
+					// mock getting model from isCPUSupported, powerBuilder.initPerf and logTopologyDetails
+					mTopology.On("getCPUModel").Return(cpumodel.INTEL_FAM6_ALDERLAKE).Times(3)
+
+					mPerf := &perfMock{}
+
+					// mock initializing perf resolvers per core type and event activation from powerBuilder.initPerf
+					mPerf.On("initHybridResolvers", jsonPaths).Return(nil).Once()
+					mPerf.On("activate", defaultEvents, cpus).Return(nil).Once()
+
+					pt, err := New(
+						withTopologyMock(mTopology),
+						withPerfMock(mPerf),
+						withHybridJSONPaths,
+					)
+
+					require.NotNil(t, pt)
+					require.NotNil(t, pt.perf)
+					require.NoError(t, err)
+
+					mTopology.AssertExpectations(t)
+					mPerf.AssertExpectations(t)
+				})
+			})
+
+			t.Run("InvalidEvents", func(t *testing.T) {
+				// mock getting model from isCPUSupported and logTopologyDetails
+				mTopology.On("getCPUModel").Return(cpumodel.INTEL_FAM6_ICELAKE_X).Twice()
+
+				mPerf := &perfMock{}
+
+				pt, err := New(
+					withTopologyMock(mTopology),
+					withPerfMock(mPerf),
+					WithPerfEvents([]string{"INST_RETIRED.ANY", "inst_retired.any"}),
+				)
+
+				require.NotNil(t, pt)
+				require.Nil(t, pt.perf)
+				require.ErrorContains(t, err, "failed to initialize perf: duplicated perf event \"inst_retired.any\"")
+
+				mTopology.AssertExpectations(t)
+				mPerf.AssertExpectations(t)
+			})
+
+			t.Run("CustomEvents", func(t *testing.T) {
+				events := []string{"CPU_CLK_UNHALTED.THREAD", "INST_RETIRED.ANY"}
+
+				// mock getting model from isCPUSupported, powerBuilder.initPerf and logTopologyDetails, perf events are
+				// not limited to processor models supporting C0 substate metrics
+				mTopology.On("getCPUModel").Return(cpumodel.INTEL_FAM6_ICELAKE_X).Times(3)
+
+				mPerf := &perfMock{}
+
+				// mock initializing perf resolver, with embedded event definitions of the processor model, and event
+				// activation from powerBuilder.initPerf
+				mPerf.On("initResolver", "embedded:icelake_core.json").Return(nil).Once()
+				mPerf.On("activate", events, cpus).Return(nil).Once()
+				mPerf.On("activeCPUIDs").Return(cpus).Once()
+
+				pt, err := New(
+					withTopologyMock(mTopology),
+					withPerfMock(mPerf),
+					WithPerfEvents(events),
+				)
+
+				require.NotNil(t, pt)
+				require.NotNil(t, pt.perf)
+				require.NoError(t, err)
+				require.Equal(t, cpus, pt.GetPerfCPUIDs())
+
+				mTopology.AssertExpectations(t)
+				mPerf.AssertExpectations(t)
+			})
 		})
 
 		t.Run("Multiple", func(t *testing.T) {
 			t.Run("FailedMsrAndPerf", func(t *testing.T) {
 				includedCPUs := []int{0, 2, 4, 6, 8}
+				setArchFixedCounters(t, 0)
 
 				// mock getting model from isCPUSupported, powerBuilder.initPerf and logTopologyDetails
 				mTopology.On("getCPUModel").Return(cpumodel.INTEL_FAM6_EMERALDRAPIDS_X).Times(3)
@@ -986,7 +2148,7 @@ func TestNew(t *testing.T) {
 
 				// mock initializing perf resolver and event activation from powerBuilder.initPerf
 				mPerf.On("initResolver", mock.AnythingOfType("string")).Return(nil).Once()
-				mPerf.On("activate", cStatePerfEvents, includedCPUs).Return(mError).Once()
+				mPerf.On("activate", append(slices.Clone(cStatePerfEvents), topdownPerfEvents...), includedCPUs).Return(mError).Once()
 
 				pt, err := New(
 					withTopologyMock(mTopology),
@@ -1007,7 +2169,7 @@ func TestNew(t *testing.T) {
 				require.NotNil(t, pt.cpuFreq)
 				require.NotNil(t, pt.uncoreFreq)
 				require.Equal(t, []int{0, 1, 2, 3}, pt.GetRaplPackageIDs())
-				require.Equal(t, includedCPUs, pt.GetPerfCPUIDs())
+				require.Nil(t, pt.GetPerfCPUIDs())
 
 				mTopology.AssertExpectations(t)
 				mMsr.AssertExpectations(t)
@@ -1033,7 +2195,8 @@ func TestNew(t *testing.T) {
 				require.NotNil(t, pt.cpuFreq)
 				require.NotNil(t, pt.uncoreFreq)
 				require.Nil(t, pt.GetRaplPackageIDs())
-				require.Equal(t, []int{0, 2, 4, 6, 8}, pt.GetPerfCPUIDs())
+				require.Equal(t, []int{0, 2, 4, 6, 8}, pt.GetMsrCPUIDs())
+				require.Nil(t, pt.GetPerfCPUIDs())
 
 				mTopology.AssertExpectations(t)
 			})
@@ -1146,14 +2309,13 @@ func Test_IsPerfAllowed(t *testing.T) {
 	models := []int{
 		0xCF, //INTEL_FAM6_EMERALDRAPIDS_X
 		0x8F, //INTEL_FAM6_SAPPHIRERAPIDS_X
-		//TODO: Hybrid models are not supported right now
-		//0x97, //INTEL_FAM6_ALDERLAKE
-		//0x9A, //INTEL_FAM6_ALDERLAKE_L
-		//0xB7, //INTEL_FAM6_RAPTORLAKE
-		//0xBA, //INTEL_FAM6_RAPTORLAKE_P
-		//0xBF, //INTEL_FAM6_RAPTORLAKE_S
-		//0xAC, //INTEL_FAM6_METEORLAKE
-		//0xAA, //INTEL_FAM6_METEORLAKE_L
+		0x97, //INTEL_FAM6_ALDERLAKE
+		0x9A, //INTEL_FAM6_ALDERLAKE_L
+		0xB7, //INTEL_FAM6_RAPTORLAKE
+		0xBA, //INTEL_FAM6_RAPTORLAKE_P
+		0xBF, //INTEL_FAM6_RAPTORLAKE_S
+		0xAC, //INTEL_FAM6_METEORLAKE
+		0xAA, //INTEL_FAM6_METEORLAKE_L
 	}
 
 	m := map[int]interface{}{}
@@ -1166,3 +2328,24 @@ func Test_IsPerfAllowed(t *testing.T) {
 		require.Equalf(t, m[model] != nil, isAllowed, "Model 0x%X")
 	}
 }
+
+func Test_IsHybridModel(t *testing.T) {
+	models := []int{
+		0x97, //INTEL_FAM6_ALDERLAKE
+		0x9A, //INTEL_FAM6_ALDERLAKE_L
+		0xB7, //INTEL_FAM6_RAPTORLAKE
+		0xBA, //INTEL_FAM6_RAPTORLAKE_P
+		0xBF, //INTEL_FAM6_RAPTORLAKE_S
+		0xAC, //INTEL_FAM6_METEORLAKE
+		0xAA, //INTEL_FAM6_METEORLAKE_L
+	}
+
+	m := map[int]interface{}{}
+	for _, model := range models {
+		m[model] = struct{}{}
+	}
+
+	for model := 0; model < 0xFF; model++ {
+		require.Equalf(t, m[model] != nil, isHybridModel(model), "Model 0x%X", model)
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/intel/powertelemetry/internal/log"
+)
+
+const (
+	// Path to folder with hardware monitoring devices.
+	defaultHwmonBasePath = "/sys/class/hwmon"
+
+	// Name of the hardware monitoring driver for Intel digital temperature sensors.
+	coreTempDriverName = "coretemp"
+
+	// Pattern of the names of the folders of hardware monitoring devices.
+	hwmonDirPattern = "hwmon*"
+
+	// Name of the file with the driver name of a hardware monitoring device.
+	hwmonNameFile = "name"
+
+	// Pattern of the names of the files with temperature sensor labels.
+	tempLabelFilePattern = "temp*_label"
+
+	// Pattern of the names of the files with temperature sensor attributes.
+	tempAttrFilePattern = "temp%d_%s"
+
+	// Patterns of the labels of package and core temperature sensors.
+	packageTempLabelPattern = "Package id %d"
+	coreTempLabelPattern    = "Core %d"
+
+	// Number of milli-degrees Celsius in a degree Celsius.
+	milliDegreesPerDegree = 1000
+)
+
+// tempAttribute represents an attribute of a temperature sensor exposed by hwmon.
+type tempAttribute string
+
+// tempAttribute enum defines supported attributes of temperature sensors.
+const (
+	tempInput tempAttribute = "input"
+	tempMax   tempAttribute = "max"
+	tempCrit  tempAttribute = "crit"
+)
+
+// TemperatureThresholds holds the temperature thresholds of a core or a package, in degrees Celsius.
+type TemperatureThresholds struct {
+	Max      uint64 // Temperature at which the hardware starts thermal throttling
+	Critical uint64 // Temperature at which the hardware triggers a thermal shutdown
+}
+
+// coreTempReader represents a mechanism for reading temperatures exposed by coretemp hwmon driver.
+type coreTempReader interface {
+	// initCoreTempMap discovers coretemp hwmon devices and maps their sensors to package and core IDs.
+	initCoreTempMap() error
+
+	// getPackageTemperature takes a package ID and a sensor attribute, and returns the attribute value
+	// of the package temperature sensor, in degrees Celsius.
+	getPackageTemperature(packageID int, attr tempAttribute) (uint64, error)
+
+	// getCoreTemperature takes a package ID, a core ID and a sensor attribute, and returns the attribute value
+	// of the core temperature sensor, in degrees Celsius.
+	getCoreTemperature(packageID, coreID int, attr tempAttribute) (uint64, error)
+}
+
+// tempSensor identifies a temperature sensor of a hwmon device.
+type tempSensor struct {
+	path  string
+	index int
+}
+
+// coreTempPackage holds the temperature sensors of a package, exposed by a coretemp hwmon device.
+type coreTempPackage struct {
+	sensor *tempSensor
+	cores  map[int]*tempSensor
+}
+
+// coreTempData allows to get temperatures exposed by coretemp hwmon driver. Implements coreTempReader interface.
+type coreTempData struct {
+	basePath   string
+	packageMap map[int]*coreTempPackage
+}
+
+// initCoreTempMap discovers coretemp hwmon devices and maps their temperature sensors to package and core IDs,
+// based on sensor labels. Each coretemp device corresponds to a single package.
+func (c *coreTempData) initCoreTempMap() error {
+	if len(c.basePath) == 0 {
+		return errors.New("base path of hwmon cannot be empty")
+	}
+	if err := checkFile(c.basePath); err != nil {
+		return fmt.Errorf("invalid base path of hwmon: %w", err)
+	}
+
+	hwmonPaths, err := filepath.Glob(filepath.Join(c.basePath, hwmonDirPattern))
+	if err != nil {
+		return fmt.Errorf("error looking for hwmon devices: %w", err)
+	}
+
+	packageMap := make(map[int]*coreTempPackage)
+	for _, hwmonPath := range hwmonPaths {
+		name, err := readSysfsString(hwmonPath, hwmonNameFile)
+		if err != nil || name != coreTempDriverName {
+			continue
+		}
+
+		packageID, pkg, err := parseCoreTempDevice(hwmonPath)
+		if err != nil {
+			log.Warnf("Skipping coretemp device %q: %v", hwmonPath, err)
+			continue
+		}
+		if _, ok := packageMap[packageID]; ok {
+			return fmt.Errorf("duplicated coretemp device for package ID: %v", packageID)
+		}
+		packageMap[packageID] = pkg
+	}
+	if len(packageMap) == 0 {
+		return fmt.Errorf("no %s hwmon devices found in %q", coreTempDriverName, c.basePath)
+	}
+	c.packageMap = packageMap
+	return nil
+}
+
+// getPackageTemperature takes a package ID and a sensor attribute, and returns the attribute value
+// of the package temperature sensor, in degrees Celsius.
+func (c *coreTempData) getPackageTemperature(packageID int, attr tempAttribute) (uint64, error) {
+	pkg, err := c.getPackage(packageID)
+	if err != nil {
+		return 0, err
+	}
+	if pkg.sensor == nil {
+		return 0, fmt.Errorf("could not find temperature sensor for package ID: %v", packageID)
+	}
+	return pkg.sensor.read(attr)
+}
+
+// getCoreTemperature takes a package ID, a core ID and a sensor attribute, and returns the attribute value
+// of the core temperature sensor, in degrees Celsius.
+func (c *coreTempData) getCoreTemperature(packageID, coreID int, attr tempAttribute) (uint64, error) {
+	pkg, err := c.getPackage(packageID)
+	if err != nil {
+		return 0, err
+	}
+	sensor, ok := pkg.cores[coreID]
+	if !ok {
+		return 0, fmt.Errorf("could not find temperature sensor for core ID %v and package ID %v", coreID, packageID)
+	}
+	return sensor.read(attr)
+}
+
+// getPackage takes a package ID and returns its coretemp sensors.
+func (c *coreTempData) getPackage(packageID int) (*coreTempPackage, error) {
+	pkg, ok := c.packageMap[packageID]
+	if !ok {
+		return nil, fmt.Errorf("could not find coretemp device for package ID: %v", packageID)
+	}
+	return pkg, nil
+}
+
+// parseCoreTempDevice takes the path of a coretemp hwmon device and returns the package ID it belongs to,
+// together with its package and core temperature sensors.
+func parseCoreTempDevice(hwmonPath string) (int, *coreTempPackage, error) {
+	labelPaths, err := filepath.Glob(filepath.Join(hwmonPath, tempLabelFilePattern))
+	if err != nil {
+		return 0, nil, fmt.Errorf("error looking for temperature labels: %w", err)
+	}
+
+	packageID := -1
+	pkg := &coreTempPackage{
+		cores: make(map[int]*tempSensor),
+	}
+	for _, labelPath := range labelPaths {
+		var index int
+		if _, err := fmt.Sscanf(filepath.Base(labelPath), "temp%d_label", &index); err != nil {
+			continue
+		}
+		label, err := readSysfsString(hwmonPath, filepath.Base(labelPath))
+		if err != nil {
+			return 0, nil, err
+		}
+
+		sensor := &tempSensor{
+			path:  hwmonPath,
+			index: index,
+		}
+		var id int
+		switch {
+		case strings.HasPrefix(label, "Package"):
+			if _, err := fmt.Sscanf(label, packageTempLabelPattern, &id); err != nil {
+				return 0, nil, fmt.Errorf("error parsing temperature label %q: %w", label, err)
+			}
+			packageID = id
+			pkg.sensor = sensor
+		case strings.HasPrefix(label, "Core"):
+			if _, err := fmt.Sscanf(label, coreTempLabelPattern, &id); err != nil {
+				return 0, nil, fmt.Errorf("error parsing temperature label %q: %w", label, err)
+			}
+			pkg.cores[id] = sensor
+		}
+	}
+	if packageID < 0 {
+		return 0, nil, errors.New("package temperature sensor not found")
+	}
+	return packageID, pkg, nil
+}
+
+// read takes a sensor attribute and returns its value, in degrees Celsius.
+func (s *tempSensor) read(attr tempAttribute) (uint64, error) {
+	value, err := readSysfsUint(s.path, fmt.Sprintf(tempAttrFilePattern, s.index, attr))
+	if err != nil {
+		return 0, err
+	}
+	return value / milliDegreesPerDegree, nil
+}
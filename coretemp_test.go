@@ -0,0 +1,159 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeHwmonDevice takes a base path, a hwmon device name, a driver name and a map of temperature sensor
+// labels by index, and writes the name, label and attribute files of the device. Sensor attribute values are
+// derived from the index, i.e. input is index*1000+500, max is 90000 and crit is 100000 milli-degrees Celsius.
+func writeHwmonDevice(t *testing.T, basePath, device, driver string, labels map[int]string) {
+	t.Helper()
+
+	path := filepath.Join(basePath, device)
+	require.NoError(t, os.MkdirAll(path, 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(path, hwmonNameFile), []byte(driver+"\n"), 0640))
+
+	for index, label := range labels {
+		files := map[string]string{
+			fmt.Sprintf("temp%d_label", index):                 label,
+			fmt.Sprintf(tempAttrFilePattern, index, tempInput): fmt.Sprintf("%d", index*1000+500),
+			fmt.Sprintf(tempAttrFilePattern, index, tempMax):   "90000",
+			fmt.Sprintf(tempAttrFilePattern, index, tempCrit):  "100000",
+		}
+		for name, content := range files {
+			require.NoError(t, os.WriteFile(filepath.Join(path, name), []byte(content+"\n"), 0640))
+		}
+	}
+}
+
+func TestCoreTempData_InitCoreTempMap(t *testing.T) {
+	t.Run("BasePathEmpty", func(t *testing.T) {
+		c := &coreTempData{}
+
+		require.ErrorContains(t, c.initCoreTempMap(), "base path of hwmon cannot be empty")
+	})
+
+	t.Run("BasePathNotExist", func(t *testing.T) {
+		c := &coreTempData{
+			basePath: "/dummy/path",
+		}
+
+		require.ErrorContains(t, c.initCoreTempMap(), "invalid base path of hwmon")
+	})
+
+	t.Run("NoCoreTempDevices", func(t *testing.T) {
+		basePath := t.TempDir()
+		writeHwmonDevice(t, basePath, "hwmon0", "acpitz", map[int]string{1: "Package id 0"})
+
+		c := &coreTempData{
+			basePath: basePath,
+		}
+
+		require.ErrorContains(t, c.initCoreTempMap(), "no coretemp hwmon devices found")
+	})
+
+	t.Run("PackageSensorNotFound", func(t *testing.T) {
+		basePath := t.TempDir()
+		writeHwmonDevice(t, basePath, "hwmon0", coreTempDriverName, map[int]string{2: "Core 0"})
+
+		c := &coreTempData{
+			basePath: basePath,
+		}
+
+		require.ErrorContains(t, c.initCoreTempMap(), "no coretemp hwmon devices found")
+	})
+
+	t.Run("DuplicatedPackage", func(t *testing.T) {
+		basePath := t.TempDir()
+		writeHwmonDevice(t, basePath, "hwmon0", coreTempDriverName, map[int]string{1: "Package id 0"})
+		writeHwmonDevice(t, basePath, "hwmon1", coreTempDriverName, map[int]string{1: "Package id 0"})
+
+		c := &coreTempData{
+			basePath: basePath,
+		}
+
+		require.ErrorContains(t, c.initCoreTempMap(), "duplicated coretemp device for package ID: 0")
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		basePath := t.TempDir()
+		writeHwmonDevice(t, basePath, "hwmon0", "acpitz", map[int]string{1: "Package id 5"})
+		writeHwmonDevice(t, basePath, "hwmon1", coreTempDriverName, map[int]string{1: "Package id 0", 2: "Core 0", 6: "Core 4"})
+		writeHwmonDevice(t, basePath, "hwmon2", coreTempDriverName, map[int]string{1: "Package id 1", 2: "Core 0"})
+
+		c := &coreTempData{
+			basePath: basePath,
+		}
+
+		require.NoError(t, c.initCoreTempMap())
+		require.Len(t, c.packageMap, 2)
+
+		path := filepath.Join(basePath, "hwmon1")
+		require.Equal(t, &coreTempPackage{
+			sensor: &tempSensor{path: path, index: 1},
+			cores: map[int]*tempSensor{
+				0: {path: path, index: 2},
+				4: {path: path, index: 6},
+			},
+		}, c.packageMap[0])
+	})
+}
+
+func TestCoreTempData_GetTemperature(t *testing.T) {
+	basePath := t.TempDir()
+	writeHwmonDevice(t, basePath, "hwmon0", coreTempDriverName, map[int]string{1: "Package id 0", 2: "Core 0", 6: "Core 4"})
+
+	c := &coreTempData{
+		basePath: basePath,
+	}
+	require.NoError(t, c.initCoreTempMap())
+
+	t.Run("PackageNotFound", func(t *testing.T) {
+		temp, err := c.getPackageTemperature(1, tempInput)
+		require.ErrorContains(t, err, "could not find coretemp device for package ID: 1")
+		require.Zero(t, temp)
+
+		temp, err = c.getCoreTemperature(1, 0, tempInput)
+		require.ErrorContains(t, err, "could not find coretemp device for package ID: 1")
+		require.Zero(t, temp)
+	})
+
+	t.Run("CoreNotFound", func(t *testing.T) {
+		temp, err := c.getCoreTemperature(0, 1, tempInput)
+		require.ErrorContains(t, err, "could not find temperature sensor for core ID 1 and package ID 0")
+		require.Zero(t, temp)
+	})
+
+	t.Run("AttributeNotFound", func(t *testing.T) {
+		require.NoError(t, os.Remove(filepath.Join(basePath, "hwmon0", fmt.Sprintf(tempAttrFilePattern, 6, tempMax))))
+
+		temp, err := c.getCoreTemperature(0, 4, tempMax)
+		require.ErrorContains(t, err, "temp6_max")
+		require.Zero(t, temp)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		temp, err := c.getPackageTemperature(0, tempInput)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), temp)
+
+		temp, err = c.getCoreTemperature(0, 4, tempInput)
+		require.NoError(t, err)
+		require.Equal(t, uint64(6), temp)
+
+		temp, err = c.getCoreTemperature(0, 0, tempCrit)
+		require.NoError(t, err)
+		require.Equal(t, uint64(100), temp)
+	})
+}
@@ -0,0 +1,447 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/intel/powertelemetry/internal/log"
+)
+
+const (
+	// Path to folder with a collection of both global and individual CPU attributes.
+	defaultCPUIdleBasePath = "/sys/devices/system/cpu"
+
+	// Pattern of the path to a folder with idle states of a CPU.
+	cpuIdlePathPattern = "cpu%d/cpuidle"
+
+	// Path to folder with parameters of intel_idle driver.
+	defaultIntelIdleParamsPath = "/sys/module/intel_idle/parameters"
+
+	// Names of the files with parameters of intel_idle driver.
+	intelIdleMaxCStateFile = "max_cstate"
+	intelIdleStatesOffFile = "states_off"
+)
+
+// Names of the files exposed by the kernel for each idle state of a CPU.
+const (
+	cpuIdleNameFile      = "name"
+	cpuIdleDescFile      = "desc"
+	cpuIdleLatencyFile   = "latency"
+	cpuIdleResidencyFile = "residency"
+	cpuIdleUsageFile     = "usage"
+	cpuIdleTimeFile      = "time"
+	cpuIdleDisableFile   = "disable"
+)
+
+// cpuIdleStateRegex is a regular expression matching idle state directory names.
+var cpuIdleStateRegex = regexp.MustCompile(`^state(\d+)$`)
+
+// CPUIdleState represents the static attributes of a CPU idle state exposed by the cpuidle subsystem.
+type CPUIdleState struct {
+	Name              string // Name of the idle state, e.g. "POLL", "C1", "C1E", "C6"
+	Description       string // Description of the idle state
+	ExitLatencyUs     uint64 // Exit latency of the idle state, in microseconds
+	TargetResidencyUs uint64 // Minimum residency time in the idle state to be worth entering it, in microseconds
+}
+
+// cpuIdleStateDelta holds the counter deltas of an idle state between the latest and the previous reading operation.
+type cpuIdleStateDelta struct {
+	usage uint64 // number of times the idle state was entered
+	time  uint64 // time spent in the idle state, in microseconds
+}
+
+// cpuIdleReader represents a mechanism for reading CPU idle state statistics exposed via filesystem.
+type cpuIdleReader interface {
+	// initCPUIdleMap takes a slice of CPU IDs and initializes the idle state storage for each of them.
+	initCPUIdleMap(cpus []int) error
+
+	// update takes a CPU ID and reads the usage and time counters of all its idle states.
+	update(cpuID int) error
+
+	// getStates takes a CPU ID and returns the attributes of its idle states, ordered by state index.
+	getStates(cpuID int) ([]CPUIdleState, error)
+
+	// getStateDeltas takes a CPU ID and returns a map of idle state name and its counter deltas
+	// between the latest and the previous reading operation.
+	getStateDeltas(cpuID int) (map[string]cpuIdleStateDelta, error)
+
+	// getTimestampDelta takes a CPU ID and returns the time interval between the latest and
+	// the previous reading operation.
+	getTimestampDelta(cpuID int) (time.Duration, error)
+
+	// isStateDisabled takes a CPU ID and an idle state name, and returns true if the idle state is disabled.
+	isStateDisabled(cpuID int, stateName string) (bool, error)
+
+	// setStateDisabled takes a CPU ID, an idle state name and a boolean, and disables or enables the idle state.
+	// The original value is recorded the first time an idle state is modified.
+	setStateDisabled(cpuID int, stateName string, disabled bool) error
+
+	// restore writes back the original values of all idle states modified via setStateDisabled.
+	restore() error
+
+	// getIntelIdleParam takes the name of an intel_idle driver parameter and returns its value.
+	getIntelIdleParam(name string) (string, error)
+}
+
+// cpuIdleState holds the attributes and the counter values of an idle state of a CPU.
+type cpuIdleState struct {
+	CPUIdleState
+
+	path       string
+	usage      uint64 // usage counter value from the last read operation
+	time       uint64 // time counter value from the last read operation
+	usageDelta uint64 // usage counter delta between the latest and its previous reading operation
+	timeDelta  uint64 // time counter delta between the latest and its previous reading operation
+}
+
+// cpuIdleStorage holds the idle states of a CPU and the timestamps of its reading operations.
+type cpuIdleStorage struct {
+	cpuID          int
+	states         []*cpuIdleState
+	timestamp      time.Time     // timestamp of the last reading operation
+	timestampDelta time.Duration // timestamp delta between the last read and its previous reading operation
+}
+
+// cpuIdleData allows to get CPU idle state statistics exposed via filesystem. Implements cpuIdleReader interface.
+type cpuIdleData struct {
+	basePath       string
+	intelIdlePath  string
+	cpuMap         map[int]*cpuIdleStorage
+	originalStates map[string]bool // original disable values of modified idle states, by idle state path
+}
+
+// initCPUIdleMap takes a slice of CPU IDs and discovers the idle states of each CPU ID. It also stores an initial
+// reading of the idle state counters. In case any of the CPU IDs does not expose idle states, an error is returned.
+func (c *cpuIdleData) initCPUIdleMap(cpus []int) error {
+	if len(c.basePath) == 0 {
+		return errors.New("base path of CPU idle states cannot be empty")
+	}
+	if err := checkFile(c.basePath); err != nil {
+		return fmt.Errorf("invalid base path of CPU idle states: %w", err)
+	}
+
+	cpuMap := make(map[int]*cpuIdleStorage, len(cpus))
+	for _, cpuID := range cpus {
+		storage, err := newCPUIdleStorage(c.basePath, cpuID)
+		if err != nil {
+			return fmt.Errorf("error initializing idle states for CPU ID %v: %w", cpuID, err)
+		}
+		if err := storage.update(); err != nil {
+			return fmt.Errorf("error reading idle states for CPU ID %v: %w", cpuID, err)
+		}
+		cpuMap[cpuID] = storage
+	}
+	c.cpuMap = cpuMap
+	return nil
+}
+
+// update takes a CPU ID and updates the storage with the latest values of its idle state counters.
+func (c *cpuIdleData) update(cpuID int) error {
+	storage, err := c.getStorage(cpuID)
+	if err != nil {
+		return err
+	}
+	return storage.update()
+}
+
+// getStates takes a CPU ID and returns the attributes of its idle states, ordered by state index.
+func (c *cpuIdleData) getStates(cpuID int) ([]CPUIdleState, error) {
+	storage, err := c.getStorage(cpuID)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]CPUIdleState, 0, len(storage.states))
+	for _, s := range storage.states {
+		states = append(states, s.CPUIdleState)
+	}
+	return states, nil
+}
+
+// getStateDeltas takes a CPU ID and returns a map of idle state name and its counter deltas between
+// the latest and the previous reading operation.
+func (c *cpuIdleData) getStateDeltas(cpuID int) (map[string]cpuIdleStateDelta, error) {
+	storage, err := c.getStorage(cpuID)
+	if err != nil {
+		return nil, err
+	}
+
+	deltas := make(map[string]cpuIdleStateDelta, len(storage.states))
+	for _, s := range storage.states {
+		deltas[s.Name] = cpuIdleStateDelta{
+			usage: s.usageDelta,
+			time:  s.timeDelta,
+		}
+	}
+	return deltas, nil
+}
+
+// getTimestampDelta takes a CPU ID and returns the time interval between the latest and the previous
+// reading operation of its idle state counters.
+func (c *cpuIdleData) getTimestampDelta(cpuID int) (time.Duration, error) {
+	storage, err := c.getStorage(cpuID)
+	if err != nil {
+		return 0, err
+	}
+	return storage.timestampDelta, nil
+}
+
+// isStateDisabled takes a CPU ID and an idle state name, and returns true if the idle state is disabled.
+func (c *cpuIdleData) isStateDisabled(cpuID int, stateName string) (bool, error) {
+	state, err := c.getState(cpuID, stateName)
+	if err != nil {
+		return false, err
+	}
+	return readCPUIdleDisabled(state.path)
+}
+
+// setStateDisabled takes a CPU ID, an idle state name and a boolean, and writes the disable file of the idle state
+// accordingly. The first time an idle state is modified its original value is recorded, so it can be put back
+// by restore.
+func (c *cpuIdleData) setStateDisabled(cpuID int, stateName string, disabled bool) error {
+	state, err := c.getState(cpuID, stateName)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := c.originalStates[state.path]; !ok {
+		original, err := readCPUIdleDisabled(state.path)
+		if err != nil {
+			return err
+		}
+		if c.originalStates == nil {
+			c.originalStates = make(map[string]bool)
+		}
+		c.originalStates[state.path] = original
+	}
+	return writeCPUIdleDisabled(state.path, disabled)
+}
+
+// restore writes back the original disable values of all idle states modified via setStateDisabled.
+// Idle states successfully restored are forgotten. If one or more idle states could not be restored,
+// an error is returned.
+func (c *cpuIdleData) restore() error {
+	var errs []error
+	for path, original := range c.originalStates {
+		if err := writeCPUIdleDisabled(path, original); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		delete(c.originalStates, path)
+	}
+	return errors.Join(errs...)
+}
+
+// getIntelIdleParam takes the name of an intel_idle driver parameter and returns its value.
+func (c *cpuIdleData) getIntelIdleParam(name string) (string, error) {
+	if len(c.intelIdlePath) == 0 {
+		return "", errors.New("path of intel_idle parameters cannot be empty")
+	}
+	return readSysfsString(c.intelIdlePath, name)
+}
+
+// getState takes a CPU ID and an idle state name, and returns the idle state of the CPU ID with the given name.
+func (c *cpuIdleData) getState(cpuID int, stateName string) (*cpuIdleState, error) {
+	storage, err := c.getStorage(cpuID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, state := range storage.states {
+		if state.Name == stateName {
+			return state, nil
+		}
+	}
+	return nil, fmt.Errorf("idle state %q not found for CPU ID: %v", stateName, cpuID)
+}
+
+// getStorage takes a CPU ID and returns its idle state storage.
+func (c *cpuIdleData) getStorage(cpuID int) (*cpuIdleStorage, error) {
+	storage, ok := c.cpuMap[cpuID]
+	if !ok {
+		return nil, fmt.Errorf("could not find idle states for CPU ID: %v", cpuID)
+	}
+	return storage, nil
+}
+
+// newCPUIdleStorage takes a base path and a CPU ID, and returns a storage with the idle states found
+// within the cpuidle directory of the CPU ID. Idle states are ordered by state index.
+func newCPUIdleStorage(basePath string, cpuID int) (*cpuIdleStorage, error) {
+	cpuIdlePath := filepath.Join(basePath, fmt.Sprintf(cpuIdlePathPattern, cpuID))
+	if err := checkFile(cpuIdlePath); err != nil {
+		return nil, err
+	}
+
+	stateDirs, err := os.ReadDir(cpuIdlePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading directory %q: %w", cpuIdlePath, err)
+	}
+
+	type indexedState struct {
+		index int
+		state *cpuIdleState
+	}
+
+	indexed := make([]indexedState, 0, len(stateDirs))
+	for _, stateDir := range stateDirs {
+		match := cpuIdleStateRegex.FindStringSubmatch(stateDir.Name())
+		if !stateDir.IsDir() || match == nil {
+			continue
+		}
+		index, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid idle state directory name %q: %w", stateDir.Name(), err)
+		}
+
+		state, err := newCPUIdleState(filepath.Join(cpuIdlePath, stateDir.Name()))
+		if err != nil {
+			return nil, err
+		}
+		indexed = append(indexed, indexedState{index: index, state: state})
+	}
+
+	if len(indexed) == 0 {
+		return nil, fmt.Errorf("no idle states found for path %q", cpuIdlePath)
+	}
+
+	slices.SortFunc(indexed, func(a, b indexedState) int {
+		return a.index - b.index
+	})
+
+	states := make([]*cpuIdleState, 0, len(indexed))
+	for _, s := range indexed {
+		states = append(states, s.state)
+	}
+
+	return &cpuIdleStorage{
+		cpuID:  cpuID,
+		states: states,
+	}, nil
+}
+
+// newCPUIdleState takes the path of an idle state directory and returns an idle state with its static attributes.
+func newCPUIdleState(path string) (*cpuIdleState, error) {
+	name, err := readSysfsString(path, cpuIdleNameFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(name) == 0 {
+		return nil, fmt.Errorf("idle state name cannot be empty for path %q", path)
+	}
+
+	desc, err := readSysfsString(path, cpuIdleDescFile)
+	if err != nil {
+		return nil, err
+	}
+
+	latency, err := readSysfsUint(path, cpuIdleLatencyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	residency, err := readSysfsUint(path, cpuIdleResidencyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cpuIdleState{
+		CPUIdleState: CPUIdleState{
+			Name:              name,
+			Description:       desc,
+			ExitLatencyUs:     latency,
+			TargetResidencyUs: residency,
+		},
+		path: path,
+	}, nil
+}
+
+// update reads the usage and time counters of all idle states of the receiver, and updates
+// both the last read values and the deltas with respect to the previous reading operation.
+func (s *cpuIdleStorage) update() error {
+	type counters struct {
+		usage uint64
+		time  uint64
+	}
+
+	latest := make([]counters, 0, len(s.states))
+	for _, state := range s.states {
+		usage, err := readSysfsUint(state.path, cpuIdleUsageFile)
+		if err != nil {
+			return err
+		}
+		t, err := readSysfsUint(state.path, cpuIdleTimeFile)
+		if err != nil {
+			return err
+		}
+		latest = append(latest, counters{usage: usage, time: t})
+	}
+
+	// Deltas of the first reading operation are not available, since there is no previous one.
+	newTimestamp := timeNowFn()
+	firstRead := s.timestamp.IsZero()
+	if !firstRead {
+		s.timestampDelta = newTimestamp.Sub(s.timestamp)
+	}
+	s.timestamp = newTimestamp
+
+	for i, state := range s.states {
+		switch {
+		case firstRead:
+			state.usageDelta, state.timeDelta = 0, 0
+		case latest[i].usage < state.usage || latest[i].time < state.time:
+			state.usageDelta, state.timeDelta = 0, 0
+			log.Warnf("A negative delta for the idle state %q and CPU ID %v", state.Name, s.cpuID)
+		default:
+			state.usageDelta = latest[i].usage - state.usage
+			state.timeDelta = latest[i].time - state.time
+		}
+		state.usage, state.time = latest[i].usage, latest[i].time
+	}
+	return nil
+}
+
+// readCPUIdleDisabled takes an idle state path and returns true if the idle state is disabled.
+func readCPUIdleDisabled(statePath string) (bool, error) {
+	value, err := readSysfsUint(statePath, cpuIdleDisableFile)
+	if err != nil {
+		return false, err
+	}
+	return value != 0, nil
+}
+
+// writeCPUIdleDisabled takes an idle state path and a boolean, and disables or enables the idle state.
+func writeCPUIdleDisabled(statePath string, disabled bool) error {
+	value := "0"
+	if disabled {
+		value = "1"
+	}
+
+	path := filepath.Join(statePath, cpuIdleDisableFile)
+	if err := writeFile(path, []byte(value)); err != nil {
+		return fmt.Errorf("error writing file %q: %w", path, err)
+	}
+	return nil
+}
+
+// isCPUIdleStateOf takes an idle state name and a C-state name, e.g. "C1", and returns true if the idle state
+// is a flavor of the C-state. For instance, "C1", "C1E" and "C1_ACPI" are flavors of "C1", while "C10" is not.
+func isCPUIdleStateOf(stateName, cState string) bool {
+	name := strings.ToUpper(stateName)
+	if !strings.HasPrefix(name, cState) {
+		return false
+	}
+	suffix := name[len(cState):]
+	return len(suffix) == 0 || suffix[0] < '0' || suffix[0] > '9'
+}
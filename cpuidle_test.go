@@ -0,0 +1,364 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testCPUIdleState holds the content of the files of an idle state directory used by tests.
+type testCPUIdleState struct {
+	name      string
+	desc      string
+	latency   uint64
+	residency uint64
+	usage     uint64
+	time      uint64
+	disabled  bool
+}
+
+// writeCPUIdleState takes a base path, a CPU ID, a state index and its content, and writes the idle state
+// files to the corresponding cpuidle directory.
+func writeCPUIdleState(t *testing.T, basePath string, cpuID, index int, s testCPUIdleState) {
+	t.Helper()
+
+	statePath := filepath.Join(basePath, fmt.Sprintf(cpuIdlePathPattern, cpuID), fmt.Sprintf("state%d", index))
+	require.NoError(t, os.MkdirAll(statePath, 0750))
+
+	disable := "0"
+	if s.disabled {
+		disable = "1"
+	}
+
+	files := map[string]string{
+		cpuIdleNameFile:      s.name,
+		cpuIdleDescFile:      s.desc,
+		cpuIdleLatencyFile:   strconv.FormatUint(s.latency, 10),
+		cpuIdleResidencyFile: strconv.FormatUint(s.residency, 10),
+		cpuIdleUsageFile:     strconv.FormatUint(s.usage, 10),
+		cpuIdleTimeFile:      strconv.FormatUint(s.time, 10),
+		cpuIdleDisableFile:   disable,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(statePath, name), []byte(content+"\n"), 0640))
+	}
+}
+
+func TestCPUIdleData_InitCPUIdleMap(t *testing.T) {
+	t.Run("BasePathEmpty", func(t *testing.T) {
+		c := &cpuIdleData{}
+
+		err := c.initCPUIdleMap([]int{0})
+		require.ErrorContains(t, err, "base path of CPU idle states cannot be empty")
+	})
+
+	t.Run("BasePathNotExist", func(t *testing.T) {
+		c := &cpuIdleData{
+			basePath: "/dummy/path",
+		}
+
+		err := c.initCPUIdleMap([]int{0})
+		require.ErrorContains(t, err, "invalid base path of CPU idle states")
+	})
+
+	t.Run("CPUIdleDirNotExist", func(t *testing.T) {
+		basePath := t.TempDir()
+
+		c := &cpuIdleData{
+			basePath: basePath,
+		}
+
+		err := c.initCPUIdleMap([]int{0})
+		require.ErrorContains(t, err, "error initializing idle states for CPU ID 0")
+		require.ErrorContains(t, err, "does not exist")
+	})
+
+	t.Run("NoStates", func(t *testing.T) {
+		basePath := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(basePath, "cpu0", "cpuidle", "driver"), 0750))
+
+		c := &cpuIdleData{
+			basePath: basePath,
+		}
+
+		err := c.initCPUIdleMap([]int{0})
+		require.ErrorContains(t, err, "no idle states found for path")
+	})
+
+	t.Run("EmptyStateName", func(t *testing.T) {
+		basePath := t.TempDir()
+		writeCPUIdleState(t, basePath, 0, 0, testCPUIdleState{})
+
+		c := &cpuIdleData{
+			basePath: basePath,
+		}
+
+		err := c.initCPUIdleMap([]int{0})
+		require.ErrorContains(t, err, "idle state name cannot be empty")
+	})
+
+	t.Run("InvalidCounterValue", func(t *testing.T) {
+		basePath := t.TempDir()
+		writeCPUIdleState(t, basePath, 0, 0, testCPUIdleState{name: "POLL"})
+		usagePath := filepath.Join(basePath, "cpu0", "cpuidle", "state0", cpuIdleUsageFile)
+		require.NoError(t, os.WriteFile(usagePath, []byte("invalid\n"), 0640))
+
+		c := &cpuIdleData{
+			basePath: basePath,
+		}
+
+		err := c.initCPUIdleMap([]int{0})
+		require.ErrorContains(t, err, "error reading idle states for CPU ID 0")
+		require.ErrorContains(t, err, fmt.Sprintf("error while converting value from file %q", usagePath))
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		basePath := t.TempDir()
+		for _, cpuID := range []int{0, 1} {
+			writeCPUIdleState(t, basePath, cpuID, 10, testCPUIdleState{name: "C10", desc: "MWAIT 0x60", latency: 890, residency: 5000})
+			writeCPUIdleState(t, basePath, cpuID, 0, testCPUIdleState{name: "POLL", desc: "CPUIDLE CORE POLL IDLE"})
+			writeCPUIdleState(t, basePath, cpuID, 2, testCPUIdleState{name: "C6", desc: "MWAIT 0x20", latency: 170, residency: 600})
+			writeCPUIdleState(t, basePath, cpuID, 1, testCPUIdleState{name: "C1", desc: "MWAIT 0x00", latency: 2, residency: 2})
+		}
+
+		c := &cpuIdleData{
+			basePath: basePath,
+		}
+
+		require.NoError(t, c.initCPUIdleMap([]int{1}))
+		require.Len(t, c.cpuMap, 1)
+
+		states, err := c.getStates(1)
+		require.NoError(t, err)
+		require.Equal(t, []CPUIdleState{
+			{Name: "POLL", Description: "CPUIDLE CORE POLL IDLE"},
+			{Name: "C1", Description: "MWAIT 0x00", ExitLatencyUs: 2, TargetResidencyUs: 2},
+			{Name: "C6", Description: "MWAIT 0x20", ExitLatencyUs: 170, TargetResidencyUs: 600},
+			{Name: "C10", Description: "MWAIT 0x60", ExitLatencyUs: 890, TargetResidencyUs: 5000},
+		}, states)
+
+		_, err = c.getStates(0)
+		require.ErrorContains(t, err, "could not find idle states for CPU ID: 0")
+	})
+}
+
+func TestCPUIdleData_Update(t *testing.T) {
+	setFakeClock()
+	defer unsetFakeClock()
+	fakeClock.Set(time.Now())
+
+	cpuID := 0
+	basePath := t.TempDir()
+	writeCPUIdleState(t, basePath, cpuID, 0, testCPUIdleState{name: "POLL", usage: 10, time: 100})
+	writeCPUIdleState(t, basePath, cpuID, 1, testCPUIdleState{name: "C1", usage: 1000, time: 200000})
+	writeCPUIdleState(t, basePath, cpuID, 2, testCPUIdleState{name: "C6", usage: 50, time: 300000})
+
+	c := &cpuIdleData{
+		basePath: basePath,
+	}
+	require.NoError(t, c.initCPUIdleMap([]int{cpuID}))
+
+	t.Run("CPUIDNotFound", func(t *testing.T) {
+		require.ErrorContains(t, c.update(1), "could not find idle states for CPU ID: 1")
+
+		_, err := c.getStateDeltas(1)
+		require.ErrorContains(t, err, "could not find idle states for CPU ID: 1")
+
+		_, err = c.getTimestampDelta(1)
+		require.ErrorContains(t, err, "could not find idle states for CPU ID: 1")
+	})
+
+	t.Run("FirstRead", func(t *testing.T) {
+		deltas, err := c.getStateDeltas(cpuID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]cpuIdleStateDelta{
+			"POLL": {},
+			"C1":   {},
+			"C6":   {},
+		}, deltas)
+
+		interval, err := c.getTimestampDelta(cpuID)
+		require.NoError(t, err)
+		require.Zero(t, interval)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		writeCPUIdleState(t, basePath, cpuID, 0, testCPUIdleState{name: "POLL", usage: 15, time: 150})
+		writeCPUIdleState(t, basePath, cpuID, 1, testCPUIdleState{name: "C1", usage: 1400, time: 450000})
+		writeCPUIdleState(t, basePath, cpuID, 2, testCPUIdleState{name: "C6", usage: 70, time: 800000})
+		fakeClock.Add(2 * time.Second)
+
+		require.NoError(t, c.update(cpuID))
+
+		deltas, err := c.getStateDeltas(cpuID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]cpuIdleStateDelta{
+			"POLL": {usage: 5, time: 50},
+			"C1":   {usage: 400, time: 250000},
+			"C6":   {usage: 20, time: 500000},
+		}, deltas)
+
+		interval, err := c.getTimestampDelta(cpuID)
+		require.NoError(t, err)
+		require.Equal(t, 2*time.Second, interval)
+	})
+
+	t.Run("NegativeDelta", func(t *testing.T) {
+		writeCPUIdleState(t, basePath, cpuID, 1, testCPUIdleState{name: "C1", usage: 10, time: 100})
+		fakeClock.Add(time.Second)
+
+		require.NoError(t, c.update(cpuID))
+
+		deltas, err := c.getStateDeltas(cpuID)
+		require.NoError(t, err)
+		require.Equal(t, cpuIdleStateDelta{}, deltas["C1"])
+		require.Equal(t, cpuIdleStateDelta{}, deltas["POLL"])
+	})
+}
+
+func TestCPUIdleData_SetStateDisabled(t *testing.T) {
+	basePath := t.TempDir()
+	for _, cpuID := range []int{0, 1} {
+		writeCPUIdleState(t, basePath, cpuID, 0, testCPUIdleState{name: "POLL"})
+		writeCPUIdleState(t, basePath, cpuID, 1, testCPUIdleState{name: "C1"})
+		writeCPUIdleState(t, basePath, cpuID, 2, testCPUIdleState{name: "C6", disabled: true})
+	}
+
+	c := &cpuIdleData{
+		basePath: basePath,
+	}
+	require.NoError(t, c.initCPUIdleMap([]int{0, 1}))
+
+	t.Run("CPUIDNotFound", func(t *testing.T) {
+		_, err := c.isStateDisabled(2, "C1")
+		require.ErrorContains(t, err, "could not find idle states for CPU ID: 2")
+
+		err = c.setStateDisabled(2, "C1", true)
+		require.ErrorContains(t, err, "could not find idle states for CPU ID: 2")
+	})
+
+	t.Run("StateNotFound", func(t *testing.T) {
+		_, err := c.isStateDisabled(0, "C3")
+		require.ErrorContains(t, err, "idle state \"C3\" not found for CPU ID: 0")
+
+		err = c.setStateDisabled(0, "C3", true)
+		require.ErrorContains(t, err, "idle state \"C3\" not found for CPU ID: 0")
+	})
+
+	t.Run("SetAndRestore", func(t *testing.T) {
+		require.NoError(t, c.setStateDisabled(0, "C1", true))
+		require.NoError(t, c.setStateDisabled(0, "C6", false))
+		require.NoError(t, c.setStateDisabled(1, "C6", false))
+		// a second modification must not override the original value
+		require.NoError(t, c.setStateDisabled(0, "C1", false))
+		require.NoError(t, c.setStateDisabled(0, "C1", true))
+
+		disabled, err := c.isStateDisabled(0, "C1")
+		require.NoError(t, err)
+		require.True(t, disabled)
+
+		disabled, err = c.isStateDisabled(0, "C6")
+		require.NoError(t, err)
+		require.False(t, disabled)
+
+		disabled, err = c.isStateDisabled(1, "C1")
+		require.NoError(t, err)
+		require.False(t, disabled)
+
+		require.Len(t, c.originalStates, 3)
+		require.NoError(t, c.restore())
+		require.Empty(t, c.originalStates)
+
+		for _, cpuID := range []int{0, 1} {
+			disabled, err = c.isStateDisabled(cpuID, "C1")
+			require.NoError(t, err)
+			require.False(t, disabled)
+
+			disabled, err = c.isStateDisabled(cpuID, "C6")
+			require.NoError(t, err)
+			require.True(t, disabled)
+		}
+	})
+
+	t.Run("FailedToRestore", func(t *testing.T) {
+		require.NoError(t, c.setStateDisabled(1, "C1", true))
+
+		disablePath := filepath.Join(basePath, "cpu1", "cpuidle", "state1", cpuIdleDisableFile)
+		require.NoError(t, os.Remove(disablePath))
+
+		err := c.restore()
+		require.ErrorContains(t, err, fmt.Sprintf("error writing file %q", disablePath))
+		require.Len(t, c.originalStates, 1)
+	})
+}
+
+func TestCPUIdleData_GetIntelIdleParam(t *testing.T) {
+	t.Run("PathEmpty", func(t *testing.T) {
+		c := &cpuIdleData{}
+
+		_, err := c.getIntelIdleParam(intelIdleMaxCStateFile)
+		require.ErrorContains(t, err, "path of intel_idle parameters cannot be empty")
+	})
+
+	t.Run("FileNotExist", func(t *testing.T) {
+		c := &cpuIdleData{
+			intelIdlePath: t.TempDir(),
+		}
+
+		_, err := c.getIntelIdleParam(intelIdleMaxCStateFile)
+		require.ErrorContains(t, err, "does not exist")
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		path := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(path, intelIdleMaxCStateFile), []byte("9\n"), 0640))
+		require.NoError(t, os.WriteFile(filepath.Join(path, intelIdleStatesOffFile), []byte("4\n"), 0640))
+
+		c := &cpuIdleData{
+			intelIdlePath: path,
+		}
+
+		value, err := c.getIntelIdleParam(intelIdleMaxCStateFile)
+		require.NoError(t, err)
+		require.Equal(t, "9", value)
+
+		value, err = c.getIntelIdleParam(intelIdleStatesOffFile)
+		require.NoError(t, err)
+		require.Equal(t, "4", value)
+	})
+}
+
+func TestIsCPUIdleStateOf(t *testing.T) {
+	testCases := []struct {
+		stateName string
+		cState    string
+		expected  bool
+	}{
+		{"C1", "C1", true},
+		{"C1E", "C1", true},
+		{"C1_ACPI", "C1", true},
+		{"c1e", "C1", true},
+		{"C10", "C1", false},
+		{"POLL", "C1", false},
+		{"C6", "C6", true},
+		{"C6S", "C6", true},
+		{"C6SP", "C6", true},
+		{"C7", "C6", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.stateName+"_"+tc.cState, func(t *testing.T) {
+			require.Equal(t, tc.expected, isCPUIdleStateOf(tc.stateName, tc.cState))
+		})
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"fmt"
+
+	"github.com/intel/powertelemetry/internal/cpumodel"
+)
+
+// CStateConfig holds the package C-state configuration of a package, as allowed by BIOS.
+type CStateConfig struct {
+	PackageCStateLimit     string // Deepest package C-state allowed, e.g. "pc6", "unlimited" or "reserved"
+	PackageCStateLimitCode uint64 // Raw encoding of the package C-state limit, MSR_PKG_CST_CONFIG_CONTROL[3:0]
+	Locked                 bool   // CFG lock, MSR_PKG_CST_CONFIG_CONTROL[15] is set
+	IOMwaitRedirection     bool   // I/O MWAIT redirection, MSR_PKG_CST_CONFIG_CONTROL[10] is set
+	C3AutoDemotion         bool   // C3 auto demotion, MSR_PKG_CST_CONFIG_CONTROL[25] is set
+	C1AutoDemotion         bool   // C1 auto demotion, MSR_PKG_CST_CONFIG_CONTROL[26] is set
+	C3AutoUndemotion       bool   // C3 auto undemotion, MSR_PKG_CST_CONFIG_CONTROL[27] is set
+	C1AutoUndemotion       bool   // C1 auto undemotion, MSR_PKG_CST_CONFIG_CONTROL[28] is set
+	C1E                    bool   // C1E enable, MSR_POWER_CTL[1] is set
+}
+
+// Package C-state limit names, as reported by turbostat.
+const (
+	pcl0   = "pc0"
+	pcl1   = "pc1"
+	pcl2   = "pc2"
+	pcl3   = "pc3"
+	pcl4   = "pc4"
+	pcl6   = "pc6"
+	pcl6n  = "pc6n"
+	pcl6r  = "pc6r"
+	pcl7   = "pc7"
+	pcl7s  = "pc7s"
+	pcl8   = "pc8"
+	pcl9   = "pc9"
+	pcl10  = "pc10"
+	pclUnl = "unlimited"
+	pclRsv = "reserved"
+)
+
+// Per-model encodings of MSR_PKG_CST_CONFIG_CONTROL[3:0] package C-state limit.
+var (
+	nhmPkgCStateLimits = [16]string{pcl0, pcl1, pcl3, pcl6, pcl7, pclRsv, pclRsv, pclUnl, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv}
+	snbPkgCStateLimits = [16]string{pcl0, pcl2, pcl6n, pcl6r, pcl7, pcl7s, pclRsv, pclUnl, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv}
+	hswPkgCStateLimits = [16]string{pcl0, pcl2, pcl3, pcl6, pcl7, pcl7s, pcl8, pcl9, pclUnl, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv}
+	slvPkgCStateLimits = [16]string{pcl0, pcl1, pclRsv, pclRsv, pcl4, pclRsv, pcl6, pcl7, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv}
+	amtPkgCStateLimits = [16]string{pclUnl, pcl1, pcl2, pclRsv, pclRsv, pclRsv, pcl6, pcl7, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv}
+	phiPkgCStateLimits = [16]string{pcl0, pcl2, pcl6n, pcl6r, pclRsv, pclRsv, pclRsv, pclUnl, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv}
+	glmPkgCStateLimits = [16]string{pclUnl, pcl1, pcl3, pcl6, pcl7, pcl7s, pcl8, pcl9, pcl10, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv}
+	skxPkgCStateLimits = [16]string{pcl0, pcl2, pcl6n, pcl6r, pclRsv, pclRsv, pclRsv, pclUnl, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv}
+	icxPkgCStateLimits = [16]string{pcl0, pcl2, pcl6, pcl6, pclRsv, pclRsv, pclRsv, pclUnl, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv, pclRsv}
+)
+
+// GetPackageCStateConfig takes a package ID and returns its package C-state configuration decoded from
+// MSR_PKG_CST_CONFIG_CONTROL and MSR_POWER_CTL registers of a CPU ID within the package.
+func (pt *PowerTelemetry) GetPackageCStateConfig(packageID int) (CStateConfig, error) {
+	if pt.msr == nil {
+		return CStateConfig{}, &ModuleNotInitializedError{Name: "msr"}
+	}
+
+	model := pt.topology.getCPUModel()
+	if err := CheckIfPackageCStateConfigSupported(model); err != nil {
+		return CStateConfig{}, err
+	}
+	limits := getPkgCStateLimits(model)
+
+	cpuID, err := pt.getCPUIDFromPackageID(packageID)
+	if err != nil {
+		return CStateConfig{}, fmt.Errorf("could not find CPU ID for package ID %v: %w", packageID, err)
+	}
+
+	cstCtl, err := pt.msr.read(pkgCStConfigControl, cpuID)
+	if err != nil {
+		return CStateConfig{}, fmt.Errorf("can't read MSR 0x%X: %w", pkgCStConfigControl, err)
+	}
+
+	powCtl, err := pt.msr.read(powerCtl, cpuID)
+	if err != nil {
+		return CStateConfig{}, fmt.Errorf("can't read MSR 0x%X: %w", powerCtl, err)
+	}
+
+	code := cstCtl & 0xF
+	return CStateConfig{
+		PackageCStateLimit:     limits[code],
+		PackageCStateLimitCode: code,
+		Locked:                 cstCtl&(1<<15) != 0,
+		IOMwaitRedirection:     cstCtl&(1<<10) != 0,
+		C3AutoDemotion:         cstCtl&(1<<25) != 0,
+		C1AutoDemotion:         cstCtl&(1<<26) != 0,
+		C3AutoUndemotion:       cstCtl&(1<<27) != 0,
+		C1AutoUndemotion:       cstCtl&(1<<28) != 0,
+		C1E:                    powCtl&(1<<1) != 0,
+	}, nil
+}
+
+// getPkgCStateLimits takes a CPU model and returns the table to decode the package C-state limit
+// of MSR_PKG_CST_CONFIG_CONTROL. If the model is not supported, it returns nil.
+func getPkgCStateLimits(model int) *[16]string {
+	switch model {
+	case
+		cpumodel.INTEL_FAM6_NEHALEM,
+		cpumodel.INTEL_FAM6_NEHALEM_G,
+		cpumodel.INTEL_FAM6_NEHALEM_EP,
+		cpumodel.INTEL_FAM6_NEHALEM_EX,
+		cpumodel.INTEL_FAM6_WESTMERE,
+		cpumodel.INTEL_FAM6_WESTMERE_EP,
+		cpumodel.INTEL_FAM6_WESTMERE_EX:
+		return &nhmPkgCStateLimits
+
+	case
+		cpumodel.INTEL_FAM6_SANDYBRIDGE,
+		cpumodel.INTEL_FAM6_SANDYBRIDGE_X,
+		cpumodel.INTEL_FAM6_IVYBRIDGE,
+		cpumodel.INTEL_FAM6_IVYBRIDGE_X,
+		cpumodel.INTEL_FAM6_HASWELL_X,
+		cpumodel.INTEL_FAM6_BROADWELL_X,
+		cpumodel.INTEL_FAM6_BROADWELL_D:
+		return &snbPkgCStateLimits
+
+	case
+		cpumodel.INTEL_FAM6_HASWELL,
+		cpumodel.INTEL_FAM6_HASWELL_L,
+		cpumodel.INTEL_FAM6_HASWELL_G,
+		cpumodel.INTEL_FAM6_BROADWELL,
+		cpumodel.INTEL_FAM6_BROADWELL_G,
+		cpumodel.INTEL_FAM6_SKYLAKE_L,
+		cpumodel.INTEL_FAM6_SKYLAKE,
+		cpumodel.INTEL_FAM6_KABYLAKE_L,
+		cpumodel.INTEL_FAM6_KABYLAKE,
+		cpumodel.INTEL_FAM6_COMETLAKE,
+		cpumodel.INTEL_FAM6_COMETLAKE_L,
+		cpumodel.INTEL_FAM6_CANNONLAKE_L,
+		cpumodel.INTEL_FAM6_ICELAKE,
+		cpumodel.INTEL_FAM6_ICELAKE_L,
+		cpumodel.INTEL_FAM6_ICELAKE_NNPI,
+		cpumodel.INTEL_FAM6_ROCKETLAKE,
+		cpumodel.INTEL_FAM6_TIGERLAKE_L,
+		cpumodel.INTEL_FAM6_TIGERLAKE,
+		cpumodel.INTEL_FAM6_LAKEFIELD,
+		cpumodel.INTEL_FAM6_ALDERLAKE,
+		cpumodel.INTEL_FAM6_ALDERLAKE_L,
+		cpumodel.INTEL_FAM6_RAPTORLAKE,
+		cpumodel.INTEL_FAM6_RAPTORLAKE_P,
+		cpumodel.INTEL_FAM6_RAPTORLAKE_S,
+		cpumodel.INTEL_FAM6_METEORLAKE,
+		cpumodel.INTEL_FAM6_METEORLAKE_L:
+		return &hswPkgCStateLimits
+
+	case
+		cpumodel.INTEL_FAM6_SKYLAKE_X,
+		cpumodel.INTEL_FAM6_SAPPHIRERAPIDS_X,
+		cpumodel.INTEL_FAM6_EMERALDRAPIDS_X:
+		return &skxPkgCStateLimits
+
+	case
+		cpumodel.INTEL_FAM6_ICELAKE_X,
+		cpumodel.INTEL_FAM6_ICELAKE_D:
+		return &icxPkgCStateLimits
+
+	case
+		cpumodel.INTEL_FAM6_ATOM_SILVERMONT,
+		cpumodel.INTEL_FAM6_ATOM_SILVERMONT_D,
+		cpumodel.INTEL_FAM6_ATOM_SILVERMONT_MID:
+		return &slvPkgCStateLimits
+
+	case cpumodel.INTEL_FAM6_ATOM_AIRMONT:
+		return &amtPkgCStateLimits
+
+	case
+		cpumodel.INTEL_FAM6_ATOM_GOLDMONT,
+		cpumodel.INTEL_FAM6_ATOM_GOLDMONT_D,
+		cpumodel.INTEL_FAM6_ATOM_GOLDMONT_PLUS,
+		cpumodel.INTEL_FAM6_ATOM_TREMONT_D,
+		cpumodel.INTEL_FAM6_ATOM_TREMONT,
+		cpumodel.INTEL_FAM6_ATOM_TREMONT_L:
+		return &glmPkgCStateLimits
+
+	case
+		cpumodel.INTEL_FAM6_XEON_PHI_KNL,
+		cpumodel.INTEL_FAM6_XEON_PHI_KNM:
+		return &phiPkgCStateLimits
+	}
+	return nil
+}
@@ -0,0 +1,335 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/intel/powertelemetry/internal/cpumodel"
+)
+
+func TestGetPkgCStateLimits(t *testing.T) {
+	testCases := []struct {
+		name   string
+		models []int
+		limits *[16]string
+	}{
+		{
+			name: "Nehalem",
+			models: []int{
+				0x1E, // INTEL_FAM6_NEHALEM
+				0x1F, // INTEL_FAM6_NEHALEM_G
+				0x1A, // INTEL_FAM6_NEHALEM_EP
+				0x2E, // INTEL_FAM6_NEHALEM_EX
+				0x25, // INTEL_FAM6_WESTMERE
+				0x2C, // INTEL_FAM6_WESTMERE_EP
+				0x2F, // INTEL_FAM6_WESTMERE_EX
+			},
+			limits: &nhmPkgCStateLimits,
+		},
+		{
+			name: "SandyBridge",
+			models: []int{
+				0x2A, // INTEL_FAM6_SANDYBRIDGE
+				0x2D, // INTEL_FAM6_SANDYBRIDGE_X
+				0x3A, // INTEL_FAM6_IVYBRIDGE
+				0x3E, // INTEL_FAM6_IVYBRIDGE_X
+				0x3F, // INTEL_FAM6_HASWELL_X
+				0x4F, // INTEL_FAM6_BROADWELL_X
+				0x56, // INTEL_FAM6_BROADWELL_D
+			},
+			limits: &snbPkgCStateLimits,
+		},
+		{
+			name: "Haswell",
+			models: []int{
+				0x3C, // INTEL_FAM6_HASWELL
+				0x45, // INTEL_FAM6_HASWELL_L
+				0x46, // INTEL_FAM6_HASWELL_G
+				0x3D, // INTEL_FAM6_BROADWELL
+				0x47, // INTEL_FAM6_BROADWELL_G
+				0x4E, // INTEL_FAM6_SKYLAKE_L
+				0x5E, // INTEL_FAM6_SKYLAKE
+				0x8E, // INTEL_FAM6_KABYLAKE_L
+				0x9E, // INTEL_FAM6_KABYLAKE
+				0xA5, // INTEL_FAM6_COMETLAKE
+				0xA6, // INTEL_FAM6_COMETLAKE_L
+				0x66, // INTEL_FAM6_CANNONLAKE_L
+				0x7D, // INTEL_FAM6_ICELAKE
+				0x7E, // INTEL_FAM6_ICELAKE_L
+				0x9D, // INTEL_FAM6_ICELAKE_NNPI
+				0xA7, // INTEL_FAM6_ROCKETLAKE
+				0x8C, // INTEL_FAM6_TIGERLAKE_L
+				0x8D, // INTEL_FAM6_TIGERLAKE
+				0x8A, // INTEL_FAM6_LAKEFIELD
+				0x97, // INTEL_FAM6_ALDERLAKE
+				0x9A, // INTEL_FAM6_ALDERLAKE_L
+				0xB7, // INTEL_FAM6_RAPTORLAKE
+				0xBA, // INTEL_FAM6_RAPTORLAKE_P
+				0xBF, // INTEL_FAM6_RAPTORLAKE_S
+				0xAC, // INTEL_FAM6_METEORLAKE
+				0xAA, // INTEL_FAM6_METEORLAKE_L
+			},
+			limits: &hswPkgCStateLimits,
+		},
+		{
+			name: "Skylake",
+			models: []int{
+				0x55, // INTEL_FAM6_SKYLAKE_X
+				0x8F, // INTEL_FAM6_SAPPHIRERAPIDS_X
+				0xCF, // INTEL_FAM6_EMERALDRAPIDS_X
+			},
+			limits: &skxPkgCStateLimits,
+		},
+		{
+			name: "Icelake",
+			models: []int{
+				0x6A, // INTEL_FAM6_ICELAKE_X
+				0x6C, // INTEL_FAM6_ICELAKE_D
+			},
+			limits: &icxPkgCStateLimits,
+		},
+		{
+			name: "Silvermont",
+			models: []int{
+				0x37, // INTEL_FAM6_ATOM_SILVERMONT
+				0x4D, // INTEL_FAM6_ATOM_SILVERMONT_D
+				0x4A, // INTEL_FAM6_ATOM_SILVERMONT_MID
+			},
+			limits: &slvPkgCStateLimits,
+		},
+		{
+			name: "Airmont",
+			models: []int{
+				0x4C, // INTEL_FAM6_ATOM_AIRMONT
+			},
+			limits: &amtPkgCStateLimits,
+		},
+		{
+			name: "Goldmont",
+			models: []int{
+				0x5C, // INTEL_FAM6_ATOM_GOLDMONT
+				0x5F, // INTEL_FAM6_ATOM_GOLDMONT_D
+				0x7A, // INTEL_FAM6_ATOM_GOLDMONT_PLUS
+				0x86, // INTEL_FAM6_ATOM_TREMONT_D
+				0x96, // INTEL_FAM6_ATOM_TREMONT
+				0x9C, // INTEL_FAM6_ATOM_TREMONT_L
+			},
+			limits: &glmPkgCStateLimits,
+		},
+		{
+			name: "XeonPhi",
+			models: []int{
+				0x57, // INTEL_FAM6_XEON_PHI_KNL
+				0x85, // INTEL_FAM6_XEON_PHI_KNM
+			},
+			limits: &phiPkgCStateLimits,
+		},
+	}
+
+	m := make(map[int]*[16]string)
+	for _, tc := range testCases {
+		for _, model := range tc.models {
+			m[model] = tc.limits
+		}
+	}
+
+	for model := 0; model < 0xFF; model++ {
+		limits := getPkgCStateLimits(model)
+		require.Samef(t, m[model], limits, "Model 0x%X", model)
+
+		err := CheckIfPackageCStateConfigSupported(model)
+		if m[model] != nil {
+			require.NoError(t, err, "CPU model 0x%X should support package c-state configuration", model)
+		} else {
+			require.ErrorContains(t, err, fmt.Sprintf("package c-state configuration not supported by CPU model: 0x%X", model),
+				"CPU model 0x%X shouldn't support package c-state configuration", model)
+		}
+	}
+}
+
+func TestGetPackageCStateConfig(t *testing.T) {
+	packageID := 1
+	cpuID := 2
+
+	// topology definition
+	topo := &topologyData{
+		topologyMap: map[int]*cpuInfo{
+			0: {
+				packageID: 0,
+			},
+			1: {
+				packageID: 0,
+			},
+			2: {
+				packageID: 1,
+			},
+			3: {
+				packageID: 1,
+			},
+		},
+		model: cpumodel.INTEL_FAM6_SAPPHIRERAPIDS_X,
+	}
+
+	t.Run("MsrIsNil", func(t *testing.T) {
+		pt := &PowerTelemetry{}
+
+		cfg, err := pt.GetPackageCStateConfig(packageID)
+		require.ErrorContains(t, err, "\"msr\" is not initialized")
+		require.Equal(t, CStateConfig{}, cfg)
+	})
+
+	t.Run("ModelNotSupported", func(t *testing.T) {
+		cpuModel := cpumodel.INTEL_FAM6_GRANITERAPIDS_X
+
+		m := &msrMock{}
+
+		pt := &PowerTelemetry{
+			topology: &topologyData{
+				model: cpuModel,
+			},
+			msr: m,
+		}
+
+		cfg, err := pt.GetPackageCStateConfig(packageID)
+		require.ErrorContains(t, err, fmt.Sprintf("package c-state configuration not supported by CPU model: 0x%X", cpuModel))
+		require.Equal(t, CStateConfig{}, cfg)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("CPUIDNotAvailable", func(t *testing.T) {
+		m := &msrMock{}
+
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+			cpus:     []int{0, 1},
+		}
+
+		cfg, err := pt.GetPackageCStateConfig(packageID)
+		require.ErrorContains(t, err, fmt.Sprintf("could not find CPU ID for package ID %v", packageID))
+		require.Equal(t, CStateConfig{}, cfg)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("FailedToReadPkgCStConfigControl", func(t *testing.T) {
+		mError := errors.New("mock error")
+
+		m := &msrMock{}
+		m.On("read", uint32(pkgCStConfigControl), cpuID).Return(uint64(0), mError).Once()
+
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+			cpus:     []int{0, 1, 2, 3},
+		}
+
+		cfg, err := pt.GetPackageCStateConfig(packageID)
+		require.ErrorContains(t, err, fmt.Sprintf("can't read MSR 0x%X", pkgCStConfigControl))
+		require.ErrorIs(t, err, mError)
+		require.Equal(t, CStateConfig{}, cfg)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("FailedToReadPowerCtl", func(t *testing.T) {
+		mError := errors.New("mock error")
+
+		m := &msrMock{}
+		m.On("read", uint32(pkgCStConfigControl), cpuID).Return(uint64(0x1E008402), nil).Once()
+		m.On("read", uint32(powerCtl), cpuID).Return(uint64(0), mError).Once()
+
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+			cpus:     []int{0, 1, 2, 3},
+		}
+
+		cfg, err := pt.GetPackageCStateConfig(packageID)
+		require.ErrorContains(t, err, fmt.Sprintf("can't read MSR 0x%X", powerCtl))
+		require.ErrorIs(t, err, mError)
+		require.Equal(t, CStateConfig{}, cfg)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			cstCtl   uint64
+			powCtl   uint64
+			expected CStateConfig
+		}{
+			{
+				name:   "AllDisabled",
+				cstCtl: 0x0,
+				powCtl: 0x0,
+				expected: CStateConfig{
+					PackageCStateLimit:     "pc0",
+					PackageCStateLimitCode: 0,
+				},
+			},
+			{
+				name:   "LockedPC6NoC1E",
+				cstCtl: 0x1C008402,
+				powCtl: 0x2904005D,
+				expected: CStateConfig{
+					PackageCStateLimit:     "pc6n",
+					PackageCStateLimitCode: 2,
+					Locked:                 true,
+					IOMwaitRedirection:     true,
+					C3AutoDemotion:         false,
+					C1AutoDemotion:         true,
+					C3AutoUndemotion:       true,
+					C1AutoUndemotion:       true,
+					C1E:                    false,
+				},
+			},
+			{
+				name:   "UnlimitedWithC1E",
+				cstCtl: 0x06000007,
+				powCtl: 0x2904005F,
+				expected: CStateConfig{
+					PackageCStateLimit:     "unlimited",
+					PackageCStateLimitCode: 7,
+					C3AutoDemotion:         true,
+					C1AutoDemotion:         true,
+					C1E:                    true,
+				},
+			},
+			{
+				name:   "Reserved",
+				cstCtl: 0x0000000F,
+				powCtl: 0x0,
+				expected: CStateConfig{
+					PackageCStateLimit:     "reserved",
+					PackageCStateLimitCode: 15,
+				},
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				m := &msrMock{}
+				m.On("read", uint32(pkgCStConfigControl), cpuID).Return(tc.cstCtl, nil).Once()
+				m.On("read", uint32(powerCtl), cpuID).Return(tc.powCtl, nil).Once()
+
+				pt := &PowerTelemetry{
+					topology: topo,
+					msr:      m,
+					cpus:     []int{0, 1, 2, 3},
+				}
+
+				cfg, err := pt.GetPackageCStateConfig(packageID)
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, cfg)
+				m.AssertExpectations(t)
+			})
+		}
+	})
+}
@@ -45,3 +45,99 @@ type MetricNotSupportedError struct {
 func (e *MetricNotSupportedError) Error() string {
 	return e.reason
 }
+
+// CPUUpdateError indicates that metrics of a CPU ID could not be updated.
+type CPUUpdateError struct {
+	CPUID int   // CPU ID whose metrics could not be updated
+	Err   error // reason of the failure
+}
+
+// Error returns a reason of this error.
+func (e *CPUUpdateError) Error() string {
+	return fmt.Sprintf("CPU ID %v: %v", e.CPUID, e.Err)
+}
+
+// Unwrap returns the reason of the failure.
+func (e *CPUUpdateError) Unwrap() error {
+	return e.Err
+}
+
+// UpdateError holds the per CPU ID errors of a whole-host metrics update, ordered by CPU ID.
+type UpdateError struct {
+	Errors []*CPUUpdateError // errors of CPU IDs whose metrics could not be updated
+	Total  int               // number of CPU IDs included in the update
+}
+
+// Error returns a reason of this error.
+func (e *UpdateError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("failed to update metrics for %d out of %d CPU IDs: %s", len(e.Errors), e.Total, strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the per CPU ID errors.
+func (e *UpdateError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// CounterResetError indicates that counters held by MSR offsets of a CPU ID were reset between the two latest
+// updates, e.g. after CPU offline/online. Values of the offsets are updated nonetheless, while their deltas
+// for the latest interval are zero.
+type CounterResetError struct {
+	CPUID   int      // CPU ID whose counters were reset
+	Offsets []uint32 // MSR offsets holding the reset counters, in ascending order
+}
+
+// Error returns a reason of this error.
+func (e *CounterResetError) Error() string {
+	offsets := make([]string, 0, len(e.Offsets))
+	for _, offset := range e.Offsets {
+		offsets = append(offsets, fmt.Sprintf("0x%X", offset))
+	}
+	return fmt.Sprintf("counter reset detected for CPU ID %v at MSR offsets: %s", e.CPUID, strings.Join(offsets, ", "))
+}
+
+// MsrWriteNotAllowedError indicates that a write operation to an MSR offset is not allowed.
+type MsrWriteNotAllowedError struct {
+	Offset uint32 // MSR offset of the rejected write operation
+	reason string
+}
+
+// Error returns a reason of this error.
+func (e *MsrWriteNotAllowedError) Error() string {
+	return fmt.Sprintf("write to MSR offset 0x%X is not allowed: %s", e.Offset, e.reason)
+}
+
+// CPUNotAvailableError indicates that a CPU ID is not available to the PowerTelemetry instance, e.g. because
+// it was excluded via WithExcludedCPUs option.
+type CPUNotAvailableError struct {
+	CPUID int // CPU ID which is not available
+}
+
+// Error returns a reason of this error.
+func (e *CPUNotAvailableError) Error() string {
+	return fmt.Sprintf("CPU ID %v is not available", e.CPUID)
+}
+
+// MsrReadError indicates that an MSR offset of a CPU ID could not be read.
+type MsrReadError struct {
+	CPUID  int    // CPU ID of the MSR
+	Offset uint32 // MSR offset which could not be read
+	Err    error  // reason of the failure
+}
+
+// Error returns a reason of this error.
+func (e *MsrReadError) Error() string {
+	return fmt.Sprintf("error reading MSR offset 0x%X for CPU ID %v: %v", e.Offset, e.CPUID, e.Err)
+}
+
+// Unwrap returns the reason of the failure.
+func (e *MsrReadError) Unwrap() error {
+	return e.Err
+}
@@ -10,6 +10,9 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -48,6 +51,70 @@ func readFileWithTimestamp(filePath string) ([]byte, time.Time, error) {
 	return fileContent, timestamp, nil
 }
 
+// writeFile writes the given content to an existing file at the given path.
+// If the file doesn't exist, it is a symlink or it can't be written, an error is returned.
+func writeFile(filePath string, content []byte) error {
+	// Check if the file exists and is not a symlink.
+	if err := checkFile(filePath); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filePath, os.O_WRONLY|os.O_TRUNC, 0)
+	if err != nil {
+		return fmt.Errorf("error opening file %q: %w", filePath, err)
+	}
+
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return fmt.Errorf("error while writing file %q: %w", filePath, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("error while closing file %q: %w", filePath, err)
+	}
+	return nil
+}
+
+// readSysfsString takes a directory path and a file name, and returns the file content
+// without trailing new line characters.
+func readSysfsString(dirPath, fileName string) (string, error) {
+	path := filepath.Join(dirPath, fileName)
+	content, err := readFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading file %q: %w", path, err)
+	}
+	return strings.TrimRight(string(content), "\n"), nil
+}
+
+// readSysfsUint takes a directory path and a file name, and returns the file content
+// converted to an unsigned integer.
+func readSysfsUint(dirPath, fileName string) (uint64, error) {
+	content, err := readSysfsString(dirPath, fileName)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := strconv.ParseUint(content, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error while converting value from file %q: %w", filepath.Join(dirPath, fileName), err)
+	}
+	return value, nil
+}
+
+// readSysfsInt takes a directory path and a file name, and returns the file content
+// converted to a signed integer.
+func readSysfsInt(dirPath, fileName string) (int64, error) {
+	content, err := readSysfsString(dirPath, fileName)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := strconv.ParseInt(content, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error while converting value from file %q: %w", filepath.Join(dirPath, fileName), err)
+	}
+	return value, nil
+}
+
 // checkFile is a helper function that returns nil if the given file path exists,
 // and it is not a symlink. Otherwise, it returns an error.
 func checkFile(path string) error {
@@ -0,0 +1,93 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package cpuid
+
+import (
+	"fmt"
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// ArchPerfmonFixedCounters returns the number of architectural fixed-function performance counters,
+// as reported by leaf 0xA of cpuid instruction. It returns zero if architectural performance monitoring
+// version 2 or later is not supported, since prior versions do not enumerate fixed-function counters.
+func ArchPerfmonFixedCounters() int {
+	maxLevel, _, _, _ := cpuid_count(0, 0)
+	if maxLevel < 0xA {
+		return 0
+	}
+	eax, _, _, edx := cpuid_count(0xA, 0)
+	if eax&0xFF < 2 {
+		return 0
+	}
+	return int(edx & 0x1F)
+}
+
+// ArchPerfmonGeneralCounters returns the number of general-purpose performance counters per logical processor,
+// as reported by leaf 0xA of cpuid instruction. It returns zero if architectural performance monitoring is not
+// supported.
+func ArchPerfmonGeneralCounters() int {
+	maxLevel, _, _, _ := cpuid_count(0, 0)
+	if maxLevel < 0xA {
+		return 0
+	}
+	eax, _, _, _ := cpuid_count(0xA, 0)
+	if eax&0xFF == 0 {
+		return 0
+	}
+	return int((eax >> 8) & 0xFF)
+}
+
+// ArchPerfmonCountersOnCPU takes a CPU ID and returns the number of general-purpose and architectural fixed-function
+// performance counters, as reported by leaf 0xA of cpuid instruction executed on that CPU. Unlike ArchPerfmonGeneralCounters
+// and ArchPerfmonFixedCounters, the result does not depend on the CPU the caller runs on, which matters for hybrid processors
+// whose core types have different numbers of counters.
+func ArchPerfmonCountersOnCPU(cpu int) (general, fixed int, err error) {
+	type result struct {
+		general, fixed int
+		err            error
+	}
+
+	ch := make(chan result, 1)
+	go func() {
+		// The thread is never unlocked, so it is terminated when the goroutine exits, instead of being
+		// reused by other goroutines with its affinity restricted to a single CPU.
+		runtime.LockOSThread()
+
+		var set unix.CPUSet
+		set.Set(cpu)
+		if err := unix.SchedSetaffinity(0, &set); err != nil {
+			ch <- result{err: fmt.Errorf("error setting affinity to CPU ID %v: %w", cpu, err)}
+			return
+		}
+		ch <- result{general: ArchPerfmonGeneralCounters(), fixed: ArchPerfmonFixedCounters()}
+	}()
+
+	res := <-ch
+	return res.general, res.fixed, res.err
+}
+
+// TSCFrequency returns the nominal frequency of the time stamp counter, in Hz. It is calculated from
+// the crystal clock frequency and TSC ratio reported by leaf 0x15 of cpuid instruction. If the crystal
+// clock frequency is not enumerated, the processor base frequency reported by leaf 0x16 is returned
+// instead. It returns zero if neither of them is available.
+func TSCFrequency() uint64 {
+	maxLevel, _, _, _ := cpuid_count(0, 0)
+	if maxLevel >= 0x15 {
+		denominator, numerator, crystalHz, _ := cpuid_count(0x15, 0)
+		if denominator != 0 && numerator != 0 && crystalHz != 0 {
+			return uint64(crystalHz) * uint64(numerator) / uint64(denominator)
+		}
+	}
+	if maxLevel >= 0x16 {
+		baseMhz, _, _, _ := cpuid_count(0x16, 0)
+		if baseMhz&0xFFFF != 0 {
+			return uint64(baseMhz&0xFFFF) * 1_000_000
+		}
+	}
+	return 0
+}
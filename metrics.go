@@ -71,6 +71,36 @@ func CheckIfCPUTemperatureSupported(cpuModel int) error {
 	return nil
 }
 
+// CheckIfPackagePPINSupported checks if package PPIN (Protected Processor Inventory Number) is supported by CPU model.
+// Returns MetricNotSupportedError if metric is not supported by the CPU model; otherwise, returns nil.
+func CheckIfPackagePPINSupported(cpuModel int) error {
+	if !isPPINSupported(cpuModel) {
+		return &MetricNotSupportedError{fmt.Sprintf("package PPIN metric not supported by CPU model: 0x%X", cpuModel)}
+	}
+
+	return nil
+}
+
+// CheckIfUncoreRatioLimitSupported checks if uncore frequency limits via MSR_UNCORE_RATIO_LIMIT are supported by CPU model.
+// Returns MetricNotSupportedError if metric is not supported by the CPU model; otherwise, returns nil.
+func CheckIfUncoreRatioLimitSupported(cpuModel int) error {
+	if !isUncoreRatioLimitSupported(cpuModel) {
+		return &MetricNotSupportedError{fmt.Sprintf("uncore ratio limit not supported by CPU model: 0x%X", cpuModel)}
+	}
+
+	return nil
+}
+
+// CheckIfPackageCStateConfigSupported checks if package C-state configuration decoding is supported by CPU model.
+// Returns MetricNotSupportedError if metric is not supported by the CPU model; otherwise, returns nil.
+func CheckIfPackageCStateConfigSupported(cpuModel int) error {
+	if getPkgCStateLimits(cpuModel) == nil {
+		return &MetricNotSupportedError{fmt.Sprintf("package c-state configuration not supported by CPU model: 0x%X", cpuModel)}
+	}
+
+	return nil
+}
+
 func isC1C6BaseTempSupported(cpuModel int) bool {
 	switch cpuModel {
 	case
@@ -224,3 +254,48 @@ func isC7Supported(cpuModel int) bool {
 	}
 	return false
 }
+
+func isPPINSupported(cpuModel int) bool {
+	switch cpuModel {
+	case
+		cpumodel.INTEL_FAM6_IVYBRIDGE_X,
+		cpumodel.INTEL_FAM6_HASWELL_X,
+		cpumodel.INTEL_FAM6_BROADWELL_X,
+		cpumodel.INTEL_FAM6_BROADWELL_D,
+		cpumodel.INTEL_FAM6_SKYLAKE_X,
+		cpumodel.INTEL_FAM6_ICELAKE_X,
+		cpumodel.INTEL_FAM6_ICELAKE_D,
+		cpumodel.INTEL_FAM6_SAPPHIRERAPIDS_X,
+		cpumodel.INTEL_FAM6_EMERALDRAPIDS_X,
+		cpumodel.INTEL_FAM6_GRANITERAPIDS_X,
+		cpumodel.INTEL_FAM6_GRANITERAPIDS_D,
+		cpumodel.INTEL_FAM6_ATOM_CRESTMONT_X,
+		cpumodel.INTEL_FAM6_XEON_PHI_KNL,
+		cpumodel.INTEL_FAM6_XEON_PHI_KNM:
+		return true
+	}
+	return false
+}
+
+func isUncoreRatioLimitSupported(cpuModel int) bool {
+	switch cpuModel {
+	case
+		cpumodel.INTEL_FAM6_BROADWELL_G,
+		cpumodel.INTEL_FAM6_BROADWELL_X,
+		cpumodel.INTEL_FAM6_BROADWELL_D,
+		cpumodel.INTEL_FAM6_SKYLAKE_X,
+		cpumodel.INTEL_FAM6_ICELAKE_X,
+		cpumodel.INTEL_FAM6_ICELAKE_D,
+		cpumodel.INTEL_FAM6_SAPPHIRERAPIDS_X,
+		cpumodel.INTEL_FAM6_EMERALDRAPIDS_X,
+		cpumodel.INTEL_FAM6_ALDERLAKE,
+		cpumodel.INTEL_FAM6_ALDERLAKE_L,
+		cpumodel.INTEL_FAM6_RAPTORLAKE,
+		cpumodel.INTEL_FAM6_RAPTORLAKE_P,
+		cpumodel.INTEL_FAM6_RAPTORLAKE_S,
+		cpumodel.INTEL_FAM6_METEORLAKE,
+		cpumodel.INTEL_FAM6_METEORLAKE_L:
+		return true
+	}
+	return false
+}
@@ -114,6 +114,40 @@ func TestCheckIfCPUTemperatureSupported(t *testing.T) {
 	}
 }
 
+func TestCheckIfPackagePPINSupported(t *testing.T) {
+	m := make(map[int]interface{})
+	for _, v := range ppinModels {
+		m[v] = struct{}{}
+	}
+
+	for model := 0; model < 0xFF; model++ {
+		err := CheckIfPackagePPINSupported(model)
+		if m[model] != nil {
+			require.NoError(t, err, "CPU model 0x%X should support package PPIN", model)
+		} else {
+			require.ErrorContains(t, err, fmt.Sprintf("package PPIN metric not supported by CPU model: 0x%X", model),
+				"CPU model 0x%X shouldn't support package PPIN", model)
+		}
+	}
+}
+
+func TestCheckIfUncoreRatioLimitSupported(t *testing.T) {
+	m := make(map[int]interface{})
+	for _, v := range uncoreRatioLimitModels {
+		m[v] = struct{}{}
+	}
+
+	for model := 0; model < 0xFF; model++ {
+		err := CheckIfUncoreRatioLimitSupported(model)
+		if m[model] != nil {
+			require.NoError(t, err, "CPU model 0x%X should support uncore ratio limit", model)
+		} else {
+			require.ErrorContains(t, err, fmt.Sprintf("uncore ratio limit not supported by CPU model: 0x%X", model),
+				"CPU model 0x%X shouldn't support uncore ratio limit", model)
+		}
+	}
+}
+
 var (
 	c1c6BaseTempModels = []int{
 		0x1E, // INTEL_FAM6_NEHALEM
@@ -253,4 +287,39 @@ var (
 		0x9C, // INTEL_FAM6_ATOM_TREMONT_L
 		0xBE, // INTEL_FAM6_ATOM_GRACEMONT
 	}
+
+	ppinModels = []int{
+		0x3E, // INTEL_FAM6_IVYBRIDGE_X
+		0x3F, // INTEL_FAM6_HASWELL_X
+		0x4F, // INTEL_FAM6_BROADWELL_X
+		0x56, // INTEL_FAM6_BROADWELL_D
+		0x55, // INTEL_FAM6_SKYLAKE_X
+		0x6A, // INTEL_FAM6_ICELAKE_X
+		0x6C, // INTEL_FAM6_ICELAKE_D
+		0x8F, // INTEL_FAM6_SAPPHIRERAPIDS_X
+		0xCF, // INTEL_FAM6_EMERALDRAPIDS_X
+		0xAD, // INTEL_FAM6_GRANITERAPIDS_X
+		0xAE, // INTEL_FAM6_GRANITERAPIDS_D
+		0xAF, // INTEL_FAM6_ATOM_CRESTMONT_X
+		0x57, // INTEL_FAM6_XEON_PHI_KNL
+		0x85, // INTEL_FAM6_XEON_PHI_KNM
+	}
+
+	uncoreRatioLimitModels = []int{
+		0x47, // INTEL_FAM6_BROADWELL_G
+		0x4F, // INTEL_FAM6_BROADWELL_X
+		0x56, // INTEL_FAM6_BROADWELL_D
+		0x55, // INTEL_FAM6_SKYLAKE_X
+		0x6A, // INTEL_FAM6_ICELAKE_X
+		0x6C, // INTEL_FAM6_ICELAKE_D
+		0x8F, // INTEL_FAM6_SAPPHIRERAPIDS_X
+		0xCF, // INTEL_FAM6_EMERALDRAPIDS_X
+		0x97, // INTEL_FAM6_ALDERLAKE
+		0x9A, // INTEL_FAM6_ALDERLAKE_L
+		0xB7, // INTEL_FAM6_RAPTORLAKE
+		0xBA, // INTEL_FAM6_RAPTORLAKE_P
+		0xBF, // INTEL_FAM6_RAPTORLAKE_S
+		0xAC, // INTEL_FAM6_METEORLAKE
+		0xAA, // INTEL_FAM6_METEORLAKE_L
+	}
 )
@@ -14,7 +14,9 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/intel/powertelemetry/internal/log"
@@ -51,13 +53,30 @@ type msrReg interface {
 	// readAll takes a slice of offsets and returns a map with offset key
 	// and content of the MSR offset value.
 	readAll(offsets []uint32) (map[uint32]uint64, error)
+
+	// write writes the given value to the MSR offset.
+	write(offset uint32, value uint64) error
+
+	// close releases the descriptor of the MSR file.
+	close() error
 }
 
 // msr represents a CPU ID specific MSR register. Implements msrReg interface.
+// The MSR file is opened once and its descriptor is kept for the lifetime of the register,
+// until it is released by close method.
 type msr struct {
 	path    string
 	cpuID   int
 	timeout time.Duration
+
+	mu       sync.Mutex
+	file     *os.File // persistent descriptor of the MSR file
+	writable bool     // true if the MSR file is opened for reading and writing, see WithMsrWrites
+	closed   bool     // true if the persistent descriptor has been released
+
+	// inflight bounds the number of pending timed reads of the MSR file to one. A read which
+	// exceeds the timeout keeps its slot until the underlying read returns.
+	inflight chan struct{}
 }
 
 // resultError is used for transmitting a value or an err through the channel.
@@ -67,8 +86,10 @@ type resultError struct {
 }
 
 // newMsr creates a new MSR register, initializing the CPU ID for this specific
-// register and the path where to find the MSR file.
-func newMsr(path string, timeout time.Duration) (msrReg, error) {
+// register and the path where to find the MSR file, given by the CPU ID path and
+// the MSR file name. If writable is true, the MSR file is opened for reading and
+// writing. Otherwise, it is opened read-only.
+func newMsr(path, fileName string, timeout time.Duration, writable bool) (msrReg, error) {
 	cpuIDStr := filepath.Base(path)
 	if !cpuIDRegex.MatchString(cpuIDStr) {
 		return nil, fmt.Errorf("invalid format for CPU ID in path %q", path)
@@ -77,15 +98,66 @@ func newMsr(path string, timeout time.Duration) (msrReg, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error converting parsed CPU ID from path to numeric")
 	}
-	cpuMsr := filepath.Join(path, msrFile)
+	cpuMsr := filepath.Join(path, fileName)
 	if err := checkFile(cpuMsr); err != nil {
 		return nil, fmt.Errorf("invalid MSR file for cpu ID %v: %w", cpuID, err)
 	}
-	return &msr{
-		path:    cpuMsr,
-		cpuID:   cpuID,
-		timeout: timeout,
-	}, nil
+
+	m := &msr{
+		path:     cpuMsr,
+		cpuID:    cpuID,
+		timeout:  timeout,
+		writable: writable,
+	}
+	// Open the MSR file upfront, since it is the operation which requires privileges.
+	if _, _, err := m.open(); err != nil {
+		return nil, fmt.Errorf("error opening MSR file for cpu ID %v: %w", cpuID, err)
+	}
+	return m, nil
+}
+
+// open returns the persistent descriptor of the receiver's MSR file, opening the file if it is not opened yet.
+// The file is opened for reading and writing only if the receiver is writable, otherwise it is opened read-only
+// and write operations fail. The returned flag is true if the descriptor allows write operations.
+func (m *msr) open() (*os.File, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return nil, false, fmt.Errorf("MSR file %q is closed", m.path)
+	}
+	if m.file != nil {
+		return m.file, m.writable, nil
+	}
+
+	flag := os.O_RDONLY
+	if m.writable {
+		flag = os.O_RDWR
+	}
+	f, err := os.OpenFile(m.path, flag, 0)
+	if err != nil {
+		return nil, false, err
+	}
+	m.file = f
+	return m.file, m.writable, nil
+}
+
+// close releases the persistent descriptor of the receiver's MSR file. Further read and write
+// operations of the receiver fail.
+func (m *msr) close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.closed = true
+	if m.file == nil {
+		return nil
+	}
+	err := m.file.Close()
+	m.file, m.writable = nil, false
+	if err != nil {
+		return fmt.Errorf("error closing MSR file %q: %w", m.path, err)
+	}
+	return nil
 }
 
 // getPath returns the MSR file path of the receiver.
@@ -101,95 +173,89 @@ func (m *msr) getCPUID() int {
 // read takes an address, specified as offset, and returns an 8-byte value with
 // the address content of the given CPU ID's MSR.
 func (m *msr) read(offset uint32) (uint64, error) {
-	f, err := os.OpenFile(m.path, os.O_RDONLY, 0400)
+	f, _, err := m.open()
 	if err != nil {
 		return 0, err
 	}
-	defer f.Close()
-	return readOffset(offset, f, m.timeout)
+	return m.readOffset(offset, f)
 }
 
 // readAll takes a slice of addresses, specified as offsets, and returns a map
 // with offset key and the offset content of the given CPU ID's MSR as value.
-// Each read offset operation is performed in a separate goroutine. In case an
-// error occurs, the function returns a nil map with the corresponding error.
+// Offsets are read sequentially. In case an error occurs, the function returns
+// a nil map with the corresponding error.
 func (m *msr) readAll(offsets []uint32) (map[uint32]uint64, error) {
-	f, err := os.OpenFile(m.path, os.O_RDONLY, 0400)
+	f, _, err := m.open()
 	if err != nil {
 		return nil, err
 	}
-	defer f.Close()
 
-	errCh := make(chan error)
-	msrOffsetChannels := make(map[uint32]chan uint64)
+	valuesMap := make(map[uint32]uint64, len(offsets))
 	for _, offset := range offsets {
-		msrOffsetChannels[offset] = make(chan uint64)
-	}
-
-	for offset, ch := range msrOffsetChannels {
-		go func(off uint32, ch chan uint64, errCh chan error) {
-			v, err := readOffset(off, f, m.timeout)
-			if err != nil {
-				errCh <- err
-				return
-			}
-			ch <- v
-		}(offset, ch, errCh)
-	}
-
-	valuesMap := make(map[uint32]uint64)
-	for offset, ch := range msrOffsetChannels {
-		select {
-		case err := <-errCh:
+		v, err := m.readOffset(offset, f)
+		if err != nil {
 			return nil, fmt.Errorf("error reading MSR offsets: %w", err)
-		case v := <-ch:
-			valuesMap[offset] = v
 		}
+		valuesMap[offset] = v
 	}
 	return valuesMap, nil
 }
 
-// readOffset is a helper function that takes an address, specified as offset, an io.ReaderAt interface, and timeout.
-// It returns an 8-byte value with the address content of the given reader argument.
-func readOffset(offset uint32, reader io.ReaderAt, timeout time.Duration) (uint64, error) {
+// readOffset takes an address, specified as offset, and an io.ReaderAt interface, and returns an 8-byte value
+// with the address content of the given reader argument. If the receiver has a timeout, the read is performed
+// in a separate goroutine. A goroutine whose read exceeds the timeout keeps running until the underlying read
+// returns. Meanwhile, further reads of the receiver wait for it within their own timeout, so at most one
+// goroutine per MSR file outlives a timed out read.
+func (m *msr) readOffset(offset uint32, reader io.ReaderAt) (uint64, error) {
 	// read without timeout
-	if timeout <= 0 {
+	if m.timeout <= 0 {
 		return readOffsetWithoutTimeout(offset, reader)
 	}
 
 	// read with timeout
-	resultCh := make(chan resultError, 1)
-	go func(resCh chan<- resultError) {
-		buf := make([]byte, 8)
-		if _, err := reader.ReadAt(buf, int64(offset)); err != nil {
-			if errors.Is(err, io.EOF) {
-				resCh <- resultError{value: 0, err: fmt.Errorf("offset 0x%x is out-of-bounds", offset)}
-			} else {
-				resCh <- resultError{value: 0, err: fmt.Errorf("error when reading file at offset 0x%x: %w", offset, err)}
-			}
-		} else {
-			resCh <- resultError{value: binary.LittleEndian.Uint64(buf), err: nil}
+	t := time.NewTimer(m.timeout)
+	defer t.Stop()
+
+	slot := m.readSlot()
+	select {
+	case slot <- struct{}{}:
+	default:
+		select {
+		case slot <- struct{}{}:
+		case <-t.C:
+			return 0, fmt.Errorf("timeout when reading file at offset 0x%x", offset)
 		}
+	}
 
-		close(resCh)
-	}(resultCh)
+	resultCh := make(chan resultError, 1)
+	go func() {
+		defer func() { <-slot }()
+		v, err := readOffsetWithoutTimeout(offset, reader)
+		resultCh <- resultError{value: v, err: err}
+	}()
 
-	t := time.NewTimer(timeout)
 	select {
 	case <-t.C:
 		return 0, fmt.Errorf("timeout when reading file at offset 0x%x", offset)
 	case result := <-resultCh:
-		if !t.Stop() {
-			<-t.C
-		}
 		if result.err != nil {
 			return 0, result.err
 		}
-
 		return result.value, nil
 	}
 }
 
+// readSlot returns the channel which bounds pending timed reads of the receiver, creating it if needed.
+func (m *msr) readSlot() chan struct{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.inflight == nil {
+		m.inflight = make(chan struct{}, 1)
+	}
+	return m.inflight
+}
+
 // readOffsetWithoutTimeout is a helper function that takes an address, specified as offset and an io.ReaderAt interface.
 // It returns an 8-byte value with the address content of the given reader argument.
 func readOffsetWithoutTimeout(offset uint32, reader io.ReaderAt) (uint64, error) {
@@ -204,6 +270,26 @@ func readOffsetWithoutTimeout(offset uint32, reader io.ReaderAt) (uint64, error)
 	return binary.LittleEndian.Uint64(buf), nil
 }
 
+// write takes an address, specified as offset, and an 8-byte value, and writes the value
+// to the address of the given CPU ID's MSR. It fails if the persistent descriptor of the MSR
+// file is read-only, since write operations are not enabled.
+func (m *msr) write(offset uint32, value uint64) error {
+	f, writable, err := m.open()
+	if err != nil {
+		return err
+	}
+	if !writable {
+		return fmt.Errorf("MSR file %q is opened read-only, write operations are not permitted", m.path)
+	}
+
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, value)
+	if _, err := f.WriteAt(buf, int64(offset)); err != nil {
+		return fmt.Errorf("error when writing file at offset 0x%x: %w", offset, err)
+	}
+	return nil
+}
+
 // msrRegWithStorage represents a CPU ID specific MSR register with the ability to read and
 // store offset values. Two types of stored offset values are supported:
 //   - offset values from the last read operation.
@@ -215,6 +301,12 @@ type msrRegWithStorage interface {
 	// getOffsetValues gets a map with offset key and offset value of the latest read operation.
 	getOffsetValues() map[uint32]uint64
 
+	// getPrevOffsetValues gets a map with offset key and offset value of the read operation previous to the latest.
+	getPrevOffsetValues() map[uint32]uint64
+
+	// getOffsetResets gets a set of offsets whose counters were reset between the latest and the previous read operation.
+	getOffsetResets() map[uint32]bool
+
 	// getOffsetDeltas gets a map with offset key and delta offset value between the latest and
 	// the previous read operation.
 	getOffsetDeltas() map[uint32]uint64
@@ -228,31 +320,40 @@ type msrRegWithStorage interface {
 
 	// update gets MSR values and updates the storage.
 	update() error
+
+	// store takes a map with offset key and offset value read by the caller, and updates the storage.
+	store(latest map[uint32]uint64)
 }
 
 // msrWithStorage represents a CPU ID specific MSR register with the ability to read and
 // store offset values. Implements msrRegWithStorage interface.
 // The offset values in the storage correspond to values for offsets specified in offsets
-// field.
+// field. Offsets are considered to hold 64-bit counters, unless a different width is
+// specified in widths field. A zero width means that the offset does not hold a counter,
+// hence its deltas are not checked for counter resets.
 type msrWithStorage struct {
 	msrReg
-	offsets        []uint32
-	offsetValues   map[uint32]uint64 // offset values from the last read operation
-	offsetDeltas   map[uint32]uint64 // delta offset values between the latest and its previous reading operation
-	timestamp      time.Time         // timestamp of the last reading operation
-	timestampDelta time.Duration     // timestamp delta between the last read and its previous reading operation
+	offsets          []uint32
+	widths           map[uint32]uint   // width in bits of the counters held by offsets
+	offsetResets     map[uint32]bool   // offsets whose counters were reset between the latest and its previous reading operation
+	offsetValues     map[uint32]uint64 // offset values from the last read operation
+	prevOffsetValues map[uint32]uint64 // offset values from the read operation previous to the last one
+	offsetDeltas     map[uint32]uint64 // delta offset values between the latest and its previous reading operation
+	timestamp        time.Time         // timestamp of the last reading operation
+	timestampDelta   time.Duration     // timestamp delta between the last read and its previous reading operation
 }
 
 // newMsrWithStorage creates a new MSR register with the ability to read and store multiple MSR
-// offset values, provided as argument. First creates an MSR register, then decorates it adding
-// storage for both offset values from the last read operation and delta offset values between
-// the latest and its previous reading operation.
-func newMsrWithStorage(path string, offsets []uint32, timeout time.Duration) (msrRegWithStorage, error) {
+// offset values, provided as argument, together with the width in bits of the counters held by
+// offsets, if different from 64. First creates an MSR register, then decorates it adding storage
+// for both offset values from the last read operation and delta offset values between the latest
+// and its previous reading operation. If writable is true, the MSR file is opened for writing as well.
+func newMsrWithStorage(path, fileName string, offsets []uint32, widths map[uint32]uint, timeout time.Duration, writable bool) (msrRegWithStorage, error) {
 	if len(offsets) == 0 {
 		return nil, errors.New("no offsets were provided")
 	}
 
-	msr, err := newMsr(path, timeout)
+	msr, err := newMsr(path, fileName, timeout, writable)
 	if err != nil {
 		return nil, fmt.Errorf("error creating MSR register for CPU path %q: %w", path, err)
 	}
@@ -260,6 +361,7 @@ func newMsrWithStorage(path string, offsets []uint32, timeout time.Duration) (ms
 	return &msrWithStorage{
 		msrReg:       msr,
 		offsets:      offsets,
+		widths:       widths,
 		offsetValues: make(map[uint32]uint64),
 		offsetDeltas: make(map[uint32]uint64),
 	}, nil
@@ -271,6 +373,18 @@ func (m *msrWithStorage) getOffsetValues() map[uint32]uint64 {
 	return m.offsetValues
 }
 
+// getPrevOffsetValues returns a map with offset key and offset values from the read operation previous
+// to the last one of the receiver.
+func (m *msrWithStorage) getPrevOffsetValues() map[uint32]uint64 {
+	return m.prevOffsetValues
+}
+
+// getOffsetResets returns a set of offsets whose counters were reset between the last read operation
+// and its previous reading operation of the receiver.
+func (m *msrWithStorage) getOffsetResets() map[uint32]bool {
+	return m.offsetResets
+}
+
 // setOffsetValues sets the given map of offset key and offset values to the receiver.
 func (m *msrWithStorage) setOffsetValues(offsetValues map[uint32]uint64) {
 	m.offsetValues = offsetValues
@@ -294,25 +408,50 @@ func (m *msrWithStorage) update() error {
 	if err != nil {
 		return err
 	}
+	m.store(latest)
+	return nil
+}
 
+// store takes a map with offset key and offset values of the latest read operation. It updates
+// last read offset values and delta offset values of the receiver. Deltas of counters are computed
+// modulo the counter width, so that a wraparound of the counter is accounted for. If a counter
+// was reset, its delta is zero and its offset is recorded as reset.
+func (m *msrWithStorage) store(latest map[uint32]uint64) {
 	// Get time interval between offset MSR read and its previous reading operation
 	newTimestamp := timeNowFn()
 	m.timestampDelta, m.timestamp = newTimestamp.Sub(m.timestamp), newTimestamp
 
 	prev := m.getOffsetValues()
 	deltasMap := make(map[uint32]uint64, len(latest))
-	for offset := range latest {
-		if latest[offset] < prev[offset] {
-			deltasMap[offset] = 0
-			log.Warnf("A negative delta for the offset 0x%X and CPU ID %v", offset, m.msrReg.getCPUID())
-		} else {
-			deltasMap[offset] = latest[offset] - prev[offset]
+	resets := make(map[uint32]bool)
+	for offset, value := range latest {
+		prevValue, ok := prev[offset]
+		if !ok {
+			deltasMap[offset] = value
+			continue
+		}
+
+		width, ok := m.widths[offset]
+		if !ok {
+			width = 64
+		}
+		if width == 0 {
+			deltasMap[offset] = value - prevValue
+			continue
 		}
+
+		delta, reset := counterDelta(prevValue, value, width)
+		if reset {
+			resets[offset] = true
+			log.Warnf("A counter reset for the offset 0x%X and CPU ID %v", offset, m.msrReg.getCPUID())
+		}
+		deltasMap[offset] = delta
 	}
 
+	m.offsetResets = resets
 	m.setOffsetDeltas(deltasMap)
+	m.prevOffsetValues = prev
 	m.setOffsetValues(latest)
-	return nil
 }
 
 // getTimestampDelta returns the timestamp delta between the offset values last reading operations
@@ -328,15 +467,35 @@ type msrReaderWithStorage interface {
 	// initMsrMap initializes a map of CPU ID key and MSR register value with storage.
 	initMsrMap(cpuIDs []int, timeout time.Duration) error
 
+	// initCounters takes a slice of user-defined MSR counters and adds their offsets to the offsets read
+	// by the storage. It must be called prior to initMsrMap.
+	initCounters(counters []MsrCounter) error
+
+	// enableWrites enables write operations, opening MSR files for reading and writing. It must be called
+	// prior to initMsrMap.
+	enableWrites()
+
 	// isMsrLoaded check if MSR kernel module is loaded.
 	isMsrLoaded(modulesPath string) (bool, error)
 
 	// read returns the MSR value for a given offset and CPU ID.
 	read(offset uint32, cpuID int) (uint64, error)
 
+	// write writes the given value to the MSR offset of a given CPU ID.
+	write(offset uint32, cpuID int, value uint64) error
+
 	// update takes a CPU ID, reads multiple MSR offset values and updates the storage.
 	update(cpuID int) error
 
+	// isBatchAvailable returns true if multiple MSR offset values of all CPU IDs can be read with a single
+	// batch operation.
+	isBatchAvailable() bool
+
+	// updateAll reads multiple MSR offset values of all CPU IDs with a single batch operation and updates
+	// the storage. It returns a map of CPU ID key and the error of its update, for CPU IDs which could not
+	// be updated.
+	updateAll() map[int]error
+
 	// getOffsetDeltas takes a CPU ID and returns MSR delta offset values between latest and its previous reading operation.
 	getOffsetDeltas(cpuID int) (map[uint32]uint64, error)
 
@@ -347,6 +506,13 @@ type msrReaderWithStorage interface {
 	// getTimestampDelta takes a CPU ID and returns the time interval between the last offset value reading operation
 	// and its previous reading operation.
 	getTimestampDelta(cpuID int) (time.Duration, error)
+
+	// getCounterDelta takes a CPU ID and the name of a user-defined MSR counter, and returns the counter delta
+	// between the last offset value reading operation and its previous reading operation.
+	getCounterDelta(cpuID int, name string) (uint64, error)
+
+	// close releases the descriptors of the MSR files of all CPU IDs.
+	close() error
 }
 
 // msrDataWithStorage represents per-CPU ID MSR registers of the host with offset values storage capabilities.
@@ -365,11 +531,25 @@ type msrReaderWithStorage interface {
 //
 // Each map entry corresponds to an MSR register, which allows reading operations
 // to specific addresses (offsets), as well as storage of the offset values read.
+//
+// If msr_safe driver is used, per-CPU ID files are named msr_safe, offsets are checked
+// against the driver's allowlist, and offsets are read via the driver's batch device if available.
 type msrDataWithStorage struct {
 	msrPath    string
 	msrOffsets []uint32
 
-	msrMap map[int]msrRegWithStorage
+	msrSafe       bool
+	allowlistPath string
+	batchPath     string
+
+	msrMap    map[int]msrRegWithStorage
+	allowlist map[uint32]uint64 // msr_safe allowed offset key and write mask value
+	batch     msrBatchReader
+
+	counters     map[string]MsrCounter // user-defined MSR counters by name
+	offsetWidths map[uint32]uint       // width in bits of the counters held by offsets, if different from 64
+
+	writable bool // true if MSR files are opened for reading and writing
 }
 
 // initMsrMap initializes a map of CPU ID key and MSR register value with storage. Each MSR register is able to update
@@ -389,6 +569,14 @@ func (m *msrDataWithStorage) initMsrMap(cpuIDs []int, timeout time.Duration) err
 		return fmt.Errorf("invalid MSR base path %q: %w", m.msrPath, err)
 	}
 
+	fileName := msrFile
+	if m.msrSafe {
+		fileName = msrSafeFile
+		if err := m.initMsrSafe(); err != nil {
+			return err
+		}
+	}
+
 	cpuDirs, err := os.ReadDir(m.msrPath)
 	if err != nil {
 		return fmt.Errorf("error reading directory %q: %w", m.msrPath, err)
@@ -403,6 +591,16 @@ func (m *msrDataWithStorage) initMsrMap(cpuIDs []int, timeout time.Duration) err
 	isFilterEmpty := len(filterCPUIDsMap) == 0
 
 	msrMap := make(map[int]msrRegWithStorage)
+	// release descriptors of MSR files opened so far if initialization fails.
+	closeAll := func() {
+		for _, reg := range msrMap {
+			_ = reg.close()
+		}
+		if m.batch != nil {
+			_ = m.batch.close()
+			m.batch = nil
+		}
+	}
 	for _, cpuDirEntry := range cpuDirs {
 		cpuDir := cpuDirEntry.Name()
 		if !cpuDirEntry.IsDir() || !cpuIDRegex.MatchString(cpuDir) {
@@ -416,19 +614,23 @@ func (m *msrDataWithStorage) initMsrMap(cpuIDs []int, timeout time.Duration) err
 		}
 
 		cpuPath := filepath.Join(m.msrPath, cpuDir)
-		cpuMsrWithStorage, err := newMsrWithStorage(cpuPath, m.msrOffsets, timeout)
+		cpuMsrWithStorage, err := newMsrWithStorage(cpuPath, fileName, m.msrOffsets, m.offsetWidths, timeout, m.writable)
 		if err != nil {
+			closeAll()
 			return fmt.Errorf("error creating MSR register with storage for CPU path %q: %w", cpuPath, err)
 		}
 
 		err = cpuMsrWithStorage.update()
 		if err != nil {
+			_ = cpuMsrWithStorage.close()
+			closeAll()
 			return fmt.Errorf("error initializing the MSR register storage for CPU ID %v: %w", cpuMsrWithStorage.getCPUID(), err)
 		}
 		msrMap[cpuMsrWithStorage.getCPUID()] = cpuMsrWithStorage
 	}
 
 	if len(msrMap) == 0 {
+		closeAll()
 		return fmt.Errorf("could not find valid CPU MSR files for path: %q", m.msrPath)
 	}
 
@@ -436,6 +638,102 @@ func (m *msrDataWithStorage) initMsrMap(cpuIDs []int, timeout time.Duration) err
 	return nil
 }
 
+// initCounters takes a slice of user-defined MSR counters, validates them and adds their offsets to the offsets
+// read by the receiver's storage. Counters which span the whole offset value set the counter width of their
+// offsets. Offsets added only for counters which span part of the offset value are not checked for counter
+// resets by the storage, since the offset value as a whole is not a counter.
+func (m *msrDataWithStorage) initCounters(counters []MsrCounter) error {
+	if err := validateMsrCounters(counters); err != nil {
+		return err
+	}
+
+	offsets := slices.Clone(m.msrOffsets)
+	widths := make(map[uint32]uint)
+	countersMap := make(map[string]MsrCounter, len(counters))
+	for _, c := range counters {
+		if c.Mask == 0 && c.Shift == 0 {
+			widths[c.Offset] = c.width()
+		}
+		countersMap[c.Name] = c
+	}
+	for _, c := range counters {
+		if slices.Contains(offsets, c.Offset) {
+			continue
+		}
+		offsets = append(offsets, c.Offset)
+		if _, ok := widths[c.Offset]; !ok {
+			widths[c.Offset] = 0
+		}
+	}
+
+	m.msrOffsets = offsets
+	m.counters = countersMap
+	m.offsetWidths = widths
+	return nil
+}
+
+// enableWrites enables write operations of the receiver, so MSR files are opened for reading and writing by
+// initMsrMap. Otherwise, MSR files are opened read-only.
+func (m *msrDataWithStorage) enableWrites() {
+	m.writable = true
+}
+
+// initMsrSafe reads the allowlist of msr_safe driver and checks that all offsets of the receiver are allowed.
+// It opens the batch device of the driver if available, otherwise offsets are read from per-CPU ID files.
+func (m *msrDataWithStorage) initMsrSafe() error {
+	allowlist, err := readMsrAllowlist(m.allowlistPath)
+	if err != nil {
+		return err
+	}
+	for _, offset := range m.msrOffsets {
+		if _, ok := allowlist[offset]; !ok {
+			return fmt.Errorf("MSR offset 0x%X is not allowed by msr_safe allowlist %q", offset, m.allowlistPath)
+		}
+	}
+	m.allowlist = allowlist
+
+	batch, err := newMsrBatch(m.batchPath)
+	if err != nil {
+		log.Warnf("Batch operations of msr_safe driver are not available, offsets will be read per CPU ID: %v", err)
+		return nil
+	}
+	m.batch = batch
+	return nil
+}
+
+// close releases the descriptors of the MSR files of all CPU IDs, and the descriptor of msr_safe batch
+// device if opened. An error is returned if one or more descriptors could not be released.
+func (m *msrDataWithStorage) close() error {
+	var errs []error
+	for _, reg := range m.msrMap {
+		if err := reg.close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if m.batch != nil {
+		if err := m.batch.close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkAllowed takes an offset and returns an error if msr_safe driver is used and the offset is not
+// in its allowlist. If write is true, the offset must be writable as well.
+func (m *msrDataWithStorage) checkAllowed(offset uint32, write bool) error {
+	if !m.msrSafe {
+		return nil
+	}
+	mask, ok := m.allowlist[offset]
+	if !ok {
+		return fmt.Errorf("MSR offset 0x%X is not allowed by msr_safe allowlist", offset)
+	}
+	if write && mask == 0 {
+		return fmt.Errorf("MSR offset 0x%X is not writable according to msr_safe allowlist", offset)
+	}
+	return nil
+}
+
 // isMsrLoaded returns true if MSR kernel module is loaded, otherwise returns false.
 func (m *msrDataWithStorage) isMsrLoaded(modulesPath string) (bool, error) {
 	if err := checkFile(modulesPath); err != nil {
@@ -456,17 +754,107 @@ func (m *msrDataWithStorage) read(offset uint32, cpuID int) (uint64, error) {
 	if !ok {
 		return 0, fmt.Errorf("could not find MSR register for CPU ID: %v", cpuID)
 	}
+	if err := m.checkAllowed(offset, false); err != nil {
+		return 0, err
+	}
 	return reg.read(offset)
 }
 
+// write takes an offset, a CPU ID and an 8-byte value, and writes the value to the given offset
+// of the associated MSR register.
+func (m *msrDataWithStorage) write(offset uint32, cpuID int, value uint64) error {
+	reg, ok := m.msrMap[cpuID]
+	if !ok {
+		return fmt.Errorf("could not find MSR register for CPU ID: %v", cpuID)
+	}
+	if err := m.checkAllowed(offset, true); err != nil {
+		return err
+	}
+	return reg.write(offset, value)
+}
+
 // update takes a CPU ID, performs reading operations along the offsets, storing the results
-// within the storage.
+// within the storage. If msr_safe batch device is available, all offsets are read with a single
+// batch operation.
 func (m *msrDataWithStorage) update(cpuID int) error {
 	reg, ok := m.msrMap[cpuID]
 	if !ok {
 		return fmt.Errorf("could not find MSR register for CPU ID: %v", cpuID)
 	}
-	return reg.update()
+	if m.batch == nil {
+		if err := reg.update(); err != nil {
+			return err
+		}
+		return checkCounterResets(cpuID, reg)
+	}
+
+	values, err := m.batch.readBatch([]int{cpuID}, m.msrOffsets)
+	if err != nil {
+		return err
+	}
+	reg.store(values[cpuID])
+	return checkCounterResets(cpuID, reg)
+}
+
+// isBatchAvailable returns true if msr_safe batch device is available, so offsets of all CPU IDs can be
+// read with a single batch operation.
+func (m *msrDataWithStorage) isBatchAvailable() bool {
+	return m.batch != nil
+}
+
+// updateAll reads the offsets of all CPU IDs with a single msr_safe batch operation, storing the results
+// within the storage. The failure of the batch operation is reported for every CPU ID. It returns a map of
+// CPU ID key and the error of its update, for CPU IDs which could not be updated or whose counters were
+// reset since the previous update, see *CounterResetError. If msr_safe batch device is not available,
+// no CPU ID is updated and the error is reported for every CPU ID.
+func (m *msrDataWithStorage) updateAll() map[int]error {
+	cpuIDs := make([]int, 0, len(m.msrMap))
+	for cpuID := range m.msrMap {
+		cpuIDs = append(cpuIDs, cpuID)
+	}
+	slices.Sort(cpuIDs)
+
+	errs := make(map[int]error)
+	if m.batch == nil {
+		err := errors.New("msr_safe batch device is not available")
+		for _, cpuID := range cpuIDs {
+			errs[cpuID] = err
+		}
+		return errs
+	}
+
+	values, err := m.batch.readBatch(cpuIDs, m.msrOffsets)
+	if err != nil {
+		for _, cpuID := range cpuIDs {
+			errs[cpuID] = err
+		}
+		return errs
+	}
+	for _, cpuID := range cpuIDs {
+		m.msrMap[cpuID].store(values[cpuID])
+		if err := checkCounterResets(cpuID, m.msrMap[cpuID]); err != nil {
+			errs[cpuID] = err
+		}
+	}
+	return errs
+}
+
+// checkCounterResets takes a CPU ID and its MSR register with storage, and returns a *CounterResetError if
+// counters of any offset were reset between the latest and its previous reading operation.
+func checkCounterResets(cpuID int, reg msrRegWithStorage) error {
+	resets := reg.getOffsetResets()
+	if len(resets) == 0 {
+		return nil
+	}
+	offsets := make([]uint32, 0, len(resets))
+	for offset := range resets {
+		offsets = append(offsets, offset)
+	}
+	slices.Sort(offsets)
+	return &CounterResetError{
+		CPUID:   cpuID,
+		Offsets: offsets,
+	}
 }
 
 // getOffsetDeltas takes a CPU ID and returns a map with offset keys and delta offset values between
@@ -510,3 +898,34 @@ func (m *msrDataWithStorage) getTimestampDelta(cpuID int) (time.Duration, error)
 	}
 	return reg.getTimestampDelta(), nil
 }
+
+// getCounterDelta takes a CPU ID and the name of a user-defined MSR counter. It returns the counter delta between
+// the last offset value reading operation and its previous reading operation, accounting for a wraparound of the
+// counter according to its width. If the counter was reset, a *CounterResetError is returned.
+func (m *msrDataWithStorage) getCounterDelta(cpuID int, name string) (uint64, error) {
+	c, ok := m.counters[name]
+	if !ok {
+		return 0, fmt.Errorf("could not find MSR counter %q", name)
+	}
+	reg, ok := m.msrMap[cpuID]
+	if !ok {
+		return 0, fmt.Errorf("could not find MSR register for CPU ID: %v", cpuID)
+	}
+
+	prev, ok := reg.getPrevOffsetValues()[c.Offset]
+	if !ok {
+		return 0, fmt.Errorf("MSR counter %q needs at least two readings for CPU ID: %v", name, cpuID)
+	}
+	latest, ok := reg.getOffsetValues()[c.Offset]
+	if !ok {
+		return 0, fmt.Errorf("could not find value of MSR counter %q for CPU ID: %v", name, cpuID)
+	}
+	delta, reset := counterDelta(c.value(prev), c.value(latest), c.width())
+	if reset {
+		return 0, &CounterResetError{
+			CPUID:   cpuID,
+			Offsets: []uint32{c.Offset},
+		}
+	}
+	return delta, nil
+}
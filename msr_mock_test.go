@@ -40,6 +40,16 @@ func (m *msrRegMock) readAll(offsets []uint32) (map[uint32]uint64, error) {
 	return args.Get(0).(map[uint32]uint64), args.Error(1)
 }
 
+func (m *msrRegMock) write(offset uint32, value uint64) error {
+	args := m.Called(offset, value)
+	return args.Error(0)
+}
+
+func (m *msrRegMock) close() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
 // msrMock represents a mock for msrDataWithStorage type. Implements msrReaderWithStorage interface.
 type msrMock struct {
 	mock.Mock
@@ -55,6 +65,11 @@ func (m *msrMock) read(offset uint32, cpuID int) (uint64, error) {
 	return args.Get(0).(uint64), args.Error(1)
 }
 
+func (m *msrMock) write(offset uint32, cpuID int, value uint64) error {
+	args := m.Called(offset, cpuID, value)
+	return args.Error(0)
+}
+
 func (m *msrMock) isMsrLoaded(modulesPath string) (bool, error) {
 	args := m.Called(modulesPath)
 	return args.Bool(0), args.Error(1)
@@ -82,3 +97,35 @@ func (m *msrMock) getTimestampDelta(cpuID int) (time.Duration, error) {
 	args := m.Called(cpuID)
 	return args.Get(0).(time.Duration), args.Error(1)
 }
+
+func (m *msrMock) close() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *msrMock) isBatchAvailable() bool {
+	args := m.Called()
+	return args.Bool(0)
+}
+
+func (m *msrMock) updateAll() map[int]error {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(map[int]error)
+}
+
+func (m *msrMock) enableWrites() {
+	m.Called()
+}
+
+func (m *msrMock) initCounters(counters []MsrCounter) error {
+	args := m.Called(counters)
+	return args.Error(0)
+}
+
+func (m *msrMock) getCounterDelta(cpuID int, name string) (uint64, error) {
+	args := m.Called(cpuID, name)
+	return args.Get(0).(uint64), args.Error(1)
+}
@@ -10,7 +10,9 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -53,15 +55,19 @@ func TestNewMsr(t *testing.T) {
 			name: "Valid",
 			path: "testdata/cpu-msr/0",
 			msr: &msr{
-				path:  "testdata/cpu-msr/0/msr",
-				cpuID: 0,
+				path:   "testdata/cpu-msr/0/msr",
+				cpuID:  0,
+				closed: true,
 			},
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			m, err := newMsr(tc.path, 0)
+			m, err := newMsr(tc.path, msrFile, 0, false)
+			if m != nil {
+				require.NoError(t, m.close())
+			}
 			require.Equal(t, tc.msr, m)
 			if tc.err != nil {
 				require.ErrorContains(t, err, tc.err.Error())
@@ -75,7 +81,7 @@ func TestNewMsr(t *testing.T) {
 func TestMsrGetters(t *testing.T) {
 	cpuID := 0
 	cpuPath := "testdata/cpu-msr/0"
-	m, err := newMsr(cpuPath, 0)
+	m, err := newMsr(cpuPath, msrFile, 0, false)
 	require.NoError(t, err)
 	require.Equal(t, filepath.Join(cpuPath, msrFile), m.getPath())
 	require.Equal(t, cpuID, m.getCPUID())
@@ -233,6 +239,132 @@ func TestMsrRead(t *testing.T) {
 	}
 }
 
+func TestMsrWrite(t *testing.T) {
+	t.Run("MsrFileNotExists", func(t *testing.T) {
+		m := &msr{
+			path: "testdata/cpu-msr-cpuID-msr-not-exist/0/msr",
+		}
+
+		err := m.write(0x0, 0x1)
+		require.ErrorContains(t, err, "open testdata/cpu-msr-cpuID-msr-not-exist/0/msr: no such file or directory")
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), msrFile)
+		require.NoError(t, os.WriteFile(path, make([]byte, 16), 0600))
+
+		m := &msr{
+			path:     path,
+			writable: true,
+		}
+
+		require.NoError(t, m.write(0x8, 0x1032547698badcfe))
+
+		out, err := m.read(0x8)
+		require.NoError(t, err)
+		require.Equal(t, uint64(0x1032547698badcfe), out)
+
+		out, err = m.read(0x0)
+		require.NoError(t, err)
+		require.Equal(t, uint64(0), out)
+	})
+}
+
+func TestMsrClose(t *testing.T) {
+	t.Run("NotOpened", func(t *testing.T) {
+		m := &msr{
+			path: "testdata/cpu-msr/0/msr",
+		}
+
+		require.NoError(t, m.close())
+		require.True(t, m.closed)
+	})
+
+	t.Run("PersistentDescriptor", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), msrFile)
+		require.NoError(t, os.WriteFile(path, make([]byte, 16), 0600))
+
+		m := &msr{
+			path:     path,
+			writable: true,
+		}
+
+		require.NoError(t, m.write(0x0, 0x1))
+		f := m.file
+		require.NotNil(t, f)
+		require.True(t, m.writable)
+
+		_, err := m.read(0x0)
+		require.NoError(t, err)
+		_, err = m.readAll([]uint32{0x0, 0x8})
+		require.NoError(t, err)
+		require.Same(t, f, m.file)
+
+		// descriptor is kept even if the file is removed
+		require.NoError(t, os.Remove(path))
+		out, err := m.read(0x0)
+		require.NoError(t, err)
+		require.Equal(t, uint64(0x1), out)
+
+		require.NoError(t, m.close())
+		require.Nil(t, m.file)
+		require.False(t, m.writable)
+
+		_, err = m.read(0x0)
+		require.ErrorContains(t, err, fmt.Sprintf("MSR file %q is closed", path))
+
+		_, err = m.readAll([]uint32{0x0})
+		require.ErrorContains(t, err, fmt.Sprintf("MSR file %q is closed", path))
+
+		err = m.write(0x0, 0x1)
+		require.ErrorContains(t, err, fmt.Sprintf("MSR file %q is closed", path))
+
+		// closing an already closed register is a no-op
+		require.NoError(t, m.close())
+	})
+
+	t.Run("ReadOnlyDescriptor", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), msrFile)
+		require.NoError(t, os.WriteFile(path, make([]byte, 16), 0600))
+
+		f, err := os.Open(path)
+		require.NoError(t, err)
+
+		m := &msr{
+			path: path,
+			file: f,
+		}
+		defer m.close()
+
+		err = m.write(0x0, 0x1)
+		require.ErrorContains(t, err, fmt.Sprintf("MSR file %q is opened read-only, write operations are not permitted", path))
+
+		out, err := m.read(0x0)
+		require.NoError(t, err)
+		require.Zero(t, out)
+	})
+	t.Run("ReadOnlyByDefault", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), msrFile)
+		require.NoError(t, os.WriteFile(path, make([]byte, 16), 0600))
+
+		m := &msr{
+			path: path,
+		}
+		defer m.close()
+
+		_, err := m.read(0x0)
+		require.NoError(t, err)
+		require.False(t, m.writable)
+
+		// the persistent descriptor is read-only, even if the file is writable
+		_, err = m.file.WriteAt(make([]byte, 8), 0)
+		require.Error(t, err)
+
+		err = m.write(0x0, 0x1)
+		require.ErrorContains(t, err, fmt.Sprintf("MSR file %q is opened read-only, write operations are not permitted", path))
+	})
+}
+
 func TestMsrReadAll(t *testing.T) {
 	testCases := []struct {
 		name       string
@@ -321,12 +453,59 @@ func TestMsrReadAll(t *testing.T) {
 	}
 }
 
+// blockingReaderAt is an io.ReaderAt whose reads block until its release channel is closed.
+type blockingReaderAt struct {
+	release chan struct{}
+	mu      sync.Mutex
+	reads   int
+}
+
+func (r *blockingReaderAt) ReadAt(p []byte, _ int64) (int, error) {
+	r.mu.Lock()
+	r.reads++
+	r.mu.Unlock()
+
+	<-r.release
+	return len(p), nil
+}
+
+func TestMsrReadOffsetTimeout(t *testing.T) {
+	reader := &blockingReaderAt{
+		release: make(chan struct{}),
+	}
+	m := &msr{
+		path:    "testdata/cpu-msr/0/msr",
+		timeout: 10 * time.Millisecond,
+	}
+
+	// first read times out, its goroutine keeps waiting for the underlying read.
+	v, err := m.readOffset(0x0, reader)
+	require.ErrorContains(t, err, "timeout when reading file at offset 0x0")
+	require.Zero(t, v)
+
+	// further reads time out without starting another read while the first one is pending.
+	v, err = m.readOffset(0x8, reader)
+	require.ErrorContains(t, err, "timeout when reading file at offset 0x8")
+	require.Zero(t, v)
+
+	reader.mu.Lock()
+	require.Equal(t, 1, reader.reads)
+	reader.mu.Unlock()
+
+	// once the pending read returns, reads are performed again.
+	close(reader.release)
+	require.Eventually(t, func() bool {
+		_, err := m.readOffset(0x0, reader)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+}
+
 func TestNewMsrWithStorage(t *testing.T) {
 	t.Run("WithoutOffsets", func(t *testing.T) {
 		reqOffsets := []uint32{}
 		cpuMsrDir := "testdata/cpu-msr/0"
 
-		m, err := newMsrWithStorage(cpuMsrDir, reqOffsets, 0)
+		m, err := newMsrWithStorage(cpuMsrDir, msrFile, reqOffsets, nil, 0, false)
 		require.Nil(t, m)
 		require.ErrorContains(t, err, "no offsets were provided")
 	})
@@ -338,22 +517,24 @@ func TestNewMsrWithStorage(t *testing.T) {
 
 		expected := &msrWithStorage{
 			msrReg: &msr{
-				path:  cpuMsrFile,
-				cpuID: 0,
+				path:   cpuMsrFile,
+				cpuID:  0,
+				closed: true,
 			},
 			offsets:      reqOffsets,
 			offsetValues: map[uint32]uint64{},
 			offsetDeltas: map[uint32]uint64{},
 		}
 
-		m, err := newMsrWithStorage(cpuMsrDir, reqOffsets, 0)
+		m, err := newMsrWithStorage(cpuMsrDir, msrFile, reqOffsets, nil, 0, false)
 		require.NoError(t, err)
+		require.NoError(t, m.close())
 		require.Equal(t, expected, m)
 	})
 }
 
 func TestMsrWithStorageGetters(t *testing.T) {
-	mReg, err := newMsr("testdata/cpu-msr/0", 0)
+	mReg, err := newMsr("testdata/cpu-msr/0", msrFile, 0, false)
 	require.NoError(t, err)
 
 	expectedValues := map[uint32]uint64{
@@ -388,7 +569,7 @@ func TestMsrWithStorageGetters(t *testing.T) {
 }
 
 func TestMsrWithStorageSetters(t *testing.T) {
-	mReg, err := newMsr("testdata/cpu-msr/0", 0)
+	mReg, err := newMsr("testdata/cpu-msr/0", msrFile, 0, false)
 	require.NoError(t, err)
 
 	m := &msrWithStorage{
@@ -495,8 +676,81 @@ func (s *msrTimeSensitiveSuite) TestMsrWithStorageUpdate() {
 		s.Require().Equal(d2, m.getTimestampDelta())
 	})
 
+	s.Run("WraparoundOffsetDelta", func() {
+		msrOffsets := []uint32{0x00, 0x02, 0x04}
+
+		offsetValuesT1 := map[uint32]uint64{
+			msrOffsets[0]: uint64(0xFFFF_FFF0),
+			msrOffsets[1]: uint64(0xFFFF_FFFF_FFFF),
+			msrOffsets[2]: uint64(0xFF00),
+		}
+
+		offsetValuesT2 := map[uint32]uint64{
+			msrOffsets[0]: uint64(0x10),
+			msrOffsets[1]: uint64(0x1),
+			msrOffsets[2]: uint64(0x0100),
+		}
+
+		expectedDeltas := map[uint32]uint64{
+			msrOffsets[0]: 0x20,
+			msrOffsets[1]: 0x2,
+			msrOffsets[2]: uint64(0xFFFF_FFFF_FFFF_0200),
+		}
+
+		mMsrReg := &msrRegMock{}
+		mMsrReg.On("readAll", msrOffsets).Return(offsetValuesT1, nil).Once()
+		mMsrReg.On("readAll", msrOffsets).Return(offsetValuesT2, nil).Once()
+
+		m := &msrWithStorage{
+			msrReg:  mMsrReg,
+			offsets: msrOffsets,
+			widths: map[uint32]uint{
+				msrOffsets[0]: 32,
+				msrOffsets[1]: 48,
+				msrOffsets[2]: 0,
+			},
+			offsetValues: map[uint32]uint64{},
+			offsetDeltas: map[uint32]uint64{},
+			timestamp:    fakeClock.Now(),
+		}
+
+		s.Require().NoError(m.update())
+		s.Require().NoError(m.update())
+		s.Require().Equal(offsetValuesT2, m.getOffsetValues())
+		s.Require().Equal(offsetValuesT1, m.getPrevOffsetValues())
+		s.Require().Equal(expectedDeltas, m.getOffsetDeltas())
+		s.Require().Empty(m.getOffsetResets())
+		mMsrReg.AssertExpectations(s.T())
+	})
+
+	s.Run("CounterReset", func() {
+		msrOffsets := []uint32{0x00, 0x02}
+
+		mMsrReg := &msrRegMock{}
+		mMsrReg.On("getCPUID").Return(1).Once()
+		mMsrReg.On("readAll", msrOffsets).Return(map[uint32]uint64{0x00: 0x1000_0000, 0x02: 100}, nil).Once()
+		mMsrReg.On("readAll", msrOffsets).Return(map[uint32]uint64{0x00: 0x10, 0x02: 200}, nil).Once()
+
+		m := &msrWithStorage{
+			msrReg:       mMsrReg,
+			offsets:      msrOffsets,
+			widths:       map[uint32]uint{0x00: 32},
+			offsetValues: map[uint32]uint64{},
+			offsetDeltas: map[uint32]uint64{},
+			timestamp:    fakeClock.Now(),
+		}
+
+		s.Require().NoError(m.update())
+		s.Require().Empty(m.getOffsetResets())
+
+		s.Require().NoError(m.update())
+		s.Require().Equal(map[uint32]uint64{0x00: 0, 0x02: 100}, m.getOffsetDeltas())
+		s.Require().Equal(map[uint32]bool{0x00: true}, m.getOffsetResets())
+		mMsrReg.AssertExpectations(s.T())
+	})
+
 	s.Run("PositiveOffsetDeltas", func() {
-		mReg, err := newMsr("testdata/cpu-msr/0", 0)
+		mReg, err := newMsr("testdata/cpu-msr/0", msrFile, 0, false)
 		s.Require().NoError(err)
 
 		m := &msrWithStorage{
@@ -628,8 +882,9 @@ func (s *msrTimeSensitiveSuite) TestInitMsrMap() {
 			msrMap: map[int]msrRegWithStorage{
 				0: &msrWithStorage{
 					msrReg: &msr{
-						cpuID: 0,
-						path:  "testdata/cpu-msr/0/msr",
+						cpuID:  0,
+						path:   "testdata/cpu-msr/0/msr",
+						closed: true,
 					},
 					offsets:        []uint32{0x00, 0x02, 0x04, 0x05, 0x06, 0x08},
 					timestamp:      fakeClock.Now(),
@@ -642,6 +897,8 @@ func (s *msrTimeSensitiveSuite) TestInitMsrMap() {
 						0x06: uint64(0x547698badcfeefcd),
 						0x08: uint64(0x1032547698badcfe),
 					},
+					prevOffsetValues: map[uint32]uint64{},
+					offsetResets:     map[uint32]bool{},
 					offsetDeltas: map[uint32]uint64{
 						0x00: uint64(0xefcdab8967452301),
 						0x02: uint64(0xdcfeefcdab896745),
@@ -653,8 +910,9 @@ func (s *msrTimeSensitiveSuite) TestInitMsrMap() {
 				},
 				1: &msrWithStorage{
 					msrReg: &msr{
-						cpuID: 1,
-						path:  "testdata/cpu-msr/1/msr",
+						cpuID:  1,
+						path:   "testdata/cpu-msr/1/msr",
+						closed: true,
 					},
 					offsets: []uint32{0x00, 0x02, 0x04, 0x05, 0x06, 0x08},
 					offsetValues: map[uint32]uint64{
@@ -665,6 +923,8 @@ func (s *msrTimeSensitiveSuite) TestInitMsrMap() {
 						0x06: uint64(0x547698badcfeefcd),
 						0x08: uint64(0x1032547698badcfe),
 					},
+					prevOffsetValues: map[uint32]uint64{},
+					offsetResets:     map[uint32]bool{},
 					offsetDeltas: map[uint32]uint64{
 						0x00: uint64(0xefcdab8967452301),
 						0x02: uint64(0xdcfeefcdab896745),
@@ -678,8 +938,9 @@ func (s *msrTimeSensitiveSuite) TestInitMsrMap() {
 				},
 				10: &msrWithStorage{
 					msrReg: &msr{
-						cpuID: 10,
-						path:  "testdata/cpu-msr/10/msr",
+						cpuID:  10,
+						path:   "testdata/cpu-msr/10/msr",
+						closed: true,
 					},
 					offsets: []uint32{0x00, 0x02, 0x04, 0x05, 0x06, 0x08},
 					offsetValues: map[uint32]uint64{
@@ -690,6 +951,8 @@ func (s *msrTimeSensitiveSuite) TestInitMsrMap() {
 						0x06: uint64(0x547698badcfeefcd),
 						0x08: uint64(0x1032547698badcfe),
 					},
+					prevOffsetValues: map[uint32]uint64{},
+					offsetResets:     map[uint32]bool{},
 					offsetDeltas: map[uint32]uint64{
 						0x00: uint64(0xefcdab8967452301),
 						0x02: uint64(0xdcfeefcdab896745),
@@ -703,8 +966,9 @@ func (s *msrTimeSensitiveSuite) TestInitMsrMap() {
 				},
 				100: &msrWithStorage{
 					msrReg: &msr{
-						cpuID: 100,
-						path:  "testdata/cpu-msr/100/msr",
+						cpuID:  100,
+						path:   "testdata/cpu-msr/100/msr",
+						closed: true,
 					},
 					offsets: []uint32{0x00, 0x02, 0x04, 0x05, 0x06, 0x08},
 					offsetValues: map[uint32]uint64{
@@ -715,6 +979,8 @@ func (s *msrTimeSensitiveSuite) TestInitMsrMap() {
 						0x06: uint64(0x547698badcfeefcd),
 						0x08: uint64(0x1032547698badcfe),
 					},
+					prevOffsetValues: map[uint32]uint64{},
+					offsetResets:     map[uint32]bool{},
 					offsetDeltas: map[uint32]uint64{
 						0x00: uint64(0xefcdab8967452301),
 						0x02: uint64(0xdcfeefcdab896745),
@@ -737,14 +1003,17 @@ func (s *msrTimeSensitiveSuite) TestInitMsrMap() {
 			msrMap: map[int]msrRegWithStorage{
 				0: &msrWithStorage{
 					msrReg: &msr{
-						cpuID: 0,
-						path:  "testdata/cpu-msr/0/msr",
+						cpuID:  0,
+						path:   "testdata/cpu-msr/0/msr",
+						closed: true,
 					},
 					offsets: []uint32{0x00, 0x02},
 					offsetValues: map[uint32]uint64{
 						0x00: uint64(0xefcdab8967452301),
 						0x02: uint64(0xdcfeefcdab896745),
 					},
+					prevOffsetValues: map[uint32]uint64{},
+					offsetResets:     map[uint32]bool{},
 					offsetDeltas: map[uint32]uint64{
 						0x00: uint64(0xefcdab8967452301),
 						0x02: uint64(0xdcfeefcdab896745),
@@ -754,14 +1023,17 @@ func (s *msrTimeSensitiveSuite) TestInitMsrMap() {
 				},
 				10: &msrWithStorage{
 					msrReg: &msr{
-						cpuID: 10,
-						path:  "testdata/cpu-msr/10/msr",
+						cpuID:  10,
+						path:   "testdata/cpu-msr/10/msr",
+						closed: true,
 					},
 					offsets: []uint32{0x00, 0x02},
 					offsetValues: map[uint32]uint64{
 						0x00: uint64(0xefcdab8967452301),
 						0x02: uint64(0xdcfeefcdab896745),
 					},
+					prevOffsetValues: map[uint32]uint64{},
+					offsetResets:     map[uint32]bool{},
 					offsetDeltas: map[uint32]uint64{
 						0x00: uint64(0xefcdab8967452301),
 						0x02: uint64(0xdcfeefcdab896745),
@@ -782,6 +1054,7 @@ func (s *msrTimeSensitiveSuite) TestInitMsrMap() {
 			}
 
 			err := m.initMsrMap(tc.cpuIDs, 0)
+			s.Require().NoError(m.close())
 			s.Require().Equal(tc.msrMap, m.msrMap)
 			if tc.err != nil {
 				s.Require().ErrorContains(err, tc.err.Error())
@@ -842,7 +1115,7 @@ func TestMsrDataWithStorageRead(t *testing.T) {
 		},
 	}
 
-	mReg, err := newMsr("testdata/cpu-msr/0", 0)
+	mReg, err := newMsr("testdata/cpu-msr/0", msrFile, 0, false)
 	require.NoError(t, err)
 	m := &msrDataWithStorage{
 		msrMap: map[int]msrRegWithStorage{
@@ -865,6 +1138,77 @@ func TestMsrDataWithStorageRead(t *testing.T) {
 	}
 }
 
+func TestMsrDataWithStorageWrite(t *testing.T) {
+	mReg := &msrRegMock{}
+	m := &msrDataWithStorage{
+		msrMap: map[int]msrRegWithStorage{
+			0: &msrWithStorage{
+				msrReg: mReg,
+			},
+		},
+	}
+
+	t.Run("InvalidCPUID", func(t *testing.T) {
+		err := m.write(0x620, 2, 0x0818)
+		require.ErrorContains(t, err, "could not find MSR register for CPU ID: 2")
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		mReg.On("write", uint32(0x620), uint64(0x0818)).Return(nil).Once()
+
+		err := m.write(0x620, 0, 0x0818)
+		require.NoError(t, err)
+		mReg.AssertExpectations(t)
+	})
+}
+
+func TestMsrDataWithStorageClose(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		mReg0 := &msrRegMock{}
+		mReg0.On("close").Return(nil).Once()
+
+		mReg1 := &msrRegMock{}
+		mReg1.On("close").Return(nil).Once()
+
+		m := &msrDataWithStorage{
+			msrMap: map[int]msrRegWithStorage{
+				0: &msrWithStorage{msrReg: mReg0},
+				1: &msrWithStorage{msrReg: mReg1},
+			},
+		}
+
+		require.NoError(t, m.close())
+		mReg0.AssertExpectations(t)
+		mReg1.AssertExpectations(t)
+	})
+
+	t.Run("FailedToClose", func(t *testing.T) {
+		mReg0 := &msrRegMock{}
+		mReg0.On("close").Return(errors.New("mock error 0")).Once()
+
+		mReg1 := &msrRegMock{}
+		mReg1.On("close").Return(nil).Once()
+
+		mReg2 := &msrRegMock{}
+		mReg2.On("close").Return(errors.New("mock error 2")).Once()
+
+		m := &msrDataWithStorage{
+			msrMap: map[int]msrRegWithStorage{
+				0: &msrWithStorage{msrReg: mReg0},
+				1: &msrWithStorage{msrReg: mReg1},
+				2: &msrWithStorage{msrReg: mReg2},
+			},
+		}
+
+		err := m.close()
+		require.ErrorContains(t, err, "mock error 0")
+		require.ErrorContains(t, err, "mock error 2")
+		mReg0.AssertExpectations(t)
+		mReg1.AssertExpectations(t)
+		mReg2.AssertExpectations(t)
+	})
+}
+
 func TestMsrDataWithStorageIsMsrLoaded(t *testing.T) {
 	testCases := []struct {
 		desc     string
@@ -993,6 +1337,40 @@ func TestMsrDataWithStorageUpdate(t *testing.T) {
 	}
 }
 
+func TestMsrDataWithStorageUpdateCounterReset(t *testing.T) {
+	offsets := []uint32{0x10, 0x309}
+
+	mReg := &msrRegMock{}
+	mReg.On("getCPUID").Return(0).Maybe()
+	mReg.On("readAll", offsets).Return(map[uint32]uint64{0x10: 1000, 0x309: 1<<48 - 10}, nil).Once()
+	mReg.On("readAll", offsets).Return(map[uint32]uint64{0x10: 10, 0x309: 20}, nil).Once()
+
+	m := &msrDataWithStorage{
+		msrMap: map[int]msrRegWithStorage{
+			0: &msrWithStorage{
+				msrReg:       mReg,
+				offsets:      offsets,
+				widths:       map[uint32]uint{0x309: 48},
+				offsetValues: map[uint32]uint64{},
+				offsetDeltas: map[uint32]uint64{},
+			},
+		},
+	}
+
+	require.NoError(t, m.update(0))
+
+	err := m.update(0)
+	var resetErr *CounterResetError
+	require.ErrorAs(t, err, &resetErr)
+	require.Equal(t, &CounterResetError{CPUID: 0, Offsets: []uint32{0x10}}, resetErr)
+	require.EqualError(t, err, "counter reset detected for CPU ID 0 at MSR offsets: 0x10")
+
+	// values are stored despite the reset, the 48-bit counter wraps around.
+	require.Equal(t, map[uint32]uint64{0x10: 10, 0x309: 20}, m.msrMap[0].getOffsetValues())
+	require.Equal(t, map[uint32]uint64{0x10: 0, 0x309: 30}, m.msrMap[0].getOffsetDeltas())
+	mReg.AssertExpectations(t)
+}
+
 func TestMsrDataWithStorageGetOffsetDeltas(t *testing.T) {
 	m := &msrDataWithStorage{
 		msrMap: map[int]msrRegWithStorage{
@@ -1049,6 +1427,137 @@ func TestMsrDataWithStorageGetOffsetDeltas(t *testing.T) {
 	})
 }
 
+func TestMsrDataWithStorageInitCounters(t *testing.T) {
+	t.Run("InvalidCounters", func(t *testing.T) {
+		m := &msrDataWithStorage{
+			msrOffsets: []uint32{0x10},
+		}
+
+		require.ErrorContains(t, m.initCounters([]MsrCounter{{Offset: 0x309}}), "name of MSR counter cannot be empty")
+		require.Equal(t, []uint32{0x10}, m.msrOffsets)
+		require.Nil(t, m.counters)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		offsets := []uint32{0x10, 0xE7}
+		m := &msrDataWithStorage{
+			msrOffsets: offsets,
+		}
+
+		counters := []MsrCounter{
+			{Name: "fixed_ctr0", Offset: 0x309, Width: 48},
+			{Name: "mperf_low", Offset: 0xE7, Mask: 0xFFFF_FFFF},
+			{Name: "field", Offset: 0x30A, Mask: 0xFF00, Shift: 8},
+		}
+		require.NoError(t, m.initCounters(counters))
+		require.Equal(t, []uint32{0x10, 0xE7, 0x309, 0x30A}, m.msrOffsets)
+		require.Equal(t, map[string]MsrCounter{
+			"fixed_ctr0": counters[0],
+			"mperf_low":  counters[1],
+			"field":      counters[2],
+		}, m.counters)
+		require.Equal(t, map[uint32]uint{
+			0x309: 48,
+			0x30A: 0,
+		}, m.offsetWidths)
+
+		// offsets of the caller are not modified.
+		require.Equal(t, []uint32{0x10, 0xE7}, offsets)
+	})
+}
+
+func TestMsrDataWithStorageGetCounterDelta(t *testing.T) {
+	const offset = uint32(0x309)
+
+	newStorage := func(t *testing.T, widths map[uint32]uint, values ...uint64) *msrDataWithStorage {
+		t.Helper()
+
+		mReg := &msrRegMock{}
+		mReg.On("getCPUID").Return(0).Maybe()
+		for _, v := range values {
+			mReg.On("readAll", []uint32{offset}).Return(map[uint32]uint64{offset: v}, nil).Once()
+		}
+
+		m := &msrDataWithStorage{
+			msrMap: map[int]msrRegWithStorage{
+				0: &msrWithStorage{
+					msrReg:       mReg,
+					offsets:      []uint32{offset},
+					widths:       widths,
+					offsetValues: map[uint32]uint64{},
+					offsetDeltas: map[uint32]uint64{},
+				},
+			},
+			counters: map[string]MsrCounter{
+				"fixed_ctr0": {Name: "fixed_ctr0", Offset: offset, Width: 48},
+				"low_byte":   {Name: "low_byte", Offset: offset, Mask: 0xFF00, Shift: 8},
+			},
+		}
+		for range values {
+			require.NoError(t, m.update(0))
+		}
+		return m
+	}
+
+	t.Run("CounterNotFound", func(t *testing.T) {
+		m := newStorage(t, nil)
+
+		delta, err := m.getCounterDelta(0, "unknown")
+		require.ErrorContains(t, err, "could not find MSR counter \"unknown\"")
+		require.Zero(t, delta)
+	})
+
+	t.Run("InvalidCPUID", func(t *testing.T) {
+		m := newStorage(t, nil)
+
+		delta, err := m.getCounterDelta(1, "fixed_ctr0")
+		require.ErrorContains(t, err, "could not find MSR register for CPU ID: 1")
+		require.Zero(t, delta)
+	})
+
+	t.Run("SingleReading", func(t *testing.T) {
+		m := newStorage(t, nil, 100)
+
+		delta, err := m.getCounterDelta(0, "fixed_ctr0")
+		require.ErrorContains(t, err, "MSR counter \"fixed_ctr0\" needs at least two readings for CPU ID: 0")
+		require.Zero(t, delta)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		m := newStorage(t, nil, 100, 350)
+
+		delta, err := m.getCounterDelta(0, "fixed_ctr0")
+		require.NoError(t, err)
+		require.Equal(t, uint64(250), delta)
+	})
+
+	t.Run("Wraparound", func(t *testing.T) {
+		m := newStorage(t, map[uint32]uint{offset: 48}, 1<<48-50, 1<<48+25)
+
+		delta, err := m.getCounterDelta(0, "fixed_ctr0")
+		require.NoError(t, err)
+		require.Equal(t, uint64(75), delta)
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		m := newStorage(t, map[uint32]uint{offset: 0}, 1<<40, 25)
+
+		delta, err := m.getCounterDelta(0, "fixed_ctr0")
+		var resetErr *CounterResetError
+		require.ErrorAs(t, err, &resetErr)
+		require.Equal(t, &CounterResetError{CPUID: 0, Offsets: []uint32{offset}}, resetErr)
+		require.Zero(t, delta)
+	})
+
+	t.Run("MaskAndShiftWraparound", func(t *testing.T) {
+		m := newStorage(t, map[uint32]uint{offset: 0}, 0xFE00, 0x0100)
+
+		delta, err := m.getCounterDelta(0, "low_byte")
+		require.NoError(t, err)
+		require.Equal(t, uint64(3), delta)
+	})
+}
+
 func TestMsrDataWithStorageScaleOffsetDeltas(t *testing.T) {
 	testCases := []struct {
 		name            string
@@ -0,0 +1,90 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// MsrCounter describes a model-specific counter held by an MSR offset. The value of the counter is obtained
+// by masking the MSR offset value and shifting it right.
+type MsrCounter struct {
+	Name   string // Unique name of the counter
+	Offset uint32 // MSR offset holding the counter
+	Width  uint   // Width of the counter in bits. If zero, it is derived from Mask and Shift
+	Mask   uint64 // Mask applied to the MSR offset value. If zero, all bits of the offset value are kept
+	Shift  uint   // Number of bits the masked MSR offset value is shifted right
+}
+
+// width returns the width of the counter in bits.
+func (c MsrCounter) width() uint {
+	if c.Width != 0 {
+		return c.Width
+	}
+	if c.Mask == 0 {
+		return 64 - c.Shift
+	}
+	return uint(bits.Len64(c.Mask >> c.Shift))
+}
+
+// value takes an MSR offset value and returns the value of the counter.
+func (c MsrCounter) value(raw uint64) uint64 {
+	if c.Mask != 0 {
+		raw &= c.Mask
+	}
+	return (raw >> c.Shift) & widthMask(c.width())
+}
+
+// validateMsrCounters takes a slice of MSR counters and returns an error if any of them is malformed,
+// or if counter names are not unique.
+func validateMsrCounters(counters []MsrCounter) error {
+	names := make(map[string]struct{}, len(counters))
+	for _, c := range counters {
+		if len(c.Name) == 0 {
+			return errors.New("name of MSR counter cannot be empty")
+		}
+		if _, ok := names[c.Name]; ok {
+			return fmt.Errorf("duplicated MSR counter name %q", c.Name)
+		}
+		names[c.Name] = struct{}{}
+
+		if c.Shift >= 64 {
+			return fmt.Errorf("invalid shift %v of MSR counter %q", c.Shift, c.Name)
+		}
+		if c.Mask != 0 && c.Mask>>c.Shift == 0 {
+			return fmt.Errorf("mask 0x%X of MSR counter %q has no bits left after shift %v", c.Mask, c.Name, c.Shift)
+		}
+		if c.Width > 64 {
+			return fmt.Errorf("invalid width %v of MSR counter %q", c.Width, c.Name)
+		}
+	}
+	return nil
+}
+
+// counterDelta takes the previous and the latest value of a counter with the given width in bits, and returns
+// the delta between them modulo 2^width. Hence, a single wraparound of the counter between both values is
+// accounted for. A modular delta greater than half of the counter range cannot be told apart from a counter
+// which went backwards, so it is considered a reset of the counter, e.g. after CPU offline/online. In such case,
+// a zero delta and true are returned.
+func counterDelta(prev, latest uint64, width uint) (uint64, bool) {
+	mask := widthMask(width)
+	delta := (latest - prev) & mask
+	if delta > mask>>1 {
+		return 0, true
+	}
+	return delta, false
+}
+
+// widthMask takes a width in bits and returns a mask with the given number of least significant bits set.
+func widthMask(width uint) uint64 {
+	if width >= 64 {
+		return math.MaxUint64
+	}
+	return 1<<width - 1
+}
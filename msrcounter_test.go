@@ -0,0 +1,181 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMsrCounterValue(t *testing.T) {
+	testCases := []struct {
+		name     string
+		counter  MsrCounter
+		raw      uint64
+		width    uint
+		expected uint64
+	}{
+		{
+			name:     "FullRegister",
+			counter:  MsrCounter{},
+			raw:      0xFFFF_0000_1234_5678,
+			width:    64,
+			expected: 0xFFFF_0000_1234_5678,
+		},
+		{
+			name:     "ExplicitWidth",
+			counter:  MsrCounter{Width: 48},
+			raw:      0xFFFF_0000_1234_5678,
+			width:    48,
+			expected: 0x0000_0000_1234_5678,
+		},
+		{
+			name:     "MaskAndShift",
+			counter:  MsrCounter{Mask: 0x0000_FFFF_FFFF_0000, Shift: 16},
+			raw:      0xAAAA_1234_5678_BBBB,
+			width:    32,
+			expected: 0x1234_5678,
+		},
+		{
+			name:     "ShiftWithoutMask",
+			counter:  MsrCounter{Shift: 32},
+			raw:      0x1234_5678_9ABC_DEF0,
+			width:    32,
+			expected: 0x1234_5678,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.width, tc.counter.width())
+			require.Equal(t, tc.expected, tc.counter.value(tc.raw))
+		})
+	}
+}
+
+func TestValidateMsrCounters(t *testing.T) {
+	testCases := []struct {
+		name     string
+		counters []MsrCounter
+		err      error
+	}{
+		{
+			name:     "EmptyName",
+			counters: []MsrCounter{{Offset: 0x10}},
+			err:      errors.New("name of MSR counter cannot be empty"),
+		},
+		{
+			name:     "DuplicatedName",
+			counters: []MsrCounter{{Name: "a", Offset: 0x10}, {Name: "a", Offset: 0x11}},
+			err:      errors.New("duplicated MSR counter name \"a\""),
+		},
+		{
+			name:     "InvalidShift",
+			counters: []MsrCounter{{Name: "a", Offset: 0x10, Shift: 64}},
+			err:      errors.New("invalid shift 64 of MSR counter \"a\""),
+		},
+		{
+			name:     "MaskShiftedOut",
+			counters: []MsrCounter{{Name: "a", Offset: 0x10, Mask: 0xFF, Shift: 8}},
+			err:      errors.New("mask 0xFF of MSR counter \"a\" has no bits left after shift 8"),
+		},
+		{
+			name:     "InvalidWidth",
+			counters: []MsrCounter{{Name: "a", Offset: 0x10, Width: 65}},
+			err:      errors.New("invalid width 65 of MSR counter \"a\""),
+		},
+		{
+			name: "Valid",
+			counters: []MsrCounter{
+				{Name: "a", Offset: 0x10},
+				{Name: "b", Offset: 0x10, Mask: 0xFFFF_FFFF, Width: 32},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateMsrCounters(tc.counters)
+			if tc.err != nil {
+				require.EqualError(t, err, tc.err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCounterDelta(t *testing.T) {
+	testCases := []struct {
+		name     string
+		prev     uint64
+		latest   uint64
+		width    uint
+		expected uint64
+		reset    bool
+	}{
+		{
+			name:     "NoWraparound",
+			prev:     100,
+			latest:   150,
+			width:    64,
+			expected: 50,
+		},
+		{
+			name:     "Wraparound64Bits",
+			prev:     math.MaxUint64 - 9,
+			latest:   10,
+			width:    64,
+			expected: 20,
+		},
+		{
+			name:     "Wraparound48Bits",
+			prev:     1<<48 - 100,
+			latest:   50,
+			width:    48,
+			expected: 150,
+		},
+		{
+			name:     "Wraparound32Bits",
+			prev:     0xFFFF_FFF0,
+			latest:   0x10,
+			width:    32,
+			expected: 0x20,
+		},
+		{
+			name:   "Reset64Bits",
+			prev:   1000,
+			latest: 10,
+			width:  64,
+			reset:  true,
+		},
+		{
+			name:   "Reset32Bits",
+			prev:   0x1000_0000,
+			latest: 0x10,
+			width:  32,
+			reset:  true,
+		},
+		{
+			name:     "NoChange",
+			prev:     0x10,
+			latest:   0x10,
+			width:    32,
+			expected: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			delta, reset := counterDelta(tc.prev, tc.latest, tc.width)
+			require.Equal(t, tc.expected, delta)
+			require.Equal(t, tc.reset, reset)
+		})
+	}
+}
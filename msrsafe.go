@@ -0,0 +1,204 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	// file name of the binary MSR file exposed by msr_safe driver for each CPU ID.
+	msrSafeFile = "msr_safe"
+
+	// path to the file with MSR offsets allowed by msr_safe driver.
+	defaultMsrAllowlistPath = "/dev/cpu/msr_allowlist"
+
+	// path to the device of msr_safe driver which allows batch operations.
+	defaultMsrBatchPath = "/dev/cpu/msr_batch"
+
+	// msrBatchIoctl is the request code of msr_safe batch operations, defined as
+	// _IOWR('c', 0xA2, struct msr_batch_array).
+	msrBatchIoctl = 0xC01063A2
+)
+
+// msrBatchOp represents a single operation of an msr_safe batch. Its layout matches
+// the msr_batch_op struct defined by msr_safe driver.
+type msrBatchOp struct {
+	cpu     uint16 // CPU ID to perform the operation on
+	isRdmsr uint16 // non-zero for read operations
+	err     int32  // negative error number set by the driver, if the operation failed
+	msr     uint32 // MSR offset
+	_       uint32
+	msrData uint64 // value read or to be written
+	wmask   uint64 // write mask set by the driver from the allowlist
+}
+
+// msrBatchArray represents an array of msr_safe batch operations. Its layout matches
+// the msr_batch_array struct defined by msr_safe driver.
+type msrBatchArray struct {
+	numOps uint32
+	_      uint32
+	ops    *msrBatchOp
+}
+
+// msrBatchIoctlFn performs the msr_safe batch ioctl on the given file descriptor.
+var msrBatchIoctlFn = func(fd uintptr, batch *msrBatchArray) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, msrBatchIoctl, uintptr(unsafe.Pointer(batch)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// msrBatchReader represents a mechanism for reading multiple MSR offsets of multiple CPU IDs
+// within a single operation.
+type msrBatchReader interface {
+	// readBatch takes a slice of CPU IDs and a slice of offsets, and returns a map of CPU ID key and
+	// a map with offset key and the offset value of the CPU ID.
+	readBatch(cpuIDs []int, offsets []uint32) (map[int]map[uint32]uint64, error)
+
+	// close releases the descriptor of the batch device.
+	close() error
+}
+
+// msrBatch allows batch reading operations of MSR offsets via msr_safe batch device. Implements
+// msrBatchReader interface.
+type msrBatch struct {
+	path string
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// newMsrBatch takes the path of the msr_safe batch device and returns an msrBatch with the device opened.
+func newMsrBatch(path string) (*msrBatch, error) {
+	if err := checkFile(path); err != nil {
+		return nil, fmt.Errorf("invalid msr_safe batch device: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("error opening msr_safe batch device: %w", err)
+	}
+	return &msrBatch{
+		path: path,
+		file: f,
+	}, nil
+}
+
+// readBatch takes a slice of CPU IDs and a slice of offsets, and reads all offsets of all CPU IDs with a single
+// batch operation. It returns a map of CPU ID key and a map with offset key and the offset value of the CPU ID.
+func (b *msrBatch) readBatch(cpuIDs []int, offsets []uint32) (map[int]map[uint32]uint64, error) {
+	if len(cpuIDs) == 0 || len(offsets) == 0 {
+		return nil, errors.New("CPU IDs and offsets cannot be empty")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.file == nil {
+		return nil, fmt.Errorf("msr_safe batch device %q is closed", b.path)
+	}
+
+	ops := make([]msrBatchOp, 0, len(cpuIDs)*len(offsets))
+	for _, cpuID := range cpuIDs {
+		for _, offset := range offsets {
+			ops = append(ops, msrBatchOp{
+				cpu:     uint16(cpuID),
+				isRdmsr: 1,
+				msr:     offset,
+			})
+		}
+	}
+
+	batch := &msrBatchArray{
+		numOps: uint32(len(ops)),
+		ops:    &ops[0],
+	}
+	err := msrBatchIoctlFn(b.file.Fd(), batch)
+	runtime.KeepAlive(ops)
+	if err != nil {
+		return nil, fmt.Errorf("error performing msr_safe batch operation: %w", err)
+	}
+
+	values := make(map[int]map[uint32]uint64, len(cpuIDs))
+	for _, op := range ops {
+		if op.err != 0 {
+			return nil, fmt.Errorf("error reading MSR offset 0x%X for CPU ID %v: %w", op.msr, op.cpu, syscall.Errno(-op.err))
+		}
+		cpuID := int(op.cpu)
+		if _, ok := values[cpuID]; !ok {
+			values[cpuID] = make(map[uint32]uint64, len(offsets))
+		}
+		values[cpuID][op.msr] = op.msrData
+	}
+	return values, nil
+}
+
+// close releases the descriptor of the batch device.
+func (b *msrBatch) close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.file == nil {
+		return nil
+	}
+	err := b.file.Close()
+	b.file = nil
+	if err != nil {
+		return fmt.Errorf("error closing msr_safe batch device %q: %w", b.path, err)
+	}
+	return nil
+}
+
+// readMsrAllowlist takes the path of msr_safe allowlist file and returns a map with the allowed MSR offset
+// key and its write mask value. Each non-comment line of the file comprises an MSR offset and its write mask,
+// both in hexadecimal format, e.g.:
+//
+// # MSR      Write Mask         # Comment
+// 0x00000010 0x0000000000000000 # "SMSR_TIME_STAMP_COUNTER"
+func readMsrAllowlist(path string) (map[uint32]uint64, error) {
+	content, err := readFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading msr_safe allowlist: %w", err)
+	}
+
+	allowlist := make(map[uint32]uint64)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("invalid msr_safe allowlist entry %q", line)
+		}
+		offset, err := strconv.ParseUint(fields[0], 0, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MSR offset in msr_safe allowlist entry %q: %w", line, err)
+		}
+		mask, err := strconv.ParseUint(fields[1], 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid write mask in msr_safe allowlist entry %q: %w", line, err)
+		}
+		allowlist[uint32(offset)] = mask
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error parsing msr_safe allowlist: %w", err)
+	}
+	return allowlist, nil
+}
@@ -0,0 +1,423 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// msrBatchMock represents a mock for msrBatch type. Implements msrBatchReader interface.
+type msrBatchMock struct {
+	mock.Mock
+}
+
+func (m *msrBatchMock) readBatch(cpuIDs []int, offsets []uint32) (map[int]map[uint32]uint64, error) {
+	args := m.Called(cpuIDs, offsets)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]map[uint32]uint64), args.Error(1)
+}
+
+func (m *msrBatchMock) close() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+// setFakeMsrBatchIoctl replaces the msr_safe batch ioctl with a fake one which sets the value of each
+// read operation to the CPU ID shifted 32 bits left, plus the MSR offset. Operations for the failing
+// offset are set with EIO error. The original ioctl is restored on test cleanup.
+func setFakeMsrBatchIoctl(t *testing.T, failingOffset uint32) {
+	t.Helper()
+
+	orig := msrBatchIoctlFn
+	t.Cleanup(func() {
+		msrBatchIoctlFn = orig
+	})
+
+	msrBatchIoctlFn = func(_ uintptr, batch *msrBatchArray) error {
+		for i, op := range unsafe.Slice(batch.ops, batch.numOps) {
+			if op.isRdmsr == 0 {
+				return syscall.EINVAL
+			}
+			if op.msr == failingOffset {
+				unsafe.Slice(batch.ops, batch.numOps)[i].err = -int32(syscall.EIO)
+				continue
+			}
+			unsafe.Slice(batch.ops, batch.numOps)[i].msrData = uint64(op.cpu)<<32 | uint64(op.msr)
+		}
+		return nil
+	}
+}
+
+// writeMsrSafeTree takes a base path and a slice of CPU IDs, and writes a 4KiB zeroed msr_safe file
+// for each CPU ID, together with the allowlist and the batch device files. It returns the paths of the
+// allowlist and the batch device files.
+func writeMsrSafeTree(t *testing.T, basePath string, cpuIDs []int, allowlist string) (string, string) {
+	t.Helper()
+
+	for _, cpuID := range cpuIDs {
+		cpuPath := filepath.Join(basePath, strconv.Itoa(cpuID))
+		require.NoError(t, os.MkdirAll(cpuPath, 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(cpuPath, msrSafeFile), make([]byte, 0x1000), 0600))
+	}
+
+	allowlistPath := filepath.Join(basePath, "msr_allowlist")
+	require.NoError(t, os.WriteFile(allowlistPath, []byte(allowlist), 0600))
+
+	batchPath := filepath.Join(basePath, "msr_batch")
+	require.NoError(t, os.WriteFile(batchPath, nil, 0600))
+	return allowlistPath, batchPath
+}
+
+func TestMsrBatchLayout(t *testing.T) {
+	require.Equal(t, uintptr(32), unsafe.Sizeof(msrBatchOp{}))
+	require.Equal(t, uintptr(8), unsafe.Offsetof(msrBatchOp{}.msr))
+	require.Equal(t, uintptr(16), unsafe.Offsetof(msrBatchOp{}.msrData))
+	require.Equal(t, uintptr(16), unsafe.Sizeof(msrBatchArray{}))
+	require.Equal(t, uintptr(8), unsafe.Offsetof(msrBatchArray{}.ops))
+}
+
+func TestReadMsrAllowlist(t *testing.T) {
+	t.Run("FileNotExist", func(t *testing.T) {
+		allowlist, err := readMsrAllowlist("/dummy/path")
+		require.ErrorContains(t, err, "error reading msr_safe allowlist")
+		require.Nil(t, allowlist)
+	})
+
+	t.Run("InvalidEntries", func(t *testing.T) {
+		testCases := []struct {
+			name    string
+			content string
+			err     string
+		}{
+			{
+				name:    "MissingWriteMask",
+				content: "0x00000010\n",
+				err:     "invalid msr_safe allowlist entry \"0x00000010\"",
+			},
+			{
+				name:    "InvalidOffset",
+				content: "0xZ 0x0\n",
+				err:     "invalid MSR offset in msr_safe allowlist entry",
+			},
+			{
+				name:    "InvalidWriteMask",
+				content: "0x10 mask\n",
+				err:     "invalid write mask in msr_safe allowlist entry",
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				path := filepath.Join(t.TempDir(), "msr_allowlist")
+				require.NoError(t, os.WriteFile(path, []byte(tc.content), 0600))
+
+				allowlist, err := readMsrAllowlist(path)
+				require.ErrorContains(t, err, tc.err)
+				require.Nil(t, allowlist)
+			})
+		}
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		content := "# MSR      Write Mask         # Comment\n" +
+			"0x00000010 0x0000000000000000 # \"SMSR_TIME_STAMP_COUNTER\"\n" +
+			"\n" +
+			"0x00000620 0x0000000000007F7F # \"MSR_UNCORE_RATIO_LIMIT\"\n"
+		path := filepath.Join(t.TempDir(), "msr_allowlist")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+		allowlist, err := readMsrAllowlist(path)
+		require.NoError(t, err)
+		require.Equal(t, map[uint32]uint64{
+			0x10:  0x0,
+			0x620: 0x7F7F,
+		}, allowlist)
+	})
+}
+
+func TestMsrBatch(t *testing.T) {
+	t.Run("DeviceNotExist", func(t *testing.T) {
+		b, err := newMsrBatch("/dummy/path")
+		require.ErrorContains(t, err, "invalid msr_safe batch device")
+		require.Nil(t, b)
+	})
+
+	t.Run("EmptyArguments", func(t *testing.T) {
+		b := &msrBatch{}
+
+		values, err := b.readBatch(nil, []uint32{0x10})
+		require.ErrorContains(t, err, "CPU IDs and offsets cannot be empty")
+		require.Nil(t, values)
+	})
+
+	t.Run("FailedIoctl", func(t *testing.T) {
+		orig := msrBatchIoctlFn
+		defer func() {
+			msrBatchIoctlFn = orig
+		}()
+		msrBatchIoctlFn = func(_ uintptr, _ *msrBatchArray) error {
+			return syscall.ENOTTY
+		}
+
+		path := filepath.Join(t.TempDir(), "msr_batch")
+		require.NoError(t, os.WriteFile(path, nil, 0600))
+		b, err := newMsrBatch(path)
+		require.NoError(t, err)
+		defer b.close()
+
+		values, err := b.readBatch([]int{0}, []uint32{0x10})
+		require.ErrorIs(t, err, syscall.ENOTTY)
+		require.ErrorContains(t, err, "error performing msr_safe batch operation")
+		require.Nil(t, values)
+	})
+
+	t.Run("FailedOperation", func(t *testing.T) {
+		setFakeMsrBatchIoctl(t, 0xE7)
+
+		path := filepath.Join(t.TempDir(), "msr_batch")
+		require.NoError(t, os.WriteFile(path, nil, 0600))
+		b, err := newMsrBatch(path)
+		require.NoError(t, err)
+		defer b.close()
+
+		values, err := b.readBatch([]int{0, 3}, []uint32{0x10, 0xE7})
+		require.ErrorIs(t, err, syscall.EIO)
+		require.ErrorContains(t, err, "error reading MSR offset 0xE7 for CPU ID 0")
+		require.Nil(t, values)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		setFakeMsrBatchIoctl(t, 0)
+
+		path := filepath.Join(t.TempDir(), "msr_batch")
+		require.NoError(t, os.WriteFile(path, nil, 0600))
+		b, err := newMsrBatch(path)
+		require.NoError(t, err)
+
+		values, err := b.readBatch([]int{0, 3}, []uint32{0x10, 0xE7})
+		require.NoError(t, err)
+		require.Equal(t, map[int]map[uint32]uint64{
+			0: {0x10: 0x10, 0xE7: 0xE7},
+			3: {0x10: 0x3_0000_0010, 0xE7: 0x3_0000_00E7},
+		}, values)
+
+		require.NoError(t, b.close())
+		require.NoError(t, b.close())
+
+		values, err = b.readBatch([]int{0}, []uint32{0x10})
+		require.ErrorContains(t, err, "is closed")
+		require.Nil(t, values)
+	})
+}
+
+func TestMsrDataWithStorageMsrSafe(t *testing.T) {
+	offsets := []uint32{0x10, 0xE7}
+	allowlist := "0x00000010 0x0000000000000000\n0x000000E7 0x0000000000000000\n0x00000620 0x0000000000007F7F\n"
+
+	t.Run("AllowlistNotExist", func(t *testing.T) {
+		basePath := t.TempDir()
+		_, batchPath := writeMsrSafeTree(t, basePath, []int{0}, allowlist)
+
+		m := &msrDataWithStorage{
+			msrPath:       basePath,
+			msrOffsets:    offsets,
+			msrSafe:       true,
+			allowlistPath: "/dummy/path",
+			batchPath:     batchPath,
+		}
+
+		require.ErrorContains(t, m.initMsrMap(nil, 0), "error reading msr_safe allowlist")
+		require.Nil(t, m.msrMap)
+	})
+
+	t.Run("OffsetNotAllowed", func(t *testing.T) {
+		basePath := t.TempDir()
+		allowlistPath, batchPath := writeMsrSafeTree(t, basePath, []int{0}, "0x00000010 0x0\n")
+
+		m := &msrDataWithStorage{
+			msrPath:       basePath,
+			msrOffsets:    offsets,
+			msrSafe:       true,
+			allowlistPath: allowlistPath,
+			batchPath:     batchPath,
+		}
+
+		err := m.initMsrMap(nil, 0)
+		require.ErrorContains(t, err, "MSR offset 0xE7 is not allowed by msr_safe allowlist")
+		require.Nil(t, m.msrMap)
+	})
+
+	t.Run("MsrSafeFilesNotExist", func(t *testing.T) {
+		m := &msrDataWithStorage{
+			msrPath:       "testdata/cpu-msr",
+			msrOffsets:    offsets,
+			msrSafe:       true,
+			allowlistPath: filepath.Join(t.TempDir(), "msr_allowlist"),
+		}
+		require.NoError(t, os.WriteFile(m.allowlistPath, []byte(allowlist), 0600))
+
+		err := m.initMsrMap(nil, 0)
+		require.ErrorContains(t, err, "msr_safe\" does not exist")
+		require.Nil(t, m.msrMap)
+	})
+
+	t.Run("WithoutBatch", func(t *testing.T) {
+		basePath := t.TempDir()
+		allowlistPath, _ := writeMsrSafeTree(t, basePath, []int{0, 1}, allowlist)
+
+		m := &msrDataWithStorage{
+			msrPath:       basePath,
+			msrOffsets:    offsets,
+			msrSafe:       true,
+			allowlistPath: allowlistPath,
+			batchPath:     "/dummy/path",
+		}
+
+		m.enableWrites()
+		require.NoError(t, m.initMsrMap(nil, 0))
+		defer m.close()
+		require.Nil(t, m.batch)
+		require.Len(t, m.msrMap, 2)
+
+		require.NoError(t, m.update(0))
+		require.False(t, m.isBatchAvailable())
+
+		out, err := m.read(0x10, 1)
+		require.NoError(t, err)
+		require.Zero(t, out)
+
+		_, err = m.read(0x1A2, 1)
+		require.ErrorContains(t, err, "MSR offset 0x1A2 is not allowed by msr_safe allowlist")
+
+		err = m.write(0xE7, 1, 0x1)
+		require.ErrorContains(t, err, "MSR offset 0xE7 is not writable according to msr_safe allowlist")
+
+		require.NoError(t, m.write(0x620, 1, 0x1))
+	})
+
+	t.Run("WithBatch", func(t *testing.T) {
+		setFakeMsrBatchIoctl(t, 0)
+		basePath := t.TempDir()
+		allowlistPath, batchPath := writeMsrSafeTree(t, basePath, []int{0, 1}, allowlist)
+
+		m := &msrDataWithStorage{
+			msrPath:       basePath,
+			msrOffsets:    offsets,
+			msrSafe:       true,
+			allowlistPath: allowlistPath,
+			batchPath:     batchPath,
+		}
+
+		require.NoError(t, m.initMsrMap(nil, 0))
+		require.NotNil(t, m.batch)
+		require.True(t, m.isBatchAvailable())
+
+		require.NoError(t, m.update(1))
+		deltas, err := m.getOffsetDeltas(1)
+		require.NoError(t, err)
+		require.Equal(t, map[uint32]uint64{0x10: 0x1_0000_0010, 0xE7: 0x1_0000_00E7}, deltas)
+
+		deltas, err = m.getOffsetDeltas(0)
+		require.NoError(t, err)
+		require.Equal(t, map[uint32]uint64{0x10: 0, 0xE7: 0}, deltas)
+
+		require.Empty(t, m.updateAll())
+		deltas, err = m.getOffsetDeltas(0)
+		require.NoError(t, err)
+		require.Equal(t, map[uint32]uint64{0x10: 0x10, 0xE7: 0xE7}, deltas)
+
+		require.NoError(t, m.close())
+		require.ErrorContains(t, m.update(0), "is closed")
+	})
+}
+
+func TestMsrDataWithStorageUpdateAll(t *testing.T) {
+	t.Run("WithoutBatch", func(t *testing.T) {
+		mReg := &msrRegMock{}
+
+		m := &msrDataWithStorage{
+			msrOffsets: []uint32{0x10},
+			msrMap: map[int]msrRegWithStorage{
+				0: &msrWithStorage{msrReg: mReg, offsets: []uint32{0x10}, offsetValues: map[uint32]uint64{}},
+				1: &msrWithStorage{msrReg: mReg, offsets: []uint32{0x10}, offsetValues: map[uint32]uint64{}},
+			},
+		}
+
+		errs := m.updateAll()
+		require.Len(t, errs, 2)
+		require.ErrorContains(t, errs[0], "msr_safe batch device is not available")
+		require.ErrorContains(t, errs[1], "msr_safe batch device is not available")
+		mReg.AssertNotCalled(t, "readAll", mock.Anything)
+	})
+
+	t.Run("FailedBatch", func(t *testing.T) {
+		mBatch := &msrBatchMock{}
+		mBatch.On("readBatch", []int{0, 1}, []uint32{0x10}).Return(nil, errors.New("mock error")).Once()
+
+		m := &msrDataWithStorage{
+			msrOffsets: []uint32{0x10},
+			msrMap: map[int]msrRegWithStorage{
+				0: &msrWithStorage{},
+				1: &msrWithStorage{},
+			},
+			batch: mBatch,
+		}
+
+		errs := m.updateAll()
+		require.Len(t, errs, 2)
+		require.ErrorContains(t, errs[0], "mock error")
+		require.ErrorContains(t, errs[1], "mock error")
+		mBatch.AssertExpectations(t)
+	})
+
+	t.Run("SingleIoctl", func(t *testing.T) {
+		setFakeMsrBatchIoctl(t, 0)
+
+		orig := msrBatchIoctlFn
+		ioctls := 0
+		msrBatchIoctlFn = func(fd uintptr, batch *msrBatchArray) error {
+			ioctls++
+			return orig(fd, batch)
+		}
+
+		cpuIDs := []int{0, 1, 2, 3, 4, 5, 6, 7}
+		basePath := t.TempDir()
+		allowlistPath, batchPath := writeMsrSafeTree(t, basePath, cpuIDs, "# MSR # Write Mask\n0x00000010 0x0000000000000000\n")
+
+		m := &msrDataWithStorage{
+			msrPath:       basePath,
+			msrOffsets:    []uint32{0x10},
+			msrSafe:       true,
+			allowlistPath: allowlistPath,
+			batchPath:     batchPath,
+		}
+		require.NoError(t, m.initMsrMap(nil, 0))
+		defer m.close()
+		require.NotNil(t, m.batch)
+
+		// offsets of all CPU IDs are read with exactly one ioctl.
+		ioctls = 0
+		require.Empty(t, m.updateAll())
+		require.Equal(t, 1, ioctls)
+
+		for _, cpuID := range cpuIDs {
+			deltas, err := m.getOffsetDeltas(cpuID)
+			require.NoError(t, err)
+			require.Equal(t, map[uint32]uint64{0x10: uint64(cpuID)<<32 | 0x10}, deltas)
+		}
+	})
+}
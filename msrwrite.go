@@ -0,0 +1,300 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// path to the parameter of msr kernel module which controls write operations to MSRs.
+	defaultMsrAllowWritesPath = "/sys/module/msr/parameters/allow_writes"
+
+	// value of allow_writes parameter which rejects write operations to MSRs.
+	msrAllowWritesOff = "off"
+)
+
+// msrJournalEntry represents a write operation to an MSR offset of a CPU ID, recorded in the MSR write
+// journal prior to the operation.
+type msrJournalEntry struct {
+	CPUID    int       `json:"cpu_id"`
+	Offset   uint32    `json:"offset"`
+	Mask     uint64    `json:"mask"`
+	Original uint64    `json:"original"` // value of the MSR offset before the write operation
+	Value    uint64    `json:"value"`    // value written to the MSR offset
+	Time     time.Time `json:"time"`
+}
+
+// msrJournal represents an append-only file which records write operations to MSR offsets, one JSON
+// entry per line. Entries are synced to disk before the write operation is performed, so that the
+// original values can be restored after a crash.
+type msrJournal struct {
+	path string
+	file *os.File
+}
+
+// openMsrJournal takes the path of the journal file and returns the journal, creating the file if it does not exist.
+func openMsrJournal(path string) (*msrJournal, error) {
+	if len(path) == 0 {
+		return nil, errors.New("path of MSR write journal cannot be empty")
+	}
+	if ok, _ := fileExists(path); ok {
+		if err := checkFile(path); err != nil {
+			return nil, fmt.Errorf("invalid MSR write journal: %w", err)
+		}
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("error opening MSR write journal: %w", err)
+	}
+	return &msrJournal{
+		path: path,
+		file: f,
+	}, nil
+}
+
+// record takes a journal entry and appends it to the journal, syncing the file to disk.
+func (j *msrJournal) record(entry msrJournalEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error encoding MSR write journal entry: %w", err)
+	}
+	if _, err := j.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("error writing MSR write journal %q: %w", j.path, err)
+	}
+	if err := j.file.Sync(); err != nil {
+		return fmt.Errorf("error syncing MSR write journal %q: %w", j.path, err)
+	}
+	return nil
+}
+
+// entries returns the entries of the journal, in the order they were recorded.
+func (j *msrJournal) entries() ([]msrJournalEntry, error) {
+	content, err := os.ReadFile(j.path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading MSR write journal %q: %w", j.path, err)
+	}
+
+	entries := make([]msrJournalEntry, 0)
+	scanner := bufio.NewScanner(strings.NewReader(string(content)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 {
+			continue
+		}
+		var entry msrJournalEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("invalid MSR write journal entry %q: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error parsing MSR write journal %q: %w", j.path, err)
+	}
+	return entries, nil
+}
+
+// clear removes all entries of the journal.
+func (j *msrJournal) clear() error {
+	if err := j.file.Truncate(0); err != nil {
+		return fmt.Errorf("error clearing MSR write journal %q: %w", j.path, err)
+	}
+	if err := j.file.Sync(); err != nil {
+		return fmt.Errorf("error syncing MSR write journal %q: %w", j.path, err)
+	}
+	return nil
+}
+
+// close releases the descriptor of the journal file.
+func (j *msrJournal) close() error {
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	if err != nil {
+		return fmt.Errorf("error closing MSR write journal %q: %w", j.path, err)
+	}
+	return nil
+}
+
+// msrWriteGuard guards write operations to MSR offsets. Write operations are allowed only if msr kernel
+// module allows them, and only to the bits of the offsets specified by the allowlist. Every write
+// operation is recorded in the journal.
+type msrWriteGuard struct {
+	allowWritesPath string
+	allowlist       map[uint32]uint64 // allowed offset key and mask of writable bits value
+	journalPath     string
+
+	mu      sync.Mutex
+	journal *msrJournal
+}
+
+// init opens the journal of the receiver. If the journal holds entries of a previous session, e.g. after a crash,
+// they are kept so that they can be replayed.
+func (g *msrWriteGuard) init() error {
+	if len(g.allowlist) == 0 {
+		return errors.New("allowlist of MSR writes cannot be empty")
+	}
+	journal, err := openMsrJournal(g.journalPath)
+	if err != nil {
+		return err
+	}
+	g.journal = journal
+	return nil
+}
+
+// checkAllowed takes an offset and a mask of the bits to be written, and returns an *MsrWriteNotAllowedError
+// if the write operation is not allowed by msr kernel module or by the allowlist of the receiver.
+func (g *msrWriteGuard) checkAllowed(offset uint32, mask uint64) error {
+	if mask == 0 {
+		return &MsrWriteNotAllowedError{Offset: offset, reason: "mask cannot be zero"}
+	}
+	writable, ok := g.allowlist[offset]
+	if !ok {
+		return &MsrWriteNotAllowedError{Offset: offset, reason: "offset is not in the allowlist"}
+	}
+	if mask&^writable != 0 {
+		return &MsrWriteNotAllowedError{
+			Offset: offset,
+			reason: fmt.Sprintf("mask 0x%X exceeds writable bits 0x%X of the allowlist", mask, writable),
+		}
+	}
+	return g.checkAllowWrites(offset)
+}
+
+// checkAllowWrites takes an offset and returns an *MsrWriteNotAllowedError if allow_writes parameter of msr
+// kernel module rejects write operations. If the parameter is not exposed, as for kernels prior to 5.9,
+// write operations are allowed.
+func (g *msrWriteGuard) checkAllowWrites(offset uint32) error {
+	content, err := os.ReadFile(g.allowWritesPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("error reading msr allow_writes parameter: %w", err)
+	}
+	if strings.TrimSpace(string(content)) == msrAllowWritesOff {
+		return &MsrWriteNotAllowedError{Offset: offset, reason: "msr kernel module does not allow writes"}
+	}
+	return nil
+}
+
+// write takes an msr reader, a CPU ID, an offset, a value and a mask of the bits to be written. If the write operation
+// is allowed, it reads the current offset value, replaces the bits specified by the mask with the corresponding bits
+// of the value, records the operation in the journal, writes the resulting value and verifies it by reading it back.
+func (g *msrWriteGuard) write(msr msrReaderWithStorage, cpuID int, offset uint32, value, mask uint64) error {
+	if err := g.checkAllowed(offset, mask); err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	original, err := msr.read(offset, cpuID)
+	if err != nil {
+		return fmt.Errorf("error reading MSR offset 0x%X for CPU ID %v: %w", offset, cpuID, err)
+	}
+	updated := original&^mask | value&mask
+	if updated == original {
+		return nil
+	}
+
+	entry := msrJournalEntry{
+		CPUID:    cpuID,
+		Offset:   offset,
+		Mask:     mask,
+		Original: original,
+		Value:    updated,
+		Time:     timeNowFn(),
+	}
+	if err := g.journal.record(entry); err != nil {
+		return err
+	}
+	return writeAndVerify(msr, cpuID, offset, updated, mask)
+}
+
+// original takes a CPU ID and an offset, and returns the value the offset had before the first write operation
+// recorded in the journal, e.g. by a previous session which did not restore it. The boolean is false if the journal
+// holds no entry for the given CPU ID and offset.
+func (g *msrWriteGuard) original(cpuID int, offset uint32) (uint64, bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	entries, err := g.journal.entries()
+	if err != nil {
+		return 0, false, err
+	}
+	for _, entry := range entries {
+		if entry.CPUID == cpuID && entry.Offset == offset {
+			return entry.Original, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// replay takes an msr reader and restores the original values of the bits written to the offsets recorded in the journal,
+// in reverse order of the write operations, so that each offset ends up with the value it had before the first recorded
+// write. Once all values are restored, the journal is cleared. Otherwise, the journal is kept and an error is returned.
+func (g *msrWriteGuard) replay(msr msrReaderWithStorage) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	entries, err := g.journal.entries()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		current, err := msr.read(entry.Offset, entry.CPUID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error reading MSR offset 0x%X for CPU ID %v: %w", entry.Offset, entry.CPUID, err))
+			continue
+		}
+		restored := current&^entry.Mask | entry.Original&entry.Mask
+		if err := writeAndVerify(msr, entry.CPUID, entry.Offset, restored, entry.Mask); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return g.journal.clear()
+}
+
+// writeAndVerify takes an msr reader, a CPU ID, an offset, a value and a mask. It writes the value to the offset and reads
+// it back to verify that the bits specified by the mask hold the written value.
+func writeAndVerify(msr msrReaderWithStorage, cpuID int, offset uint32, value, mask uint64) error {
+	if err := msr.write(offset, cpuID, value); err != nil {
+		return fmt.Errorf("error writing MSR offset 0x%X for CPU ID %v: %w", offset, cpuID, err)
+	}
+	readBack, err := msr.read(offset, cpuID)
+	if err != nil {
+		return fmt.Errorf("error reading back MSR offset 0x%X for CPU ID %v: %w", offset, cpuID, err)
+	}
+	if readBack&mask != value&mask {
+		return fmt.Errorf("verification of MSR offset 0x%X for CPU ID %v failed, written: 0x%X, read back: 0x%X", offset, cpuID, value&mask, readBack&mask)
+	}
+	return nil
+}
+
+// close releases the journal of the receiver.
+func (g *msrWriteGuard) close() error {
+	if g.journal == nil {
+		return nil
+	}
+	return g.journal.close()
+}
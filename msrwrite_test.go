@@ -0,0 +1,344 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestMsrWriteGuard takes an allowlist and returns an msrWriteGuard with a journal in a temporary directory,
+// and the path of a file holding allow_writes parameter set to the given value.
+func newTestMsrWriteGuard(t *testing.T, allowlist map[uint32]uint64, allowWrites string) *msrWriteGuard {
+	t.Helper()
+
+	dir := t.TempDir()
+	allowWritesPath := filepath.Join(dir, "allow_writes")
+	require.NoError(t, os.WriteFile(allowWritesPath, []byte(allowWrites+"\n"), 0640))
+
+	g := &msrWriteGuard{
+		allowWritesPath: allowWritesPath,
+		allowlist:       allowlist,
+		journalPath:     filepath.Join(dir, "msr_journal"),
+	}
+	require.NoError(t, g.init())
+	t.Cleanup(func() {
+		require.NoError(t, g.close())
+	})
+	return g
+}
+
+func TestMsrJournal(t *testing.T) {
+	setFakeClock()
+	defer unsetFakeClock()
+	fakeClock.Set(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	t.Run("EmptyPath", func(t *testing.T) {
+		j, err := openMsrJournal("")
+		require.ErrorContains(t, err, "path of MSR write journal cannot be empty")
+		require.Nil(t, j)
+	})
+
+	t.Run("Symlink", func(t *testing.T) {
+		dir := t.TempDir()
+		target := filepath.Join(dir, "target")
+		require.NoError(t, os.WriteFile(target, nil, 0600))
+		path := filepath.Join(dir, "journal")
+		require.NoError(t, os.Symlink(target, path))
+
+		j, err := openMsrJournal(path)
+		require.ErrorContains(t, err, "invalid MSR write journal")
+		require.Nil(t, j)
+	})
+
+	t.Run("InvalidEntry", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal")
+		require.NoError(t, os.WriteFile(path, []byte("invalid\n"), 0600))
+
+		j, err := openMsrJournal(path)
+		require.NoError(t, err)
+		defer j.close()
+
+		entries, err := j.entries()
+		require.ErrorContains(t, err, "invalid MSR write journal entry \"invalid\"")
+		require.Nil(t, entries)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal")
+
+		j, err := openMsrJournal(path)
+		require.NoError(t, err)
+
+		entries, err := j.entries()
+		require.NoError(t, err)
+		require.Empty(t, entries)
+
+		expected := []msrJournalEntry{
+			{CPUID: 0, Offset: 0x1A0, Mask: 0x1, Original: 0x0, Value: 0x1, Time: fakeClock.Now()},
+			{CPUID: 1, Offset: 0x1A0, Mask: 0x1, Original: 0x1, Value: 0x0, Time: fakeClock.Now()},
+		}
+		for _, entry := range expected {
+			require.NoError(t, j.record(entry))
+		}
+		require.NoError(t, j.close())
+
+		// entries are kept across sessions.
+		j, err = openMsrJournal(path)
+		require.NoError(t, err)
+		defer j.close()
+
+		entries, err = j.entries()
+		require.NoError(t, err)
+		require.Equal(t, expected, entries)
+
+		require.NoError(t, j.clear())
+		entries, err = j.entries()
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+}
+
+func TestMsrWriteGuard_CheckAllowed(t *testing.T) {
+	allowlist := map[uint32]uint64{
+		0x1A0: 0x1,
+		0x620: 0x7F7F,
+	}
+
+	testCases := []struct {
+		name        string
+		allowWrites string
+		offset      uint32
+		mask        uint64
+		err         error
+	}{
+		{
+			name:        "ZeroMask",
+			allowWrites: "on",
+			offset:      0x1A0,
+			mask:        0,
+			err:         errors.New("write to MSR offset 0x1A0 is not allowed: mask cannot be zero"),
+		},
+		{
+			name:        "OffsetNotAllowed",
+			allowWrites: "on",
+			offset:      0x10,
+			mask:        0x1,
+			err:         errors.New("write to MSR offset 0x10 is not allowed: offset is not in the allowlist"),
+		},
+		{
+			name:        "MaskNotAllowed",
+			allowWrites: "on",
+			offset:      0x620,
+			mask:        0x8000,
+			err:         errors.New("write to MSR offset 0x620 is not allowed: mask 0x8000 exceeds writable bits 0x7F7F of the allowlist"),
+		},
+		{
+			name:        "WritesOff",
+			allowWrites: "off",
+			offset:      0x620,
+			mask:        0x7F,
+			err:         errors.New("write to MSR offset 0x620 is not allowed: msr kernel module does not allow writes"),
+		},
+		{
+			name:        "WritesOn",
+			allowWrites: "on",
+			offset:      0x620,
+			mask:        0x7F,
+		},
+		{
+			name:        "WritesDefault",
+			allowWrites: "default",
+			offset:      0x620,
+			mask:        0x7F00,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := newTestMsrWriteGuard(t, allowlist, tc.allowWrites)
+
+			err := g.checkAllowed(tc.offset, tc.mask)
+			if tc.err != nil {
+				var notAllowedErr *MsrWriteNotAllowedError
+				require.ErrorAs(t, err, &notAllowedErr)
+				require.Equal(t, tc.offset, notAllowedErr.Offset)
+				require.EqualError(t, err, tc.err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("AllowWritesNotExposed", func(t *testing.T) {
+		g := newTestMsrWriteGuard(t, allowlist, "on")
+		g.allowWritesPath = filepath.Join(t.TempDir(), "not-exist")
+
+		require.NoError(t, g.checkAllowed(0x1A0, 0x1))
+	})
+}
+
+func TestMsrWriteGuard_Write(t *testing.T) {
+	setFakeClock()
+	defer unsetFakeClock()
+	fakeClock.Set(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	cpuID := 2
+	offset := uint32(0x620)
+	allowlist := map[uint32]uint64{offset: 0x7F7F}
+
+	t.Run("NotAllowed", func(t *testing.T) {
+		g := newTestMsrWriteGuard(t, allowlist, "off")
+		m := &msrMock{}
+
+		err := g.write(m, cpuID, offset, 0x10, 0x7F)
+		var notAllowedErr *MsrWriteNotAllowedError
+		require.ErrorAs(t, err, &notAllowedErr)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("FailedToRead", func(t *testing.T) {
+		g := newTestMsrWriteGuard(t, allowlist, "on")
+		m := &msrMock{}
+		m.On("read", offset, cpuID).Return(uint64(0), errors.New("mock error")).Once()
+
+		err := g.write(m, cpuID, offset, 0x10, 0x7F)
+		require.ErrorContains(t, err, "error reading MSR offset 0x620 for CPU ID 2: mock error")
+		m.AssertExpectations(t)
+	})
+
+	t.Run("Unchanged", func(t *testing.T) {
+		g := newTestMsrWriteGuard(t, allowlist, "on")
+		m := &msrMock{}
+		m.On("read", offset, cpuID).Return(uint64(0xFF0010), nil).Once()
+
+		require.NoError(t, g.write(m, cpuID, offset, 0x10, 0x7F))
+
+		entries, err := g.journal.entries()
+		require.NoError(t, err)
+		require.Empty(t, entries)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("VerificationFailed", func(t *testing.T) {
+		g := newTestMsrWriteGuard(t, allowlist, "on")
+		m := &msrMock{}
+		m.On("read", offset, cpuID).Return(uint64(0xFF0C18), nil).Once()
+		m.On("write", offset, cpuID, uint64(0xFF0C10)).Return(nil).Once()
+		m.On("read", offset, cpuID).Return(uint64(0xFF0C18), nil).Once()
+
+		err := g.write(m, cpuID, offset, 0x10, 0x7F)
+		require.ErrorContains(t, err, "verification of MSR offset 0x620 for CPU ID 2 failed, written: 0x10, read back: 0x18")
+
+		// the operation is recorded even if it failed, so that the original value can be restored.
+		entries, err := g.journal.entries()
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		g := newTestMsrWriteGuard(t, allowlist, "on")
+		m := &msrMock{}
+		m.On("read", offset, cpuID).Return(uint64(0xFF0C18), nil).Once()
+		m.On("write", offset, cpuID, uint64(0xFF0C10)).Return(nil).Once()
+		m.On("read", offset, cpuID).Return(uint64(0xFF0C10), nil).Once()
+
+		require.NoError(t, g.write(m, cpuID, offset, 0xFFFF_FF10, 0x7F))
+
+		entries, err := g.journal.entries()
+		require.NoError(t, err)
+		require.Equal(t, []msrJournalEntry{
+			{CPUID: cpuID, Offset: offset, Mask: 0x7F, Original: 0xFF0C18, Value: 0xFF0C10, Time: fakeClock.Now()},
+		}, entries)
+		m.AssertExpectations(t)
+	})
+}
+
+func TestMsrWriteGuard_Original(t *testing.T) {
+	setFakeClock()
+	defer unsetFakeClock()
+
+	g := newTestMsrWriteGuard(t, map[uint32]uint64{0x620: 0x7F7F}, "on")
+
+	t.Run("EmptyJournal", func(t *testing.T) {
+		_, ok, err := g.original(0, 0x620)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("FirstEntry", func(t *testing.T) {
+		require.NoError(t, g.journal.record(msrJournalEntry{CPUID: 1, Offset: 0x620, Original: 0x0A14, Value: 0x0C12}))
+		require.NoError(t, g.journal.record(msrJournalEntry{CPUID: 0, Offset: 0x620, Original: 0x0818, Value: 0x0A14}))
+		require.NoError(t, g.journal.record(msrJournalEntry{CPUID: 0, Offset: 0x620, Original: 0x0A14, Value: 0x0C12}))
+
+		original, ok, err := g.original(0, 0x620)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, uint64(0x0818), original)
+
+		_, ok, err = g.original(0, 0x1A0)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func TestMsrWriteGuard_Replay(t *testing.T) {
+	offset := uint32(0x620)
+	allowlist := map[uint32]uint64{offset: 0x7F7F}
+
+	entries := []msrJournalEntry{
+		{CPUID: 0, Offset: offset, Mask: 0x7F, Original: 0x0C18, Value: 0x0C10},
+		{CPUID: 0, Offset: offset, Mask: 0x7F00, Original: 0x0C10, Value: 0x0810},
+	}
+
+	t.Run("FailedToRestore", func(t *testing.T) {
+		g := newTestMsrWriteGuard(t, allowlist, "on")
+		for _, entry := range entries {
+			require.NoError(t, g.journal.record(entry))
+		}
+
+		m := &msrMock{}
+		m.On("read", offset, 0).Return(uint64(0), errors.New("mock error")).Twice()
+
+		err := g.replay(m)
+		require.ErrorContains(t, err, "error reading MSR offset 0x620 for CPU ID 0: mock error")
+
+		// journal is kept, so that the replay can be retried.
+		journal, err := g.journal.entries()
+		require.NoError(t, err)
+		require.Len(t, journal, len(entries))
+		m.AssertExpectations(t)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		g := newTestMsrWriteGuard(t, allowlist, "on")
+		for _, entry := range entries {
+			require.NoError(t, g.journal.record(entry))
+		}
+
+		m := &msrMock{}
+		// restore the latest write first, then the earliest one.
+		m.On("read", offset, 0).Return(uint64(0xF0810), nil).Once()
+		m.On("write", offset, 0, uint64(0xF0C10)).Return(nil).Once()
+		m.On("read", offset, 0).Return(uint64(0xF0C10), nil).Once()
+		m.On("read", offset, 0).Return(uint64(0xF0C10), nil).Once()
+		m.On("write", offset, 0, uint64(0xF0C18)).Return(nil).Once()
+		m.On("read", offset, 0).Return(uint64(0xF0C18), nil).Once()
+
+		require.NoError(t, g.replay(m))
+
+		journal, err := g.journal.entries()
+		require.NoError(t, err)
+		require.Empty(t, journal)
+		m.AssertExpectations(t)
+	})
+}
@@ -11,12 +11,15 @@ import (
 	"fmt"
 	"math/big"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"syscall"
 
 	ev "github.com/intel/iaevents"
 	"golang.org/x/sync/errgroup"
+
+	"github.com/intel/powertelemetry/internal/cpuid"
 )
 
 // File path which monitors the maximum number of file-handles that the Linux
@@ -133,13 +136,79 @@ func (t c0StateType) String() string {
 	}
 }
 
+// PerfEventValue represents the values of a perf event read for a CPU ID.
+type PerfEventValue struct {
+	Raw     uint64 // Raw counter value of the event
+	Enabled uint64 // Time the event was enabled, in nanoseconds
+	Running uint64 // Time the event was counting, in nanoseconds
+	Scaled  uint64 // Raw value scaled by the ratio of enabled and running times
+	Delta   uint64 // Scaled increase of the counter value between the two latest reads, zero after the first read
+
+	// Fraction of the enabled time the event was counting, between the two latest reads or since activation after
+	// the first read. It is lower than 1 if the event was multiplexed with other events.
+	MultiplexRatio float64
+	// True if MultiplexRatio is lower than LowConfidenceMultiplexRatio, hence scaled values are estimated from
+	// a small sample of the interval and should be treated with caution.
+	LowConfidence bool
+
+	CoreType CoreType // Core type of the CPU ID on hybrid processors, empty otherwise
+}
+
+// LowConfidenceMultiplexRatio is the multiplexing ratio below which perf event values are flagged as low confidence.
+const LowConfidenceMultiplexRatio = 0.5
+
+// PerfMultiplexing represents the multiplexing information of the perf events of a CPU ID, between the two latest reads
+// or since activation after the first read.
+type PerfMultiplexing struct {
+	MinRatio      float64 // Lowest multiplexing ratio among the events of the CPU ID
+	MeanRatio     float64 // Mean multiplexing ratio of the events of the CPU ID
+	LowConfidence bool    // True if any of the events of the CPU ID is flagged as low confidence
+
+	LowConfidenceEvents []string // Names of the events flagged as low confidence
+}
+
+// Event names of architectural fixed-function counters, other than CPU_CLK_UNHALTED.THREAD.
+const (
+	instRetired = "INST_RETIRED.ANY"         // instructions retired
+	refCycles   = "CPU_CLK_UNHALTED.REF_TSC" // unhalted cycles at the TSC rate
+)
+
+// Event names of the perf metrics feature, which provides level 1 of Top-down Microarchitecture Analysis (TMA).
+// Topdown metric events count the pipeline slots attributed to each category, and they can only be activated
+// within a group led by the slots event.
+const (
+	topdownSlots          = "TOPDOWN.SLOTS"
+	topdownRetiring       = "PERF_METRICS.RETIRING"
+	topdownBadSpeculation = "PERF_METRICS.BAD_SPECULATION"
+	topdownFrontendBound  = "PERF_METRICS.FRONTEND_BOUND"
+	topdownBackendBound   = "PERF_METRICS.BACKEND_BOUND"
+)
+
+var (
+	// archFixedCounters points to a function that returns the number of architectural fixed-function counters.
+	archFixedCounters = cpuid.ArchPerfmonFixedCounters
+
+	// archGeneralCounters points to a function that returns the number of general-purpose counters per CPU.
+	archGeneralCounters = cpuid.ArchPerfmonGeneralCounters
+
+	// cpuPerfmonCounters points to a function that takes a CPU ID and returns the number of general-purpose and
+	// architectural fixed-function counters of that CPU.
+	cpuPerfmonCounters = cpuid.ArchPerfmonCountersOnCPU
+
+	// tscFrequency points to a function that returns the nominal frequency of the time stamp counter, in Hz.
+	tscFrequency = cpuid.TSCFrequency
+)
+
 // coreMetric represents the values of a core event read at a specific time instant.
 type coreMetric struct {
 	name  string
 	cpuID int
 
-	values ev.CounterValue
-	scaled uint64
+	values         ev.CounterValue
+	scaled         uint64
+	delta          uint64
+	multiplexRatio float64  // fraction of the enabled time the event was counting, within the latest interval
+	coreType       CoreType // core type of the CPU ID on hybrid processors, empty otherwise
 }
 
 // eventsResolver resolves event names, from a core event group, to custom events which
@@ -208,9 +277,20 @@ func (*placementMakerImpl) makeCorePlacement(cpuIDs []int, factory ev.PlacementF
 	var cpuPlacements []ev.PlacementProvider
 	var err error
 
-	switch len(cpuIDs) {
-	case 0:
+	if len(cpuIDs) == 0 {
 		return nil, errors.New("no CPU IDs were provided")
+	}
+
+	// core events of hybrid processors belong to the core PMU of their core type, instead of the default core PMU
+	if event, ok := factory.(*ev.PerfEvent); ok && len(coreTypeFromPMU(event.PMUName)) != 0 {
+		cpuPlacements, err = factory.NewPlacements(event.PMUName, cpuIDs[0], cpuIDs[1:]...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create core placements for PMU %q: %w", event.PMUName, err)
+		}
+		return cpuPlacements, nil
+	}
+
+	switch len(cpuIDs) {
 	case 1:
 		cpuPlacements, err = ev.NewCorePlacements(factory, cpuIDs[0])
 		if err != nil {
@@ -331,9 +411,10 @@ func (r *eventsReaderImpl) readEvents(events []*ev.ActiveEvent) ([]coreMetric, e
 
 			cpu, _ := activeEvent.PMUPlacement()
 			metrics[index] = coreMetric{
-				values: values,
-				cpuID:  cpu,
-				name:   activeEvent.PerfEvent.Name,
+				values:   values,
+				cpuID:    cpu,
+				name:     activeEvent.PerfEvent.Name,
+				coreType: coreTypeFromPMU(activeEvent.PerfEvent.PMUName),
 			}
 			return nil
 		})
@@ -398,11 +479,15 @@ func (d *eventsDeactivatorImpl) deactivateEvents(events []*ev.ActiveEvent) ([]*e
 type perfReader interface {
 	initResolver(jsonFile string) error
 
+	initHybridResolvers(jsonFiles map[CoreType]string) error
+
 	activate(events []string, cores []int) error
 
 	read() ([]coreMetric, error)
 
 	deactivate() error
+
+	activeCPUIDs() []int
 }
 
 // perf implements perfReader interface. It keeps track of the current active events.
@@ -412,8 +497,11 @@ type perf struct {
 	deactivator    eventsDeactivator
 	valuesReader   eventsReader
 	fileInfoReader fileInfoProvider
+	pmuPath        string // base path of PMUs exposed by `perf_events` kernel interface
 
+	hybridPMUs   []*hybridPMU // core PMUs of hybrid processors, one per core type
 	activeEvents []*ev.ActiveEvent
+	activeCPUs   []int // CPU IDs the active events were activated for, in ascending order
 }
 
 // newPerf takes a path string, corresponding to a JSON file which comprises processor model
@@ -427,39 +515,60 @@ func newPerf() perfReader {
 		deactivator:    &eventsDeactivatorImpl{&eventDeactivatorImpl{}},
 		valuesReader:   &eventsReaderImpl{&valuesReaderImpl{}},
 		fileInfoReader: &fsHelper{},
+		pmuPath:        pmuDevicesPath,
 	}
 }
 
 func (p *perf) initResolver(jsonFile string) error {
-	reader := ev.NewFilesReader()
-	if err := reader.AddFiles(jsonFile); err != nil {
-		return fmt.Errorf("error adding file to reader: %w", err)
+	var err error
+	p.resolver, err = newEventsResolver(jsonFile)
+	return err
+}
+
+// newEventsResolver takes a path string, corresponding to a JSON file which comprises processor model
+// specific events, and returns an eventsResolver for the events of the file.
+func newEventsResolver(jsonFile string) (eventsResolver, error) {
+	reader, err := newEventsReader(jsonFile)
+	if err != nil {
+		return nil, err
 	}
 
-	p.resolver = &eventsResolverImpl{
+	return &eventsResolverImpl{
 		reader:      reader,
 		transformer: ev.NewPerfTransformer(),
-	}
-	return nil
+	}, nil
+}
+
+// activationTarget represents custom events to be activated on a set of cores, together with the number of
+// general-purpose and fixed-function counters of those cores, which the event groups must fit.
+type activationTarget struct {
+	events          []ev.CustomizableEvent
+	cores           []int
+	generalCounters int
+	fixedCounters   int
 }
 
 // activate takes a slice of core event names and cores. It resolves the given event
-// names into perf events and activates them. If number of file descriptors needed to
-// read the events it returns an error.
+// names into perf events and activates them. On hybrid processors, events are resolved
+// and activated separately for the cores of each core type. If number of file descriptors
+// needed to read the events exceeds the limits it returns an error. If a group of events
+// fails to be activated, the events activated so far are deactivated.
 // TODO: Do not receive events from arguments.
 func (p *perf) activate(events []string, cores []int) error {
 	// resolve
-	customEvents, err := p.resolver.resolveEvents(events)
+	targets, err := p.resolveTargets(events, cores)
 	if err != nil {
 		return fmt.Errorf("error resolving event: %w", err)
 	}
 
 	// calculate file descriptors needed to access all events
-	numEvents := uint64(len(customEvents))
-	numCores := uint64(len(cores))
-	fd, err := multiply(numEvents, numCores)
-	if err != nil {
-		return err
+	fd := uint64(0)
+	for _, target := range targets {
+		targetFd, err := multiply(uint64(len(target.events)), uint64(len(target.cores)))
+		if err != nil {
+			return err
+		}
+		fd += targetFd
 	}
 
 	// check maximum allowed number of file descriptors
@@ -468,19 +577,163 @@ func (p *perf) activate(events []string, cores []int) error {
 		return fmt.Errorf("error checking available file descriptors: %w", err)
 	}
 
+	// group
+	targetGroups := make([][][]ev.CustomizableEvent, len(targets))
+	for i, target := range targets {
+		targetGroups[i], err = groupEvents(target.events, target.generalCounters, target.fixedCounters)
+		if err != nil {
+			return err
+		}
+	}
+
 	// activate
-	p.activeEvents, err = p.activator.activateEvents(customEvents, cores)
-	if err != nil {
-		return fmt.Errorf("error during event activation: %w", err)
+	p.activeEvents = make([]*ev.ActiveEvent, 0, fd)
+	p.activeCPUs = nil
+	activeCPUs := make([]int, 0, len(cores))
+	for i, groups := range targetGroups {
+		for _, group := range groups {
+			activeEvents, err := p.activator.activateEvents(group, targets[i].cores)
+			p.activeEvents = append(p.activeEvents, activeEvents...)
+			if err != nil {
+				err = fmt.Errorf("error during event activation: %w", err)
+				// Deactivate the events activated so far, so that no descriptors are left open. Events which
+				// could not be deactivated are kept.
+				if deactivateErr := p.deactivate(); deactivateErr != nil {
+					return errors.Join(err, fmt.Errorf("error during event deactivation: %w", deactivateErr))
+				}
+				return err
+			}
+		}
+		activeCPUs = append(activeCPUs, targets[i].cores...)
 	}
+	slices.Sort(activeCPUs)
+	p.activeCPUs = slices.Compact(activeCPUs)
 	return nil
 }
 
+// activeCPUIDs returns the CPU IDs, in ascending order, for which events were activated. On hybrid processors,
+// it comprises the CPU IDs of the core types events were resolved for only.
+func (p *perf) activeCPUIDs() []int {
+	return p.activeCPUs
+}
+
+// resolveTargets takes a slice of core event names and cores, and resolves the events into activation targets.
+// Unless the receiver has been initialized for a hybrid processor, there is a single target with all cores, whose
+// counters are the architectural counters of the processor.
+func (p *perf) resolveTargets(events []string, cores []int) ([]activationTarget, error) {
+	if len(p.hybridPMUs) != 0 {
+		return resolveHybridTargets(p.hybridPMUs, events, cores)
+	}
+
+	customEvents, err := p.resolver.resolveEvents(events)
+	if err != nil {
+		return nil, err
+	}
+	return []activationTarget{{
+		events:          customEvents,
+		cores:           cores,
+		generalCounters: archGeneralCounters(),
+		fixedCounters:   archFixedCounters(),
+	}}, nil
+}
+
+// groupEvents takes a slice of custom events and the number of general-purpose and fixed-function counters, and splits
+// the events into the groups to be activated. Topdown metric events are placed into a separate group led by the slots
+// event, while the remaining events are split into groups which fit the counters, see splitEvents. If topdown metric
+// events are given without the slots event, an error is returned.
+func groupEvents(customEvents []ev.CustomizableEvent, generalCounters, fixedCounters int) ([][]ev.CustomizableEvent, error) {
+	events := make([]ev.CustomizableEvent, 0, len(customEvents))
+	topdownEvents := make([]ev.CustomizableEvent, 0)
+	hasSlots := false
+	for _, event := range customEvents {
+		if event.Event == nil {
+			return nil, errors.New("invalid custom event")
+		}
+		switch {
+		case strings.EqualFold(event.Event.Name, topdownSlots):
+			// slots event leads the group of topdown metric events
+			topdownEvents = append([]ev.CustomizableEvent{event}, topdownEvents...)
+			hasSlots = true
+		case isTopdownMetricEvent(event.Event.Name):
+			topdownEvents = append(topdownEvents, event)
+		default:
+			events = append(events, event)
+		}
+	}
+
+	groups := make([][]ev.CustomizableEvent, 0, 2)
+	if len(events) != 0 {
+		groups = append(groups, splitEvents(events, generalCounters, fixedCounters)...)
+	}
+	if len(topdownEvents) != 0 {
+		if !hasSlots {
+			return nil, fmt.Errorf("topdown metric events require %q event", topdownSlots)
+		}
+		groups = append(groups, topdownEvents)
+	}
+	return groups, nil
+}
+
+// splitEvents takes a slice of custom events and the number of general-purpose and fixed-function counters, and splits
+// the events, in the given order, into groups which fit the counters, since a group is only counting when all of its
+// events are scheduled at once. Events of architectural fixed-function counters do not take a general-purpose counter
+// if the processor has the fixed-function counter. If the number of general-purpose counters is unknown, the events
+// form a single group.
+func splitEvents(events []ev.CustomizableEvent, generalCounters, fixedCounters int) [][]ev.CustomizableEvent {
+	if generalCounters <= 0 {
+		return [][]ev.CustomizableEvent{events}
+	}
+
+	groups := make([][]ev.CustomizableEvent, 0, 1)
+	group := make([]ev.CustomizableEvent, 0, len(events))
+	used := 0
+	for _, event := range events {
+		if isFixedCounterEvent(event.Event.Name, fixedCounters) {
+			group = append(group, event)
+			continue
+		}
+		if used == generalCounters {
+			groups = append(groups, group)
+			group = make([]ev.CustomizableEvent, 0, len(events))
+			used = 0
+		}
+		group = append(group, event)
+		used++
+	}
+	return append(groups, group)
+}
+
+// isFixedCounterEvent takes an event name and the number of fixed-function counters of the processor, and returns
+// true if the event is counted by one of the fixed-function counters.
+func isFixedCounterEvent(name string, fixedCounters int) bool {
+	// fixed-function counter index corresponds to the index of its event
+	for i, event := range fixedPerfEvents {
+		if strings.EqualFold(name, event) {
+			return i < fixedCounters
+		}
+	}
+	return false
+}
+
+// isTopdownMetricEvent returns true if the given event name corresponds to a topdown metric event of
+// the perf metrics feature.
+func isTopdownMetricEvent(name string) bool {
+	for _, event := range []string{topdownRetiring, topdownBadSpeculation, topdownFrontendBound, topdownBackendBound} {
+		if strings.EqualFold(name, event) {
+			return true
+		}
+	}
+	return false
+}
+
 // deactivate deactivates all active events. If an event or events could not
 // be successfully deactivated, an error is returned.
 func (p *perf) deactivate() error {
 	var err error
 	p.activeEvents, err = p.deactivator.deactivateEvents(p.activeEvents)
+	if len(p.activeEvents) == 0 {
+		p.activeCPUs = nil
+	}
 	return err
 }
 
@@ -511,21 +764,110 @@ type perfWithStorage struct {
 }
 
 // update reads values for active core events specified by the receiver. It updates the metrics
-// field with the latest values returned by read method and calculates scaled value of a metric.
+// field with the latest values returned by read method and calculates scaled value of a metric,
+// as well as its scaled increase since the previous call.
 func (p *perfWithStorage) update() error {
+	prev := p.metrics
+
 	var err error
 	p.metrics, err = p.read()
 	if err != nil {
 		return err
 	}
 
+	prevValues := make(map[coreMetricKey]ev.CounterValue, len(prev))
+	for _, metric := range prev {
+		prevValues[coreMetricKey{name: metric.name, cpuID: metric.cpuID}] = metric.values
+	}
+
 	for i := range p.metrics {
 		p.metrics[i].scaled, err = scaleMetricValues(p.metrics[i].values)
 		if err != nil {
 			return err
 		}
+		prevMetricValues, ok := prevValues[coreMetricKey{name: p.metrics[i].name, cpuID: p.metrics[i].cpuID}]
+		p.metrics[i].delta, err = scaleMetricDelta(p.metrics[i], prevMetricValues, ok)
+		if err != nil {
+			return err
+		}
+		p.metrics[i].multiplexRatio = multiplexRatio(p.metrics[i].values, prevMetricValues)
 	}
-	return nil
+	return checkScheduledMetrics(p.metrics, prevValues)
+}
+
+// checkScheduledMetrics takes a slice of core metrics and a map with the values of the metrics read previously. It
+// returns an error with the names of the events which were enabled but never counted since activation, e.g. because
+// their group does not fit the available counters. Metrics without previous values are not checked, since their
+// group might not have been scheduled yet.
+func checkScheduledMetrics(metrics []coreMetric, prev map[coreMetricKey]ev.CounterValue) error {
+	names := make([]string, 0)
+	for _, metric := range metrics {
+		if _, ok := prev[coreMetricKey{name: metric.name, cpuID: metric.cpuID}]; !ok {
+			continue
+		}
+		if metric.values.Enabled == 0 || metric.values.Running != 0 || slices.Contains(names, metric.name) {
+			continue
+		}
+		names = append(names, metric.name)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	slices.Sort(names)
+	return fmt.Errorf("events %q could not be scheduled on the counters since activation", strings.Join(names, ", "))
+}
+
+// coreMetricKey identifies the core metrics of an event name and CPU ID among the metrics of different reads.
+type coreMetricKey struct {
+	name  string
+	cpuID int
+}
+
+// multiplexRatio takes the latest and previous values of an event counter, and returns the fraction of the enabled
+// time the event was counting within the interval between both values. If the event was not enabled within the interval,
+// zero is returned.
+func multiplexRatio(latest, previous ev.CounterValue) float64 {
+	if latest.Enabled <= previous.Enabled {
+		return 0
+	}
+	if latest.Running < previous.Running {
+		return 0
+	}
+
+	enabled := latest.Enabled - previous.Enabled
+	running := latest.Running - previous.Running
+	if running >= enabled {
+		return 1
+	}
+	return float64(running) / float64(enabled)
+}
+
+// scaleMetricDelta takes a core metric, the values of the previous metric with the same name and CPU ID, and a flag
+// which is false if there is no previous metric. It returns the scaled increase of the metric values with respect to
+// the previous values. Scaling is based on the increase of enabled and running times, so that the result reflects
+// the multiplexing within the interval only. If there is no previous metric, zero is returned.
+func scaleMetricDelta(metric coreMetric, prev ev.CounterValue, hasPrev bool) (uint64, error) {
+	if !hasPrev {
+		return 0, nil
+	}
+	delta, err := scaleCounterDelta(metric.values, prev)
+	if err != nil {
+		return 0, fmt.Errorf("values of event %q for CPU ID %v %w", metric.name, metric.cpuID, err)
+	}
+	return delta, nil
+}
+
+// scaleCounterDelta takes the latest and previous values of an event counter, and returns the scaled increase of
+// the counter values. If any of the latest values is lower than its previous value, an error is returned.
+func scaleCounterDelta(latest, previous ev.CounterValue) (uint64, error) {
+	if latest.Raw < previous.Raw || latest.Enabled < previous.Enabled || latest.Running < previous.Running {
+		return 0, errors.New("decreased since the previous read")
+	}
+	return scaleMetricValues(ev.CounterValue{
+		Raw:     latest.Raw - previous.Raw,
+		Enabled: latest.Enabled - previous.Enabled,
+		Running: latest.Running - previous.Running,
+	})
 }
 
 // scaleMetricValues calculates scaled value from metric values. Scaled value is equal to
@@ -455,6 +455,13 @@ func TestPerf_PlacementMaker_MakeCorePlacement(t *testing.T) {
 			expected:  nil,
 			err:       errors.New("failed to create multiple core placements"),
 		},
+		{
+			name:      "HybridCorePlacementFailed",
+			cpuIDs:    []int{0, 1},
+			perfEvent: &ev.PerfEvent{PMUName: "cpu_core"},
+			expected:  nil,
+			err:       errors.New("failed to create core placements for PMU \"cpu_core\""),
+		},
 		{
 			name:      "SingleCorePlacement",
 			cpuIDs:    []int{0},
@@ -810,17 +817,61 @@ func TestPerf_Perf_Activate(t *testing.T) {
 		mActivator := &mockActivator{}
 		mActivator.On("activateEvents", customEvents, cores).Return(activeEvents, mError)
 
+		// events activated before the failure are deactivated
+		mDeactivator := &mockEventsDeactivator{}
+		mDeactivator.On("deactivateEvents", activeEvents).Return([]*ev.ActiveEvent{}, nil).Once()
+
 		perf := &perf{
 			resolver:       mResolver,
 			activator:      mActivator,
+			deactivator:    mDeactivator,
 			fileInfoReader: mFileInfoProvider,
 		}
 
 		require.ErrorContains(t, perf.activate(events, cores), "error during event activation")
-		require.Equal(t, activeEvents, perf.activeEvents)
+		require.Empty(t, perf.activeEvents)
+		require.Nil(t, perf.activeCPUIDs())
+		mResolver.AssertExpectations(t)
+		mFileInfoProvider.AssertExpectations(t)
+		mActivator.AssertExpectations(t)
+		mDeactivator.AssertExpectations(t)
+	})
+
+	t.Run("FailedToDeactivateEvents", func(t *testing.T) {
+		mResolver := &mockResolver{}
+		mResolver.On("resolveEvents", events).Return(customEvents, nil).Once()
+
+		mFileInfoProvider := &mockFileInfoProvider{}
+		mFileInfoProvider.On("readFile", fileMaxPath).Return([]byte("10\n"), nil).Once()
+		mFileInfoProvider.On("rlimit").Return(uint64(10), nil).Once()
+
+		activeEvents := []*ev.ActiveEvent{
+			{PerfEvent: customEvents[0].Event},
+			{PerfEvent: customEvents[1].Event},
+		}
+		mActivator := &mockActivator{}
+		mActivator.On("activateEvents", customEvents, cores).Return(activeEvents, mError).Once()
+
+		// events which could not be deactivated are kept
+		mDeactivator := &mockEventsDeactivator{}
+		mDeactivator.On("deactivateEvents", activeEvents).Return(activeEvents[1:], errors.New("mock deactivation error")).Once()
+
+		perf := &perf{
+			resolver:       mResolver,
+			activator:      mActivator,
+			deactivator:    mDeactivator,
+			fileInfoReader: mFileInfoProvider,
+		}
+
+		err := perf.activate(events, cores)
+		require.ErrorContains(t, err, "error during event activation")
+		require.ErrorContains(t, err, "error during event deactivation: mock deactivation error")
+		require.Equal(t, activeEvents[1:], perf.activeEvents)
+		require.Nil(t, perf.activeCPUIDs())
 		mResolver.AssertExpectations(t)
 		mFileInfoProvider.AssertExpectations(t)
 		mActivator.AssertExpectations(t)
+		mDeactivator.AssertExpectations(t)
 	})
 
 	t.Run("EventsActivated", func(t *testing.T) {
@@ -846,12 +897,240 @@ func TestPerf_Perf_Activate(t *testing.T) {
 
 		require.NoError(t, perf.activate(events, cores))
 		require.Equal(t, activeEvents, perf.activeEvents)
+		require.Equal(t, cores, perf.activeCPUIDs())
 		mResolver.AssertExpectations(t)
 		mFileInfoProvider.AssertExpectations(t)
 		mActivator.AssertExpectations(t)
 	})
 }
 
+// setArchGeneralCounters takes a number of general-purpose counters to be reported by archGeneralCounters function,
+// which is restored once the test completes.
+func setArchGeneralCounters(t *testing.T, n int) {
+	t.Helper()
+
+	orig := archGeneralCounters
+	archGeneralCounters = func() int {
+		return n
+	}
+	t.Cleanup(func() {
+		archGeneralCounters = orig
+	})
+}
+
+func TestPerf_Perf_ActivateSplitGroups(t *testing.T) {
+	setArchGeneralCounters(t, 2)
+	setArchFixedCounters(t, 0)
+
+	events := []string{"Event.Mock.1", "Event.Mock.2", "Event.Mock.3"}
+	cores := []int{0, 1}
+
+	customEvents := make([]ev.CustomizableEvent, 0, len(events))
+	activeEvents := make([]*ev.ActiveEvent, 0, len(events))
+	for _, event := range events {
+		perfEvent := &ev.PerfEvent{Name: event, PMUName: "cpu", PMUTypes: []ev.NamedPMUType{{Name: "cpu", PMUType: 4}}}
+		customEvents = append(customEvents, ev.CustomizableEvent{Event: perfEvent})
+		activeEvents = append(activeEvents, &ev.ActiveEvent{PerfEvent: perfEvent})
+	}
+
+	mResolver := &mockResolver{}
+	mResolver.On("resolveEvents", events).Return(customEvents, nil).Once()
+
+	mFileInfoProvider := &mockFileInfoProvider{}
+	mFileInfoProvider.On("readFile", fileMaxPath).Return([]byte("100\n"), nil).Once()
+	mFileInfoProvider.On("rlimit").Return(uint64(100), nil).Once()
+
+	// events exceeding the general-purpose counters are activated as a separate group.
+	mActivator := &mockActivator{}
+	mActivator.On("activateEvents", customEvents[:2], cores).Return(activeEvents[:2], nil).Once()
+	mActivator.On("activateEvents", customEvents[2:], cores).Return(activeEvents[2:], nil).Once()
+
+	perf := &perf{
+		resolver:       mResolver,
+		activator:      mActivator,
+		fileInfoReader: mFileInfoProvider,
+	}
+
+	require.NoError(t, perf.activate(events, cores))
+	require.Equal(t, activeEvents, perf.activeEvents)
+	mResolver.AssertExpectations(t)
+	mFileInfoProvider.AssertExpectations(t)
+	mActivator.AssertExpectations(t)
+}
+
+func TestPerf_Perf_ActivateTopdown(t *testing.T) {
+	events := []string{
+		topdownRetiring,
+		topdownSlots,
+		instRetired,
+		topdownBackendBound,
+	}
+
+	cores := []int{0, 1}
+
+	customEvents := make([]ev.CustomizableEvent, 0, len(events))
+	for _, event := range events {
+		customEvents = append(customEvents, ev.CustomizableEvent{
+			Event: &ev.PerfEvent{Name: event, PMUName: "cpu", PMUTypes: []ev.NamedPMUType{{Name: "cpu", PMUType: 4}}},
+		})
+	}
+	coreGroup := []ev.CustomizableEvent{customEvents[2]}
+	topdownGroup := []ev.CustomizableEvent{customEvents[1], customEvents[0], customEvents[3]}
+
+	mError := errors.New("mock error")
+
+	newFileInfoProvider := func() *mockFileInfoProvider {
+		m := &mockFileInfoProvider{}
+		m.On("readFile", fileMaxPath).Return([]byte("100\n"), nil).Once()
+		m.On("rlimit").Return(uint64(100), nil).Once()
+		return m
+	}
+
+	t.Run("FailedToActivateTopdownGroup", func(t *testing.T) {
+		mResolver := &mockResolver{}
+		mResolver.On("resolveEvents", events).Return(customEvents, nil).Once()
+
+		coreActiveEvents := []*ev.ActiveEvent{{PerfEvent: customEvents[2].Event}}
+		mActivator := &mockActivator{}
+		mActivator.On("activateEvents", coreGroup, cores).Return(coreActiveEvents, nil).Once()
+		mActivator.On("activateEvents", topdownGroup, cores).Return(nil, mError).Once()
+
+		// events of the core group, already activated, are deactivated
+		mDeactivator := &mockEventsDeactivator{}
+		mDeactivator.On("deactivateEvents", coreActiveEvents).Return([]*ev.ActiveEvent{}, nil).Once()
+
+		perf := &perf{
+			resolver:       mResolver,
+			activator:      mActivator,
+			deactivator:    mDeactivator,
+			fileInfoReader: newFileInfoProvider(),
+		}
+
+		require.ErrorContains(t, perf.activate(events, cores), "error during event activation")
+		require.Empty(t, perf.activeEvents)
+		mResolver.AssertExpectations(t)
+		mActivator.AssertExpectations(t)
+		mDeactivator.AssertExpectations(t)
+	})
+
+	t.Run("EventsActivated", func(t *testing.T) {
+		mResolver := &mockResolver{}
+		mResolver.On("resolveEvents", events).Return(customEvents, nil).Once()
+
+		coreActiveEvents := []*ev.ActiveEvent{{PerfEvent: customEvents[2].Event}}
+		topdownActiveEvents := []*ev.ActiveEvent{
+			{PerfEvent: customEvents[1].Event},
+			{PerfEvent: customEvents[0].Event},
+			{PerfEvent: customEvents[3].Event},
+		}
+		mActivator := &mockActivator{}
+		mActivator.On("activateEvents", coreGroup, cores).Return(coreActiveEvents, nil).Once()
+		mActivator.On("activateEvents", topdownGroup, cores).Return(topdownActiveEvents, nil).Once()
+
+		perf := &perf{
+			resolver:       mResolver,
+			activator:      mActivator,
+			fileInfoReader: newFileInfoProvider(),
+		}
+
+		require.NoError(t, perf.activate(events, cores))
+		require.Equal(t, append(coreActiveEvents, topdownActiveEvents...), perf.activeEvents)
+		mResolver.AssertExpectations(t)
+		mActivator.AssertExpectations(t)
+	})
+}
+
+func TestPerf_GroupEvents(t *testing.T) {
+	newEvents := func(names ...string) []ev.CustomizableEvent {
+		events := make([]ev.CustomizableEvent, 0, len(names))
+		for _, name := range names {
+			events = append(events, ev.CustomizableEvent{Event: &ev.PerfEvent{Name: name}})
+		}
+		return events
+	}
+
+	t.Run("InvalidEvent", func(t *testing.T) {
+		groups, err := groupEvents([]ev.CustomizableEvent{{}}, 8, 3)
+		require.ErrorContains(t, err, "invalid custom event")
+		require.Nil(t, groups)
+	})
+
+	t.Run("TopdownWithoutSlots", func(t *testing.T) {
+		groups, err := groupEvents(newEvents(thread.String(), topdownRetiring), 8, 3)
+		require.ErrorContains(t, err, fmt.Sprintf("topdown metric events require %q event", topdownSlots))
+		require.Nil(t, groups)
+	})
+
+	t.Run("CoreEventsOnly", func(t *testing.T) {
+		events := newEvents(c01.String(), thread.String())
+
+		groups, err := groupEvents(events, 8, 3)
+		require.NoError(t, err)
+		require.Equal(t, [][]ev.CustomizableEvent{events}, groups)
+	})
+
+	t.Run("TopdownEventsOnly", func(t *testing.T) {
+		events := newEvents("perf_metrics.frontend_bound", "topdown.slots")
+
+		groups, err := groupEvents(events, 8, 3)
+		require.NoError(t, err)
+		require.Equal(t, [][]ev.CustomizableEvent{{events[1], events[0]}}, groups)
+	})
+
+	t.Run("SlotsWithoutMetrics", func(t *testing.T) {
+		events := newEvents(thread.String(), topdownSlots)
+
+		groups, err := groupEvents(events, 8, 3)
+		require.NoError(t, err)
+		require.Equal(t, [][]ev.CustomizableEvent{{events[0]}, {events[1]}}, groups)
+	})
+
+	t.Run("SplitIntoCounterGroups", func(t *testing.T) {
+		events := newEvents("Event.1", instRetired, "Event.2", "Event.3", thread.String(), "Event.4", refCycles, "Event.5")
+
+		// fixed-function counter events do not take general-purpose counters.
+		groups, err := groupEvents(events, 2, 3)
+		require.NoError(t, err)
+		require.Equal(t, [][]ev.CustomizableEvent{
+			{events[0], events[1], events[2]},
+			{events[3], events[4], events[5], events[6]},
+			{events[7]},
+		}, groups)
+
+		// without fixed-function counters, all events take general-purpose counters.
+		groups, err = groupEvents(events, 4, 0)
+		require.NoError(t, err)
+		require.Equal(t, [][]ev.CustomizableEvent{events[:4], events[4:]}, groups)
+	})
+
+	t.Run("UnknownCounters", func(t *testing.T) {
+		events := newEvents("Event.1", "Event.2", "Event.3")
+
+		groups, err := groupEvents(events, 0, 0)
+		require.NoError(t, err)
+		require.Equal(t, [][]ev.CustomizableEvent{events}, groups)
+	})
+
+	t.Run("CoreAndTopdownEvents", func(t *testing.T) {
+		events := newEvents(
+			topdownRetiring,
+			thread.String(),
+			topdownBadSpeculation,
+			topdownSlots,
+			instRetired,
+			topdownFrontendBound,
+			topdownBackendBound,
+		)
+
+		groups, err := groupEvents(events, 8, 3)
+		require.NoError(t, err)
+		require.Equal(t, [][]ev.CustomizableEvent{
+			{events[1], events[4]},
+			{events[3], events[0], events[2], events[5], events[6]},
+		}, groups)
+	})
+}
+
 type mockEventsReader struct {
 	mock.Mock
 }
@@ -966,10 +1245,12 @@ func TestPerf_Perf_Deactivate(t *testing.T) {
 			deactivator: mDeactivator,
 
 			activeEvents: events,
+			activeCPUs:   []int{0, 1},
 		}
 
 		err := perf.deactivate()
 		require.Equal(t, activeEventsExp, perf.activeEvents)
+		require.Equal(t, []int{0, 1}, perf.activeCPUIDs())
 		require.ErrorContains(t, err, mError.Error())
 		mDeactivator.AssertExpectations(t)
 	})
@@ -984,10 +1265,12 @@ func TestPerf_Perf_Deactivate(t *testing.T) {
 			deactivator: mDeactivator,
 
 			activeEvents: events,
+			activeCPUs:   []int{0, 1},
 		}
 
 		err := perf.deactivate()
 		require.Equal(t, activeEventsExp, perf.activeEvents)
+		require.Nil(t, perf.activeCPUIDs())
 		require.NoError(t, err)
 		mDeactivator.AssertExpectations(t)
 	})
@@ -1083,6 +1366,7 @@ func TestPerf_PerfWithStorage_Update(t *testing.T) {
 			mMetrics[1],
 		}
 		metricsExp[0].scaled = mMetrics[0].values.Raw
+		metricsExp[0].multiplexRatio = 1
 		metricsExp[1].scaled = mMetrics[1].values.Raw
 
 		mReader := &mockEventsReader{}
@@ -1135,7 +1419,9 @@ func TestPerf_PerfWithStorage_Update(t *testing.T) {
 			mMetrics[1],
 		}
 		metricsExp[0].scaled = 67023478
+		metricsExp[0].multiplexRatio = 1
 		metricsExp[1].scaled = 849835456
+		metricsExp[1].multiplexRatio = 1
 
 		mReader := &mockEventsReader{}
 		mReader.On("readEvents", mActiveEvents).Return(mMetrics, nil).Once()
@@ -1155,6 +1441,146 @@ func TestPerf_PerfWithStorage_Update(t *testing.T) {
 	})
 }
 
+func TestPerf_PerfWithStorage_UpdateDelta(t *testing.T) {
+	mEventName := "Event.1"
+	mActiveEvents := []*ev.ActiveEvent{{PerfEvent: &ev.PerfEvent{Name: mEventName}}}
+
+	t.Run("ValuesDecreased", func(t *testing.T) {
+		mReader := &mockEventsReader{}
+		mReader.On("readEvents", mActiveEvents).Return([]coreMetric{
+			{name: mEventName, cpuID: 0, values: ev.CounterValue{Raw: 100, Enabled: 2000, Running: 2000}},
+		}, nil).Once()
+
+		perfWithStorage := &perfWithStorage{
+			perfReader: &perf{
+				valuesReader: mReader,
+				activeEvents: mActiveEvents,
+			},
+			metrics: []coreMetric{
+				{name: mEventName, cpuID: 0, values: ev.CounterValue{Raw: 200, Enabled: 1000, Running: 1000}, scaled: 200},
+			},
+		}
+
+		err := perfWithStorage.update()
+		require.ErrorContains(t, err, "values of event \"Event.1\" for CPU ID 0 decreased since the previous read")
+		mReader.AssertExpectations(t)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		mReader := &mockEventsReader{}
+		mReader.On("readEvents", mActiveEvents).Return([]coreMetric{
+			{name: mEventName, cpuID: 0, values: ev.CounterValue{Raw: 100, Enabled: 1000, Running: 1000}},
+			{name: mEventName, cpuID: 1, values: ev.CounterValue{Raw: 300, Enabled: 1000, Running: 500}},
+		}, nil).Once()
+		mReader.On("readEvents", mActiveEvents).Return([]coreMetric{
+			{name: mEventName, cpuID: 0, values: ev.CounterValue{Raw: 250, Enabled: 2000, Running: 2000}},
+			{name: mEventName, cpuID: 1, values: ev.CounterValue{Raw: 400, Enabled: 2000, Running: 750}},
+		}, nil).Once()
+
+		perfWithStorage := &perfWithStorage{
+			perfReader: &perf{
+				valuesReader: mReader,
+				activeEvents: mActiveEvents,
+			},
+		}
+
+		// first read, no deltas available, multiplexing ratios are based on values accumulated since activation.
+		require.NoError(t, perfWithStorage.update())
+		require.Equal(t, []coreMetric{
+			{name: mEventName, cpuID: 0, values: ev.CounterValue{Raw: 100, Enabled: 1000, Running: 1000}, scaled: 100, multiplexRatio: 1},
+			{name: mEventName, cpuID: 1, values: ev.CounterValue{Raw: 300, Enabled: 1000, Running: 500}, scaled: 600, multiplexRatio: 0.5},
+		}, perfWithStorage.metrics)
+
+		// deltas and multiplexing ratios are based on the enabled and running times of the interval.
+		require.NoError(t, perfWithStorage.update())
+		require.Equal(t, []coreMetric{
+			{name: mEventName, cpuID: 0, values: ev.CounterValue{Raw: 250, Enabled: 2000, Running: 2000}, scaled: 250, delta: 150, multiplexRatio: 1},
+			{name: mEventName, cpuID: 1, values: ev.CounterValue{Raw: 400, Enabled: 2000, Running: 750}, scaled: 1066, delta: 400, multiplexRatio: 0.25},
+		}, perfWithStorage.metrics)
+		mReader.AssertExpectations(t)
+	})
+
+	t.Run("NeverScheduled", func(t *testing.T) {
+		mReader := &mockEventsReader{}
+		mReader.On("readEvents", mActiveEvents).Return([]coreMetric{
+			{name: mEventName, cpuID: 0, values: ev.CounterValue{Raw: 100, Enabled: 1000, Running: 1000}},
+			{name: mEventName, cpuID: 1, values: ev.CounterValue{Raw: 0, Enabled: 1000, Running: 0}},
+		}, nil).Twice()
+
+		perfWithStorage := &perfWithStorage{
+			perfReader: &perf{
+				valuesReader: mReader,
+				activeEvents: mActiveEvents,
+			},
+		}
+
+		// first read, the group might not have been scheduled yet.
+		require.NoError(t, perfWithStorage.update())
+
+		err := perfWithStorage.update()
+		require.ErrorContains(t, err, "events \"Event.1\" could not be scheduled on the counters since activation")
+		require.Len(t, perfWithStorage.metrics, 2)
+		mReader.AssertExpectations(t)
+	})
+}
+
+func TestPerf_MultiplexRatio(t *testing.T) {
+	testCases := []struct {
+		name     string
+		latest   ev.CounterValue
+		previous ev.CounterValue
+		expected float64
+	}{
+		{
+			name:     "NotEnabled",
+			latest:   ev.CounterValue{Raw: 100, Enabled: 1000, Running: 1000},
+			previous: ev.CounterValue{Raw: 100, Enabled: 1000, Running: 1000},
+			expected: 0,
+		},
+		{
+			name:     "RunningDecreased",
+			latest:   ev.CounterValue{Raw: 200, Enabled: 2000, Running: 500},
+			previous: ev.CounterValue{Raw: 100, Enabled: 1000, Running: 1000},
+			expected: 0,
+		},
+		{
+			name:     "NotRunning",
+			latest:   ev.CounterValue{Raw: 100, Enabled: 2000, Running: 1000},
+			previous: ev.CounterValue{Raw: 100, Enabled: 1000, Running: 1000},
+			expected: 0,
+		},
+		{
+			name:     "NotMultiplexed",
+			latest:   ev.CounterValue{Raw: 200, Enabled: 2000, Running: 2000},
+			previous: ev.CounterValue{Raw: 100, Enabled: 1000, Running: 1000},
+			expected: 1,
+		},
+		{
+			name:     "RunningExceedsEnabled",
+			latest:   ev.CounterValue{Raw: 200, Enabled: 2000, Running: 2100},
+			previous: ev.CounterValue{Raw: 100, Enabled: 1000, Running: 1000},
+			expected: 1,
+		},
+		{
+			name:     "Multiplexed",
+			latest:   ev.CounterValue{Raw: 200, Enabled: 5000, Running: 1600},
+			previous: ev.CounterValue{Raw: 100, Enabled: 1000, Running: 1000},
+			expected: 0.15,
+		},
+		{
+			name:     "SinceActivation",
+			latest:   ev.CounterValue{Raw: 200, Enabled: 4000, Running: 1000},
+			expected: 0.25,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.InDelta(t, tc.expected, multiplexRatio(tc.latest, tc.previous), 1e-9)
+		})
+	}
+}
+
 func TestPerf_PerfWithStorage_GetCoreMetrics(t *testing.T) {
 	metrics := []coreMetric{
 		{name: "Mock.Event.1", cpuID: 1},
@@ -0,0 +1,44 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Names of PMUs exposed by `perf_events` kernel interface, which count C-state residencies.
+const (
+	cstateCorePMU = "cstate_core"
+	cstatePkgPMU  = "cstate_pkg"
+)
+
+// cstateResidencyEventRegex matches names of C-state residency events, e.g. "c6-residency".
+var cstateResidencyEventRegex = regexp.MustCompile(`^c\d+-residency$`)
+
+// cstatePMUs is a slice of PMUs which count core and package C-state residencies.
+var cstatePMUs = []sysfsPMU{
+	{
+		name:    cstateCorePMU,
+		isEvent: isCStateResidencyEvent,
+	},
+	{
+		name:       cstatePkgPMU,
+		perPackage: true,
+		isEvent:    isCStateResidencyEvent,
+	},
+}
+
+// isCStateResidencyEvent takes the name of an event exposed by a cstate PMU and reports whether it is a C-state
+// residency event. Files describing the event, such as its unit and scale, are not events.
+func isCStateResidencyEvent(name string) bool {
+	return cstateResidencyEventRegex.MatchString(name)
+}
+
+// cstateResidencyEvent takes the name of a C-state, e.g. "C6", and returns the name of its residency event.
+func cstateResidencyEvent(state string) string {
+	return strings.ToLower(state) + "-residency"
+}
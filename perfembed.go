@@ -0,0 +1,113 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	ev "github.com/intel/iaevents"
+
+	"github.com/intel/powertelemetry/internal/cpumodel"
+)
+
+// Prefix of paths referring to perf event definition files embedded in the library, instead of files of the file system.
+const embeddedEventsPrefix = "embedded:"
+
+// Names of perf event definition files embedded in the library. Each file comprises the subset of events, of a
+// microarchitecture, which are used by the library.
+const (
+	architecturalEventsFile = "architectural_core.json"
+	icelakeEventsFile       = "icelake_core.json"
+	goldencoveEventsFile    = "goldencove_core.json"
+)
+
+// Directory of perf event definition files embedded in the library.
+const embeddedEventsDir = "perfmon"
+
+//go:embed perfmon/*.json
+var embeddedEvents embed.FS
+
+// embeddedEventsPath takes the name of an embedded perf event definition file and returns its path, marked as embedded.
+func embeddedEventsPath(name string) string {
+	return embeddedEventsPrefix + name
+}
+
+// embeddedPerfEvents takes a processor model and returns the path of the embedded perf event definition file
+// which corresponds to its microarchitecture. Processor models without a specific file fall back to architectural
+// events, which are common to all processor models.
+func embeddedPerfEvents(model int) string {
+	switch model {
+	case cpumodel.INTEL_FAM6_ICELAKE,
+		cpumodel.INTEL_FAM6_ICELAKE_L,
+		cpumodel.INTEL_FAM6_ICELAKE_X,
+		cpumodel.INTEL_FAM6_ICELAKE_D,
+		cpumodel.INTEL_FAM6_TIGERLAKE,
+		cpumodel.INTEL_FAM6_TIGERLAKE_L,
+		cpumodel.INTEL_FAM6_ROCKETLAKE:
+		return embeddedEventsPath(icelakeEventsFile)
+	case cpumodel.INTEL_FAM6_SAPPHIRERAPIDS_X,
+		cpumodel.INTEL_FAM6_EMERALDRAPIDS_X,
+		cpumodel.INTEL_FAM6_GRANITERAPIDS_X,
+		cpumodel.INTEL_FAM6_GRANITERAPIDS_D:
+		return embeddedEventsPath(goldencoveEventsFile)
+	default:
+		return embeddedEventsPath(architecturalEventsFile)
+	}
+}
+
+// embeddedHybridPerfEvents takes a processor model and returns a map with core types as keys and paths of the embedded
+// perf event definition files, specific to each core type, as values. If the processor model is not hybrid, nil is returned.
+func embeddedHybridPerfEvents(model int) map[CoreType]string {
+	if !isHybridModel(model) {
+		return nil
+	}
+	return map[CoreType]string{
+		CoreTypeCore: embeddedEventsPath(goldencoveEventsFile),
+		CoreTypeAtom: embeddedEventsPath(architecturalEventsFile),
+	}
+}
+
+// embeddedEventsReader implements ev.Reader interface, reading events from a perf event definition file embedded in the library.
+type embeddedEventsReader struct {
+	name string
+}
+
+// Read returns the events of the embedded perf event definition file specified by the receiver. Events are decoded
+// on each call, so that callers are free to modify them.
+func (r *embeddedEventsReader) Read() ([]*ev.JSONEvent, error) {
+	content, err := embeddedEvents.ReadFile(path.Join(embeddedEventsDir, r.name))
+	if err != nil {
+		return nil, fmt.Errorf("error reading embedded file %q: %w", r.name, err)
+	}
+
+	var data ev.JSONData
+	if err := json.Unmarshal(content, &data); err != nil {
+		return nil, fmt.Errorf("error decoding embedded file %q: %w", r.name, err)
+	}
+	return data.Events, nil
+}
+
+// newEventsReader takes a path string, corresponding to a JSON file which comprises perf event definitions, and returns
+// an ev.Reader for it. If the path is marked as embedded, events are read from the file embedded in the library.
+func newEventsReader(jsonFile string) (ev.Reader, error) {
+	if name, ok := strings.CutPrefix(jsonFile, embeddedEventsPrefix); ok {
+		if _, err := fs.Stat(embeddedEvents, path.Join(embeddedEventsDir, name)); err != nil {
+			return nil, fmt.Errorf("embedded file %q not found", name)
+		}
+		return &embeddedEventsReader{name: name}, nil
+	}
+
+	reader := ev.NewFilesReader()
+	if err := reader.AddFiles(jsonFile); err != nil {
+		return nil, fmt.Errorf("error adding file to reader: %w", err)
+	}
+	return reader, nil
+}
@@ -0,0 +1,156 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"slices"
+	"testing"
+
+	ev "github.com/intel/iaevents"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intel/powertelemetry/internal/cpumodel"
+)
+
+func TestEmbeddedPerfEvents(t *testing.T) {
+	testCases := []struct {
+		name     string
+		model    int
+		expected string
+	}{
+		{
+			name:     "IceLakeX",
+			model:    cpumodel.INTEL_FAM6_ICELAKE_X,
+			expected: "embedded:icelake_core.json",
+		},
+		{
+			name:     "TigerLake",
+			model:    cpumodel.INTEL_FAM6_TIGERLAKE,
+			expected: "embedded:icelake_core.json",
+		},
+		{
+			name:     "SapphireRapids",
+			model:    cpumodel.INTEL_FAM6_SAPPHIRERAPIDS_X,
+			expected: "embedded:goldencove_core.json",
+		},
+		{
+			name:     "GraniteRapids",
+			model:    cpumodel.INTEL_FAM6_GRANITERAPIDS_X,
+			expected: "embedded:goldencove_core.json",
+		},
+		{
+			name:     "SkylakeX",
+			model:    cpumodel.INTEL_FAM6_SKYLAKE_X,
+			expected: "embedded:architectural_core.json",
+		},
+		{
+			name:     "Unknown",
+			model:    0x00,
+			expected: "embedded:architectural_core.json",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, embeddedPerfEvents(tc.model))
+		})
+	}
+}
+
+func TestEmbeddedHybridPerfEvents(t *testing.T) {
+	t.Run("NotHybrid", func(t *testing.T) {
+		require.Nil(t, embeddedHybridPerfEvents(cpumodel.INTEL_FAM6_SAPPHIRERAPIDS_X))
+	})
+
+	t.Run("Hybrid", func(t *testing.T) {
+		require.Equal(t, map[CoreType]string{
+			CoreTypeCore: "embedded:goldencove_core.json",
+			CoreTypeAtom: "embedded:architectural_core.json",
+		}, embeddedHybridPerfEvents(cpumodel.INTEL_FAM6_ALDERLAKE))
+	})
+}
+
+func TestNewEventsReader(t *testing.T) {
+	t.Run("EmbeddedFileNotFound", func(t *testing.T) {
+		reader, err := newEventsReader("embedded:not_exist.json")
+		require.ErrorContains(t, err, "embedded file \"not_exist.json\" not found")
+		require.Nil(t, reader)
+	})
+
+	t.Run("FileNotFound", func(t *testing.T) {
+		reader, err := newEventsReader("testdata/not_exist.json")
+		require.ErrorContains(t, err, "error adding file to reader")
+		require.Nil(t, reader)
+	})
+
+	t.Run("File", func(t *testing.T) {
+		reader, err := newEventsReader("testdata/sapphirerapids_core.json")
+		require.NoError(t, err)
+		require.IsType(t, &ev.JSONFilesReader{}, reader)
+	})
+
+	t.Run("Embedded", func(t *testing.T) {
+		reader, err := newEventsReader("embedded:architectural_core.json")
+		require.NoError(t, err)
+		require.Equal(t, &embeddedEventsReader{name: "architectural_core.json"}, reader)
+	})
+}
+
+func TestEmbeddedEventsReader_Read(t *testing.T) {
+	t.Run("FileNotFound", func(t *testing.T) {
+		r := &embeddedEventsReader{name: "not_exist.json"}
+
+		events, err := r.Read()
+		require.ErrorContains(t, err, "error reading embedded file \"not_exist.json\"")
+		require.Nil(t, events)
+	})
+
+	// each embedded file comprises the events activated by default on the processor models it corresponds to.
+	testCases := []struct {
+		name   string
+		file   string
+		events []string
+	}{
+		{
+			name:   "Architectural",
+			file:   architecturalEventsFile,
+			events: fixedPerfEvents,
+		},
+		{
+			name:   "IceLake",
+			file:   icelakeEventsFile,
+			events: append(slices.Clone(fixedPerfEvents), topdownPerfEvents...),
+		},
+		{
+			name:   "GoldenCove",
+			file:   goldencoveEventsFile,
+			events: append(append(slices.Clone(fixedPerfEvents), topdownPerfEvents...), c01.String(), c02.String(), c0Wait.String()),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &embeddedEventsReader{name: tc.file}
+
+			events, err := r.Read()
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(events))
+			for _, event := range events {
+				require.NotNil(t, event)
+				require.Empty(t, event.Unit)
+				names = append(names, event.EventName)
+			}
+			require.ElementsMatch(t, tc.events, names)
+
+			// events are decoded on each call.
+			events[0].Unit = "cpu_core"
+			events, err = r.Read()
+			require.NoError(t, err)
+			require.Empty(t, events[0].Unit)
+		})
+	}
+}
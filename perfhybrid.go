@@ -0,0 +1,253 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	ev "github.com/intel/iaevents"
+)
+
+// Path to the directory of PMUs exposed by `perf_events` kernel interface.
+const pmuDevicesPath = "/sys/bus/event_source/devices"
+
+// CoreType represents the type of a core of a hybrid processor, corresponding to the name of its core PMU.
+type CoreType string
+
+// CoreType enum defines supported core types of hybrid processors.
+const (
+	CoreTypeCore CoreType = "cpu_core" // performance core
+	CoreTypeAtom CoreType = "cpu_atom" // efficient core
+)
+
+// coreTypes is a slice of supported core types of hybrid processors, in the order events are activated.
+var coreTypes = []CoreType{CoreTypeCore, CoreTypeAtom}
+
+// coreTypeFromPMU takes the PMU name of a perf event and returns the core type the PMU belongs to. If the PMU is not
+// specific to a core type of a hybrid processor, an empty string is returned.
+func coreTypeFromPMU(pmu string) CoreType {
+	coreType := CoreType(pmu)
+	if slices.Contains(coreTypes, coreType) {
+		return coreType
+	}
+	return ""
+}
+
+// coreTypeReader decorates ev.Reader, setting the unit of core events, which have no unit defined, to the PMU of
+// a core type. This way, events are resolved into perf events of the PMU of that core type.
+type coreTypeReader struct {
+	ev.Reader
+
+	coreType CoreType
+}
+
+// Read returns the events read by the decorated ev.Reader, with the unit of core events set to the PMU of the receiver's core type.
+func (r *coreTypeReader) Read() ([]*ev.JSONEvent, error) {
+	events, err := r.Reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		if event != nil && len(event.Unit) == 0 {
+			event.Unit = string(r.coreType)
+		}
+	}
+	return events, nil
+}
+
+// newCoreTypeEventsResolver takes a path string, corresponding to a JSON file which comprises events specific to a core type
+// of a hybrid processor, and returns an eventsResolver which resolves core events into perf events of the PMU of that core type.
+func newCoreTypeEventsResolver(jsonFile string, coreType CoreType) (eventsResolver, error) {
+	reader, err := newEventsReader(jsonFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &eventsResolverImpl{
+		reader: &coreTypeReader{
+			Reader:   reader,
+			coreType: coreType,
+		},
+		transformer: ev.NewPerfTransformer(),
+	}, nil
+}
+
+// hybridPMU represents the core PMU of a core type of a hybrid processor.
+type hybridPMU struct {
+	coreType        CoreType
+	resolver        eventsResolver
+	cpus            []int // CPU IDs of the core type
+	generalCounters int   // number of general-purpose counters per CPU of the core type
+	fixedCounters   int   // number of fixed-function counters per CPU of the core type
+}
+
+// initHybridResolvers takes a map with core types as keys and paths of JSON files, which comprise events specific to each
+// core type, as values. It initializes a resolver for each core type, along with the CPU IDs the core type comprises and
+// the number of counters of its CPUs.
+func (p *perf) initHybridResolvers(jsonFiles map[CoreType]string) error {
+	if len(jsonFiles) == 0 {
+		return errors.New("no JSON files provided")
+	}
+	for coreType := range jsonFiles {
+		if !slices.Contains(coreTypes, coreType) {
+			return fmt.Errorf("unsupported core type %q", coreType)
+		}
+	}
+
+	pmus := make([]*hybridPMU, 0, len(jsonFiles))
+	for _, coreType := range coreTypes {
+		jsonFile, ok := jsonFiles[coreType]
+		if !ok {
+			continue
+		}
+
+		cpus, err := readCoreTypeCPUs(p.fileInfoReader, p.pmuPath, coreType)
+		if err != nil {
+			return err
+		}
+
+		generalCounters, fixedCounters, err := readCoreTypeCounters(coreType, cpus)
+		if err != nil {
+			return err
+		}
+
+		resolver, err := newCoreTypeEventsResolver(jsonFile, coreType)
+		if err != nil {
+			return fmt.Errorf("error initializing resolver for core type %q: %w", coreType, err)
+		}
+
+		pmus = append(pmus, &hybridPMU{
+			coreType:        coreType,
+			resolver:        resolver,
+			cpus:            cpus,
+			generalCounters: generalCounters,
+			fixedCounters:   fixedCounters,
+		})
+	}
+	p.hybridPMUs = pmus
+	return nil
+}
+
+// readCoreTypeCPUs takes a fileInfoProvider, the base path of PMUs and a core type, and returns the CPU IDs of the core
+// type, exposed by its core PMU.
+func readCoreTypeCPUs(reader fileInfoProvider, basePath string, coreType CoreType) ([]int, error) {
+	path := filepath.Join(basePath, string(coreType), "cpus")
+	content, err := reader.readFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading CPU IDs of core type %q: %w", coreType, err)
+	}
+
+	cpus, err := parseCPUList(strings.TrimSpace(string(content)))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing CPU IDs of core type %q: %w", coreType, err)
+	}
+	return cpus, nil
+}
+
+// readCoreTypeCounters takes a core type and its CPU IDs, and returns the number of general-purpose and fixed-function
+// counters of the core type, as reported by the first CPU ID of the core type which could be queried. Counters must be
+// queried on a CPU of the core type, since each core type of a hybrid processor has its own number of counters.
+func readCoreTypeCounters(coreType CoreType, cpus []int) (int, int, error) {
+	errs := make([]error, 0, len(cpus))
+	for _, cpu := range cpus {
+		generalCounters, fixedCounters, err := cpuPerfmonCounters(cpu)
+		if err == nil {
+			return generalCounters, fixedCounters, nil
+		}
+		errs = append(errs, err)
+	}
+	return 0, 0, fmt.Errorf("error reading counters of core type %q: %w", coreType, errors.Join(errs...))
+}
+
+// parseCPUList takes a string with a list of CPU IDs in the format used by the kernel, e.g. "0-3,8,10-11",
+// and returns a slice with the CPU IDs.
+func parseCPUList(list string) ([]int, error) {
+	if len(list) == 0 {
+		return nil, errors.New("CPU list cannot be empty")
+	}
+
+	cpus := make([]int, 0)
+	for _, item := range strings.Split(list, ",") {
+		bounds := strings.SplitN(item, "-", 2)
+		low, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid CPU ID %q: %w", bounds[0], err)
+		}
+		high := low
+		if len(bounds) == 2 {
+			high, err = strconv.Atoi(bounds[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid CPU ID %q: %w", bounds[1], err)
+			}
+		}
+		if low < 0 || high < low {
+			return nil, fmt.Errorf("invalid CPU range %q", item)
+		}
+		for cpu := low; cpu <= high; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus, nil
+}
+
+// resolveHybridTargets takes a slice of hybrid PMUs, a slice of event names and a slice of CPU IDs. It resolves the events for
+// each core type into activation targets, comprising the CPU IDs and the counters of the core type. An event which could not be resolved for
+// a core type is not activated on CPUs of that core type. If an event could not be resolved for any core type, an error is returned.
+func resolveHybridTargets(pmus []*hybridPMU, events []string, cpus []int) ([]activationTarget, error) {
+	if len(events) == 0 {
+		return nil, errors.New("event group cannot be empty")
+	}
+
+	targets := make([]activationTarget, 0, len(pmus))
+	resolved := make(map[string]bool, len(events))
+	resolveErrs := make(map[string][]error, len(events))
+	for _, pmu := range pmus {
+		pmuCPUs := make([]int, 0, len(cpus))
+		for _, cpu := range cpus {
+			if slices.Contains(pmu.cpus, cpu) {
+				pmuCPUs = append(pmuCPUs, cpu)
+			}
+		}
+		if len(pmuCPUs) == 0 {
+			continue
+		}
+
+		customEvents := make([]ev.CustomizableEvent, 0, len(events))
+		for _, event := range events {
+			customEvent, err := pmu.resolver.resolveEvents([]string{event})
+			if err != nil {
+				resolveErrs[event] = append(resolveErrs[event], fmt.Errorf("core type %q: %w", pmu.coreType, err))
+				continue
+			}
+			resolved[event] = true
+			customEvents = append(customEvents, customEvent...)
+		}
+		if len(customEvents) == 0 {
+			continue
+		}
+		targets = append(targets, activationTarget{
+			events:          customEvents,
+			cores:           pmuCPUs,
+			generalCounters: pmu.generalCounters,
+			fixedCounters:   pmu.fixedCounters,
+		})
+	}
+
+	if len(targets) == 0 && len(resolveErrs) == 0 {
+		return nil, errors.New("none of the CPU IDs belong to a supported core type")
+	}
+	for _, event := range events {
+		if !resolved[event] {
+			return nil, fmt.Errorf("event %q could not be resolved for any core type: %w", event, errors.Join(resolveErrs[event]...))
+		}
+	}
+	return targets, nil
+}
@@ -0,0 +1,505 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	ev "github.com/intel/iaevents"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockJSONReader struct {
+	mock.Mock
+}
+
+func (m *mockJSONReader) Read() ([]*ev.JSONEvent, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*ev.JSONEvent), args.Error(1)
+}
+
+func TestPerf_CoreTypeFromPMU(t *testing.T) {
+	require.Equal(t, CoreTypeCore, coreTypeFromPMU("cpu_core"))
+	require.Equal(t, CoreTypeAtom, coreTypeFromPMU("cpu_atom"))
+	require.Empty(t, coreTypeFromPMU("cpu"))
+	require.Empty(t, coreTypeFromPMU("uncore_imc_0"))
+}
+
+func TestPerf_CoreTypeReader_Read(t *testing.T) {
+	t.Run("FailedToRead", func(t *testing.T) {
+		mReader := &mockJSONReader{}
+		mReader.On("Read").Return(nil, errors.New("mock error")).Once()
+
+		r := &coreTypeReader{
+			Reader:   mReader,
+			coreType: CoreTypeAtom,
+		}
+
+		events, err := r.Read()
+		require.ErrorContains(t, err, "mock error")
+		require.Nil(t, events)
+		mReader.AssertExpectations(t)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		mReader := &mockJSONReader{}
+		mReader.On("Read").Return([]*ev.JSONEvent{
+			{EventName: "INST_RETIRED.ANY"},
+			nil,
+			{EventName: "UNC_M_CAS_COUNT.RD", Unit: "imc"},
+		}, nil).Once()
+
+		r := &coreTypeReader{
+			Reader:   mReader,
+			coreType: CoreTypeAtom,
+		}
+
+		events, err := r.Read()
+		require.NoError(t, err)
+		require.Equal(t, []*ev.JSONEvent{
+			{EventName: "INST_RETIRED.ANY", Unit: "cpu_atom"},
+			nil,
+			{EventName: "UNC_M_CAS_COUNT.RD", Unit: "imc"},
+		}, events)
+		mReader.AssertExpectations(t)
+	})
+}
+
+func TestPerf_ParseCPUList(t *testing.T) {
+	testCases := []struct {
+		name     string
+		list     string
+		expected []int
+		err      error
+	}{
+		{
+			name: "Empty",
+			list: "",
+			err:  errors.New("CPU list cannot be empty"),
+		},
+		{
+			name: "InvalidCPUID",
+			list: "0-3,a",
+			err:  errors.New("invalid CPU ID \"a\""),
+		},
+		{
+			name: "InvalidUpperBound",
+			list: "0-",
+			err:  errors.New("invalid CPU ID \"\""),
+		},
+		{
+			name: "InvalidRange",
+			list: "7-4",
+			err:  errors.New("invalid CPU range \"7-4\""),
+		},
+		{
+			name:     "SingleCPUID",
+			list:     "5",
+			expected: []int{5},
+		},
+		{
+			name:     "RangesAndCPUIDs",
+			list:     "0-3,8,10-11",
+			expected: []int{0, 1, 2, 3, 8, 10, 11},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cpus, err := parseCPUList(tc.list)
+			require.Equal(t, tc.expected, cpus)
+			if tc.err != nil {
+				require.ErrorContains(t, err, tc.err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func setCPUPerfmonCounters(t *testing.T, fn func(cpu int) (int, int, error)) {
+	t.Helper()
+
+	orig := cpuPerfmonCounters
+	cpuPerfmonCounters = fn
+	t.Cleanup(func() {
+		cpuPerfmonCounters = orig
+	})
+}
+
+func TestPerf_Perf_InitHybridResolvers(t *testing.T) {
+	jsonFile := "testdata/alderlake_goldencove_core.json"
+	pmuPath := "/dummy/devices"
+	corePath := filepath.Join(pmuPath, "cpu_core", "cpus")
+	atomPath := filepath.Join(pmuPath, "cpu_atom", "cpus")
+
+	// performance cores have 8 general-purpose counters, while efficient cores have 6
+	setCPUPerfmonCounters(t, func(cpu int) (int, int, error) {
+		if cpu < 8 {
+			return 8, 4, nil
+		}
+		return 6, 3, nil
+	})
+
+	t.Run("NoJSONFiles", func(t *testing.T) {
+		p := &perf{}
+		require.ErrorContains(t, p.initHybridResolvers(nil), "no JSON files provided")
+	})
+
+	t.Run("UnsupportedCoreType", func(t *testing.T) {
+		p := &perf{}
+		require.ErrorContains(t, p.initHybridResolvers(map[CoreType]string{"cpu": jsonFile}), "unsupported core type \"cpu\"")
+	})
+
+	t.Run("FailedToReadCPUs", func(t *testing.T) {
+		mFileInfoProvider := &mockFileInfoProvider{}
+		mFileInfoProvider.On("readFile", corePath).Return(nil, errors.New("mock error")).Once()
+
+		p := &perf{
+			fileInfoReader: mFileInfoProvider,
+			pmuPath:        pmuPath,
+		}
+
+		err := p.initHybridResolvers(map[CoreType]string{CoreTypeCore: jsonFile})
+		require.ErrorContains(t, err, "error reading CPU IDs of core type \"cpu_core\": mock error")
+		require.Nil(t, p.hybridPMUs)
+		mFileInfoProvider.AssertExpectations(t)
+	})
+
+	t.Run("InvalidCPUs", func(t *testing.T) {
+		mFileInfoProvider := &mockFileInfoProvider{}
+		mFileInfoProvider.On("readFile", atomPath).Return([]byte("\n"), nil).Once()
+
+		p := &perf{
+			fileInfoReader: mFileInfoProvider,
+			pmuPath:        pmuPath,
+		}
+
+		err := p.initHybridResolvers(map[CoreType]string{CoreTypeAtom: jsonFile})
+		require.ErrorContains(t, err, "error parsing CPU IDs of core type \"cpu_atom\": CPU list cannot be empty")
+		require.Nil(t, p.hybridPMUs)
+		mFileInfoProvider.AssertExpectations(t)
+	})
+
+	t.Run("FailedToReadCounters", func(t *testing.T) {
+		mFileInfoProvider := &mockFileInfoProvider{}
+		mFileInfoProvider.On("readFile", atomPath).Return([]byte("8-9\n"), nil).Once()
+
+		setCPUPerfmonCounters(t, func(cpu int) (int, int, error) {
+			return 0, 0, fmt.Errorf("mock error %v", cpu)
+		})
+
+		p := &perf{
+			fileInfoReader: mFileInfoProvider,
+			pmuPath:        pmuPath,
+		}
+
+		err := p.initHybridResolvers(map[CoreType]string{CoreTypeAtom: jsonFile})
+		require.ErrorContains(t, err, "error reading counters of core type \"cpu_atom\": mock error 8\nmock error 9")
+		require.Nil(t, p.hybridPMUs)
+		mFileInfoProvider.AssertExpectations(t)
+	})
+
+	t.Run("CountersOfFirstAvailableCPU", func(t *testing.T) {
+		mFileInfoProvider := &mockFileInfoProvider{}
+		mFileInfoProvider.On("readFile", atomPath).Return([]byte("8-11\n"), nil).Once()
+
+		// the process is not allowed to run on CPU ID 8
+		queried := make([]int, 0)
+		setCPUPerfmonCounters(t, func(cpu int) (int, int, error) {
+			queried = append(queried, cpu)
+			if cpu == 8 {
+				return 0, 0, errors.New("mock error")
+			}
+			return 6, 3, nil
+		})
+
+		p := &perf{
+			fileInfoReader: mFileInfoProvider,
+			pmuPath:        pmuPath,
+		}
+
+		require.NoError(t, p.initHybridResolvers(map[CoreType]string{CoreTypeAtom: jsonFile}))
+		require.Len(t, p.hybridPMUs, 1)
+		require.Equal(t, 6, p.hybridPMUs[0].generalCounters)
+		require.Equal(t, 3, p.hybridPMUs[0].fixedCounters)
+		require.Equal(t, []int{8, 9}, queried)
+		mFileInfoProvider.AssertExpectations(t)
+	})
+
+	t.Run("InvalidJSONFile", func(t *testing.T) {
+		mFileInfoProvider := &mockFileInfoProvider{}
+		mFileInfoProvider.On("readFile", corePath).Return([]byte("0-7\n"), nil).Once()
+
+		p := &perf{
+			fileInfoReader: mFileInfoProvider,
+			pmuPath:        pmuPath,
+		}
+
+		err := p.initHybridResolvers(map[CoreType]string{CoreTypeCore: "testdata/not_existing.json"})
+		require.ErrorContains(t, err, "error initializing resolver for core type \"cpu_core\"")
+		require.Nil(t, p.hybridPMUs)
+		mFileInfoProvider.AssertExpectations(t)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		mFileInfoProvider := &mockFileInfoProvider{}
+		mFileInfoProvider.On("readFile", corePath).Return([]byte("0-7\n"), nil).Once()
+		mFileInfoProvider.On("readFile", atomPath).Return([]byte("8-11\n"), nil).Once()
+
+		p := &perf{
+			fileInfoReader: mFileInfoProvider,
+			pmuPath:        pmuPath,
+		}
+
+		require.NoError(t, p.initHybridResolvers(map[CoreType]string{
+			CoreTypeAtom: jsonFile,
+			CoreTypeCore: jsonFile,
+		}))
+		require.Len(t, p.hybridPMUs, 2)
+
+		// events are activated on performance cores first
+		require.Equal(t, CoreTypeCore, p.hybridPMUs[0].coreType)
+		require.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7}, p.hybridPMUs[0].cpus)
+		require.Equal(t, 8, p.hybridPMUs[0].generalCounters)
+		require.Equal(t, 4, p.hybridPMUs[0].fixedCounters)
+		require.NotNil(t, p.hybridPMUs[0].resolver)
+		require.Equal(t, CoreTypeAtom, p.hybridPMUs[1].coreType)
+		require.Equal(t, []int{8, 9, 10, 11}, p.hybridPMUs[1].cpus)
+		require.Equal(t, 6, p.hybridPMUs[1].generalCounters)
+		require.Equal(t, 3, p.hybridPMUs[1].fixedCounters)
+		require.NotNil(t, p.hybridPMUs[1].resolver)
+		mFileInfoProvider.AssertExpectations(t)
+	})
+}
+
+func TestPerf_ResolveHybridTargets(t *testing.T) {
+	newCustomEvent := func(name string, coreType CoreType) ev.CustomizableEvent {
+		return ev.CustomizableEvent{
+			Event: &ev.PerfEvent{Name: name, PMUName: string(coreType)},
+		}
+	}
+
+	mError := errors.New("event could not be resolved")
+
+	coreInst := newCustomEvent(instRetired, CoreTypeCore)
+	coreC01 := newCustomEvent(c01.String(), CoreTypeCore)
+	atomInst := newCustomEvent(instRetired, CoreTypeAtom)
+
+	t.Run("NoEvents", func(t *testing.T) {
+		targets, err := resolveHybridTargets(nil, nil, []int{0})
+		require.ErrorContains(t, err, "event group cannot be empty")
+		require.Nil(t, targets)
+	})
+
+	t.Run("NoCPUsOfCoreTypes", func(t *testing.T) {
+		pmus := []*hybridPMU{
+			{coreType: CoreTypeCore, resolver: &mockResolver{}, cpus: []int{0, 1}},
+		}
+
+		targets, err := resolveHybridTargets(pmus, []string{instRetired}, []int{2, 3})
+		require.ErrorContains(t, err, "none of the CPU IDs belong to a supported core type")
+		require.Nil(t, targets)
+	})
+
+	t.Run("EventNotResolved", func(t *testing.T) {
+		mCoreResolver := &mockResolver{}
+		mCoreResolver.On("resolveEvents", []string{"EVENT.MOCK"}).Return(nil, mError).Once()
+
+		mAtomResolver := &mockResolver{}
+		mAtomResolver.On("resolveEvents", []string{"EVENT.MOCK"}).Return(nil, mError).Once()
+
+		pmus := []*hybridPMU{
+			{coreType: CoreTypeCore, resolver: mCoreResolver, cpus: []int{0, 1}},
+			{coreType: CoreTypeAtom, resolver: mAtomResolver, cpus: []int{2, 3}},
+		}
+
+		targets, err := resolveHybridTargets(pmus, []string{"EVENT.MOCK"}, []int{0, 1, 2, 3})
+		require.ErrorContains(t, err, "event \"EVENT.MOCK\" could not be resolved for any core type")
+		require.ErrorContains(t, err, fmt.Sprintf("core type \"cpu_core\": %v", mError))
+		require.ErrorContains(t, err, fmt.Sprintf("core type \"cpu_atom\": %v", mError))
+		require.Nil(t, targets)
+		mCoreResolver.AssertExpectations(t)
+		mAtomResolver.AssertExpectations(t)
+	})
+
+	t.Run("EventsResolvedPerCoreType", func(t *testing.T) {
+		mCoreResolver := &mockResolver{}
+		mCoreResolver.On("resolveEvents", []string{instRetired}).Return([]ev.CustomizableEvent{coreInst}, nil).Once()
+		mCoreResolver.On("resolveEvents", []string{c01.String()}).Return([]ev.CustomizableEvent{coreC01}, nil).Once()
+
+		// C0.1 substate event is not available for efficient cores
+		mAtomResolver := &mockResolver{}
+		mAtomResolver.On("resolveEvents", []string{instRetired}).Return([]ev.CustomizableEvent{atomInst}, nil).Once()
+		mAtomResolver.On("resolveEvents", []string{c01.String()}).Return(nil, mError).Once()
+
+		pmus := []*hybridPMU{
+			{coreType: CoreTypeCore, resolver: mCoreResolver, cpus: []int{0, 1, 2, 3}, generalCounters: 8, fixedCounters: 4},
+			{coreType: CoreTypeAtom, resolver: mAtomResolver, cpus: []int{4, 5, 6, 7}, generalCounters: 6, fixedCounters: 3},
+		}
+
+		targets, err := resolveHybridTargets(pmus, []string{instRetired, c01.String()}, []int{1, 3, 5, 7})
+		require.NoError(t, err)
+		require.Equal(t, []activationTarget{
+			{events: []ev.CustomizableEvent{coreInst, coreC01}, cores: []int{1, 3}, generalCounters: 8, fixedCounters: 4},
+			{events: []ev.CustomizableEvent{atomInst}, cores: []int{5, 7}, generalCounters: 6, fixedCounters: 3},
+		}, targets)
+		mCoreResolver.AssertExpectations(t)
+		mAtomResolver.AssertExpectations(t)
+	})
+}
+
+func TestPerf_Perf_ActivateHybrid(t *testing.T) {
+	events := []string{instRetired, thread.String()}
+
+	newCustomEvents := func(coreType CoreType) []ev.CustomizableEvent {
+		customEvents := make([]ev.CustomizableEvent, 0, len(events))
+		for _, event := range events {
+			customEvents = append(customEvents, ev.CustomizableEvent{
+				Event: &ev.PerfEvent{Name: event, PMUName: string(coreType)},
+			})
+		}
+		return customEvents
+	}
+	coreEvents := newCustomEvents(CoreTypeCore)
+	atomEvents := newCustomEvents(CoreTypeAtom)
+
+	mCoreResolver := &mockResolver{}
+	mAtomResolver := &mockResolver{}
+	for i, event := range events {
+		mCoreResolver.On("resolveEvents", []string{event}).Return(coreEvents[i:i+1], nil)
+		mAtomResolver.On("resolveEvents", []string{event}).Return(atomEvents[i:i+1], nil)
+	}
+
+	pmus := []*hybridPMU{
+		{coreType: CoreTypeCore, resolver: mCoreResolver, cpus: []int{0, 1}},
+		{coreType: CoreTypeAtom, resolver: mAtomResolver, cpus: []int{2, 3, 4}},
+	}
+
+	t.Run("FailedToCheckFileDescriptors", func(t *testing.T) {
+		// 2 events on 2 performance cores and 2 events on 3 efficient cores
+		mFileInfoProvider := &mockFileInfoProvider{}
+		mFileInfoProvider.On("readFile", fileMaxPath).Return([]byte("9\n"), nil).Once()
+
+		p := &perf{
+			hybridPMUs:     pmus,
+			fileInfoReader: mFileInfoProvider,
+		}
+
+		err := p.activate(events, []int{0, 1, 2, 3, 4})
+		require.ErrorContains(t, err, "required file descriptors 10, exceeds the maximum number of available file descriptors 9")
+		mFileInfoProvider.AssertExpectations(t)
+	})
+
+	t.Run("EventsActivated", func(t *testing.T) {
+		mFileInfoProvider := &mockFileInfoProvider{}
+		mFileInfoProvider.On("readFile", fileMaxPath).Return([]byte("10\n"), nil).Once()
+		mFileInfoProvider.On("rlimit").Return(uint64(10), nil).Once()
+
+		coreActiveEvents := []*ev.ActiveEvent{{PerfEvent: coreEvents[0].Event}, {PerfEvent: coreEvents[1].Event}}
+		atomActiveEvents := []*ev.ActiveEvent{{PerfEvent: atomEvents[0].Event}, {PerfEvent: atomEvents[1].Event}}
+
+		mActivator := &mockActivator{}
+		mActivator.On("activateEvents", coreEvents, []int{0, 1}).Return(coreActiveEvents, nil).Once()
+		mActivator.On("activateEvents", atomEvents, []int{2, 3, 4}).Return(atomActiveEvents, nil).Once()
+
+		p := &perf{
+			hybridPMUs:     pmus,
+			activator:      mActivator,
+			fileInfoReader: mFileInfoProvider,
+		}
+
+		require.NoError(t, p.activate(events, []int{0, 1, 2, 3, 4}))
+		require.Equal(t, append(coreActiveEvents, atomActiveEvents...), p.activeEvents)
+		require.Equal(t, []int{0, 1, 2, 3, 4}, p.activeCPUIDs())
+		mFileInfoProvider.AssertExpectations(t)
+		mActivator.AssertExpectations(t)
+	})
+
+	t.Run("GroupsSizedPerCoreType", func(t *testing.T) {
+		mFileInfoProvider := &mockFileInfoProvider{}
+		mFileInfoProvider.On("readFile", fileMaxPath).Return([]byte("10\n"), nil).Once()
+		mFileInfoProvider.On("rlimit").Return(uint64(10), nil).Once()
+
+		coreActiveEvents := []*ev.ActiveEvent{{PerfEvent: coreEvents[0].Event}, {PerfEvent: coreEvents[1].Event}}
+		atomActiveEvents := []*ev.ActiveEvent{{PerfEvent: atomEvents[0].Event}, {PerfEvent: atomEvents[1].Event}}
+
+		// both events fit the 2 general-purpose counters of performance cores, while efficient cores have a single one.
+		mActivator := &mockActivator{}
+		mActivator.On("activateEvents", coreEvents, []int{0, 1}).Return(coreActiveEvents, nil).Once()
+		mActivator.On("activateEvents", atomEvents[:1], []int{2, 3, 4}).Return(atomActiveEvents[:1], nil).Once()
+		mActivator.On("activateEvents", atomEvents[1:], []int{2, 3, 4}).Return(atomActiveEvents[1:], nil).Once()
+
+		p := &perf{
+			hybridPMUs: []*hybridPMU{
+				{coreType: CoreTypeCore, resolver: mCoreResolver, cpus: []int{0, 1}, generalCounters: 2},
+				{coreType: CoreTypeAtom, resolver: mAtomResolver, cpus: []int{2, 3, 4}, generalCounters: 1},
+			},
+			activator:      mActivator,
+			fileInfoReader: mFileInfoProvider,
+		}
+
+		require.NoError(t, p.activate(events, []int{0, 1, 2, 3, 4}))
+		require.Equal(t, append(coreActiveEvents, atomActiveEvents...), p.activeEvents)
+		mFileInfoProvider.AssertExpectations(t)
+		mActivator.AssertExpectations(t)
+	})
+
+	t.Run("CPUsWithoutCoreType", func(t *testing.T) {
+		mFileInfoProvider := &mockFileInfoProvider{}
+		mFileInfoProvider.On("readFile", fileMaxPath).Return([]byte("10\n"), nil).Once()
+		mFileInfoProvider.On("rlimit").Return(uint64(10), nil).Once()
+
+		coreActiveEvents := []*ev.ActiveEvent{{PerfEvent: coreEvents[0].Event}, {PerfEvent: coreEvents[1].Event}}
+
+		mActivator := &mockActivator{}
+		mActivator.On("activateEvents", coreEvents, []int{1}).Return(coreActiveEvents, nil).Once()
+
+		p := &perf{
+			hybridPMUs:     pmus[:1],
+			activator:      mActivator,
+			fileInfoReader: mFileInfoProvider,
+		}
+
+		// CPU IDs of core types without resolved events have no active events.
+		require.NoError(t, p.activate(events, []int{1, 2, 3}))
+		require.Equal(t, []int{1}, p.activeCPUIDs())
+		mFileInfoProvider.AssertExpectations(t)
+		mActivator.AssertExpectations(t)
+	})
+}
+
+func TestPerf_EventsReader_ReadEventsCoreType(t *testing.T) {
+	events := []*ev.ActiveEvent{
+		{PerfEvent: &ev.PerfEvent{Name: instRetired, PMUName: "cpu_core"}},
+		{PerfEvent: &ev.PerfEvent{Name: instRetired, PMUName: "cpu_atom"}},
+		{PerfEvent: &ev.PerfEvent{Name: instRetired, PMUName: "cpu"}},
+	}
+
+	mValuesReader := &mockValuesReader{}
+	for _, event := range events {
+		mValuesReader.On("readValue", event).Return(ev.CounterValue{Raw: 1, Enabled: 1, Running: 1}, nil).Once()
+	}
+
+	reader := &eventsReaderImpl{
+		eventReader: mValuesReader,
+	}
+
+	metrics, err := reader.readEvents(events)
+	require.NoError(t, err)
+	require.Len(t, metrics, len(events))
+	require.Equal(t, CoreTypeCore, metrics[0].coreType)
+	require.Equal(t, CoreTypeAtom, metrics[1].coreType)
+	require.Empty(t, metrics[2].coreType)
+	mValuesReader.AssertExpectations(t)
+}
@@ -0,0 +1,37 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"slices"
+)
+
+// Name of the PMU exposed by `perf_events` kernel interface, which counts free-running MSR counters.
+const msrPMU = "msr"
+
+// Names of events of msr PMU.
+const (
+	msrPMUTsc   = "tsc"   // IA32_TIME_STAMP_COUNTER
+	msrPMUAperf = "aperf" // IA32_APERF
+	msrPMUMperf = "mperf" // IA32_MPERF
+)
+
+// msrPMUEvents is a slice of events of msr PMU to be activated, i.e. those read by C0 state residency and busy
+// frequency metrics. Events not supported by the processor model are not exposed by the kernel.
+var msrPMUEvents = []string{msrPMUTsc, msrPMUAperf, msrPMUMperf}
+
+// msrPMUs is a slice with msr PMU, whose events count per CPU ID.
+var msrPMUs = []sysfsPMU{
+	{
+		name:    msrPMU,
+		isEvent: isMsrPMUEvent,
+	},
+}
+
+// isMsrPMUEvent takes the name of an event exposed by msr PMU and reports whether it is activated.
+func isMsrPMUEvent(name string) bool {
+	return slices.Contains(msrPMUEvents, name)
+}
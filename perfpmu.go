@@ -0,0 +1,344 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	ev "github.com/intel/iaevents"
+)
+
+// errPMUCounterNotFound indicates that a PMU counter is not exposed, e.g. a C-state residency counter not supported
+// by the processor model.
+var errPMUCounterNotFound = errors.New("counter not found")
+
+// sysfsPMU describes a PMU of `perf_events` kernel interface which exposes free-running counters, whose events
+// are discovered from sysfs.
+type sysfsPMU struct {
+	name       string            // name of the PMU, as exposed in sysfs
+	perPackage bool              // events count per package, hence they are activated on a single CPU ID of each package
+	isEvent    func(string) bool // reports whether an event exposed by the PMU shall be activated
+}
+
+// pmuCounter identifies an event of a sysfs PMU.
+type pmuCounter struct {
+	pmu   string
+	event string
+}
+
+// pmuEventActivator activates an event of a sysfs PMU on a CPU ID.
+type pmuEventActivator interface {
+	activateEvent(event *ev.PerfEvent, cpuID int) (*ev.ActiveEvent, error)
+}
+
+// pmuEventActivatorImpl implements pmuEventActivator interface.
+type pmuEventActivatorImpl struct{}
+
+// activateEvent takes a perf event of a sysfs PMU and a CPU ID, and activates the event on the CPU ID, counting
+// for all processes.
+func (*pmuEventActivatorImpl) activateEvent(event *ev.PerfEvent, cpuID int) (*ev.ActiveEvent, error) {
+	if event == nil {
+		return nil, errors.New("perf event is nil")
+	}
+
+	placements, err := event.NewPlacements(event.PMUName, cpuID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make placement: %w", err)
+	}
+
+	options, err := ev.NewOptions().Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build event options: %w", err)
+	}
+	return event.Activate(placements[0], ev.NewEventTargetProcess(-1, 0), options)
+}
+
+// pmuValuesReader reads values of an active event of a sysfs PMU.
+type pmuValuesReader interface {
+	readValue(event *ev.ActiveEvent) (ev.CounterValue, error)
+}
+
+// pmuValuesReaderImpl implements pmuValuesReader interface.
+type pmuValuesReaderImpl struct{}
+
+// readValue takes an active event and returns its values. It is a wrapper of ReadValue method of an
+// ev.ActiveEvent value type.
+func (*pmuValuesReaderImpl) readValue(event *ev.ActiveEvent) (ev.CounterValue, error) {
+	return event.ReadValue()
+}
+
+// pmuCounterReader represents a mechanism for reading free-running counters of sysfs PMUs, per CPU ID, via
+// `perf_events` kernel interface.
+type pmuCounterReader interface {
+	// initCounterMap takes a slice of CPU IDs and a slice of CPU IDs on which per package events are activated. It
+	// activates the events of the PMUs for each CPU ID, and initializes the counter storage with an initial read.
+	initCounterMap(cpus, packageCPUs []int) error
+
+	// update takes a CPU ID and reads the counters activated on it.
+	update(cpuID int) error
+
+	// getCounterDeltas takes a CPU ID and returns the counter deltas between the latest and the previous
+	// reading operation.
+	getCounterDeltas(cpuID int) (map[pmuCounter]uint64, error)
+
+	// getTimestampDelta takes a CPU ID and returns the time interval between the latest and the previous
+	// reading operation.
+	getTimestampDelta(cpuID int) (time.Duration, error)
+
+	// deactivate deactivates the events of all CPU IDs.
+	deactivate() error
+}
+
+// cpuPMUCounterStorage holds the active events of sysfs PMUs on a CPU ID, their counter values and the timestamps
+// of its reading operations.
+type cpuPMUCounterStorage struct {
+	cpuID          int
+	events         map[pmuCounter]*ev.ActiveEvent
+	values         map[pmuCounter]uint64 // counter values from the last read operation
+	deltas         map[pmuCounter]uint64 // counter deltas between the latest and its previous reading operation
+	timestamp      time.Time             // timestamp of the last reading operation
+	timestampDelta time.Duration         // timestamp delta between the last read and its previous reading operation
+}
+
+// pmuCounterData allows to read free-running counters of sysfs PMUs. Implements pmuCounterReader interface.
+type pmuCounterData struct {
+	basePath     string
+	pmus         []sysfsPMU
+	activator    pmuEventActivator
+	valuesReader pmuValuesReader
+
+	cpuMap map[int]*cpuPMUCounterStorage
+}
+
+// newPMUCounterData takes a base path of sysfs PMUs and a slice of PMUs, and returns a pmuCounterData with
+// the default activation and reading mechanisms.
+func newPMUCounterData(basePath string, pmus []sysfsPMU) *pmuCounterData {
+	return &pmuCounterData{
+		basePath:     basePath,
+		pmus:         pmus,
+		activator:    &pmuEventActivatorImpl{},
+		valuesReader: &pmuValuesReaderImpl{},
+	}
+}
+
+// initCounterMap takes a slice of CPU IDs and a slice of CPU IDs on which per package events are activated. It discovers
+// the events of each PMU of the receiver and activates them on each CPU ID. If an event could not be activated, the events
+// activated so far are deactivated and an error is returned.
+func (p *pmuCounterData) initCounterMap(cpus, packageCPUs []int) error {
+	if len(p.basePath) == 0 {
+		return errors.New("base path of PMUs cannot be empty")
+	}
+	if len(p.pmus) == 0 {
+		return errors.New("no PMUs provided")
+	}
+
+	cpuMap := make(map[int]*cpuPMUCounterStorage, len(cpus))
+	for _, cpuID := range cpus {
+		cpuMap[cpuID] = &cpuPMUCounterStorage{
+			cpuID:  cpuID,
+			events: make(map[pmuCounter]*ev.ActiveEvent),
+			values: make(map[pmuCounter]uint64),
+			deltas: make(map[pmuCounter]uint64),
+		}
+	}
+
+	for _, pmu := range p.pmus {
+		events, err := discoverPMUEvents(p.basePath, pmu)
+		if err != nil {
+			_ = deactivatePMUCounters(cpuMap)
+			return err
+		}
+
+		pmuCPUs := cpus
+		if pmu.perPackage {
+			pmuCPUs = packageCPUs
+		}
+		for _, cpuID := range pmuCPUs {
+			storage, ok := cpuMap[cpuID]
+			if !ok {
+				_ = deactivatePMUCounters(cpuMap)
+				return fmt.Errorf("CPU ID %v of package events of %s PMU is not available", cpuID, pmu.name)
+			}
+			for _, event := range events {
+				active, err := p.activator.activateEvent(event, cpuID)
+				if err != nil {
+					_ = deactivatePMUCounters(cpuMap)
+					return fmt.Errorf("error during activation of event %q of %s PMU for CPU ID %v: %w", event.Name, pmu.name, cpuID, err)
+				}
+				storage.events[pmuCounter{pmu: pmu.name, event: event.Name}] = active
+			}
+		}
+	}
+
+	for cpuID, storage := range cpuMap {
+		if err := storage.update(p.valuesReader); err != nil {
+			_ = deactivatePMUCounters(cpuMap)
+			return fmt.Errorf("error reading PMU counters for CPU ID %v: %w", cpuID, err)
+		}
+	}
+	p.cpuMap = cpuMap
+	return nil
+}
+
+// update takes a CPU ID and updates the storage with the latest values of the counters activated on it.
+func (p *pmuCounterData) update(cpuID int) error {
+	storage, err := p.getStorage(cpuID)
+	if err != nil {
+		return err
+	}
+	return storage.update(p.valuesReader)
+}
+
+// getCounterDeltas takes a CPU ID and returns the counter deltas between the latest and the previous reading operation.
+func (p *pmuCounterData) getCounterDeltas(cpuID int) (map[pmuCounter]uint64, error) {
+	storage, err := p.getStorage(cpuID)
+	if err != nil {
+		return nil, err
+	}
+	return storage.deltas, nil
+}
+
+// getTimestampDelta takes a CPU ID and returns the time interval between the latest and the previous reading
+// operation of its counters.
+func (p *pmuCounterData) getTimestampDelta(cpuID int) (time.Duration, error) {
+	storage, err := p.getStorage(cpuID)
+	if err != nil {
+		return 0, err
+	}
+	return storage.timestampDelta, nil
+}
+
+// deactivate deactivates the events of all CPU IDs. If one or more events could not be deactivated, an error
+// is returned.
+func (p *pmuCounterData) deactivate() error {
+	err := deactivatePMUCounters(p.cpuMap)
+	p.cpuMap = nil
+	return err
+}
+
+// getStorage takes a CPU ID and returns its PMU counter storage.
+func (p *pmuCounterData) getStorage(cpuID int) (*cpuPMUCounterStorage, error) {
+	storage, ok := p.cpuMap[cpuID]
+	if !ok {
+		return nil, fmt.Errorf("could not find PMU counters for CPU ID: %v", cpuID)
+	}
+	return storage, nil
+}
+
+// update takes a pmuValuesReader and reads the counters of the receiver, updating both the last read values
+// and the deltas with respect to the previous reading operation.
+func (s *cpuPMUCounterStorage) update(reader pmuValuesReader) error {
+	latest := make(map[pmuCounter]uint64, len(s.events))
+	for counter, event := range s.events {
+		values, err := reader.readValue(event)
+		if err != nil {
+			return fmt.Errorf("failed to read event %q of %s PMU: %w", counter.event, counter.pmu, err)
+		}
+		latest[counter] = values.Raw
+	}
+	timestamp := timeNowFn()
+
+	deltas := make(map[pmuCounter]uint64, len(latest))
+	if !s.timestamp.IsZero() {
+		for counter, value := range latest {
+			prev := s.values[counter]
+			if value < prev {
+				return fmt.Errorf("counter of event %q of %s PMU decreased since the previous read", counter.event, counter.pmu)
+			}
+			deltas[counter] = value - prev
+		}
+		s.timestampDelta = timestamp.Sub(s.timestamp)
+	}
+
+	s.values = latest
+	s.deltas = deltas
+	s.timestamp = timestamp
+	return nil
+}
+
+// deactivatePMUCounters takes a map of PMU counter storages and deactivates their active events. If one or more
+// events could not be deactivated, an error is returned.
+func deactivatePMUCounters(cpuMap map[int]*cpuPMUCounterStorage) error {
+	var errs []error
+	for cpuID, storage := range cpuMap {
+		for counter, event := range storage.events {
+			if event == nil {
+				continue
+			}
+			if err := event.Deactivate(); err != nil {
+				errs = append(errs, fmt.Errorf("failed to deactivate event %q of %s PMU for CPU ID %v: %w", counter.event, counter.pmu, cpuID, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// discoverPMUEvents takes a base path of sysfs PMUs and a PMU, and returns the perf events of the PMU to be activated,
+// resolved from the type of the PMU and the encoding of each event exposed in its events directory.
+func discoverPMUEvents(basePath string, pmu sysfsPMU) ([]*ev.PerfEvent, error) {
+	pmuPath := filepath.Join(basePath, pmu.name)
+	pmuType, err := readSysfsUint(pmuPath, "type")
+	if err != nil {
+		return nil, fmt.Errorf("error reading type of %s PMU: %w", pmu.name, err)
+	}
+
+	eventsPath := filepath.Join(pmuPath, "events")
+	entries, err := os.ReadDir(eventsPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading events of %s PMU: %w", pmu.name, err)
+	}
+
+	events := make([]*ev.PerfEvent, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if pmu.isEvent != nil && !pmu.isEvent(name) {
+			continue
+		}
+
+		encoding, err := readSysfsString(eventsPath, name)
+		if err != nil {
+			return nil, fmt.Errorf("error reading event %q of %s PMU: %w", name, pmu.name, err)
+		}
+		config, err := parsePMUEventConfig(encoding)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing event %q of %s PMU: %w", name, pmu.name, err)
+		}
+
+		event := ev.NewPerfEvent()
+		event.Name = name
+		event.PMUName = pmu.name
+		event.PMUTypes = []ev.NamedPMUType{{Name: pmu.name, PMUType: uint32(pmuType)}}
+		event.Attr.Type = uint32(pmuType)
+		event.Attr.Config = config
+		events = append(events, event)
+	}
+
+	if len(events) == 0 {
+		return nil, fmt.Errorf("no events found for %s PMU", pmu.name)
+	}
+	return events, nil
+}
+
+// parsePMUEventConfig takes the encoding of an event exposed by a sysfs PMU, e.g. "event=0x02", and returns the config
+// of the event. Only PMUs whose event format maps to the whole config field, such as cstate and msr PMUs, are supported.
+func parsePMUEventConfig(encoding string) (uint64, error) {
+	value, ok := strings.CutPrefix(strings.TrimSpace(encoding), "event=")
+	if !ok || strings.Contains(value, ",") {
+		return 0, fmt.Errorf("unsupported event encoding %q", encoding)
+	}
+
+	config, err := strconv.ParseUint(value, 0, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid event code %q: %w", value, err)
+	}
+	return config, nil
+}
@@ -0,0 +1,419 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ev "github.com/intel/iaevents"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// pmuEventActivatorMock represents a mock for pmuEventActivatorImpl type. Implements pmuEventActivator interface.
+type pmuEventActivatorMock struct {
+	mock.Mock
+}
+
+func (m *pmuEventActivatorMock) activateEvent(event *ev.PerfEvent, cpuID int) (*ev.ActiveEvent, error) {
+	args := m.Called(event, cpuID)
+	if fn, ok := args.Get(0).(func(*ev.PerfEvent, int) *ev.ActiveEvent); ok {
+		return fn(event, cpuID), args.Error(1)
+	}
+	return args.Get(0).(*ev.ActiveEvent), args.Error(1)
+}
+
+// pmuValuesReaderFake implements pmuValuesReader interface, returning the raw values of active events set by
+// the test. Active events are identified by the name of their perf event and the CPU ID, which is held by the
+// file descriptor of the active events returned by newTestActiveEvent.
+type pmuValuesReaderFake struct {
+	values map[string]uint64
+	err    error
+}
+
+func (f *pmuValuesReaderFake) readValue(event *ev.ActiveEvent) (ev.CounterValue, error) {
+	if f.err != nil {
+		return ev.CounterValue{}, f.err
+	}
+	return ev.CounterValue{Raw: f.values[activeEventKey(event.PerfEvent.Name, event.FileDescriptor)]}, nil
+}
+
+// activeEventKey takes the name of an event and a CPU ID, and returns the key of its value in pmuValuesReaderFake.
+func activeEventKey(name string, cpuID int) string {
+	return fmt.Sprintf("%s/%d", name, cpuID)
+}
+
+// newTestActiveEvent takes a perf event and a CPU ID, and returns an active event which can be read by pmuValuesReaderFake.
+func newTestActiveEvent(event *ev.PerfEvent, cpuID int) *ev.ActiveEvent {
+	return &ev.ActiveEvent{
+		PerfEvent:      event,
+		FileDescriptor: cpuID,
+	}
+}
+
+// writeSysfsPMU takes a base path, the name of a PMU, its type and a map with event names as keys and their
+// encodings as values, and writes the files of the PMU as exposed by `perf_events` kernel interface.
+func writeSysfsPMU(t *testing.T, basePath, name, pmuType string, events map[string]string) {
+	t.Helper()
+
+	eventsPath := filepath.Join(basePath, name, "events")
+	require.NoError(t, os.MkdirAll(eventsPath, 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(basePath, name, "type"), []byte(pmuType+"\n"), 0640))
+	for event, encoding := range events {
+		require.NoError(t, os.WriteFile(filepath.Join(eventsPath, event), []byte(encoding+"\n"), 0640))
+	}
+}
+
+func TestParsePMUEventConfig(t *testing.T) {
+	testCases := []struct {
+		name     string
+		encoding string
+		config   uint64
+		err      string
+	}{
+		{
+			name:     "Hex",
+			encoding: "event=0x02",
+			config:   0x02,
+		},
+		{
+			name:     "Decimal",
+			encoding: "event=3\n",
+			config:   3,
+		},
+		{
+			name:     "MissingEventTerm",
+			encoding: "umask=0x01",
+			err:      "unsupported event encoding",
+		},
+		{
+			name:     "MultipleTerms",
+			encoding: "event=0x3c,umask=0x01",
+			err:      "unsupported event encoding",
+		},
+		{
+			name:     "InvalidCode",
+			encoding: "event=0xzz",
+			err:      "invalid event code \"0xzz\"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := parsePMUEventConfig(tc.encoding)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				require.Zero(t, config)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.config, config)
+		})
+	}
+}
+
+func TestDiscoverPMUEvents(t *testing.T) {
+	pmu := sysfsPMU{
+		name:    cstateCorePMU,
+		isEvent: isCStateResidencyEvent,
+	}
+
+	t.Run("TypeNotFound", func(t *testing.T) {
+		events, err := discoverPMUEvents(t.TempDir(), pmu)
+		require.ErrorContains(t, err, "error reading type of cstate_core PMU")
+		require.Nil(t, events)
+	})
+
+	t.Run("EventsNotFound", func(t *testing.T) {
+		basePath := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(basePath, cstateCorePMU), 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(basePath, cstateCorePMU, "type"), []byte("24\n"), 0640))
+
+		events, err := discoverPMUEvents(basePath, pmu)
+		require.ErrorContains(t, err, "error reading events of cstate_core PMU")
+		require.Nil(t, events)
+	})
+
+	t.Run("InvalidEncoding", func(t *testing.T) {
+		basePath := t.TempDir()
+		writeSysfsPMU(t, basePath, cstateCorePMU, "24", map[string]string{
+			"c6-residency": "config=0x02",
+		})
+
+		events, err := discoverPMUEvents(basePath, pmu)
+		require.ErrorContains(t, err, "error parsing event \"c6-residency\" of cstate_core PMU")
+		require.Nil(t, events)
+	})
+
+	t.Run("NoEvents", func(t *testing.T) {
+		basePath := t.TempDir()
+		writeSysfsPMU(t, basePath, cstateCorePMU, "24", map[string]string{
+			"c6-residency.unit": "cycles",
+		})
+
+		events, err := discoverPMUEvents(basePath, pmu)
+		require.ErrorContains(t, err, "no events found for cstate_core PMU")
+		require.Nil(t, events)
+	})
+
+	t.Run("Ok", func(t *testing.T) {
+		basePath := t.TempDir()
+		writeSysfsPMU(t, basePath, cstateCorePMU, "24", map[string]string{
+			"c3-residency":      "event=0x01",
+			"c6-residency":      "event=0x02",
+			"c6-residency.unit": "cycles",
+		})
+
+		events, err := discoverPMUEvents(basePath, pmu)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+
+		for i, name := range []string{"c3-residency", "c6-residency"} {
+			require.Equal(t, name, events[i].Name)
+			require.Equal(t, cstateCorePMU, events[i].PMUName)
+			require.Equal(t, []ev.NamedPMUType{{Name: cstateCorePMU, PMUType: 24}}, events[i].PMUTypes)
+			require.Equal(t, uint32(24), events[i].Attr.Type)
+			require.Equal(t, uint64(i+1), events[i].Attr.Config)
+		}
+	})
+}
+
+func TestPMUCounterData_InitCounterMap(t *testing.T) {
+	cpus := []int{0, 1}
+	packageCPUs := []int{0}
+
+	basePath := t.TempDir()
+	writeSysfsPMU(t, basePath, cstateCorePMU, "24", map[string]string{
+		"c6-residency": "event=0x02",
+	})
+	writeSysfsPMU(t, basePath, cstatePkgPMU, "25", map[string]string{
+		"c2-residency": "event=0x00",
+	})
+
+	t.Run("EmptyBasePath", func(t *testing.T) {
+		p := &pmuCounterData{
+			pmus: cstatePMUs,
+		}
+
+		require.ErrorContains(t, p.initCounterMap(cpus, packageCPUs), "base path of PMUs cannot be empty")
+		require.Nil(t, p.cpuMap)
+	})
+
+	t.Run("NoPMUs", func(t *testing.T) {
+		p := &pmuCounterData{
+			basePath: basePath,
+		}
+
+		require.ErrorContains(t, p.initCounterMap(cpus, packageCPUs), "no PMUs provided")
+		require.Nil(t, p.cpuMap)
+	})
+
+	t.Run("PMUNotFound", func(t *testing.T) {
+		p := &pmuCounterData{
+			basePath: basePath,
+			pmus:     []sysfsPMU{{name: "msr"}},
+		}
+
+		require.ErrorContains(t, p.initCounterMap(cpus, packageCPUs), "error reading type of msr PMU")
+		require.Nil(t, p.cpuMap)
+	})
+
+	t.Run("PackageCPUNotAvailable", func(t *testing.T) {
+		m := &pmuEventActivatorMock{}
+		m.On("activateEvent", mock.AnythingOfType("*iaevents.PerfEvent"), mock.AnythingOfType("int")).Return(&ev.ActiveEvent{}, nil).Twice()
+
+		p := &pmuCounterData{
+			basePath:  basePath,
+			pmus:      cstatePMUs,
+			activator: m,
+		}
+
+		require.ErrorContains(t, p.initCounterMap(cpus, []int{2}), "CPU ID 2 of package events of cstate_pkg PMU is not available")
+		require.Nil(t, p.cpuMap)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("FailedToActivate", func(t *testing.T) {
+		m := &pmuEventActivatorMock{}
+		m.On("activateEvent", mock.AnythingOfType("*iaevents.PerfEvent"), 0).Return(&ev.ActiveEvent{}, nil).Once()
+		m.On("activateEvent", mock.AnythingOfType("*iaevents.PerfEvent"), 1).Return(&ev.ActiveEvent{}, errors.New("mock error")).Once()
+
+		p := &pmuCounterData{
+			basePath:  basePath,
+			pmus:      cstatePMUs,
+			activator: m,
+		}
+
+		err := p.initCounterMap(cpus, packageCPUs)
+		require.ErrorContains(t, err, "error during activation of event \"c6-residency\" of cstate_core PMU for CPU ID 1")
+		require.Nil(t, p.cpuMap)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("FailedToRead", func(t *testing.T) {
+		m := &pmuEventActivatorMock{}
+		m.On("activateEvent", mock.AnythingOfType("*iaevents.PerfEvent"), mock.AnythingOfType("int")).Return(&ev.ActiveEvent{}, nil).Times(3)
+
+		p := &pmuCounterData{
+			basePath:     basePath,
+			pmus:         cstatePMUs,
+			activator:    m,
+			valuesReader: &pmuValuesReaderFake{err: errors.New("mock error")},
+		}
+
+		err := p.initCounterMap(cpus, packageCPUs)
+		require.ErrorContains(t, err, "error reading PMU counters for CPU ID")
+		require.Nil(t, p.cpuMap)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("Ok", func(t *testing.T) {
+		m := &pmuEventActivatorMock{}
+		m.On("activateEvent", mock.AnythingOfType("*iaevents.PerfEvent"), mock.AnythingOfType("int")).Return(newTestActiveEvent, nil).Times(3)
+
+		p := &pmuCounterData{
+			basePath:  basePath,
+			pmus:      cstatePMUs,
+			activator: m,
+			valuesReader: &pmuValuesReaderFake{
+				values: map[string]uint64{
+					activeEventKey("c6-residency", 0): 100,
+					activeEventKey("c6-residency", 1): 200,
+					activeEventKey("c2-residency", 0): 300,
+				},
+			},
+		}
+
+		require.NoError(t, p.initCounterMap(cpus, packageCPUs))
+		require.Len(t, p.cpuMap, 2)
+
+		coreC6 := pmuCounter{pmu: cstateCorePMU, event: "c6-residency"}
+		pkgC2 := pmuCounter{pmu: cstatePkgPMU, event: "c2-residency"}
+		require.Equal(t, map[pmuCounter]uint64{coreC6: 100, pkgC2: 300}, p.cpuMap[0].values)
+		require.Equal(t, map[pmuCounter]uint64{coreC6: 200}, p.cpuMap[1].values)
+
+		// deltas are not available until the counters are read again.
+		for _, cpuID := range cpus {
+			deltas, err := p.getCounterDeltas(cpuID)
+			require.NoError(t, err)
+			require.Empty(t, deltas)
+
+			interval, err := p.getTimestampDelta(cpuID)
+			require.NoError(t, err)
+			require.Zero(t, interval)
+		}
+		m.AssertExpectations(t)
+	})
+}
+
+func TestPMUCounterData_Update(t *testing.T) {
+	setFakeClock()
+	defer unsetFakeClock()
+	fakeClock.Set(time.Now())
+
+	cpuID := 0
+	basePath := t.TempDir()
+	writeSysfsPMU(t, basePath, cstateCorePMU, "24", map[string]string{
+		"c6-residency": "event=0x02",
+	})
+
+	m := &pmuEventActivatorMock{}
+	m.On("activateEvent", mock.AnythingOfType("*iaevents.PerfEvent"), cpuID).Return(newTestActiveEvent, nil).Once()
+
+	reader := &pmuValuesReaderFake{
+		values: map[string]uint64{
+			activeEventKey("c6-residency", cpuID): 1000,
+		},
+	}
+	p := &pmuCounterData{
+		basePath:     basePath,
+		pmus:         []sysfsPMU{cstatePMUs[0]},
+		activator:    m,
+		valuesReader: reader,
+	}
+	require.NoError(t, p.initCounterMap([]int{cpuID}, nil))
+	m.AssertExpectations(t)
+
+	counter := pmuCounter{pmu: cstateCorePMU, event: "c6-residency"}
+
+	t.Run("CPUIDNotFound", func(t *testing.T) {
+		require.ErrorContains(t, p.update(1), "could not find PMU counters for CPU ID: 1")
+
+		_, err := p.getCounterDeltas(1)
+		require.ErrorContains(t, err, "could not find PMU counters for CPU ID: 1")
+
+		_, err = p.getTimestampDelta(1)
+		require.ErrorContains(t, err, "could not find PMU counters for CPU ID: 1")
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		reader.values[activeEventKey("c6-residency", cpuID)] = 1500
+		fakeClock.Add(5 * time.Second)
+
+		require.NoError(t, p.update(cpuID))
+
+		deltas, err := p.getCounterDeltas(cpuID)
+		require.NoError(t, err)
+		require.Equal(t, map[pmuCounter]uint64{counter: 500}, deltas)
+
+		interval, err := p.getTimestampDelta(cpuID)
+		require.NoError(t, err)
+		require.Equal(t, 5*time.Second, interval)
+	})
+
+	t.Run("FailedToRead", func(t *testing.T) {
+		reader.err = errors.New("mock error")
+		defer func() {
+			reader.err = nil
+		}()
+
+		require.ErrorContains(t, p.update(cpuID), "failed to read event \"c6-residency\" of cstate_core PMU")
+	})
+
+	t.Run("CounterDecreased", func(t *testing.T) {
+		reader.values[activeEventKey("c6-residency", cpuID)] = 1200
+		fakeClock.Add(time.Second)
+
+		err := p.update(cpuID)
+		require.ErrorContains(t, err, "counter of event \"c6-residency\" of cstate_core PMU decreased since the previous read")
+	})
+
+	t.Run("Deactivate", func(t *testing.T) {
+		// active events of the test are not backed by file descriptors, hence they cannot be closed.
+		err := p.deactivate()
+		require.ErrorContains(t, err, "failed to deactivate event \"c6-residency\" of cstate_core PMU for CPU ID 0")
+		require.Nil(t, p.cpuMap)
+	})
+}
+
+func TestIsCStateResidencyEvent(t *testing.T) {
+	for _, name := range []string{"c1-residency", "c6-residency", "c10-residency"} {
+		require.True(t, isCStateResidencyEvent(name), name)
+	}
+	for _, name := range []string{"c6-residency.unit", "c6-residency.scale", "residency", "cx-residency"} {
+		require.False(t, isCStateResidencyEvent(name), name)
+	}
+}
+
+func TestCStateResidencyEvent(t *testing.T) {
+	require.Equal(t, "c6-residency", cstateResidencyEvent("C6"))
+	require.Equal(t, "c10-residency", cstateResidencyEvent("C10"))
+}
+
+func TestIsMsrPMUEvent(t *testing.T) {
+	for _, name := range []string{"tsc", "aperf", "mperf"} {
+		require.True(t, isMsrPMUEvent(name), name)
+	}
+	// events which are not read by any metric are not activated
+	for _, name := range []string{"pperf", "smi", "cpu_thermal_margin", "irperf", "ptsc", "tsc.unit"} {
+		require.False(t, isMsrPMUEvent(name), name)
+	}
+}
@@ -0,0 +1,321 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	ev "github.com/intel/iaevents"
+)
+
+const (
+	// uncore event names counting CAS commands issued by the integrated memory controller to DRAM, for read
+	// and write operations respectively.
+	imcCASCountRead  = "UNC_M_CAS_COUNT.RD"
+	imcCASCountWrite = "UNC_M_CAS_COUNT.WR"
+
+	// number of bytes transferred by a single CAS command, corresponding to a cache line.
+	casBytes = 64
+)
+
+// uncoreMetric represents the values of an uncore event of a package read at a specific time instant.
+// Uncore events are activated on each unit of the package, e.g. each memory controller channel, hence
+// the metric comprises the values of all units.
+type uncoreMetric struct {
+	name      string
+	packageID int
+
+	values []ev.CounterValue // values of the event, per unit
+	scaled uint64            // sum of the scaled values of all units
+	delta  uint64            // sum of the scaled increases of all units since the previous read
+}
+
+// uncoreEventActivator activates an uncore event on all units of a package.
+type uncoreEventActivator interface {
+	activateEvent(event ev.CustomizableEvent, packageID int) (*ev.ActiveMultiEvent, error)
+}
+
+// uncoreEventActivatorImpl implements uncoreEventActivator interface.
+type uncoreEventActivatorImpl struct{}
+
+// activateEvent takes a custom uncore event and a package ID, and activates the event on all units of the package
+// the event belongs to.
+func (*uncoreEventActivatorImpl) activateEvent(event ev.CustomizableEvent, packageID int) (*ev.ActiveMultiEvent, error) {
+	if event.Event == nil {
+		return nil, errors.New("perf event is nil")
+	}
+
+	placements, err := ev.NewUncoreAllPlacements(event.Event, packageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make uncore placements: %w", err)
+	}
+
+	options := event.Options
+	if options == nil {
+		options, err = ev.NewOptions().Build()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build event options: %w", err)
+		}
+	}
+	return event.Event.ActivateMulti(placements, ev.NewEventTargetProcess(-1, 0), options)
+}
+
+// uncoreValuesReader reads values of an active uncore event.
+type uncoreValuesReader interface {
+	readValues(event *ev.ActiveMultiEvent) ([]ev.CounterValue, error)
+}
+
+// uncoreValuesReaderImpl implements uncoreValuesReader interface.
+type uncoreValuesReaderImpl struct{}
+
+// readValues takes an active uncore event and returns its values, per unit.
+// It is a wrapper of ReadValues method of an ev.ActiveMultiEvent value type.
+func (*uncoreValuesReaderImpl) readValues(event *ev.ActiveMultiEvent) ([]ev.CounterValue, error) {
+	return event.ReadValues()
+}
+
+// activeUncoreEvent represents an uncore event activated on all units of a package.
+type activeUncoreEvent struct {
+	name      string
+	packageID int
+	event     *ev.ActiveMultiEvent
+}
+
+// uncorePerfReader activates, reads and deactivates uncore events accessible via `perf_events`
+// kernel interface, per package.
+type uncorePerfReader interface {
+	initResolver(jsonFile string) error
+
+	activate(events []string, packageIDs []int) error
+
+	read() ([]uncoreMetric, error)
+
+	deactivate() error
+}
+
+// uncorePerf implements uncorePerfReader interface. It keeps track of the current active events.
+type uncorePerf struct {
+	resolver       eventsResolver
+	activator      uncoreEventActivator
+	valuesReader   uncoreValuesReader
+	fileInfoReader fileInfoProvider
+
+	activeEvents []*activeUncoreEvent
+}
+
+// newUncorePerf returns an uncorePerfReader with the default activation and reading mechanisms.
+func newUncorePerf() uncorePerfReader {
+	return &uncorePerf{
+		activator:      &uncoreEventActivatorImpl{},
+		valuesReader:   &uncoreValuesReaderImpl{},
+		fileInfoReader: &fsHelper{},
+	}
+}
+
+// initResolver takes a path string, corresponding to a JSON file which comprises processor model
+// specific uncore events, and initializes the resolver of the receiver.
+func (p *uncorePerf) initResolver(jsonFile string) error {
+	var err error
+	p.resolver, err = newEventsResolver(jsonFile)
+	return err
+}
+
+// activate takes a slice of uncore event names and package IDs. It resolves the given event names into
+// perf events and activates each of them on all units of each package. If an event could not be activated,
+// the events activated so far are deactivated and an error is returned.
+func (p *uncorePerf) activate(events []string, packageIDs []int) error {
+	if len(packageIDs) == 0 {
+		return errors.New("no package IDs provided")
+	}
+
+	// resolve
+	customEvents, err := p.resolver.resolveEvents(events)
+	if err != nil {
+		return fmt.Errorf("error resolving event: %w", err)
+	}
+
+	// calculate file descriptors needed to access all events, one per unit
+	numUnits := uint64(0)
+	for _, event := range customEvents {
+		if event.Event == nil {
+			return errors.New("invalid custom event")
+		}
+		numUnits += uint64(len(event.Event.PMUTypes))
+	}
+	fd, err := multiply(numUnits, uint64(len(packageIDs)))
+	if err != nil {
+		return err
+	}
+
+	// check maximum allowed number of file descriptors
+	if err := checkFileDescriptors(fd, p.fileInfoReader); err != nil {
+		return fmt.Errorf("error checking available file descriptors: %w", err)
+	}
+
+	// activate
+	activeEvents := make([]*activeUncoreEvent, 0, len(customEvents)*len(packageIDs))
+	for _, packageID := range packageIDs {
+		for _, event := range customEvents {
+			active, err := p.activator.activateEvent(event, packageID)
+			if err != nil {
+				deactivateUncoreEvents(activeEvents)
+				return fmt.Errorf("error during activation of event %q for package ID %v: %w", event.Event.Name, packageID, err)
+			}
+			activeEvents = append(activeEvents, &activeUncoreEvent{
+				name:      event.Event.Name,
+				packageID: packageID,
+				event:     active,
+			})
+		}
+	}
+	p.activeEvents = activeEvents
+	return nil
+}
+
+// read performs a single read of all active uncore events and returns a slice with the metrics for each
+// event and package.
+func (p *uncorePerf) read() ([]uncoreMetric, error) {
+	if len(p.activeEvents) == 0 {
+		return nil, errors.New("no active events provided")
+	}
+
+	metrics := make([]uncoreMetric, 0, len(p.activeEvents))
+	for _, active := range p.activeEvents {
+		if active == nil || active.event == nil {
+			return nil, errors.New("invalid active event")
+		}
+		values, err := p.valuesReader.readValues(active.event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read values for event %q of package ID %v: %w", active.name, active.packageID, err)
+		}
+		metrics = append(metrics, uncoreMetric{
+			name:      active.name,
+			packageID: active.packageID,
+			values:    values,
+		})
+	}
+	return metrics, nil
+}
+
+// deactivate deactivates all active uncore events.
+func (p *uncorePerf) deactivate() error {
+	deactivateUncoreEvents(p.activeEvents)
+	p.activeEvents = nil
+	return nil
+}
+
+// deactivateUncoreEvents takes a slice of active uncore events and deactivates them on all units.
+func deactivateUncoreEvents(events []*activeUncoreEvent) {
+	for _, active := range events {
+		if active == nil || active.event == nil {
+			continue
+		}
+		active.event.Deactivate()
+	}
+}
+
+// uncorePerfReaderWithStorage decorates uncorePerfReader with the ability to store uncore event read
+// values and to retrieve all metrics that belong to a specific package ID.
+type uncorePerfReaderWithStorage interface {
+	uncorePerfReader
+
+	update() error
+
+	getPackageMetrics(packageID int) []uncoreMetric
+
+	getTimestampDelta() time.Duration
+}
+
+// uncorePerfWithStorage implements uncorePerfReaderWithStorage interface. The content of metrics field
+// are the uncore event values read from the last call to read method.
+type uncorePerfWithStorage struct {
+	uncorePerfReader
+
+	metrics        []uncoreMetric
+	timestamp      time.Time     // timestamp of the last reading operation
+	timestampDelta time.Duration // timestamp delta between the last read and its previous reading operation
+}
+
+// update reads values for active uncore events specified by the receiver. It updates the metrics field with
+// the latest values returned by read method, and calculates the scaled value of a metric as well as its scaled
+// increase since the previous call, as the sum of the values of all units.
+func (p *uncorePerfWithStorage) update() error {
+	prev := p.metrics
+
+	metrics, err := p.read()
+	if err != nil {
+		return err
+	}
+	timestamp := timeNowFn()
+
+	for i := range metrics {
+		if err := scaleUncoreMetric(&metrics[i], prev); err != nil {
+			return err
+		}
+	}
+
+	p.metrics = metrics
+	if !p.timestamp.IsZero() {
+		p.timestampDelta = timestamp.Sub(p.timestamp)
+	}
+	p.timestamp = timestamp
+	return nil
+}
+
+// scaleUncoreMetric takes an uncore metric and a slice of uncore metrics read previously. It sets the scaled value
+// of the metric and its scaled increase with respect to the previous metric with the same name and package ID. If
+// there is no previous metric, the increase is zero.
+func scaleUncoreMetric(metric *uncoreMetric, prev []uncoreMetric) error {
+	var prevValues []ev.CounterValue
+	for _, prevMetric := range prev {
+		if prevMetric.name == metric.name && prevMetric.packageID == metric.packageID {
+			prevValues = prevMetric.values
+			break
+		}
+	}
+	if prevValues != nil && len(prevValues) != len(metric.values) {
+		return fmt.Errorf("number of units of event %q for package ID %v changed since the previous read", metric.name, metric.packageID)
+	}
+
+	metric.scaled, metric.delta = 0, 0
+	for i, values := range metric.values {
+		scaled, err := scaleMetricValues(values)
+		if err != nil {
+			return err
+		}
+		metric.scaled += scaled
+
+		if prevValues == nil {
+			continue
+		}
+		delta, err := scaleCounterDelta(values, prevValues[i])
+		if err != nil {
+			return fmt.Errorf("values of event %q for package ID %v %w", metric.name, metric.packageID, err)
+		}
+		metric.delta += delta
+	}
+	return nil
+}
+
+// getPackageMetrics takes a package ID as argument and returns all uncore metrics specific to this package
+// stored in metrics field.
+func (p *uncorePerfWithStorage) getPackageMetrics(packageID int) []uncoreMetric {
+	metrics := make([]uncoreMetric, 0)
+	for _, metric := range p.metrics {
+		if metric.packageID == packageID {
+			metrics = append(metrics, metric)
+		}
+	}
+	return metrics
+}
+
+// getTimestampDelta returns the time interval between the two latest reading operations. It is zero until
+// the events are read twice.
+func (p *uncorePerfWithStorage) getTimestampDelta() time.Duration {
+	return p.timestampDelta
+}
@@ -0,0 +1,347 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	ev "github.com/intel/iaevents"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockUncoreEventActivator struct {
+	mock.Mock
+}
+
+func (m *mockUncoreEventActivator) activateEvent(event ev.CustomizableEvent, packageID int) (*ev.ActiveMultiEvent, error) {
+	args := m.Called(event, packageID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ev.ActiveMultiEvent), args.Error(1)
+}
+
+type mockUncoreValuesReader struct {
+	mock.Mock
+}
+
+func (m *mockUncoreValuesReader) readValues(event *ev.ActiveMultiEvent) ([]ev.CounterValue, error) {
+	args := m.Called(event)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]ev.CounterValue), args.Error(1)
+}
+
+type mockUncorePerf struct {
+	mock.Mock
+}
+
+func (m *mockUncorePerf) initResolver(jsonFile string) error {
+	args := m.Called(jsonFile)
+	return args.Error(0)
+}
+
+func (m *mockUncorePerf) activate(events []string, packageIDs []int) error {
+	args := m.Called(events, packageIDs)
+	return args.Error(0)
+}
+
+func (m *mockUncorePerf) read() ([]uncoreMetric, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uncoreMetric), args.Error(1)
+}
+
+func (m *mockUncorePerf) deactivate() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func TestUncorePerf_Activate(t *testing.T) {
+	events := []string{imcCASCountRead, imcCASCountWrite}
+	packageIDs := []int{0, 1}
+
+	// each event has two memory controller units.
+	customEvents := make([]ev.CustomizableEvent, 0, len(events))
+	for _, event := range events {
+		customEvents = append(customEvents, ev.CustomizableEvent{
+			Event: &ev.PerfEvent{
+				Name:     event,
+				Uncore:   true,
+				PMUName:  "imc",
+				PMUTypes: []ev.NamedPMUType{{Name: "uncore_imc_0", PMUType: 20}, {Name: "uncore_imc_1", PMUType: 21}},
+			},
+		})
+	}
+
+	mError := errors.New("mock error")
+
+	t.Run("NoPackageIDs", func(t *testing.T) {
+		p := &uncorePerf{}
+
+		require.ErrorContains(t, p.activate(events, nil), "no package IDs provided")
+	})
+
+	t.Run("FailedToResolve", func(t *testing.T) {
+		mResolver := &mockResolver{}
+		mResolver.On("resolveEvents", events).Return(nil, mError).Once()
+
+		p := &uncorePerf{
+			resolver: mResolver,
+		}
+
+		require.ErrorContains(t, p.activate(events, packageIDs), "error resolving event")
+		mResolver.AssertExpectations(t)
+	})
+
+	t.Run("TooManyFileDescriptors", func(t *testing.T) {
+		mResolver := &mockResolver{}
+		mResolver.On("resolveEvents", events).Return(customEvents, nil).Once()
+
+		// 2 events * 2 units * 2 packages
+		mFileInfoProvider := &mockFileInfoProvider{}
+		mFileInfoProvider.On("readFile", fileMaxPath).Return([]byte("7\n"), nil).Once()
+
+		p := &uncorePerf{
+			resolver:       mResolver,
+			fileInfoReader: mFileInfoProvider,
+		}
+
+		err := p.activate(events, packageIDs)
+		require.ErrorContains(t, err, "error checking available file descriptors")
+		require.ErrorContains(t, err, "required file descriptors 8")
+		mResolver.AssertExpectations(t)
+		mFileInfoProvider.AssertExpectations(t)
+	})
+
+	t.Run("FailedToActivate", func(t *testing.T) {
+		mResolver := &mockResolver{}
+		mResolver.On("resolveEvents", events).Return(customEvents, nil).Once()
+
+		mFileInfoProvider := &mockFileInfoProvider{}
+		mFileInfoProvider.On("readFile", fileMaxPath).Return([]byte("100\n"), nil).Once()
+		mFileInfoProvider.On("rlimit").Return(uint64(100), nil).Once()
+
+		mActivator := &mockUncoreEventActivator{}
+		mActivator.On("activateEvent", customEvents[0], 0).Return(&ev.ActiveMultiEvent{}, nil).Once()
+		mActivator.On("activateEvent", customEvents[1], 0).Return(nil, mError).Once()
+
+		p := &uncorePerf{
+			resolver:       mResolver,
+			activator:      mActivator,
+			fileInfoReader: mFileInfoProvider,
+		}
+
+		err := p.activate(events, packageIDs)
+		require.ErrorContains(t, err, fmt.Sprintf("error during activation of event %q for package ID 0: mock error", imcCASCountWrite))
+		require.Empty(t, p.activeEvents)
+		mResolver.AssertExpectations(t)
+		mFileInfoProvider.AssertExpectations(t)
+		mActivator.AssertExpectations(t)
+	})
+
+	t.Run("EventsActivated", func(t *testing.T) {
+		mResolver := &mockResolver{}
+		mResolver.On("resolveEvents", events).Return(customEvents, nil).Once()
+
+		mFileInfoProvider := &mockFileInfoProvider{}
+		mFileInfoProvider.On("readFile", fileMaxPath).Return([]byte("100\n"), nil).Once()
+		mFileInfoProvider.On("rlimit").Return(uint64(100), nil).Once()
+
+		activeEvents := make([]*ev.ActiveMultiEvent, 4)
+		mActivator := &mockUncoreEventActivator{}
+		for i := range activeEvents {
+			activeEvents[i] = &ev.ActiveMultiEvent{}
+			mActivator.On("activateEvent", customEvents[i%2], packageIDs[i/2]).Return(activeEvents[i], nil).Once()
+		}
+
+		p := &uncorePerf{
+			resolver:       mResolver,
+			activator:      mActivator,
+			fileInfoReader: mFileInfoProvider,
+		}
+
+		require.NoError(t, p.activate(events, packageIDs))
+		require.Equal(t, []*activeUncoreEvent{
+			{name: imcCASCountRead, packageID: 0, event: activeEvents[0]},
+			{name: imcCASCountWrite, packageID: 0, event: activeEvents[1]},
+			{name: imcCASCountRead, packageID: 1, event: activeEvents[2]},
+			{name: imcCASCountWrite, packageID: 1, event: activeEvents[3]},
+		}, p.activeEvents)
+		mResolver.AssertExpectations(t)
+		mFileInfoProvider.AssertExpectations(t)
+		mActivator.AssertExpectations(t)
+	})
+}
+
+func TestUncorePerf_Read(t *testing.T) {
+	t.Run("NoActiveEvents", func(t *testing.T) {
+		p := &uncorePerf{}
+
+		metrics, err := p.read()
+		require.ErrorContains(t, err, "no active events provided")
+		require.Nil(t, metrics)
+	})
+
+	t.Run("FailedToRead", func(t *testing.T) {
+		active := &ev.ActiveMultiEvent{}
+		mReader := &mockUncoreValuesReader{}
+		mReader.On("readValues", active).Return(nil, errors.New("mock error")).Once()
+
+		p := &uncorePerf{
+			valuesReader: mReader,
+			activeEvents: []*activeUncoreEvent{
+				{name: imcCASCountRead, packageID: 1, event: active},
+			},
+		}
+
+		metrics, err := p.read()
+		require.ErrorContains(t, err, fmt.Sprintf("failed to read values for event %q of package ID 1: mock error", imcCASCountRead))
+		require.Nil(t, metrics)
+		mReader.AssertExpectations(t)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		readEvent, writeEvent := &ev.ActiveMultiEvent{}, &ev.ActiveMultiEvent{}
+		readValues := []ev.CounterValue{{Raw: 100, Enabled: 10, Running: 10}, {Raw: 200, Enabled: 10, Running: 10}}
+		writeValues := []ev.CounterValue{{Raw: 50, Enabled: 10, Running: 10}, {Raw: 60, Enabled: 10, Running: 10}}
+
+		mReader := &mockUncoreValuesReader{}
+		mReader.On("readValues", readEvent).Return(readValues, nil).Once()
+		mReader.On("readValues", writeEvent).Return(writeValues, nil).Once()
+
+		p := &uncorePerf{
+			valuesReader: mReader,
+			activeEvents: []*activeUncoreEvent{
+				{name: imcCASCountRead, packageID: 0, event: readEvent},
+				{name: imcCASCountWrite, packageID: 0, event: writeEvent},
+			},
+		}
+
+		metrics, err := p.read()
+		require.NoError(t, err)
+		require.Equal(t, []uncoreMetric{
+			{name: imcCASCountRead, packageID: 0, values: readValues},
+			{name: imcCASCountWrite, packageID: 0, values: writeValues},
+		}, metrics)
+		mReader.AssertExpectations(t)
+	})
+}
+
+func TestUncorePerf_Deactivate(t *testing.T) {
+	p := &uncorePerf{
+		activeEvents: []*activeUncoreEvent{
+			{name: imcCASCountRead, packageID: 0, event: &ev.ActiveMultiEvent{}},
+			nil,
+		},
+	}
+
+	require.NoError(t, p.deactivate())
+	require.Nil(t, p.activeEvents)
+}
+
+func TestUncorePerfWithStorage_Update(t *testing.T) {
+	setFakeClock()
+	defer unsetFakeClock()
+	fakeClock.Set(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	t.Run("FailedToRead", func(t *testing.T) {
+		mPerf := &mockUncorePerf{}
+		mPerf.On("read").Return(nil, errors.New("mock error")).Once()
+
+		p := &uncorePerfWithStorage{
+			uncorePerfReader: mPerf,
+		}
+
+		require.ErrorContains(t, p.update(), "mock error")
+		require.Zero(t, p.getTimestampDelta())
+		mPerf.AssertExpectations(t)
+	})
+
+	t.Run("UnitsChanged", func(t *testing.T) {
+		mPerf := &mockUncorePerf{}
+		mPerf.On("read").Return([]uncoreMetric{
+			{name: imcCASCountRead, packageID: 0, values: []ev.CounterValue{{Raw: 100, Enabled: 10, Running: 10}}},
+		}, nil).Once()
+
+		p := &uncorePerfWithStorage{
+			uncorePerfReader: mPerf,
+			metrics: []uncoreMetric{
+				{name: imcCASCountRead, packageID: 0, values: []ev.CounterValue{{}, {}}},
+			},
+		}
+
+		err := p.update()
+		require.ErrorContains(t, err, fmt.Sprintf("number of units of event %q for package ID 0 changed since the previous read", imcCASCountRead))
+		mPerf.AssertExpectations(t)
+	})
+
+	t.Run("ValuesDecreased", func(t *testing.T) {
+		mPerf := &mockUncorePerf{}
+		mPerf.On("read").Return([]uncoreMetric{
+			{name: imcCASCountRead, packageID: 0, values: []ev.CounterValue{{Raw: 100, Enabled: 10, Running: 10}}},
+		}, nil).Once()
+
+		p := &uncorePerfWithStorage{
+			uncorePerfReader: mPerf,
+			metrics: []uncoreMetric{
+				{name: imcCASCountRead, packageID: 0, values: []ev.CounterValue{{Raw: 200, Enabled: 5, Running: 5}}},
+			},
+		}
+
+		err := p.update()
+		require.ErrorContains(t, err, fmt.Sprintf("values of event %q for package ID 0 decreased since the previous read", imcCASCountRead))
+		mPerf.AssertExpectations(t)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		mPerf := &mockUncorePerf{}
+		mPerf.On("read").Return([]uncoreMetric{
+			{name: imcCASCountRead, packageID: 0, values: []ev.CounterValue{{Raw: 100, Enabled: 10, Running: 10}, {Raw: 200, Enabled: 10, Running: 5}}},
+		}, nil).Once()
+		mPerf.On("read").Return([]uncoreMetric{
+			{name: imcCASCountRead, packageID: 0, values: []ev.CounterValue{{Raw: 150, Enabled: 20, Running: 20}, {Raw: 300, Enabled: 20, Running: 10}}},
+		}, nil).Once()
+
+		p := &uncorePerfWithStorage{
+			uncorePerfReader: mPerf,
+		}
+
+		// first read, neither deltas nor elapsed interval are available.
+		require.NoError(t, p.update())
+		require.Equal(t, []uncoreMetric{
+			{
+				name:      imcCASCountRead,
+				packageID: 0,
+				values:    []ev.CounterValue{{Raw: 100, Enabled: 10, Running: 10}, {Raw: 200, Enabled: 10, Running: 5}},
+				scaled:    500,
+			},
+		}, p.getPackageMetrics(0))
+		require.Zero(t, p.getTimestampDelta())
+
+		fakeClock.Add(time.Second)
+		require.NoError(t, p.update())
+		require.Equal(t, []uncoreMetric{
+			{
+				name:      imcCASCountRead,
+				packageID: 0,
+				values:    []ev.CounterValue{{Raw: 150, Enabled: 20, Running: 20}, {Raw: 300, Enabled: 20, Running: 10}},
+				scaled:    750,
+				delta:     250,
+			},
+		}, p.getPackageMetrics(0))
+		require.Empty(t, p.getPackageMetrics(1))
+		require.Equal(t, time.Second, p.getTimestampDelta())
+		mPerf.AssertExpectations(t)
+	})
+}
@@ -6,17 +6,26 @@
 package powertelemetry
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
+	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/intel/powertelemetry/internal/cpumodel"
+	"github.com/intel/powertelemetry/internal/log"
 )
 
 // MSR offset definitions.
 const (
 	uncorePerfStatus = 0x621 // UNCORE_PERF_STATUS
+	uncoreRatioLimit = 0x620 // MSR_UNCORE_RATIO_LIMIT
 
 	fsbFreq      = 0xCD // MSR_FSB_FREQ
 	platformInfo = 0xCE // MSR_PLATFORM_INFO
@@ -36,38 +45,192 @@ const (
 	turboRatioLimit2         = 0x1AF // MSR_TURBO_RATIO_LIMIT2
 	secondaryTurboRatioLimit = 0x650 // MSR_SECONDARY_TURBO_RATIO_LIMIT
 	atomCoreTurboRatios      = 0x66C // MSR_ATOM_CORE_TURBO_RATIOS
+
+	ppinCtl = 0x4E // MSR_PPIN_CTL
+	ppin    = 0x4F // MSR_PPIN
+
+	pkgCStConfigControl = 0xE2  // MSR_PKG_CST_CONFIG_CONTROL
+	powerCtl            = 0x1FC // MSR_POWER_CTL
 )
 
 // GetInitialUncoreFrequencyMin retrieves the minimum initial uncore frequency limit (in MHz) for the specified package and die.
+// First it tries to retrieve this value from sysfs. In case of error, it attempts to get this value from
+// MSR_UNCORE_RATIO_LIMIT value read during initialization.
 func (pt *PowerTelemetry) GetInitialUncoreFrequencyMin(packageID, dieID int) (float64, error) {
-	if pt.uncoreFreq == nil {
-		return 0.0, &ModuleNotInitializedError{Name: "uncore_frequency"}
-	}
-	return pt.uncoreFreq.getUncoreFrequencyMhz(packageID, dieID, "initial_min")
+	return pt.getUncoreFrequencyLimitMhz(packageID, dieID, initialMin)
 }
 
 // GetCustomizedUncoreFrequencyMin retrieves the minimum customized uncore frequency limit (in MHz) for the specified package and die.
+// First it tries to retrieve this value from sysfs. In case of error, it attempts to get this value from
+// MSR_UNCORE_RATIO_LIMIT of a CPU ID within the given package ID.
 func (pt *PowerTelemetry) GetCustomizedUncoreFrequencyMin(packageID, dieID int) (float64, error) {
-	if pt.uncoreFreq == nil {
-		return 0.0, &ModuleNotInitializedError{Name: "uncore_frequency"}
-	}
-	return pt.uncoreFreq.getUncoreFrequencyMhz(packageID, dieID, "min")
+	return pt.getUncoreFrequencyLimitMhz(packageID, dieID, customizedMin)
 }
 
 // GetInitialUncoreFrequencyMax retrieves the maximum initial uncore frequency limit (in MHz) for the specified package and die.
+// First it tries to retrieve this value from sysfs. In case of error, it attempts to get this value from
+// MSR_UNCORE_RATIO_LIMIT value read during initialization.
 func (pt *PowerTelemetry) GetInitialUncoreFrequencyMax(packageID, dieID int) (float64, error) {
-	if pt.uncoreFreq == nil {
-		return 0.0, &ModuleNotInitializedError{Name: "uncore_frequency"}
-	}
-	return pt.uncoreFreq.getUncoreFrequencyMhz(packageID, dieID, "initial_max")
+	return pt.getUncoreFrequencyLimitMhz(packageID, dieID, initialMax)
 }
 
 // GetCustomizedUncoreFrequencyMax retrieves the maximum customized uncore frequency limit (in MHz) for the specified package and die.
+// First it tries to retrieve this value from sysfs. In case of error, it attempts to get this value from
+// MSR_UNCORE_RATIO_LIMIT of a CPU ID within the given package ID.
 func (pt *PowerTelemetry) GetCustomizedUncoreFrequencyMax(packageID, dieID int) (float64, error) {
-	if pt.uncoreFreq == nil {
+	return pt.getUncoreFrequencyLimitMhz(packageID, dieID, customizedMax)
+}
+
+// getUncoreFrequencyLimitMhz is a helper method that takes a package ID, die ID and an uncore frequency limit type,
+// and returns the value of the limit (in MHz). The value is read from sysfs. If sysfs is not initialized or its read
+// fails, the value is decoded from MSR_UNCORE_RATIO_LIMIT as a fallback, provided that msr is initialized.
+func (pt *PowerTelemetry) getUncoreFrequencyLimitMhz(packageID, dieID int, freqType uncoreFreqType) (float64, error) {
+	// Get uncore frequency limit value from sysfs
+	if pt.uncoreFreq != nil {
+		freq, err := pt.uncoreFreq.getUncoreFrequencyMhz(packageID, dieID, freqType.String())
+		if err == nil || pt.msr == nil {
+			return freq, err
+		}
+	}
+
+	// Fallback method to get the value via MSR
+	if pt.msr == nil {
 		return 0.0, &ModuleNotInitializedError{Name: "uncore_frequency"}
 	}
-	return pt.uncoreFreq.getUncoreFrequencyMhz(packageID, dieID, "max")
+
+	model := pt.topology.getCPUModel()
+	if err := CheckIfUncoreRatioLimitSupported(model); err != nil {
+		return 0.0, err
+	}
+
+	var res uint64
+	switch freqType {
+	case initialMin, initialMax:
+		var ok bool
+		res, ok = pt.uncoreRatioLimits[packageID]
+		if !ok {
+			return 0.0, fmt.Errorf("initial uncore ratio limit not found for package ID: %v", packageID)
+		}
+	case customizedMin, customizedMax:
+		cpuID, err := pt.getCPUIDFromPackageID(packageID)
+		if err != nil {
+			return 0.0, err
+		}
+
+		res, err = pt.msr.read(uncoreRatioLimit, cpuID)
+		if err != nil {
+			return 0.0, err
+		}
+	default:
+		return 0.0, fmt.Errorf("unsupported uncore frequency limit type %q", freqType)
+	}
+
+	minRatio, maxRatio := decodeUncoreRatioLimit(res)
+	if freqType == initialMin || freqType == customizedMin {
+		return float64(minRatio) * 100, nil
+	}
+	return float64(maxRatio) * 100, nil
+}
+
+// SetUncoreFrequencyLimits takes a package ID, a minimum and a maximum uncore frequency limit (in MHz), and writes them
+// to MSR_UNCORE_RATIO_LIMIT of a CPU ID within the given package ID. It is intended for hosts where the
+// intel_uncore_frequency driver cannot be loaded, hence it fails if uncore frequency sysfs interface is initialized.
+// Limits must be multiples of 100 MHz, and they must be within the initial limits of the package. Initial limits are
+// a snapshot of MSR_UNCORE_RATIO_LIMIT taken when the PowerTelemetry instance is created, unless the MSR write journal
+// holds the original value of a previous session, hence a range narrowed by other software before can't be widened.
+// The write operation is guarded by WithMsrWrites option, whose allowlist must include MSR_UNCORE_RATIO_LIMIT,
+// so that the original limits are recorded in the journal and restored by Restore or Close methods.
+func (pt *PowerTelemetry) SetUncoreFrequencyLimits(packageID int, minMhz, maxMhz float64) error {
+	if pt.uncoreFreq != nil {
+		return errors.New("uncore frequency limits are managed by intel_uncore_frequency driver, use its sysfs interface instead")
+	}
+	if pt.msr == nil {
+		return &ModuleNotInitializedError{Name: "msr"}
+	}
+	if pt.msrWrite == nil {
+		return &ModuleNotInitializedError{Name: "msr_write"}
+	}
+
+	model := pt.topology.getCPUModel()
+	if err := CheckIfUncoreRatioLimitSupported(model); err != nil {
+		return err
+	}
+
+	if minMhz < 0 || minMhz > maxMhz {
+		return fmt.Errorf("invalid uncore frequency limits, min: %v MHz, max: %v MHz", minMhz, maxMhz)
+	}
+
+	initial, ok := pt.uncoreRatioLimits[packageID]
+	if !ok {
+		return fmt.Errorf("initial uncore ratio limit not found for package ID: %v", packageID)
+	}
+	initialMinRatio, initialMaxRatio := decodeUncoreRatioLimit(initial)
+
+	// Uncore ratios are in steps of 100 MHz.
+	if math.Mod(minMhz, 100) != 0 || math.Mod(maxMhz, 100) != 0 {
+		return fmt.Errorf("uncore frequency limits must be multiples of 100 MHz, min: %v MHz, max: %v MHz", minMhz, maxMhz)
+	}
+	minRatio := uint64(minMhz / 100)
+	maxRatio := uint64(maxMhz / 100)
+	if minRatio < initialMinRatio || maxRatio > initialMaxRatio {
+		return fmt.Errorf("uncore frequency limits out of initial range [%v, %v] MHz", initialMinRatio*100, initialMaxRatio*100)
+	}
+
+	cpuID, err := pt.getCPUIDFromPackageID(packageID)
+	if err != nil {
+		return err
+	}
+
+	// Keep reserved bits of MSR_UNCORE_RATIO_LIMIT unchanged.
+	if err := pt.msrWrite.write(pt.msr, cpuID, uncoreRatioLimit, minRatio<<8|maxRatio, uncoreRatioLimitMask); err != nil {
+		return fmt.Errorf("error writing uncore ratio limit for package ID %v: %w", packageID, err)
+	}
+	return nil
+}
+
+// Mask of MSR_UNCORE_RATIO_LIMIT bits which hold MIN_CLR_RATIO[14:8] and MAX_CLR_RATIO[6:0].
+const uncoreRatioLimitMask = 0x7F7F
+
+// decodeUncoreRatioLimit takes a value of MSR_UNCORE_RATIO_LIMIT and returns its minimum and maximum
+// uncore ratios. MSR_UNCORE_RATIO_LIMIT[6:0] corresponds to MAX_CLR_RATIO and MSR_UNCORE_RATIO_LIMIT[14:8]
+// corresponds to MIN_CLR_RATIO, both in steps of 100 MHz.
+func decodeUncoreRatioLimit(value uint64) (minRatio, maxRatio uint64) {
+	return (value >> 8) & 0x7F, value & 0x7F
+}
+
+// readUncoreRatioLimits returns a map with package ID key and MSR_UNCORE_RATIO_LIMIT value read from
+// a CPU ID within each package. These values are considered as initial uncore frequency limits. They are a snapshot
+// of the register, except when the MSR write journal holds its original value written by a previous session which
+// did not restore it. If the CPU model does not support MSR_UNCORE_RATIO_LIMIT, it returns nil.
+func (pt *PowerTelemetry) readUncoreRatioLimits() map[int]uint64 {
+	if err := CheckIfUncoreRatioLimitSupported(pt.topology.getCPUModel()); err != nil {
+		return nil
+	}
+
+	limits := make(map[int]uint64)
+	for _, packageID := range pt.topology.getPackageIDs() {
+		cpuID, err := pt.getCPUIDFromPackageID(packageID)
+		if err != nil {
+			continue
+		}
+
+		res, err := pt.msr.read(uncoreRatioLimit, cpuID)
+		if err != nil {
+			log.Warnf("Failed to read uncore ratio limit for package ID %v: %v", packageID, err)
+			continue
+		}
+		if pt.msrWrite != nil {
+			original, ok, err := pt.msrWrite.original(cpuID, uncoreRatioLimit)
+			if err != nil {
+				log.Warnf("Failed to read original uncore ratio limit for package ID %v: %v", packageID, err)
+			}
+			if ok {
+				res = res&^uncoreRatioLimitMask | original&uncoreRatioLimitMask
+			}
+		}
+		limits[packageID] = res
+	}
+	return limits
 }
 
 // GetCurrentUncoreFrequency takes a package ID and returns the current uncore frequency
@@ -133,6 +296,68 @@ func (pt *PowerTelemetry) GetCPUBaseFrequency(packageID int) (uint64, error) {
 	return uint64(float64((res>>8)&0xFF) * pt.busClock), nil
 }
 
+// GetPackagePPIN takes a package ID and returns its Protected Processor Inventory Number (PPIN).
+// PPIN is read from MSR_PPIN of a CPU ID within the package, provided that MSR_PPIN_CTL reports
+// the access to MSR_PPIN as enabled. Otherwise, an error is returned.
+func (pt *PowerTelemetry) GetPackagePPIN(packageID int) (uint64, error) {
+	if pt.msr == nil {
+		return 0, &ModuleNotInitializedError{Name: "msr"}
+	}
+
+	model := pt.topology.getCPUModel()
+	if err := CheckIfPackagePPINSupported(model); err != nil {
+		return 0, err
+	}
+
+	cpuID, err := pt.getCPUIDFromPackageID(packageID)
+	if err != nil {
+		return 0, fmt.Errorf("could not find CPU ID for package ID %v: %w", packageID, err)
+	}
+
+	ctl, err := pt.msr.read(ppinCtl, cpuID)
+	if err != nil {
+		return 0, err
+	}
+
+	// MSR_PPIN_CTL[1] (Enable_PPIN) allows reads of MSR_PPIN. MSR_PPIN_CTL[0] (LockOut)
+	// prevents further writes to MSR_PPIN_CTL until the next reset.
+	if ctl&0x2 == 0 {
+		if ctl&0x1 != 0 {
+			return 0, fmt.Errorf("PPIN is disabled and locked for package ID: %v", packageID)
+		}
+		return 0, fmt.Errorf("PPIN is disabled for package ID: %v", packageID)
+	}
+
+	return pt.msr.read(ppin, cpuID)
+}
+
+// GetPackagePPINs returns a map with package IDs of the host as keys and their Protected Processor Inventory Numbers
+// (PPIN) as values, which serves as an inventory report of the host's packages. Packages whose PPIN could not be read
+// are not included in the map, and their errors are joined into the returned error.
+func (pt *PowerTelemetry) GetPackagePPINs() (map[int]uint64, error) {
+	if pt.msr == nil {
+		return nil, &ModuleNotInitializedError{Name: "msr"}
+	}
+
+	model := pt.topology.getCPUModel()
+	if err := CheckIfPackagePPINSupported(model); err != nil {
+		return nil, err
+	}
+
+	packageIDs := pt.topology.getPackageIDs()
+	ppins := make(map[int]uint64, len(packageIDs))
+	var errs []error
+	for _, packageID := range packageIDs {
+		value, err := pt.GetPackagePPIN(packageID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error retrieving PPIN for package ID %v: %w", packageID, err))
+			continue
+		}
+		ppins[packageID] = value
+	}
+	return ppins, errors.Join(errs...)
+}
+
 // GetCPUFrequency returns current frequency value of specified cpu (in MHz).
 func (pt *PowerTelemetry) GetCPUFrequency(cpuID int) (float64, error) {
 	if pt.cpuFreq == nil {
@@ -322,9 +547,13 @@ func (pt *PowerTelemetry) getAirmontBusClock(cpuID int) (float64, error) {
 // CPU temperature is calculated based on cpu-specific msr offsets:
 // temp[C] = MSR_TEMPERATURE_TARGET[23:16] - IA32_THERM_STATUS[22:16]
 // If an error occurs while reading msr offsets, the function returns zero value for
-// the temperature and the corresponding error.
+// the temperature and the corresponding error. If msr is not initialized, the temperature
+// is read from the core temperature sensor exposed by coretemp hwmon driver.
 func (pt *PowerTelemetry) GetCPUTemperature(cpuID int) (uint64, error) {
 	if pt.msr == nil {
+		if pt.coreTemp != nil {
+			return pt.getCoreTemperature(cpuID, tempInput)
+		}
 		return 0, &ModuleNotInitializedError{Name: "msr"}
 	}
 
@@ -352,8 +581,12 @@ func (pt *PowerTelemetry) GetCPUTemperature(cpuID int) (uint64, error) {
 }
 
 // GetCPUC0StateResidency takes a CPU ID and returns its C0 state residency metric, as a percentage.
+// If msr is not initialized, it falls back to the MPERF and TSC counters of msr PMU.
 func (pt *PowerTelemetry) GetCPUC0StateResidency(cpuID int) (float64, error) {
 	if pt.msr == nil {
+		if pt.msrPerf != nil {
+			return pt.getPerfMsrC0StateResidency(cpuID)
+		}
 		return 0, &ModuleNotInitializedError{Name: "msr"}
 	}
 
@@ -379,9 +612,11 @@ func (pt *PowerTelemetry) GetCPUC0StateResidency(cpuID int) (float64, error) {
 }
 
 // GetCPUC1StateResidency takes a CPU ID and returns its C1 state residency metric, as a percentage.
+// If msr is not initialized, it falls back to the C1 residency counter of cstate_core PMU or, if not available,
+// to the residency of C1 flavored idle states (e.g. C1, C1E) reported by cpuidle subsystem.
 func (pt *PowerTelemetry) GetCPUC1StateResidency(cpuID int) (float64, error) {
 	if pt.msr == nil {
-		return 0, &ModuleNotInitializedError{Name: "msr"}
+		return pt.getCPUCStateResidencyFallback(cpuID, "C1")
 	}
 
 	model := pt.topology.getCPUModel()
@@ -427,8 +662,12 @@ func (pt *PowerTelemetry) GetCPUC1StateResidency(cpuID int) (float64, error) {
 }
 
 // GetCPUC3StateResidency takes a CPU ID and returns its C3 state residency metric, as a percentage.
+// If msr is not initialized, it falls back to the C3 residency counter of cstate_core PMU.
 func (pt *PowerTelemetry) GetCPUC3StateResidency(cpuID int) (float64, error) {
 	if pt.msr == nil {
+		if pt.cstatePerf != nil {
+			return pt.getPerfCStateResidency(cpuID, cstateCorePMU, "C3")
+		}
 		return 0, &ModuleNotInitializedError{Name: "msr"}
 	}
 
@@ -463,9 +702,11 @@ func (pt *PowerTelemetry) GetCPUC3StateResidency(cpuID int) (float64, error) {
 // GetCPUC6StateResidency takes a CPU ID and returns its C6 state residency metric, as a percentage.
 // C6 state residency is calculated within a time interval and the formula is as follows:
 // c6[%] = 100 *(MSR_CORE_C6_RESIDENCY_2 - MSR_CORE_C6_RESIDENCY_1) / (IA32_TIME_STAMP_COUNTER_2 - IA32_TIME_STAMP_COUNTER_1).
+// If msr is not initialized, it falls back to the C6 residency counter of cstate_core PMU or, if not available,
+// to the residency of C6 flavored idle states (e.g. C6, C6S) reported by cpuidle subsystem.
 func (pt *PowerTelemetry) GetCPUC6StateResidency(cpuID int) (float64, error) {
 	if pt.msr == nil {
-		return 0, &ModuleNotInitializedError{Name: "msr"}
+		return pt.getCPUCStateResidencyFallback(cpuID, "C6")
 	}
 
 	model := pt.topology.getCPUModel()
@@ -497,8 +738,12 @@ func (pt *PowerTelemetry) GetCPUC6StateResidency(cpuID int) (float64, error) {
 }
 
 // GetCPUC7StateResidency takes a CPU ID and returns its C7 state residency metric, as a percentage.
+// If msr is not initialized, it falls back to the C7 residency counter of cstate_core PMU.
 func (pt *PowerTelemetry) GetCPUC7StateResidency(cpuID int) (float64, error) {
 	if pt.msr == nil {
+		if pt.cstatePerf != nil {
+			return pt.getPerfCStateResidency(cpuID, cstateCorePMU, "C7")
+		}
 		return 0, &ModuleNotInitializedError{Name: "msr"}
 	}
 
@@ -530,9 +775,75 @@ func (pt *PowerTelemetry) GetCPUC7StateResidency(cpuID int) (float64, error) {
 	return (float64(c7Delta) / float64(tscDelta)) * 100, nil
 }
 
+// GetPackageCStateResidency takes a package ID and the name of a package C-state, e.g. "C6", and returns its residency
+// metric, as a percentage, within the interval between the last two updates of the metrics of the first available CPU ID
+// of the package. Package C-state residencies are read from cstate_pkg PMU, set by WithPerfCStates option.
+func (pt *PowerTelemetry) GetPackageCStateResidency(packageID int, state string) (float64, error) {
+	if pt.cstatePerf == nil {
+		return 0, &ModuleNotInitializedError{Name: "perf_cstates"}
+	}
+
+	cpuID, err := pt.getCPUIDFromPackageID(packageID)
+	if err != nil {
+		return 0, fmt.Errorf("could not find CPU ID for package ID %v: %w", packageID, err)
+	}
+	return pt.getPerfCStateResidency(cpuID, cstatePkgPMU, state)
+}
+
+// getCPUCStateResidencyFallback takes a CPU ID and the name of a C-state, and returns its residency, as a percentage,
+// when msr is not initialized. The residency counter of cstate_core PMU is preferred. If the counter is not available,
+// the residency of idle states reported by cpuidle subsystem is returned.
+func (pt *PowerTelemetry) getCPUCStateResidencyFallback(cpuID int, state string) (float64, error) {
+	if pt.cstatePerf != nil {
+		residency, err := pt.getPerfCStateResidency(cpuID, cstateCorePMU, state)
+		if pt.cpuIdle == nil || !errors.Is(err, errPMUCounterNotFound) {
+			return residency, err
+		}
+	}
+	if pt.cpuIdle != nil {
+		return pt.getCPUIdleCStateResidency(cpuID, state)
+	}
+	return 0, &ModuleNotInitializedError{Name: "msr"}
+}
+
+// getPerfCStateResidency takes a CPU ID, the name of a cstate PMU and the name of a C-state, and returns the residency of
+// the C-state, as a percentage, within the interval between the last two updates of the metrics of the CPU ID. Residency
+// counters of cstate PMUs count at the nominal frequency of the time stamp counter.
+func (pt *PowerTelemetry) getPerfCStateResidency(cpuID int, pmu, state string) (float64, error) {
+	interval, err := pt.cstatePerf.getTimestampDelta(cpuID)
+	if err != nil {
+		return 0.0, fmt.Errorf("error retrieving timestamp delta for CPU ID %v: %w", cpuID, err)
+	}
+	if interval <= 0 {
+		return 0.0, errors.New("timestamp delta must be greater than zero")
+	}
+
+	deltas, err := pt.cstatePerf.getCounterDeltas(cpuID)
+	if err != nil {
+		return 0.0, fmt.Errorf("error retrieving counter deltas for CPU ID %v: %w", cpuID, err)
+	}
+
+	event := cstateResidencyEvent(state)
+	delta, ok := deltas[pmuCounter{pmu: pmu, event: event}]
+	if !ok {
+		return 0.0, fmt.Errorf("%w: event %q of %s PMU for CPU ID %v", errPMUCounterNotFound, event, pmu, cpuID)
+	}
+
+	tscHz := tscFrequency()
+	if tscHz == 0 {
+		return 0.0, errors.New("nominal frequency of the time stamp counter is not available")
+	}
+	residency := float64(delta) / (interval.Seconds() * float64(tscHz)) * 100
+	return min(residency, 100), nil
+}
+
 // GetCPUBusyFrequencyMhz takes a CPU ID and returns its busy frequency metric, in MHz.
+// If msr is not initialized, it falls back to the TSC, APERF and MPERF counters of msr PMU.
 func (pt *PowerTelemetry) GetCPUBusyFrequencyMhz(cpuID int) (float64, error) {
 	if pt.msr == nil {
+		if pt.msrPerf != nil {
+			return pt.getPerfMsrBusyFrequencyMhz(cpuID)
+		}
 		return 0, &ModuleNotInitializedError{Name: "msr"}
 	}
 
@@ -571,17 +882,135 @@ func (pt *PowerTelemetry) GetCPUBusyFrequencyMhz(cpuID int) (float64, error) {
 		(float64(aperfDelta) / float64(mperfDelta)) / (float64(timestampDelta.Nanoseconds()) * fromNanosecondsToSecondsRatio), nil
 }
 
+// getPerfMsrC0StateResidency takes a CPU ID and returns its C0 state residency, as a percentage, calculated from
+// the MPERF and TSC counters of msr PMU within the interval between the last two updates of the metrics of the CPU ID.
+func (pt *PowerTelemetry) getPerfMsrC0StateResidency(cpuID int) (float64, error) {
+	deltas, err := pt.getPerfMsrDeltas(cpuID, msrPMUMperf, msrPMUTsc)
+	if err != nil {
+		return 0.0, err
+	}
+
+	mperfDelta, tscDelta := deltas[0], deltas[1]
+	if tscDelta == 0 {
+		return 0.0, fmt.Errorf("tsc counter delta is zero for CPU ID: %v", cpuID)
+	}
+	return min(float64(mperfDelta)/float64(tscDelta)*100, 100), nil
+}
+
+// getPerfMsrBusyFrequencyMhz takes a CPU ID and returns its busy frequency, in MHz, calculated from the TSC, APERF
+// and MPERF counters of msr PMU within the interval between the last two updates of the metrics of the CPU ID.
+func (pt *PowerTelemetry) getPerfMsrBusyFrequencyMhz(cpuID int) (float64, error) {
+	timestampDelta, err := pt.msrPerf.getTimestampDelta(cpuID)
+	if err != nil {
+		return 0.0, fmt.Errorf("error retrieving timestamp delta for CPU ID %v: %w", cpuID, err)
+	}
+	if timestampDelta <= 0 {
+		return 0.0, errors.New("timestamp delta must be greater than zero")
+	}
+
+	deltas, err := pt.getPerfMsrDeltas(cpuID, msrPMUTsc, msrPMUAperf, msrPMUMperf)
+	if err != nil {
+		return 0.0, err
+	}
+
+	tscDelta, aperfDelta, mperfDelta := deltas[0], deltas[1], deltas[2]
+	if mperfDelta == 0 {
+		return 0.0, fmt.Errorf("mperf counter delta is zero for CPU ID: %v", cpuID)
+	}
+	return float64(tscDelta) * fromProcessorCyclesToHertz *
+		(float64(aperfDelta) / float64(mperfDelta)) / (float64(timestampDelta.Nanoseconds()) * fromNanosecondsToSecondsRatio), nil
+}
+
+// getPerfMsrDeltas takes a CPU ID and names of events of msr PMU, and returns the counter deltas of the events, in the
+// same order, between the last two updates of the metrics of the CPU ID.
+func (pt *PowerTelemetry) getPerfMsrDeltas(cpuID int, events ...string) ([]uint64, error) {
+	deltas, err := pt.msrPerf.getCounterDeltas(cpuID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving counter deltas for CPU ID %v: %w", cpuID, err)
+	}
+
+	values := make([]uint64, 0, len(events))
+	for _, event := range events {
+		delta, ok := deltas[pmuCounter{pmu: msrPMU, event: event}]
+		if !ok {
+			return nil, fmt.Errorf("%w: event %q of %s PMU for CPU ID %v", errPMUCounterNotFound, event, msrPMU, cpuID)
+		}
+		values = append(values, delta)
+	}
+	return values, nil
+}
+
 // UpdatePerCPUMetrics takes a CPU ID and updates the msr storage with offset values and deltas corresponding to
-// msr file for CPU ID.
+// msr file for CPU ID. If cpuidle, thermal throttle, perf cstate or perf msr subsystems are initialized, idle state
+// counters, thermal throttle counters, C-state residency counters and msr PMU counters of the CPU ID are updated
+// as well. If MSR counters of the CPU ID were reset since the previous update, the storage is updated and
+// a *CounterResetError is returned. A failure to update one subsystem does not prevent the others from being
+// updated, and the errors are joined.
 func (pt *PowerTelemetry) UpdatePerCPUMetrics(cpuID int) error {
-	if pt.msr == nil {
+	if !pt.isCPUMetricsInitialized() {
 		return &ModuleNotInitializedError{Name: "msr"}
 	}
 
-	if err := pt.msr.update(cpuID); err != nil {
+	var msrErr error
+	if pt.msr != nil {
+		msrErr = pt.msr.update(cpuID)
+	}
+	return pt.updateCPUMetrics(cpuID, msrErr)
+}
+
+// isCPUMetricsInitialized returns true if any of the subsystems updated by UpdatePerCPUMetrics is initialized.
+func (pt *PowerTelemetry) isCPUMetricsInitialized() bool {
+	return pt.msr != nil || pt.cpuIdle != nil || pt.throttle != nil || pt.cstatePerf != nil || pt.msrPerf != nil
+}
+
+// updatePerCPUSources takes a CPU ID and updates the counters of the initialized subsystems which are read per
+// CPU ID, i.e. cpuidle, thermal throttle, perf cstate and perf msr. It returns the errors of the failed updates.
+func (pt *PowerTelemetry) updatePerCPUSources(cpuID int) []error {
+	var errs []error
+	if pt.cpuIdle != nil {
+		if err := pt.cpuIdle.update(cpuID); err != nil {
+			errs = append(errs, fmt.Errorf("error updating idle states for CPU ID %v: %w", cpuID, err))
+		}
+	}
+
+	if pt.throttle != nil {
+		if err := pt.throttle.update(cpuID); err != nil {
+			errs = append(errs, fmt.Errorf("error updating thermal throttle for CPU ID %v: %w", cpuID, err))
+		}
+	}
+
+	if pt.cstatePerf != nil {
+		if err := pt.cstatePerf.update(cpuID); err != nil {
+			errs = append(errs, fmt.Errorf("error updating C-state residency counters for CPU ID %v: %w", cpuID, err))
+		}
+	}
+
+	if pt.msrPerf != nil {
+		if err := pt.msrPerf.update(cpuID); err != nil {
+			errs = append(errs, fmt.Errorf("error updating msr PMU counters for CPU ID %v: %w", cpuID, err))
+		}
+	}
+	return errs
+}
+
+// scaleMsrMetrics takes a CPU ID and the error of the msr storage update of the CPU ID, and scales the deltas of
+// the MSR offsets needed for C-state residencies. If MSR counters of the CPU ID were reset since the previous update,
+// the deltas are scaled and the *CounterResetError is returned.
+func (pt *PowerTelemetry) scaleMsrMetrics(cpuID int, updateErr error) error {
+	var resetErr *CounterResetError
+	if updateErr != nil && !errors.As(updateErr, &resetErr) {
+		return updateErr
+	}
+
+	if err := pt.scaleCStateDeltas(cpuID); err != nil {
 		return err
 	}
+	return updateErr
+}
 
+// scaleCStateDeltas takes a CPU ID and scales the deltas of the MSR offsets needed for C-state residencies, so
+// that their sum does not exceed the timestamp counter delta.
+func (pt *PowerTelemetry) scaleCStateDeltas(cpuID int) error {
 	deltas, err := pt.msr.getOffsetDeltas(cpuID)
 	if err != nil {
 		return fmt.Errorf("error retrieving offset deltas for CPU ID %v: %w", cpuID, err)
@@ -623,76 +1052,849 @@ func (pt *PowerTelemetry) UpdatePerCPUMetrics(cpuID int) error {
 	return nil
 }
 
-// IsFlagSupported takes a flag's value and returns true if first CPU supports it and false if it doesn't.
-func (pt *PowerTelemetry) IsFlagSupported(flag string) (bool, error) {
-	flags, err := pt.topology.getCPUFlags(0)
-	if err != nil {
-		return false, fmt.Errorf("error retrieving CPU flags: %w", err)
+// UpdateAllCPUMetrics updates the metrics of all available CPU IDs, as UpdatePerCPUMetrics does for a single
+// CPU ID. CPU IDs are updated concurrently by a bounded pool of workers, whose size is set by WithUpdateWorkers
+// option. If msr_safe batch device is available, the msr storage of all CPU IDs is updated beforehand with a single
+// batch operation, and workers only update the remaining subsystems which are read per CPU ID. The context is checked
+// before each CPU ID is updated: if it is done, pending CPU IDs are not updated and the context error is reported
+// for them. The method returns once all workers have finished. If any CPU ID could not be updated, an
+// *UpdateError with the errors of each failed CPU ID is returned.
+func (pt *PowerTelemetry) UpdateAllCPUMetrics(ctx context.Context) error {
+	if !pt.isCPUMetricsInitialized() {
+		return &ModuleNotInitializedError{Name: "msr"}
 	}
-	for _, f := range flags {
-		if f == flag {
-			return true, nil
+
+	workers := pt.updateWorkers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(pt.cpus) {
+		workers = len(pt.cpus)
+	}
+
+	var msrErrs map[int]error
+	msrBatch := pt.msr != nil && pt.msr.isBatchAvailable()
+	if msrBatch && ctx.Err() == nil {
+		msrErrs = pt.msr.updateAll()
+	}
+
+	var (
+		mu   sync.Mutex
+		errs []*CPUUpdateError
+	)
+	addErr := func(cpuID int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, &CPUUpdateError{CPUID: cpuID, Err: err})
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for cpuID := range jobs {
+				err := ctx.Err()
+				if err == nil {
+					msrErr := msrErrs[cpuID]
+					if pt.msr != nil && !msrBatch {
+						msrErr = pt.msr.update(cpuID)
+					}
+					err = pt.updateCPUMetrics(cpuID, msrErr)
+				}
+				if err != nil {
+					addErr(cpuID, err)
+				}
+			}
+		}()
+	}
+
+feed:
+	for i, cpuID := range pt.cpus {
+		select {
+		case jobs <- cpuID:
+		case <-ctx.Done():
+			for _, pending := range pt.cpus[i:] {
+				addErr(pending, ctx.Err())
+			}
+			break feed
 		}
 	}
-	return false, nil
-}
+	close(jobs)
+	wg.Wait()
 
-// ReadPerfEvents reads the perf events related to supported C0 state residency metrics
-// and updates the storage to make metrics available. If one or more events could not be
-// read an error is returned.
-func (pt *PowerTelemetry) ReadPerfEvents() error {
-	if pt.perf == nil {
-		return &ModuleNotInitializedError{Name: "perf"}
+	if len(errs) == 0 {
+		return nil
+	}
+	slices.SortFunc(errs, func(a, b *CPUUpdateError) int {
+		return a.CPUID - b.CPUID
+	})
+	return &UpdateError{
+		Errors: errs,
+		Total:  len(pt.cpus),
 	}
-	return pt.perf.update()
 }
 
-// DeactivatePerfEvents deactivates all active events. If an event or events could not
-// be successfully deactivated, an error is returned.
-// This method should be explicitly called to avoid resource leakage.
-func (pt *PowerTelemetry) DeactivatePerfEvents() error {
-	if pt.perf == nil {
-		return &ModuleNotInitializedError{Name: "perf"}
+// updateCPUMetrics takes a CPU ID and the error of the msr storage update of the CPU ID, which is performed
+// beforehand. It updates the subsystems which are read per CPU ID and scales the msr deltas of the CPU ID,
+// joining the errors.
+func (pt *PowerTelemetry) updateCPUMetrics(cpuID int, msrErr error) error {
+	errs := pt.updatePerCPUSources(cpuID)
+	if pt.msr != nil {
+		if err := pt.scaleMsrMetrics(cpuID, msrErr); err != nil {
+			errs = append(errs, err)
+		}
 	}
-	return pt.perf.deactivate()
+	return errors.Join(errs...)
 }
 
-// GetCPUC0SubstateC01Percent takes a CPU ID and returns a value indicating the percentage of time
-// the processor spent in its C0.1 substate out of the total time in the C0 state.
-// C0.1 is characterized by a light-weight slower wakeup time but more power-saving optimized state.
-func (pt *PowerTelemetry) GetCPUC0SubstateC01Percent(cpuID int) (float64, error) {
-	return pt.getPerfMetricRatio(cpuID, c01.String(), thread.String())
-}
+// GetMsrCounterDelta takes a CPU ID and the name of a counter set by WithMsrCounters option, and returns the
+// counter delta between the two latest updates of the CPU ID's metrics, see UpdatePerCPUMetrics. A wraparound
+// of the counter between both updates is accounted for according to its width.
+func (pt *PowerTelemetry) GetMsrCounterDelta(cpuID int, name string) (uint64, error) {
+	if pt.msr == nil {
+		return 0, &ModuleNotInitializedError{Name: "msr"}
+	}
 
-// GetCPUC0SubstateC02Percent takes a CPU ID and returns a value indicating the percentage of time
-// the processor spent in its C0.2 substate out of the total time in the C0 state.
-// C0.2 is characterized by a light-weight faster wakeup time but less power saving optimized state.
-func (pt *PowerTelemetry) GetCPUC0SubstateC02Percent(cpuID int) (float64, error) {
-	return pt.getPerfMetricRatio(cpuID, c02.String(), thread.String())
+	delta, err := pt.msr.getCounterDelta(cpuID, name)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving MSR counter delta for CPU ID %v: %w", cpuID, err)
+	}
+	return delta, nil
 }
 
-// GetCPUC0SubstateC0WaitPercent takes a CPU ID and returns a value indicating the percentage of time
-// the processor spent in its C0_Wait substate out of the total time in the C0 state.
-// CPU is in C0_Wait substate when the thread is in the C0.1 or C0.2 or running a PAUSE in C0 ACPI state.
-func (pt *PowerTelemetry) GetCPUC0SubstateC0WaitPercent(cpuID int) (float64, error) {
-	return pt.getPerfMetricRatio(cpuID, c0Wait.String(), thread.String())
+// GetMsrCounterRate takes a CPU ID and the name of a counter set by WithMsrCounters option, and returns the
+// counter rate, in counts per second, between the two latest updates of the CPU ID's metrics, see UpdatePerCPUMetrics.
+func (pt *PowerTelemetry) GetMsrCounterRate(cpuID int, name string) (float64, error) {
+	delta, err := pt.GetMsrCounterDelta(cpuID, name)
+	if err != nil {
+		return 0, err
+	}
+
+	timestampDelta, err := pt.msr.getTimestampDelta(cpuID)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving timestamp delta for CPU ID %v: %w", cpuID, err)
+	}
+	if timestampDelta <= 0 {
+		return 0, errors.New("timestamp delta must be greater than zero")
+	}
+	return float64(delta) / timestampDelta.Seconds(), nil
 }
 
-// getPerfMetricRatio is a helper method that takes a CPU ID, a target metric name and reference metric name.
-// First, it fetches the specified metrics from the perf storage. Then, it calculates the percentage of the target
-// metric, with respect to the reference metric.
-func (pt *PowerTelemetry) getPerfMetricRatio(cpuID int, target, reference string) (float64, error) {
-	if pt.perf == nil {
-		return 0.0, &ModuleNotInitializedError{Name: "perf"}
+// GetCPUIdleStates takes a CPU ID and returns the idle states exposed by cpuidle subsystem for the CPU ID,
+// ordered by state index.
+func (pt *PowerTelemetry) GetCPUIdleStates(cpuID int) ([]CPUIdleState, error) {
+	if pt.cpuIdle == nil {
+		return nil, &ModuleNotInitializedError{Name: "cpuidle"}
 	}
-	coreMetrics := pt.perf.getCoreMetrics(cpuID)
-	if len(coreMetrics) == 0 {
-		return 0.0, fmt.Errorf("no core metrics found for CPU ID: %v", cpuID)
+	return pt.cpuIdle.getStates(cpuID)
+}
+
+// GetCPUIdleStateResidencyPercent takes a CPU ID and an idle state name, and returns the percentage of time
+// the CPU spent in the idle state within the interval between the last two updates of its metrics.
+func (pt *PowerTelemetry) GetCPUIdleStateResidencyPercent(cpuID int, stateName string) (float64, error) {
+	if pt.cpuIdle == nil {
+		return 0, &ModuleNotInitializedError{Name: "cpuidle"}
 	}
 
-	targetMetric, err := getMetric(coreMetrics, target)
+	deltas, interval, err := pt.getCPUIdleStateDeltas(cpuID)
 	if err != nil {
-		return 0.0, err
+		return 0, err
+	}
+
+	delta, ok := deltas[stateName]
+	if !ok {
+		return 0, fmt.Errorf("idle state %q not found for CPU ID: %v", stateName, cpuID)
+	}
+	return float64(delta.time) / float64(interval.Microseconds()) * 100, nil
+}
+
+// GetCPUIdleStateEntryRate takes a CPU ID and an idle state name, and returns the number of times per second
+// the CPU entered the idle state within the interval between the last two updates of its metrics.
+func (pt *PowerTelemetry) GetCPUIdleStateEntryRate(cpuID int, stateName string) (float64, error) {
+	if pt.cpuIdle == nil {
+		return 0, &ModuleNotInitializedError{Name: "cpuidle"}
+	}
+
+	deltas, interval, err := pt.getCPUIdleStateDeltas(cpuID)
+	if err != nil {
+		return 0, err
+	}
+
+	delta, ok := deltas[stateName]
+	if !ok {
+		return 0, fmt.Errorf("idle state %q not found for CPU ID: %v", stateName, cpuID)
+	}
+	return float64(delta.usage) / (float64(interval.Nanoseconds()) * fromNanosecondsToSecondsRatio), nil
+}
+
+// IsIdleStateDisabled takes a CPU ID and an idle state name, and returns true if the idle state is disabled
+// for the CPU ID.
+func (pt *PowerTelemetry) IsIdleStateDisabled(cpuID int, stateName string) (bool, error) {
+	if pt.cpuIdle == nil {
+		return false, &ModuleNotInitializedError{Name: "cpuidle"}
+	}
+	return pt.cpuIdle.isStateDisabled(cpuID, stateName)
+}
+
+// SetIdleStateDisabled takes a CPU ID, an idle state name and a boolean, and disables or enables the idle state
+// for the CPU ID. The original value of the idle state is recorded the first time it is modified, and it can
+// be put back by calling Restore or Close.
+func (pt *PowerTelemetry) SetIdleStateDisabled(cpuID int, stateName string, disabled bool) error {
+	if pt.cpuIdle == nil {
+		return &ModuleNotInitializedError{Name: "cpuidle"}
+	}
+	return pt.cpuIdle.setStateDisabled(cpuID, stateName, disabled)
+}
+
+// GetIntelIdleMaxCState returns the value of max_cstate parameter of intel_idle driver, which is the
+// deepest idle state index the driver is allowed to use.
+func (pt *PowerTelemetry) GetIntelIdleMaxCState() (int, error) {
+	if pt.cpuIdle == nil {
+		return 0, &ModuleNotInitializedError{Name: "cpuidle"}
+	}
+
+	value, err := pt.cpuIdle.getIntelIdleParam(intelIdleMaxCStateFile)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving intel_idle parameter %q: %w", intelIdleMaxCStateFile, err)
+	}
+
+	maxCState, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("error converting intel_idle parameter %q: %w", intelIdleMaxCStateFile, err)
+	}
+	return maxCState, nil
+}
+
+// GetIntelIdleStatesOff returns the value of states_off parameter of intel_idle driver, which is a bitmask
+// of idle state indexes disabled by default.
+func (pt *PowerTelemetry) GetIntelIdleStatesOff() (uint64, error) {
+	if pt.cpuIdle == nil {
+		return 0, &ModuleNotInitializedError{Name: "cpuidle"}
+	}
+
+	value, err := pt.cpuIdle.getIntelIdleParam(intelIdleStatesOffFile)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving intel_idle parameter %q: %w", intelIdleStatesOffFile, err)
+	}
+
+	statesOff, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error converting intel_idle parameter %q: %w", intelIdleStatesOffFile, err)
+	}
+	return statesOff, nil
+}
+
+// ReadMSR takes a CPU ID and an MSR offset, and returns the raw value of the offset. The offset is read through
+// the MSR registers of the instance, hence the CPU ID must be within the available CPU IDs, see GetMsrCPUIDs.
+// Otherwise, a *CPUNotAvailableError is returned. If the offset could not be read, an *MsrReadError is returned.
+func (pt *PowerTelemetry) ReadMSR(cpuID int, offset uint32) (uint64, error) {
+	if pt.msr == nil {
+		return 0, &ModuleNotInitializedError{Name: "msr"}
+	}
+	if !slices.Contains(pt.cpus, cpuID) {
+		return 0, &CPUNotAvailableError{CPUID: cpuID}
+	}
+
+	value, err := pt.msr.read(offset, cpuID)
+	if err != nil {
+		return 0, &MsrReadError{CPUID: cpuID, Offset: offset, Err: err}
+	}
+	return value, nil
+}
+
+// ReadMSRs takes a slice of CPU IDs and a slice of MSR offsets, and returns a map of CPU ID key and a map with offset key
+// and raw offset value. If the slice of CPU IDs is empty, offsets of all available CPU IDs are read. If any CPU ID is not
+// within the available CPU IDs, a *CPUNotAvailableError is returned prior to reading any offset. If an offset could not
+// be read, an *MsrReadError is returned.
+func (pt *PowerTelemetry) ReadMSRs(cpuIDs []int, offsets []uint32) (map[int]map[uint32]uint64, error) {
+	if pt.msr == nil {
+		return nil, &ModuleNotInitializedError{Name: "msr"}
+	}
+	if len(offsets) == 0 {
+		return nil, errors.New("MSR offsets cannot be empty")
+	}
+	if len(cpuIDs) == 0 {
+		cpuIDs = pt.cpus
+	}
+	for _, cpuID := range cpuIDs {
+		if !slices.Contains(pt.cpus, cpuID) {
+			return nil, &CPUNotAvailableError{CPUID: cpuID}
+		}
+	}
+
+	values := make(map[int]map[uint32]uint64, len(cpuIDs))
+	for _, cpuID := range cpuIDs {
+		cpuValues := make(map[uint32]uint64, len(offsets))
+		for _, offset := range offsets {
+			value, err := pt.msr.read(offset, cpuID)
+			if err != nil {
+				return nil, &MsrReadError{CPUID: cpuID, Offset: offset, Err: err}
+			}
+			cpuValues[offset] = value
+		}
+		values[cpuID] = cpuValues
+	}
+	return values, nil
+}
+
+// WriteMSR takes a CPU ID, an MSR offset, a value and a mask. It replaces the bits of the offset specified by the mask with
+// the corresponding bits of the value, and verifies the result by reading it back. The write operation must be enabled by
+// WithMsrWrites option and allowed by its allowlist, as well as by allow_writes parameter of msr kernel module. Otherwise,
+// an *MsrWriteNotAllowedError is returned. The original value of the offset is recorded in the journal prior to the write
+// operation, so that it can be restored by Restore or ReplayMsrJournal methods.
+func (pt *PowerTelemetry) WriteMSR(cpuID int, offset uint32, value, mask uint64) error {
+	if pt.msr == nil {
+		return &ModuleNotInitializedError{Name: "msr"}
+	}
+	if pt.msrWrite == nil {
+		return &ModuleNotInitializedError{Name: "msr_write"}
+	}
+	return pt.msrWrite.write(pt.msr, cpuID, offset, value, mask)
+}
+
+// ReplayMsrJournal restores the original values of the MSR offsets recorded in the journal set by WithMsrWrites option,
+// including those recorded by a previous session which did not restore them, e.g. due to a crash. Once all values are
+// restored, the journal is cleared.
+func (pt *PowerTelemetry) ReplayMsrJournal() error {
+	if pt.msr == nil {
+		return &ModuleNotInitializedError{Name: "msr"}
+	}
+	if pt.msrWrite == nil {
+		return &ModuleNotInitializedError{Name: "msr_write"}
+	}
+	return pt.msrWrite.replay(pt.msr)
+}
+
+// Restore puts back the original values of the settings modified through the PowerTelemetry instance,
+// such as idle states disabled or enabled via SetIdleStateDisabled, and MSR offsets written via WriteMSR.
+// An error is returned if one or more settings could not be restored.
+func (pt *PowerTelemetry) Restore() error {
+	var errs []error
+	if pt.cpuIdle != nil {
+		if err := pt.cpuIdle.restore(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore idle states: %w", err))
+		}
+	}
+	if pt.msrWrite != nil && pt.msr != nil {
+		if err := pt.msrWrite.replay(pt.msr); err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore MSR offsets: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close restores the original values of the settings modified through the PowerTelemetry instance, and
+// releases the descriptors of the MSR files held by the instance. It should be called once the instance
+// is no longer needed.
+func (pt *PowerTelemetry) Close() error {
+	var errs []error
+	if err := pt.Restore(); err != nil {
+		errs = append(errs, err)
+	}
+	if pt.msrWrite != nil {
+		if err := pt.msrWrite.close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close msr write journal: %w", err))
+		}
+	}
+	if pt.msr != nil {
+		if err := pt.msr.close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close msr: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// getCPUIdleStateDeltas takes a CPU ID and returns a map of idle state name and its counter deltas,
+// together with the time interval they were collected within.
+func (pt *PowerTelemetry) getCPUIdleStateDeltas(cpuID int) (map[string]cpuIdleStateDelta, time.Duration, error) {
+	interval, err := pt.cpuIdle.getTimestampDelta(cpuID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error retrieving timestamp delta for CPU ID %v: %w", cpuID, err)
+	}
+	if interval <= 0 {
+		return nil, 0, errors.New("elapsed interval is not available, idle state counters must be read at least twice")
+	}
+	// Time counters of idle states are in microseconds.
+	if interval < time.Microsecond {
+		return nil, 0, fmt.Errorf("elapsed interval %v is shorter than 1 microsecond", interval)
+	}
+
+	deltas, err := pt.cpuIdle.getStateDeltas(cpuID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error retrieving idle state deltas for CPU ID %v: %w", cpuID, err)
+	}
+	return deltas, interval, nil
+}
+
+// getCPUIdleCStateResidency takes a CPU ID and a C-state name, and returns the percentage of time the CPU
+// spent in idle states which are flavors of the C-state, e.g. C1 and C1E for C1.
+func (pt *PowerTelemetry) getCPUIdleCStateResidency(cpuID int, cState string) (float64, error) {
+	deltas, interval, err := pt.getCPUIdleStateDeltas(cpuID)
+	if err != nil {
+		return 0, err
+	}
+
+	found := false
+	var residencyUs uint64
+	for name, delta := range deltas {
+		if !isCPUIdleStateOf(name, cState) {
+			continue
+		}
+		found = true
+		residencyUs += delta.time
+	}
+	if !found {
+		return 0, fmt.Errorf("no idle states of %s found for CPU ID: %v", cState, cpuID)
+	}
+	return float64(residencyUs) / float64(interval.Microseconds()) * 100, nil
+}
+
+// GetPackageTemperature takes a package ID and returns its temperature, in degrees Celsius, read from
+// the package temperature sensor exposed by coretemp hwmon driver.
+func (pt *PowerTelemetry) GetPackageTemperature(packageID int) (uint64, error) {
+	if pt.coreTemp == nil {
+		return 0, &ModuleNotInitializedError{Name: "coretemp"}
+	}
+	return pt.coreTemp.getPackageTemperature(packageID, tempInput)
+}
+
+// GetCPUTemperatureThresholds takes a CPU ID and returns the temperature thresholds of its core,
+// read from the core temperature sensor exposed by coretemp hwmon driver.
+func (pt *PowerTelemetry) GetCPUTemperatureThresholds(cpuID int) (TemperatureThresholds, error) {
+	if pt.coreTemp == nil {
+		return TemperatureThresholds{}, &ModuleNotInitializedError{Name: "coretemp"}
+	}
+
+	maxTemp, err := pt.getCoreTemperature(cpuID, tempMax)
+	if err != nil {
+		return TemperatureThresholds{}, err
+	}
+	critTemp, err := pt.getCoreTemperature(cpuID, tempCrit)
+	if err != nil {
+		return TemperatureThresholds{}, err
+	}
+	return TemperatureThresholds{Max: maxTemp, Critical: critTemp}, nil
+}
+
+// GetPackageTemperatureThresholds takes a package ID and returns its temperature thresholds, read from
+// the package temperature sensor exposed by coretemp hwmon driver.
+func (pt *PowerTelemetry) GetPackageTemperatureThresholds(packageID int) (TemperatureThresholds, error) {
+	if pt.coreTemp == nil {
+		return TemperatureThresholds{}, &ModuleNotInitializedError{Name: "coretemp"}
+	}
+
+	maxTemp, err := pt.coreTemp.getPackageTemperature(packageID, tempMax)
+	if err != nil {
+		return TemperatureThresholds{}, err
+	}
+	critTemp, err := pt.coreTemp.getPackageTemperature(packageID, tempCrit)
+	if err != nil {
+		return TemperatureThresholds{}, err
+	}
+	return TemperatureThresholds{Max: maxTemp, Critical: critTemp}, nil
+}
+
+// getCoreTemperature takes a CPU ID and a sensor attribute, and returns the attribute value of the temperature
+// sensor of its core. The CPU ID is mapped to the package and core IDs of the sensor via the topology.
+func (pt *PowerTelemetry) getCoreTemperature(cpuID int, attr tempAttribute) (uint64, error) {
+	packageID, err := pt.topology.getCPUPackageID(cpuID)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving package ID for CPU ID %v: %w", cpuID, err)
+	}
+	coreID, err := pt.topology.getCPUCoreID(cpuID)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving core ID for CPU ID %v: %w", cpuID, err)
+	}
+	return pt.coreTemp.getCoreTemperature(packageID, coreID, attr)
+}
+
+// GetThermalZones returns the thermal zones exposed by the thermal subsystem, ordered by ID, together with
+// their current temperature, governor policy, trip points and bound cooling devices. If some thermal zones cannot be
+// read, the remaining ones are returned together with an error.
+func (pt *PowerTelemetry) GetThermalZones() ([]ThermalZone, error) {
+	if pt.thermal == nil {
+		return nil, &ModuleNotInitializedError{Name: "thermal_zone"}
+	}
+	return pt.thermal.getThermalZones()
+}
+
+// GetCoolingDevices returns the cooling devices exposed by the thermal subsystem, ordered by ID,
+// together with their current and maximum cooling states. If some cooling devices cannot be read, the remaining ones
+// are returned together with an error.
+func (pt *PowerTelemetry) GetCoolingDevices() ([]CoolingDevice, error) {
+	if pt.thermal == nil {
+		return nil, &ModuleNotInitializedError{Name: "thermal_zone"}
+	}
+	return pt.thermal.getCoolingDevices()
+}
+
+// GetCPUThermalThrottleStats takes a CPU ID and returns the thermal throttling statistics of its core,
+// within the interval between the last two updates of its metrics.
+func (pt *PowerTelemetry) GetCPUThermalThrottleStats(cpuID int) (ThermalThrottleStats, error) {
+	if pt.throttle == nil {
+		return ThermalThrottleStats{}, &ModuleNotInitializedError{Name: "thermal_throttle"}
+	}
+	return pt.getThermalThrottleStats(cpuID, coreThrottle)
+}
+
+// GetPackageThermalThrottleStats takes a package ID and returns its thermal throttling statistics, within the
+// interval between the last two updates of the metrics of the first available CPU ID of the package.
+func (pt *PowerTelemetry) GetPackageThermalThrottleStats(packageID int) (ThermalThrottleStats, error) {
+	if pt.throttle == nil {
+		return ThermalThrottleStats{}, &ModuleNotInitializedError{Name: "thermal_throttle"}
+	}
+
+	cpuID, err := pt.getCPUIDFromPackageID(packageID)
+	if err != nil {
+		return ThermalThrottleStats{}, fmt.Errorf("could not find CPU ID for package ID %v: %w", packageID, err)
+	}
+	return pt.getThermalThrottleStats(cpuID, packageThrottle)
+}
+
+// getThermalThrottleStats takes a CPU ID and a thermal throttle domain, and returns the thermal throttling
+// statistics of the domain.
+func (pt *PowerTelemetry) getThermalThrottleStats(cpuID int, domain throttleDomain) (ThermalThrottleStats, error) {
+	interval, err := pt.throttle.getTimestampDelta(cpuID)
+	if err != nil {
+		return ThermalThrottleStats{}, fmt.Errorf("error retrieving timestamp delta for CPU ID %v: %w", cpuID, err)
+	}
+	if interval <= 0 {
+		return ThermalThrottleStats{}, errors.New("elapsed interval is not available, thermal throttle counters must be read at least twice")
+	}
+
+	deltas, err := pt.throttle.getThrottleDeltas(cpuID, domain)
+	if err != nil {
+		return ThermalThrottleStats{}, fmt.Errorf("error retrieving thermal throttle deltas for CPU ID %v: %w", cpuID, err)
+	}
+
+	maxTimeMs, err := pt.throttle.getThrottleMaxTimeMs(cpuID, domain)
+	if err != nil {
+		return ThermalThrottleStats{}, fmt.Errorf("error retrieving thermal throttle max time for CPU ID %v: %w", cpuID, err)
+	}
+
+	return ThermalThrottleStats{
+		Count:     deltas.count,
+		TimeMs:    deltas.timeMs,
+		MaxTimeMs: maxTimeMs,
+		Interval:  interval,
+	}, nil
+}
+
+// IsFlagSupported takes a flag's value and returns true if first CPU supports it and false if it doesn't.
+func (pt *PowerTelemetry) IsFlagSupported(flag string) (bool, error) {
+	flags, err := pt.topology.getCPUFlags(0)
+	if err != nil {
+		return false, fmt.Errorf("error retrieving CPU flags: %w", err)
+	}
+	for _, f := range flags {
+		if f == flag {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ReadPerfEvents reads the active perf events, either related to supported C0 state residency metrics
+// or set by WithPerfEvents option, as well as the active uncore perf events set by WithPerfUncore option,
+// and updates the storage to make metrics available. If one or more events could not be read an error is returned.
+func (pt *PowerTelemetry) ReadPerfEvents() error {
+	if pt.perf == nil && pt.perfUncore == nil {
+		return &ModuleNotInitializedError{Name: "perf"}
+	}
+
+	var errs []error
+	if pt.perf != nil {
+		if err := pt.perf.update(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if pt.perfUncore != nil {
+		if err := pt.perfUncore.update(); err != nil {
+			errs = append(errs, fmt.Errorf("error reading uncore perf events: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DeactivatePerfEvents deactivates all active events, including uncore events, C-state residency events and msr PMU events.
+// If an event or events could not be successfully deactivated, an error is returned.
+// This method should be explicitly called to avoid resource leakage.
+func (pt *PowerTelemetry) DeactivatePerfEvents() error {
+	if pt.perf == nil && pt.perfUncore == nil && pt.cstatePerf == nil && pt.msrPerf == nil {
+		return &ModuleNotInitializedError{Name: "perf"}
+	}
+
+	var errs []error
+	if pt.perf != nil {
+		if err := pt.perf.deactivate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if pt.perfUncore != nil {
+		if err := pt.perfUncore.deactivate(); err != nil {
+			errs = append(errs, fmt.Errorf("error deactivating uncore perf events: %w", err))
+		}
+	}
+	if pt.cstatePerf != nil {
+		if err := pt.cstatePerf.deactivate(); err != nil {
+			errs = append(errs, fmt.Errorf("error deactivating C-state residency events: %w", err))
+		}
+	}
+	if pt.msrPerf != nil {
+		if err := pt.msrPerf.deactivate(); err != nil {
+			errs = append(errs, fmt.Errorf("error deactivating msr PMU events: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GetCPUC0SubstateC01Percent takes a CPU ID and returns a value indicating the percentage of time
+// the processor spent in its C0.1 substate out of the total time in the C0 state, within the interval
+// between the two latest ReadPerfEvents method calls.
+// C0.1 is characterized by a light-weight slower wakeup time but more power-saving optimized state.
+func (pt *PowerTelemetry) GetCPUC0SubstateC01Percent(cpuID int) (float64, error) {
+	return pt.getC0SubstatePercent(cpuID, c01)
+}
+
+// GetCPUC0SubstateC02Percent takes a CPU ID and returns a value indicating the percentage of time
+// the processor spent in its C0.2 substate out of the total time in the C0 state, within the interval
+// between the two latest ReadPerfEvents method calls.
+// C0.2 is characterized by a light-weight faster wakeup time but less power saving optimized state.
+func (pt *PowerTelemetry) GetCPUC0SubstateC02Percent(cpuID int) (float64, error) {
+	return pt.getC0SubstatePercent(cpuID, c02)
+}
+
+// GetCPUC0SubstateC0WaitPercent takes a CPU ID and returns a value indicating the percentage of time
+// the processor spent in its C0_Wait substate out of the total time in the C0 state, within the interval
+// between the two latest ReadPerfEvents method calls.
+// CPU is in C0_Wait substate when the thread is in the C0.1 or C0.2 or running a PAUSE in C0 ACPI state.
+func (pt *PowerTelemetry) GetCPUC0SubstateC0WaitPercent(cpuID int) (float64, error) {
+	return pt.getC0SubstatePercent(cpuID, c0Wait)
+}
+
+// getC0SubstatePercent is a helper method that takes a CPU ID and a C0 substate, and returns the percentage of
+// unhalted core cycles spent in the substate, within the interval between the two latest ReadPerfEvents method calls.
+func (pt *PowerTelemetry) getC0SubstatePercent(cpuID int, substate c0StateType) (float64, error) {
+	ratio, err := pt.getPerfRatio(cpuID, substate.String(), thread.String())
+	if err != nil {
+		return 0.0, err
+	}
+	return ratio * 100, nil
+}
+
+// GetCPUIPC takes a CPU ID and returns the number of instructions retired per unhalted core cycle, within
+// the interval between the two latest ReadPerfEvents method calls.
+func (pt *PowerTelemetry) GetCPUIPC(cpuID int) (float64, error) {
+	return pt.getPerfRatio(cpuID, instRetired, thread.String())
+}
+
+// GetCPUCPI takes a CPU ID and returns the number of unhalted core cycles per instruction retired, within
+// the interval between the two latest ReadPerfEvents method calls.
+func (pt *PowerTelemetry) GetCPUCPI(cpuID int) (float64, error) {
+	return pt.getPerfRatio(cpuID, thread.String(), instRetired)
+}
+
+// GetCPUUnhaltedFrequencyMhz takes a CPU ID and returns the average frequency of the CPU while unhalted, in MHz,
+// within the interval between the two latest ReadPerfEvents method calls. It is calculated as the ratio of unhalted
+// core cycles to unhalted reference cycles, which are counted at the nominal frequency of the time stamp counter.
+func (pt *PowerTelemetry) GetCPUUnhaltedFrequencyMhz(cpuID int) (float64, error) {
+	ratio, err := pt.getPerfRatio(cpuID, thread.String(), refCycles)
+	if err != nil {
+		return 0.0, err
+	}
+
+	tscHz := tscFrequency()
+	if tscHz == 0 {
+		return 0.0, errors.New("nominal frequency of the time stamp counter is not available")
+	}
+	return ratio * float64(tscHz) * fromHertzToMegaHertzRatio, nil
+}
+
+// GetCPUTopdownRetiringPercent takes a CPU ID and returns the percentage of pipeline slots utilized by
+// micro-operations that eventually retired, within the interval between the two latest ReadPerfEvents method calls.
+// It is a level 1 metric of Top-down Microarchitecture Analysis (TMA).
+func (pt *PowerTelemetry) GetCPUTopdownRetiringPercent(cpuID int) (float64, error) {
+	return pt.getTopdownPercent(cpuID, topdownRetiring)
+}
+
+// GetCPUTopdownBadSpeculationPercent takes a CPU ID and returns the percentage of pipeline slots wasted due to
+// incorrect speculation, within the interval between the two latest ReadPerfEvents method calls. It is a level 1
+// metric of Top-down Microarchitecture Analysis (TMA).
+func (pt *PowerTelemetry) GetCPUTopdownBadSpeculationPercent(cpuID int) (float64, error) {
+	return pt.getTopdownPercent(cpuID, topdownBadSpeculation)
+}
+
+// GetCPUTopdownFrontendBoundPercent takes a CPU ID and returns the percentage of pipeline slots where the
+// frontend undersupplied the backend, within the interval between the two latest ReadPerfEvents method calls.
+// It is a level 1 metric of Top-down Microarchitecture Analysis (TMA).
+func (pt *PowerTelemetry) GetCPUTopdownFrontendBoundPercent(cpuID int) (float64, error) {
+	return pt.getTopdownPercent(cpuID, topdownFrontendBound)
+}
+
+// GetCPUTopdownBackendBoundPercent takes a CPU ID and returns the percentage of pipeline slots where no
+// micro-operations were delivered due to a lack of backend resources, within the interval between the two latest
+// ReadPerfEvents method calls. It is a level 1 metric of Top-down Microarchitecture Analysis (TMA).
+func (pt *PowerTelemetry) GetCPUTopdownBackendBoundPercent(cpuID int) (float64, error) {
+	return pt.getTopdownPercent(cpuID, topdownBackendBound)
+}
+
+// getTopdownPercent is a helper method that takes a CPU ID and a topdown metric event name, and returns the percentage
+// of pipeline slots attributed to the event, within the interval between the two latest ReadPerfEvents method calls.
+func (pt *PowerTelemetry) getTopdownPercent(cpuID int, event string) (float64, error) {
+	ratio, err := pt.getPerfRatio(cpuID, event, topdownSlots)
+	if err != nil {
+		return 0.0, err
+	}
+	return ratio * 100, nil
+}
+
+// GetPerfEventValue takes a CPU ID and a perf event name, and returns the values of the event read by the latest
+// ReadPerfEvents method call. Event names are case-insensitive. Delta field holds the scaled increase of the event
+// counter between the two latest ReadPerfEvents method calls.
+func (pt *PowerTelemetry) GetPerfEventValue(cpuID int, name string) (PerfEventValue, error) {
+	if pt.perf == nil {
+		return PerfEventValue{}, &ModuleNotInitializedError{Name: "perf"}
+	}
+	for _, metric := range pt.perf.getCoreMetrics(cpuID) {
+		if strings.EqualFold(metric.name, name) {
+			return PerfEventValue{
+				Raw:     metric.values.Raw,
+				Enabled: metric.values.Enabled,
+				Running: metric.values.Running,
+				Scaled:  metric.scaled,
+				Delta:   metric.delta,
+
+				MultiplexRatio: metric.multiplexRatio,
+				LowConfidence:  metric.multiplexRatio < LowConfidenceMultiplexRatio,
+
+				CoreType: metric.coreType,
+			}, nil
+		}
+	}
+	return PerfEventValue{}, fmt.Errorf("could not find perf event %q for CPU ID %v", name, cpuID)
+}
+
+// GetCPUPerfMultiplexing takes a CPU ID and returns the multiplexing information of the perf events of the CPU ID,
+// read by the two latest ReadPerfEvents method calls. Values of events which were counting for a fraction of the
+// interval lower than LowConfidenceMultiplexRatio are flagged as low confidence.
+func (pt *PowerTelemetry) GetCPUPerfMultiplexing(cpuID int) (PerfMultiplexing, error) {
+	if pt.perf == nil {
+		return PerfMultiplexing{}, &ModuleNotInitializedError{Name: "perf"}
+	}
+	coreMetrics := pt.perf.getCoreMetrics(cpuID)
+	if len(coreMetrics) == 0 {
+		return PerfMultiplexing{}, fmt.Errorf("no core metrics found for CPU ID: %v", cpuID)
+	}
+
+	multiplexing := PerfMultiplexing{
+		MinRatio: coreMetrics[0].multiplexRatio,
+	}
+	sum := 0.0
+	for _, metric := range coreMetrics {
+		ratio := metric.multiplexRatio
+		sum += ratio
+		multiplexing.MinRatio = min(multiplexing.MinRatio, ratio)
+		if ratio < LowConfidenceMultiplexRatio {
+			multiplexing.LowConfidence = true
+			multiplexing.LowConfidenceEvents = append(multiplexing.LowConfidenceEvents, metric.name)
+		}
+	}
+	multiplexing.MeanRatio = sum / float64(len(coreMetrics))
+	return multiplexing, nil
+}
+
+// GetCPUCoreType takes a CPU ID and returns the core type of the CPU ID on hybrid processors, according to the core PMU
+// its perf events were activated on. On processors which are not hybrid, it returns an empty string.
+func (pt *PowerTelemetry) GetCPUCoreType(cpuID int) (CoreType, error) {
+	if pt.perf == nil {
+		return "", &ModuleNotInitializedError{Name: "perf"}
+	}
+	coreMetrics := pt.perf.getCoreMetrics(cpuID)
+	if len(coreMetrics) == 0 {
+		return "", fmt.Errorf("no core metrics found for CPU ID: %v", cpuID)
+	}
+	return coreMetrics[0].coreType, nil
+}
+
+// MemoryBandwidth represents the DRAM bandwidth of a package within an elapsed interval.
+type MemoryBandwidth struct {
+	Read  float64 // Read bandwidth, in GB/s
+	Write float64 // Write bandwidth, in GB/s
+}
+
+// GetPackageMemoryBandwidth takes a package ID and returns the DRAM read and write bandwidth of the package, in GB/s,
+// within the interval between the two latest ReadPerfEvents method calls. Bandwidth is calculated from the CAS commands
+// issued by all memory controller channels of the package, each one transferring a cache line of 64 bytes.
+func (pt *PowerTelemetry) GetPackageMemoryBandwidth(packageID int) (MemoryBandwidth, error) {
+	if pt.perfUncore == nil {
+		return MemoryBandwidth{}, &ModuleNotInitializedError{Name: "perf_uncore"}
+	}
+
+	interval := pt.perfUncore.getTimestampDelta()
+	if interval <= 0 {
+		return MemoryBandwidth{}, errors.New("elapsed interval is not available, uncore perf events must be read at least twice")
+	}
+
+	metrics := pt.perfUncore.getPackageMetrics(packageID)
+	if len(metrics) == 0 {
+		return MemoryBandwidth{}, fmt.Errorf("no uncore metrics found for package ID: %v", packageID)
+	}
+
+	read, err := getUncoreMetric(metrics, imcCASCountRead)
+	if err != nil {
+		return MemoryBandwidth{}, err
+	}
+	write, err := getUncoreMetric(metrics, imcCASCountWrite)
+	if err != nil {
+		return MemoryBandwidth{}, err
+	}
+
+	// bytes per nanosecond are equivalent to GB/s
+	nanoseconds := float64(interval.Nanoseconds())
+	return MemoryBandwidth{
+		Read:  float64(read.delta) * casBytes / nanoseconds,
+		Write: float64(write.delta) * casBytes / nanoseconds,
+	}, nil
+}
+
+// getUncoreMetric is a helper function that takes a slice of uncoreMetrics and a string name,
+// and returns the uncoreMetric corresponding to the name specified.
+func getUncoreMetric(metrics []uncoreMetric, name string) (uncoreMetric, error) {
+	for _, metric := range metrics {
+		if strings.EqualFold(metric.name, name) {
+			return metric, nil
+		}
+	}
+	return uncoreMetric{}, fmt.Errorf("could not find uncore metric: %q", name)
+}
+
+// getPerfRatio is a helper method that takes a CPU ID, a target metric name and reference metric name. It returns
+// the ratio of the target metric to the reference metric within the interval between the two latest reads or, if
+// enabled by WithPerfCumulativeRatios option, since activation.
+func (pt *PowerTelemetry) getPerfRatio(cpuID int, target, reference string) (float64, error) {
+	if pt.perfCumulative {
+		return pt.getPerfMetricRatio(cpuID, target, reference)
+	}
+	return pt.getPerfMetricDeltaRatio(cpuID, target, reference)
+}
+
+// getPerfMetricRatio is a helper method that takes a CPU ID, a target metric name and reference metric name.
+// First, it fetches the specified metrics from the perf storage. Then, it calculates the ratio of the target
+// metric scaled value to the reference metric scaled value, accumulated since activation.
+func (pt *PowerTelemetry) getPerfMetricRatio(cpuID int, target, reference string) (float64, error) {
+	if pt.perf == nil {
+		return 0.0, &ModuleNotInitializedError{Name: "perf"}
+	}
+	coreMetrics := pt.perf.getCoreMetrics(cpuID)
+	if len(coreMetrics) == 0 {
+		return 0.0, fmt.Errorf("no core metrics found for CPU ID: %v", cpuID)
+	}
+
+	targetMetric, err := getMetric(coreMetrics, target)
+	if err != nil {
+		return 0.0, err
 	}
 
 	refMetric, err := getMetric(coreMetrics, reference)
@@ -704,14 +1906,43 @@ func (pt *PowerTelemetry) getPerfMetricRatio(cpuID int, target, reference string
 		return 0.0, fmt.Errorf("zero scaled value for reference metric: %q", reference)
 	}
 
-	return float64(targetMetric.scaled) / float64(refMetric.scaled) * 100, nil
+	return float64(targetMetric.scaled) / float64(refMetric.scaled), nil
+}
+
+// getPerfMetricDeltaRatio is a helper method that takes a CPU ID, a target metric name and reference metric name.
+// First, it fetches the specified metrics from the perf storage. Then, it calculates the ratio of the target metric
+// delta to the reference metric delta, within the interval between the two latest reads.
+func (pt *PowerTelemetry) getPerfMetricDeltaRatio(cpuID int, target, reference string) (float64, error) {
+	if pt.perf == nil {
+		return 0.0, &ModuleNotInitializedError{Name: "perf"}
+	}
+	coreMetrics := pt.perf.getCoreMetrics(cpuID)
+	if len(coreMetrics) == 0 {
+		return 0.0, fmt.Errorf("no core metrics found for CPU ID: %v", cpuID)
+	}
+
+	targetMetric, err := getMetric(coreMetrics, target)
+	if err != nil {
+		return 0.0, err
+	}
+
+	refMetric, err := getMetric(coreMetrics, reference)
+	if err != nil {
+		return 0.0, err
+	}
+
+	if refMetric.delta == 0 {
+		return 0.0, fmt.Errorf("zero delta for reference metric: %q", reference)
+	}
+
+	return float64(targetMetric.delta) / float64(refMetric.delta), nil
 }
 
 // getMetric is a helper function that takes a slice of coreMetrics and a string name,
-// and returns the first coreMetric corresponding to the name specified.
+// and returns the first coreMetric whose name matches the name specified, case-insensitively.
 func getMetric(metrics []coreMetric, name string) (coreMetric, error) {
 	for _, metric := range metrics {
-		if strings.Contains(metric.name, name) {
+		if strings.EqualFold(metric.name, name) {
 			return metric, nil
 		}
 	}
@@ -758,12 +1989,14 @@ func (pt *PowerTelemetry) GetMsrCPUIDs() []int {
 	return pt.cpus
 }
 
-// GetPerfCPUIDs returns a slice with available CPU IDs of the host, for which perf has access to.
+// GetPerfCPUIDs returns a slice with CPU IDs of the host, in ascending order, for which perf core events are active.
+// On hybrid processors, it may be a subset of the available CPU IDs, since events are only activated for CPU IDs of
+// the core types given to WithPerf option. If perf is not initialized, it returns nil.
 func (pt *PowerTelemetry) GetPerfCPUIDs() []int {
-	// TODO: This implementation should be changed when this library will
-	// support hybrid CPUs. Only performance cores should be returned here
-	// so that the result may be a subset of slice pt.cpus.
-	return pt.cpus
+	if pt.perf == nil {
+		return nil
+	}
+	return pt.perf.activeCPUIDs()
 }
 
 // GetCPUPackageID gets cpu's package ID value. If no cpu is found for the corresponding cpuID
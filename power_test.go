@@ -6,13 +6,17 @@
 package powertelemetry
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
 	"math/big"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	ev "github.com/intel/iaevents"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
@@ -48,6 +52,168 @@ func (m *uncoreFreqMock) getUncoreFrequencyMhz(packageID, dieID int, freqType st
 	return args.Get(0).(float64), args.Error(1)
 }
 
+// cpuIdleMock represents a mock for cpuIdleData type. Implements cpuIdleReader interface.
+type cpuIdleMock struct {
+	mock.Mock
+}
+
+func (m *cpuIdleMock) initCPUIdleMap(cpus []int) error {
+	args := m.Called(cpus)
+	return args.Error(0)
+}
+
+func (m *cpuIdleMock) update(cpuID int) error {
+	args := m.Called(cpuID)
+	return args.Error(0)
+}
+
+func (m *cpuIdleMock) getStates(cpuID int) ([]CPUIdleState, error) {
+	args := m.Called(cpuID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]CPUIdleState), args.Error(1)
+}
+
+func (m *cpuIdleMock) getStateDeltas(cpuID int) (map[string]cpuIdleStateDelta, error) {
+	args := m.Called(cpuID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]cpuIdleStateDelta), args.Error(1)
+}
+
+func (m *cpuIdleMock) getTimestampDelta(cpuID int) (time.Duration, error) {
+	args := m.Called(cpuID)
+	return args.Get(0).(time.Duration), args.Error(1)
+}
+
+func (m *cpuIdleMock) isStateDisabled(cpuID int, stateName string) (bool, error) {
+	args := m.Called(cpuID, stateName)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *cpuIdleMock) setStateDisabled(cpuID int, stateName string, disabled bool) error {
+	args := m.Called(cpuID, stateName, disabled)
+	return args.Error(0)
+}
+
+func (m *cpuIdleMock) restore() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *cpuIdleMock) getIntelIdleParam(name string) (string, error) {
+	args := m.Called(name)
+	return args.String(0), args.Error(1)
+}
+
+// thermalThrottleMock represents a mock for thermalThrottleData type. Implements thermalThrottleReader interface.
+type thermalThrottleMock struct {
+	mock.Mock
+}
+
+func (m *thermalThrottleMock) initThrottleMap(cpus []int) error {
+	args := m.Called(cpus)
+	return args.Error(0)
+}
+
+func (m *thermalThrottleMock) update(cpuID int) error {
+	args := m.Called(cpuID)
+	return args.Error(0)
+}
+
+func (m *thermalThrottleMock) getThrottleDeltas(cpuID int, domain throttleDomain) (throttleCounters, error) {
+	args := m.Called(cpuID, domain)
+	return args.Get(0).(throttleCounters), args.Error(1)
+}
+
+func (m *thermalThrottleMock) getThrottleMaxTimeMs(cpuID int, domain throttleDomain) (uint64, error) {
+	args := m.Called(cpuID, domain)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *thermalThrottleMock) getTimestampDelta(cpuID int) (time.Duration, error) {
+	args := m.Called(cpuID)
+	return args.Get(0).(time.Duration), args.Error(1)
+}
+
+// pmuCounterMock represents a mock for pmuCounterData type. Implements pmuCounterReader interface.
+type pmuCounterMock struct {
+	mock.Mock
+}
+
+func (m *pmuCounterMock) initCounterMap(cpus, packageCPUs []int) error {
+	args := m.Called(cpus, packageCPUs)
+	return args.Error(0)
+}
+
+func (m *pmuCounterMock) update(cpuID int) error {
+	args := m.Called(cpuID)
+	return args.Error(0)
+}
+
+func (m *pmuCounterMock) getCounterDeltas(cpuID int) (map[pmuCounter]uint64, error) {
+	args := m.Called(cpuID)
+	return args.Get(0).(map[pmuCounter]uint64), args.Error(1)
+}
+
+func (m *pmuCounterMock) getTimestampDelta(cpuID int) (time.Duration, error) {
+	args := m.Called(cpuID)
+	return args.Get(0).(time.Duration), args.Error(1)
+}
+
+func (m *pmuCounterMock) deactivate() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+// coreTempMock represents a mock for coreTempData type. Implements coreTempReader interface.
+type coreTempMock struct {
+	mock.Mock
+}
+
+func (m *coreTempMock) initCoreTempMap() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *coreTempMock) getPackageTemperature(packageID int, attr tempAttribute) (uint64, error) {
+	args := m.Called(packageID, attr)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *coreTempMock) getCoreTemperature(packageID, coreID int, attr tempAttribute) (uint64, error) {
+	args := m.Called(packageID, coreID, attr)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+// thermalZoneMock represents a mock for thermalZoneData type. Implements thermalZoneReader interface.
+type thermalZoneMock struct {
+	mock.Mock
+}
+
+func (m *thermalZoneMock) initThermalZones() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *thermalZoneMock) getThermalZones() ([]ThermalZone, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]ThermalZone), args.Error(1)
+}
+
+func (m *thermalZoneMock) getCoolingDevices() ([]CoolingDevice, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]CoolingDevice), args.Error(1)
+}
+
 func TestGetInitialUncoreFrequencyMin(t *testing.T) {
 	pt := &PowerTelemetry{
 		uncoreFreq: &uncoreFreqData{
@@ -248,2973 +414,5637 @@ func TestGetCustomizedUncoreFrequencyMax(t *testing.T) {
 	})
 }
 
-func TestGetCurrentUncoreFrequency(t *testing.T) {
-	newTopology := &topologyData{
+func TestGetUncoreFrequencyLimitsFromMsr(t *testing.T) {
+	mError := errors.New("mock error")
+
+	// topology definition
+	topo := &topologyData{
 		topologyMap: map[int]*cpuInfo{
 			0: {
-				vendorID:  "IdOfVendor",
-				family:    "13",
 				packageID: 0,
 			},
 			1: {
-				vendorID:  "IdOfVendor",
-				family:    "13",
 				packageID: 1,
 			},
-			2: {
-				vendorID:  "IdOfVendor",
-				family:    "13",
-				packageID: 0,
-			},
 		},
+		model: cpumodel.INTEL_FAM6_SAPPHIRERAPIDS_X,
 	}
 
-	t.Run("FromFileSystem", func(t *testing.T) {
-		packageID := 0
-		dieID := 1
-		uncoreFreqExp := 2000.0
+	t.Run("UncoreFreqAndMsrAreNil", func(t *testing.T) {
+		pt := &PowerTelemetry{}
 
-		m := &uncoreFreqMock{}
+		freqOut, err := pt.GetCustomizedUncoreFrequencyMax(0, 0)
+		require.ErrorContains(t, err, "\"uncore_frequency\" is not initialized")
+		require.Equal(t, 0.0, freqOut)
+	})
 
-		// mock getting the current uncore frequency
-		m.On("getUncoreFrequencyMhz", packageID, dieID, "current").Return(uncoreFreqExp, nil).Once()
+	t.Run("ModelNotSupported", func(t *testing.T) {
+		cpuModel := cpumodel.INTEL_FAM6_ICELAKE
+
+		m := &msrMock{}
 
 		pt := &PowerTelemetry{
-			uncoreFreq: m,
+			topology: &topologyData{
+				model: cpuModel,
+			},
+			msr: m,
 		}
 
-		uncoreFreqOut, err := pt.GetCurrentUncoreFrequency(packageID, dieID)
-
-		require.NoError(t, err)
-		require.Equal(t, uncoreFreqExp, uncoreFreqOut)
+		freqOut, err := pt.GetCustomizedUncoreFrequencyMin(0, 0)
+		require.ErrorContains(t, err, fmt.Sprintf("uncore ratio limit not supported by CPU model: 0x%X", cpuModel))
+		require.Equal(t, 0.0, freqOut)
 		m.AssertExpectations(t)
 	})
 
-	t.Run("FromMsr", func(t *testing.T) {
-		t.Run("UncoreFreqFailed", func(t *testing.T) {
-			packageID := 1
-			dieID := 0
-			msrValue := uint64(0xffffff08)
-			uncoreFreqExp := 800.0
-
-			mUncoreFreq := &uncoreFreqMock{}
+	t.Run("UncoreFreqFailed", func(t *testing.T) {
+		packageID := 1
+		dieID := 0
 
-			// mock getting current uncore frequency
-			mUncoreFreq.On("getUncoreFrequencyMhz", packageID, dieID, "current").Return(0.0, errors.New("failed to read current uncore frequency file")).Once()
+		mUncoreFreq := &uncoreFreqMock{}
+		mUncoreFreq.On("getUncoreFrequencyMhz", packageID, dieID, "max").Return(0.0, mError).Once()
 
-			mMsr := &msrMock{}
+		m := &msrMock{}
+		m.On("read", uint32(uncoreRatioLimit), 1).Return(uint64(0x0818), nil).Once()
 
-			// mock reading msr offset UNCORE_PERF_STATUS of CPU ID 1
-			mMsr.On("read", uint32(uncorePerfStatus), 1).Return(msrValue, nil).Once()
+		pt := &PowerTelemetry{
+			topology:   topo,
+			uncoreFreq: mUncoreFreq,
+			msr:        m,
+			cpus:       []int{0, 1},
+		}
 
-			pt := &PowerTelemetry{
-				topology:   newTopology,
-				uncoreFreq: mUncoreFreq,
-				msr:        mMsr,
+		freqOut, err := pt.GetCustomizedUncoreFrequencyMax(packageID, dieID)
+		require.NoError(t, err)
+		require.Equal(t, 2400.0, freqOut)
+		mUncoreFreq.AssertExpectations(t)
+		m.AssertExpectations(t)
+	})
 
-				cpus: []int{0, 1, 2},
-			}
+	t.Run("UncoreFreqFailedAndMsrIsNil", func(t *testing.T) {
+		packageID := 1
+		dieID := 0
 
-			uncoreFreqOut, err := pt.GetCurrentUncoreFrequency(packageID, dieID)
+		mUncoreFreq := &uncoreFreqMock{}
+		mUncoreFreq.On("getUncoreFrequencyMhz", packageID, dieID, "min").Return(0.0, mError).Once()
 
-			require.Equal(t, uncoreFreqExp, uncoreFreqOut)
-			require.NoError(t, err)
-			mUncoreFreq.AssertExpectations(t)
-			mMsr.AssertExpectations(t)
-		})
+		pt := &PowerTelemetry{
+			uncoreFreq: mUncoreFreq,
+		}
 
-		t.Run("UncoreFreqIsNil", func(t *testing.T) {
-			packageID := 1
-			dieID := 0
-			msrValue := uint64(0xffffff08)
-			uncoreFreqExp := 800.0
+		freqOut, err := pt.GetCustomizedUncoreFrequencyMin(packageID, dieID)
+		require.ErrorIs(t, err, mError)
+		require.Equal(t, 0.0, freqOut)
+		mUncoreFreq.AssertExpectations(t)
+	})
 
-			mMsr := &msrMock{}
+	t.Run("CustomizedFailedToReadMsr", func(t *testing.T) {
+		m := &msrMock{}
+		m.On("read", uint32(uncoreRatioLimit), 0).Return(uint64(0), mError).Once()
 
-			// mock reading msr offset UNCORE_PERF_STATUS of CPU ID 1
-			mMsr.On("read", uint32(uncorePerfStatus), 1).Return(msrValue, nil).Once()
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+			cpus:     []int{0, 1},
+		}
 
-			pt := &PowerTelemetry{
-				topology: newTopology,
-				msr:      mMsr,
+		freqOut, err := pt.GetCustomizedUncoreFrequencyMin(0, 0)
+		require.ErrorIs(t, err, mError)
+		require.Equal(t, 0.0, freqOut)
+		m.AssertExpectations(t)
+	})
 
-				cpus: []int{0, 1, 2},
-			}
+	t.Run("CustomizedCPUIDNotAvailable", func(t *testing.T) {
+		m := &msrMock{}
 
-			uncoreFreqOut, err := pt.GetCurrentUncoreFrequency(packageID, dieID)
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+			cpus:     []int{0},
+		}
 
-			require.Equal(t, uncoreFreqExp, uncoreFreqOut)
-			require.NoError(t, err)
-			mMsr.AssertExpectations(t)
-		})
+		freqOut, err := pt.GetCustomizedUncoreFrequencyMax(1, 0)
+		require.ErrorContains(t, err, "unable to get CPU ID for package ID: 1")
+		require.Equal(t, 0.0, freqOut)
+		m.AssertExpectations(t)
 	})
 
-	t.Run("FromMsrFailed", func(t *testing.T) {
-		t.Run("MsrIsNil", func(t *testing.T) {
-			packageID := 0
-			dieID := 1
-			uncoreFreqExp := 0.0
-
-			pt := &PowerTelemetry{}
+	t.Run("Customized", func(t *testing.T) {
+		m := &msrMock{}
+		m.On("read", uint32(uncoreRatioLimit), 0).Return(uint64(0xFFFF0C14), nil).Twice()
 
-			uncoreFreqOut, err := pt.GetCurrentUncoreFrequency(packageID, dieID)
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+			cpus:     []int{0, 1},
+		}
 
-			require.ErrorContains(t, err, "\"msr\" is not initialized")
-			require.Equal(t, uncoreFreqExp, uncoreFreqOut)
-		})
+		minOut, err := pt.GetCustomizedUncoreFrequencyMin(0, 0)
+		require.NoError(t, err)
+		require.Equal(t, 1200.0, minOut)
 
-		t.Run("PackageIDNotFound", func(t *testing.T) {
-			packageID := 2
-			dieID := 1
-			uncoreFreqExp := 0.0
+		maxOut, err := pt.GetCustomizedUncoreFrequencyMax(0, 0)
+		require.NoError(t, err)
+		require.Equal(t, 2000.0, maxOut)
+		m.AssertExpectations(t)
+	})
 
-			mMsr := &msrMock{}
-			mUncoreFreq := &uncoreFreqMock{}
+	t.Run("InitialNotFound", func(t *testing.T) {
+		m := &msrMock{}
 
-			// mock getting current uncore frequency
-			mUncoreFreq.On("getUncoreFrequencyMhz", packageID, dieID, "current").Return(0.0, errors.New("failed to read current uncore frequency file")).Once()
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+			cpus:     []int{0, 1},
+		}
 
-			pt := &PowerTelemetry{
-				topology:   newTopology,
-				uncoreFreq: mUncoreFreq,
-				msr:        mMsr,
+		freqOut, err := pt.GetInitialUncoreFrequencyMin(0, 0)
+		require.ErrorContains(t, err, "initial uncore ratio limit not found for package ID: 0")
+		require.Equal(t, 0.0, freqOut)
+		m.AssertExpectations(t)
+	})
 
-				cpus: []int{0, 1, 2},
-			}
+	t.Run("Initial", func(t *testing.T) {
+		m := &msrMock{}
 
-			uncoreFreqOut, err := pt.GetCurrentUncoreFrequency(packageID, dieID)
-			require.Equal(t, uncoreFreqExp, uncoreFreqOut)
-			require.ErrorContains(t, err, "unable to get CPU ID for package ID: 2")
-			mUncoreFreq.AssertExpectations(t)
-			mMsr.AssertExpectations(t)
-		})
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+			cpus:     []int{0, 1},
+			uncoreRatioLimits: map[int]uint64{
+				0: 0x0819,
+				1: 0x0618,
+			},
+		}
 
-		t.Run("ReadMsrError", func(t *testing.T) {
-			packageID := 1
-			dieID := 0
-			uncoreFreqExp := 0.0
-			errMsg := "error reading msr file"
+		minOut, err := pt.GetInitialUncoreFrequencyMin(1, 0)
+		require.NoError(t, err)
+		require.Equal(t, 600.0, minOut)
 
-			mUncoreFreq := &uncoreFreqMock{}
+		maxOut, err := pt.GetInitialUncoreFrequencyMax(1, 0)
+		require.NoError(t, err)
+		require.Equal(t, 2400.0, maxOut)
+		m.AssertExpectations(t)
+	})
+}
 
-			// mock getting current uncore frequency
-			mUncoreFreq.On("getUncoreFrequencyMhz", packageID, dieID, "current").Return(0.0, errors.New("failed to read current uncore frequency file")).Once()
+func TestSetUncoreFrequencyLimits(t *testing.T) {
+	packageID := 0
+	cpuID := 0
 
-			mMsr := &msrMock{}
+	// topology definition
+	topo := &topologyData{
+		topologyMap: map[int]*cpuInfo{
+			0: {
+				packageID: 0,
+			},
+		},
+		model: cpumodel.INTEL_FAM6_ICELAKE_X,
+	}
 
-			// mock reading msr offset UNCORE_PERF_STATUS of CPU ID 1
-			mMsr.On("read", uint32(uncorePerfStatus), 1).Return(uint64(0), errors.New(errMsg)).Once()
+	// initial limits [800, 2400] MHz
+	initialLimits := map[int]uint64{packageID: 0x0818}
 
-			pt := &PowerTelemetry{
-				topology:   newTopology,
-				uncoreFreq: mUncoreFreq,
-				msr:        mMsr,
+	t.Run("UncoreFreqIsInitialized", func(t *testing.T) {
+		pt := &PowerTelemetry{
+			uncoreFreq: &uncoreFreqMock{},
+			msr:        &msrMock{},
+		}
 
-				cpus: []int{0, 1, 2},
-			}
+		err := pt.SetUncoreFrequencyLimits(packageID, 1000, 2000)
+		require.ErrorContains(t, err, "managed by intel_uncore_frequency driver")
+	})
 
-			uncoreFreqOut, err := pt.GetCurrentUncoreFrequency(packageID, dieID)
+	t.Run("MsrIsNil", func(t *testing.T) {
+		pt := &PowerTelemetry{}
 
-			require.Equal(t, uncoreFreqExp, uncoreFreqOut)
-			require.ErrorContains(t, err, errMsg)
-			mUncoreFreq.AssertExpectations(t)
-			mMsr.AssertExpectations(t)
-		})
+		err := pt.SetUncoreFrequencyLimits(packageID, 1000, 2000)
+		require.ErrorContains(t, err, "\"msr\" is not initialized")
 	})
-}
 
-func TestGetCPUFrequency(t *testing.T) {
-	pt := &PowerTelemetry{
-		cpuFreq: &cpuFreqData{
-			cpuFrequencyFilePath: "testdata/cpu-freq",
-		},
-	}
+	t.Run("MsrWriteIsNil", func(t *testing.T) {
+		pt := &PowerTelemetry{
+			msr: &msrMock{},
+		}
 
-	t.Run("CPUFreqIsNil", func(t *testing.T) {
-		expectedFreq := 0.0
+		err := pt.SetUncoreFrequencyLimits(packageID, 1000, 2000)
+		require.ErrorContains(t, err, "\"msr_write\" is not initialized")
+	})
 
-		ptel := &PowerTelemetry{}
+	t.Run("ModelNotSupported", func(t *testing.T) {
+		cpuModel := cpumodel.INTEL_FAM6_GRANITERAPIDS_X
 
-		actualFreq, err := ptel.GetCPUFrequency(0)
-		require.Equal(t, expectedFreq, actualFreq)
-		require.ErrorContains(t, err, "\"cpu_frequency\" is not initialized")
-	})
+		pt := &PowerTelemetry{
+			topology: &topologyData{
+				model: cpuModel,
+			},
+			msr:      &msrMock{},
+			msrWrite: &msrWriteGuard{},
+		}
 
-	t.Run("Valid", func(t *testing.T) {
-		expectedFreq := 888.888
-		actualFreq, err := pt.GetCPUFrequency(0)
-		require.Equal(t, expectedFreq, actualFreq)
-		require.NoError(t, err)
+		err := pt.SetUncoreFrequencyLimits(packageID, 1000, 2000)
+		require.ErrorContains(t, err, fmt.Sprintf("uncore ratio limit not supported by CPU model: 0x%X", cpuModel))
 	})
 
-	t.Run("Invalid", func(t *testing.T) {
-		expectedFreq := 0.0
-		expectedError := "error reading file"
-		actualFreq, err := pt.GetCPUFrequency(1)
-		require.Equal(t, expectedFreq, actualFreq)
-		require.ErrorContains(t, err, expectedError)
+	t.Run("MinGreaterThanMax", func(t *testing.T) {
+		pt := &PowerTelemetry{
+			topology:          topo,
+			msr:               &msrMock{},
+			msrWrite:          &msrWriteGuard{},
+			uncoreRatioLimits: initialLimits,
+		}
+
+		err := pt.SetUncoreFrequencyLimits(packageID, 2000, 1000)
+		require.ErrorContains(t, err, "invalid uncore frequency limits")
 	})
-}
 
-func TestGetBusClock(t *testing.T) {
-	type msrFreqTuple struct {
-		msrValue uint64
-		freq     float64
-	}
+	t.Run("InitialNotFound", func(t *testing.T) {
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      &msrMock{},
+			msrWrite: &msrWriteGuard{},
+		}
 
-	models100 := []int{
-		0x2A, // INTEL_FAM6_SANDYBRIDGE
-		0x2D, // INTEL_FAM6_SANDYBRIDGE_X
-		0x3A, // INTEL_FAM6_IVYBRIDGE
-		0x3E, // INTEL_FAM6_IVYBRIDGE_X
-		0x3C, // INTEL_FAM6_HASWELL
-		0x3F, // INTEL_FAM6_HASWELL_X
-		0x45, // INTEL_FAM6_HASWELL_L
-		0x46, // INTEL_FAM6_HASWELL_G
-		0x3D, // INTEL_FAM6_BROADWELL
-		0x47, // INTEL_FAM6_BROADWELL_G
-		0x4F, // INTEL_FAM6_BROADWELL_X
-		0x56, // INTEL_FAM6_BROADWELL_D
-		0x4E, // INTEL_FAM6_SKYLAKE_L
-		0x5E, // INTEL_FAM6_SKYLAKE
-		0x55, // INTEL_FAM6_SKYLAKE_X
-		0x8E, // INTEL_FAM6_KABYLAKE_L
-		0x9E, // INTEL_FAM6_KABYLAKE
-		0xA5, // INTEL_FAM6_COMETLAKE
-		0xA6, // INTEL_FAM6_COMETLAKE_L
-		0x66, // INTEL_FAM6_CANNONLAKE_L
-		0x6A, // INTEL_FAM6_ICELAKE_X
-		0x6C, // INTEL_FAM6_ICELAKE_D
-		0x7D, // INTEL_FAM6_ICELAKE
-		0x7E, // INTEL_FAM6_ICELAKE_L
-		0x9D, // INTEL_FAM6_ICELAKE_NNPI
-		0xA7, // INTEL_FAM6_ROCKETLAKE
-		0x8C, // INTEL_FAM6_TIGERLAKE_L
-		0x8D, // INTEL_FAM6_TIGERLAKE
-		0x8F, // INTEL_FAM6_SAPPHIRERAPIDS_X
-		0xCF, // INTEL_FAM6_EMERALDRAPIDS_X
-		0xAD, // INTEL_FAM6_GRANITERAPIDS_X
-		0xAE, // INTEL_FAM6_GRANITERAPIDS_D
-		0x8A, // INTEL_FAM6_LAKEFIELD
-		0x97, // INTEL_FAM6_ALDERLAKE
-		0x9A, // INTEL_FAM6_ALDERLAKE_L
-		0xB7, // INTEL_FAM6_RAPTORLAKE
-		0xBA, // INTEL_FAM6_RAPTORLAKE_P
-		0xBF, // INTEL_FAM6_RAPTORLAKE_S
-		0xAC, // INTEL_FAM6_METEORLAKE
-		0xAA, // INTEL_FAM6_METEORLAKE_L
-		0xC6, // INTEL_FAM6_ARROWLAKE
-		0xBD, // INTEL_FAM6_LUNARLAKE_M
-		0x5C, // INTEL_FAM6_ATOM_GOLDMONT
-		0x5F, // INTEL_FAM6_ATOM_GOLDMONT_D
-		0x7A, // INTEL_FAM6_ATOM_GOLDMONT_PLUS
-		0x86, // INTEL_FAM6_ATOM_TREMONT_D
-		0x96, // INTEL_FAM6_ATOM_TREMONT
-		0x9C, // INTEL_FAM6_ATOM_TREMONT_L
-		0xBE, // INTEL_FAM6_ATOM_GRACEMONT
-		0xAF, // INTEL_FAM6_ATOM_CRESTMONT_X
-		0xB6, // INTEL_FAM6_ATOM_CRESTMONT
-		0x57, // INTEL_FAM6_XEON_PHI_KNL
-		0x85, // INTEL_FAM6_XEON_PHI_KNM
-	}
+		err := pt.SetUncoreFrequencyLimits(packageID, 1000, 2000)
+		require.ErrorContains(t, err, "initial uncore ratio limit not found for package ID: 0")
+	})
 
-	models133 := []int{
-		0x1E, // INTEL_FAM6_NEHALEM
-		0x1F, // INTEL_FAM6_NEHALEM_G
-		0x1A, // INTEL_FAM6_NEHALEM_EP
-		0x2E, // INTEL_FAM6_NEHALEM_EX
-		0x25, // INTEL_FAM6_WESTMERE
-		0x2C, // INTEL_FAM6_WESTMERE_EP
-		0x2F, // INTEL_FAM6_WESTMERE_EX
-	}
+	t.Run("OutOfInitialRange", func(t *testing.T) {
+		pt := &PowerTelemetry{
+			topology:          topo,
+			msr:               &msrMock{},
+			msrWrite:          &msrWriteGuard{},
+			uncoreRatioLimits: initialLimits,
+		}
 
-	modelsSilvermont := []int{
-		0x37, // INTEL_FAM6_ATOM_SILVERMONT
-		0x4D, // INTEL_FAM6_ATOM_SILVERMONT_D
-		0x4A, // INTEL_FAM6_ATOM_SILVERMONT_MID
-		0x5A, // INTEL_FAM6_ATOM_SILVERMONT_SMARTPHONE
-	}
+		err := pt.SetUncoreFrequencyLimits(packageID, 700, 2000)
+		require.ErrorContains(t, err, "uncore frequency limits out of initial range [800, 2400] MHz")
 
-	modelsAirmont := []int{
-		0x4C, // INTEL_FAM6_ATOM_AIRMONT
-	}
+		err = pt.SetUncoreFrequencyLimits(packageID, 1000, 2500)
+		require.ErrorContains(t, err, "uncore frequency limits out of initial range [800, 2400] MHz")
+	})
 
-	supportedModels := make([]int, 0)
-	supportedModels = append(supportedModels, models100...)
-	supportedModels = append(supportedModels, models133...)
-	supportedModels = append(supportedModels, modelsSilvermont...)
-	supportedModels = append(supportedModels, modelsAirmont...)
+	t.Run("NotMultipleOf100Mhz", func(t *testing.T) {
+		pt := &PowerTelemetry{
+			topology:          topo,
+			msr:               &msrMock{},
+			msrWrite:          &msrWriteGuard{},
+			uncoreRatioLimits: initialLimits,
+		}
 
-	supportedModelMap := map[int]interface{}{}
-	for _, m := range supportedModels {
-		supportedModelMap[m] = struct{}{}
-	}
+		err := pt.SetUncoreFrequencyLimits(packageID, 1050, 2000)
+		require.ErrorContains(t, err, "uncore frequency limits must be multiples of 100 MHz, min: 1050 MHz, max: 2000 MHz")
 
-	t.Run("BusClockSilvermont", func(t *testing.T) {
-		t.Run("NoCPUsAvailable", func(t *testing.T) {
-			busClockExp := 0.0
+		err = pt.SetUncoreFrequencyLimits(packageID, 1000, 1999.5)
+		require.ErrorContains(t, err, "uncore frequency limits must be multiples of 100 MHz, min: 1000 MHz, max: 1999.5 MHz")
+	})
 
-			for _, model := range modelsSilvermont {
-				pt := &PowerTelemetry{
-					cpus: []int{}, // no CPU IDs available
-				}
+	t.Run("WriteNotAllowed", func(t *testing.T) {
+		pt := &PowerTelemetry{
+			topology:          topo,
+			msr:               &msrMock{},
+			msrWrite:          newTestMsrWriteGuard(t, map[uint32]uint64{uncoreRatioLimit: uncoreRatioLimitMask}, "off"),
+			cpus:              []int{cpuID},
+			uncoreRatioLimits: initialLimits,
+		}
 
-				busClockOut, err := pt.getBusClock(model)
-				require.Equal(t, busClockExp, busClockOut)
-				require.ErrorContains(t, err, "no available CPUs were found")
-			}
-		})
+		err := pt.SetUncoreFrequencyLimits(packageID, 1000, 2000)
+		var notAllowedErr *MsrWriteNotAllowedError
+		require.ErrorAs(t, err, &notAllowedErr)
+	})
 
-		t.Run("MsrIsNil", func(t *testing.T) {
-			busClockExp := 0.0
+	t.Run("FailedToWrite", func(t *testing.T) {
+		mError := errors.New("mock error")
 
-			for _, model := range modelsSilvermont {
-				pt := &PowerTelemetry{
-					cpus: []int{0},
-				}
+		m := &msrMock{}
+		m.On("read", uint32(uncoreRatioLimit), cpuID).Return(uint64(0x0818), nil).Once()
+		m.On("write", uint32(uncoreRatioLimit), cpuID, uint64(0x0A14)).Return(mError).Once()
 
-				busClockOut, err := pt.getBusClock(model)
-				require.Equal(t, busClockExp, busClockOut)
-				require.ErrorContains(t, err, "\"msr\" is not initialized")
-			}
-		})
+		pt := &PowerTelemetry{
+			topology:          topo,
+			msr:               m,
+			msrWrite:          newTestMsrWriteGuard(t, map[uint32]uint64{uncoreRatioLimit: uncoreRatioLimitMask}, "on"),
+			cpus:              []int{cpuID},
+			uncoreRatioLimits: initialLimits,
+		}
 
-		t.Run("FailedToReadMsr", func(t *testing.T) {
-			cpuID := 0
-			busClockExp := 0.0
-			mError := errors.New("mock error")
+		err := pt.SetUncoreFrequencyLimits(packageID, 1000, 2000)
+		require.ErrorIs(t, err, mError)
+		m.AssertExpectations(t)
+	})
 
-			for _, model := range modelsSilvermont {
-				mMsr := msrMock{}
-				mMsr.On("read", uint32(fsbFreq), cpuID).Return(uint64(0), mError).Once()
+	t.Run("Valid", func(t *testing.T) {
+		m := &msrMock{}
+		m.On("read", uint32(uncoreRatioLimit), cpuID).Return(uint64(0xFF008818), nil).Once()
+		m.On("write", uint32(uncoreRatioLimit), cpuID, uint64(0xFF008A14)).Return(nil).Once()
+		m.On("read", uint32(uncoreRatioLimit), cpuID).Return(uint64(0xFF008A14), nil).Once()
 
-				pt := &PowerTelemetry{
-					msr:  &mMsr,
-					cpus: []int{cpuID},
-				}
+		guard := newTestMsrWriteGuard(t, map[uint32]uint64{uncoreRatioLimit: uncoreRatioLimitMask}, "on")
+		pt := &PowerTelemetry{
+			topology:          topo,
+			msr:               m,
+			msrWrite:          guard,
+			cpus:              []int{cpuID},
+			uncoreRatioLimits: initialLimits,
+		}
 
-				busClockOut, err := pt.getBusClock(model)
-				require.Equal(t, busClockExp, busClockOut)
-				require.ErrorContains(t, err, mError.Error())
-				mMsr.AssertExpectations(t)
-			}
-		})
+		err := pt.SetUncoreFrequencyLimits(packageID, 1000, 2000)
+		require.NoError(t, err)
+		m.AssertExpectations(t)
 
-		t.Run("InvalidFrequencyIndex", func(t *testing.T) {
-			cpuID := 0
-			busClockExp := 0.0
+		// the original limits are recorded in the journal, so that they are restored.
+		entries, err := guard.journal.entries()
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, uint64(0xFF008818), entries[0].Original)
+		require.Equal(t, uint64(uncoreRatioLimitMask), entries[0].Mask)
+	})
+}
 
-			for _, model := range modelsSilvermont {
-				mMsr := msrMock{}
-				mMsrValue := uint64(0xF5)
-				mMsr.On("read", uint32(fsbFreq), cpuID).Return(mMsrValue, nil).Once()
+func TestReadUncoreRatioLimits(t *testing.T) {
+	setFakeClock()
+	defer unsetFakeClock()
 
-				pt := &PowerTelemetry{
-					msr:  &mMsr,
-					cpus: []int{cpuID},
-				}
+	packageID := 0
+	cpuID := 0
 
-				busClockOut, err := pt.getBusClock(model)
-				require.Equal(t, busClockExp, busClockOut)
-				require.ErrorContains(t, err, fmt.Sprintf("error while getting bus clock: index %d is outside of bounds", 5))
-				mMsr.AssertExpectations(t)
-			}
-		})
-
-		t.Run("Ok", func(t *testing.T) {
-			cpuID := 0
-			silvermontTuples := []msrFreqTuple{
-				{0x00, 83.3},
-				{0x01, 100.0},
-				{0x02, 133.3},
-				{0x03, 116.7},
-				{0x04, 80.0},
-			}
-			for _, model := range modelsSilvermont {
-				for _, tuple := range silvermontTuples {
-					mMsr := msrMock{}
-					mMsrValue := tuple.msrValue
-					mMsr.On("read", uint32(fsbFreq), cpuID).Return(mMsrValue, nil).Once()
+	// topology definition
+	topo := &topologyData{
+		topologyMap: map[int]*cpuInfo{
+			0: {
+				packageID: 0,
+			},
+		},
+		packageIDs: []int{0},
+		model:      cpumodel.INTEL_FAM6_ICELAKE_X,
+	}
 
-					pt := &PowerTelemetry{
-						msr:  &mMsr,
-						cpus: []int{cpuID},
-					}
+	t.Run("ModelNotSupported", func(t *testing.T) {
+		pt := &PowerTelemetry{
+			topology: &topologyData{
+				model: cpumodel.INTEL_FAM6_GRANITERAPIDS_X,
+			},
+		}
 
-					busClockExp := tuple.freq
-					busClockOut, err := pt.getBusClock(model)
-					require.Equal(t, busClockExp, busClockOut)
-					require.NoError(t, err)
-					mMsr.AssertExpectations(t)
-				}
-			}
-		})
+		require.Nil(t, pt.readUncoreRatioLimits())
 	})
 
-	t.Run("BusClockAirmont", func(t *testing.T) {
-		t.Run("NoCPUsAvailable", func(t *testing.T) {
-			busClockExp := 0.0
+	t.Run("FailedToRead", func(t *testing.T) {
+		m := &msrMock{}
+		m.On("read", uint32(uncoreRatioLimit), cpuID).Return(uint64(0), errors.New("mock error")).Once()
 
-			for _, model := range modelsAirmont {
-				pt := &PowerTelemetry{
-					cpus: []int{}, // no CPU IDs available
-				}
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+			cpus:     []int{cpuID},
+		}
 
-				busClockOut, err := pt.getBusClock(model)
-				require.Equal(t, busClockExp, busClockOut)
-				require.ErrorContains(t, err, "no available CPUs were found")
-			}
-		})
+		require.Empty(t, pt.readUncoreRatioLimits())
+		m.AssertExpectations(t)
+	})
 
-		t.Run("MsrIsNil", func(t *testing.T) {
-			busClockExp := 0.0
+	t.Run("Snapshot", func(t *testing.T) {
+		m := &msrMock{}
+		m.On("read", uint32(uncoreRatioLimit), cpuID).Return(uint64(0xFF008A14), nil).Once()
 
-			for _, model := range modelsAirmont {
-				pt := &PowerTelemetry{
-					cpus: []int{0},
-				}
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+			msrWrite: newTestMsrWriteGuard(t, map[uint32]uint64{uncoreRatioLimit: uncoreRatioLimitMask}, "on"),
+			cpus:     []int{cpuID},
+		}
 
-				busClockOut, err := pt.getBusClock(model)
-				require.Equal(t, busClockExp, busClockOut)
-				require.ErrorContains(t, err, "\"msr\" is not initialized")
-			}
-		})
+		require.Equal(t, map[int]uint64{packageID: 0xFF008A14}, pt.readUncoreRatioLimits())
+		m.AssertExpectations(t)
+	})
 
-		t.Run("FailedToReadMsr", func(t *testing.T) {
-			cpuID := 0
-			busClockExp := 0.0
-			mError := errors.New("mock error")
+	t.Run("OriginalFromJournal", func(t *testing.T) {
+		guard := newTestMsrWriteGuard(t, map[uint32]uint64{uncoreRatioLimit: uncoreRatioLimitMask}, "on")
+
+		// entries left by a previous session which narrowed the limits twice without restoring them
+		require.NoError(t, guard.journal.record(msrJournalEntry{
+			CPUID:    cpuID,
+			Offset:   uncoreRatioLimit,
+			Mask:     uncoreRatioLimitMask,
+			Original: 0xFF000818,
+			Value:    0xFF000A14,
+		}))
+		require.NoError(t, guard.journal.record(msrJournalEntry{
+			CPUID:    cpuID,
+			Offset:   uncoreRatioLimit,
+			Mask:     uncoreRatioLimitMask,
+			Original: 0xFF000A14,
+			Value:    0xFF000C12,
+		}))
 
-			for _, model := range modelsAirmont {
-				mMsr := msrMock{}
-				mMsr.On("read", uint32(fsbFreq), cpuID).Return(uint64(0), mError).Once()
+		m := &msrMock{}
+		m.On("read", uint32(uncoreRatioLimit), cpuID).Return(uint64(0xFF008C12), nil).Once()
 
-				pt := &PowerTelemetry{
-					msr:  &mMsr,
-					cpus: []int{cpuID},
-				}
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+			msrWrite: guard,
+			cpus:     []int{cpuID},
+		}
 
-				busClockOut, err := pt.getBusClock(model)
-				require.Equal(t, busClockExp, busClockOut)
-				require.ErrorContains(t, err, mError.Error())
-				mMsr.AssertExpectations(t)
-			}
-		})
+		require.Equal(t, map[int]uint64{packageID: 0xFF008818}, pt.readUncoreRatioLimits())
+		m.AssertExpectations(t)
+	})
+}
 
-		t.Run("InvalidFrequencyIndex", func(t *testing.T) {
-			cpuID := 0
-			busClockExp := 0.0
+func TestGetCurrentUncoreFrequency(t *testing.T) {
+	newTopology := &topologyData{
+		topologyMap: map[int]*cpuInfo{
+			0: {
+				vendorID:  "IdOfVendor",
+				family:    "13",
+				packageID: 0,
+			},
+			1: {
+				vendorID:  "IdOfVendor",
+				family:    "13",
+				packageID: 1,
+			},
+			2: {
+				vendorID:  "IdOfVendor",
+				family:    "13",
+				packageID: 0,
+			},
+		},
+	}
 
-			for _, model := range modelsAirmont {
-				mMsr := msrMock{}
-				mMsrValue := uint64(0xF9)
-				mMsr.On("read", uint32(fsbFreq), cpuID).Return(mMsrValue, nil).Once()
+	t.Run("FromFileSystem", func(t *testing.T) {
+		packageID := 0
+		dieID := 1
+		uncoreFreqExp := 2000.0
 
-				pt := &PowerTelemetry{
-					msr:  &mMsr,
-					cpus: []int{cpuID},
-				}
+		m := &uncoreFreqMock{}
 
-				busClockOut, err := pt.getBusClock(model)
-				require.Equal(t, busClockExp, busClockOut)
-				require.ErrorContains(t, err, fmt.Sprintf("error while getting bus clock: index %d is outside of bounds", 9))
-				mMsr.AssertExpectations(t)
-			}
-		})
+		// mock getting the current uncore frequency
+		m.On("getUncoreFrequencyMhz", packageID, dieID, "current").Return(uncoreFreqExp, nil).Once()
 
-		t.Run("Ok", func(t *testing.T) {
-			cpuID := 0
-			airmontTuples := []msrFreqTuple{
-				{0x00, 83.3},
-				{0x01, 100.0},
-				{0x02, 133.3},
-				{0x03, 116.7},
-				{0x04, 80.0},
-				{0x05, 93.3},
-				{0x06, 90.0},
-				{0x07, 88.9},
-				{0x08, 87.5},
-			}
-			for _, model := range modelsAirmont {
-				for _, tuple := range airmontTuples {
-					mMsr := msrMock{}
-					mMsrValue := tuple.msrValue
-					mMsr.On("read", uint32(fsbFreq), cpuID).Return(mMsrValue, nil).Once()
+		pt := &PowerTelemetry{
+			uncoreFreq: m,
+		}
 
-					pt := &PowerTelemetry{
-						msr:  &mMsr,
-						cpus: []int{cpuID},
-					}
+		uncoreFreqOut, err := pt.GetCurrentUncoreFrequency(packageID, dieID)
 
-					busClockExp := tuple.freq
-					busClockOut, err := pt.getBusClock(model)
-					require.Equal(t, busClockExp, busClockOut)
-					require.NoError(t, err)
-					mMsr.AssertExpectations(t)
-				}
-			}
-		})
+		require.NoError(t, err)
+		require.Equal(t, uncoreFreqExp, uncoreFreqOut)
+		m.AssertExpectations(t)
 	})
 
-	t.Run("BusClock100.0", func(t *testing.T) {
-		busClockExp := 100.0
-		pt := &PowerTelemetry{}
+	t.Run("FromMsr", func(t *testing.T) {
+		t.Run("UncoreFreqFailed", func(t *testing.T) {
+			packageID := 1
+			dieID := 0
+			msrValue := uint64(0xffffff08)
+			uncoreFreqExp := 800.0
 
-		for _, model := range models100 {
-			busClockOut, err := pt.getBusClock(model)
-			require.NoError(t, err)
-			require.Equalf(t, busClockExp, busClockOut, "Model 0x%X", model)
-		}
-	})
+			mUncoreFreq := &uncoreFreqMock{}
 
-	t.Run("BusClock133.0", func(t *testing.T) {
-		busClockExp := 133.0
-		pt := &PowerTelemetry{}
+			// mock getting current uncore frequency
+			mUncoreFreq.On("getUncoreFrequencyMhz", packageID, dieID, "current").Return(0.0, errors.New("failed to read current uncore frequency file")).Once()
 
-		for _, model := range models133 {
-			busClockOut, err := pt.getBusClock(model)
-			require.NoError(t, err)
-			require.Equalf(t, busClockExp, busClockOut, "Model 0x%X", model)
-		}
-	})
+			mMsr := &msrMock{}
 
-	t.Run("UnsupportedModels", func(t *testing.T) {
-		busClockExp := 0.0
-		pt := &PowerTelemetry{}
+			// mock reading msr offset UNCORE_PERF_STATUS of CPU ID 1
+			mMsr.On("read", uint32(uncorePerfStatus), 1).Return(msrValue, nil).Once()
 
-		for model := 0; model < 0xFF; model++ {
-			if supportedModelMap[model] == nil {
-				busClockOut, err := pt.getBusClock(model)
-				require.Equalf(t, busClockExp, busClockOut, "Model 0x%X", model)
-				require.ErrorContains(t, err, fmt.Sprintf("busClock is not supported by the CPU model: %v", model))
+			pt := &PowerTelemetry{
+				topology:   newTopology,
+				uncoreFreq: mUncoreFreq,
+				msr:        mMsr,
+
+				cpus: []int{0, 1, 2},
 			}
-		}
-	})
-}
 
-func TestGetCPUTemperature(t *testing.T) {
-	t.Run("MsrIsNil", func(t *testing.T) {
-		cpuID := 0
+			uncoreFreqOut, err := pt.GetCurrentUncoreFrequency(packageID, dieID)
 
-		tempExp := uint64(0)
+			require.Equal(t, uncoreFreqExp, uncoreFreqOut)
+			require.NoError(t, err)
+			mUncoreFreq.AssertExpectations(t)
+			mMsr.AssertExpectations(t)
+		})
 
-		pt := &PowerTelemetry{}
+		t.Run("UncoreFreqIsNil", func(t *testing.T) {
+			packageID := 1
+			dieID := 0
+			msrValue := uint64(0xffffff08)
+			uncoreFreqExp := 800.0
 
-		tempOut, err := pt.GetCPUTemperature(cpuID)
-		require.Equal(t, tempExp, tempOut)
-		require.ErrorContains(t, err, "\"msr\" is not initialized")
-	})
+			mMsr := &msrMock{}
 
-	t.Run("FailedToReadTemperatureTarget", func(t *testing.T) {
-		cpuID := 0
-		mError := errors.New("mock error")
+			// mock reading msr offset UNCORE_PERF_STATUS of CPU ID 1
+			mMsr.On("read", uint32(uncorePerfStatus), 1).Return(msrValue, nil).Once()
 
-		tempExp := uint64(0)
+			pt := &PowerTelemetry{
+				topology: newTopology,
+				msr:      mMsr,
 
-		m := &msrMock{}
-		m.On("read", uint32(temperatureTarget), cpuID).Return(uint64(0), mError).Once()
+				cpus: []int{0, 1, 2},
+			}
 
-		pt := &PowerTelemetry{
-			topology: &topologyData{
-				model: cpumodel.INTEL_FAM6_SAPPHIRERAPIDS_X,
-			},
-			msr: m,
-		}
+			uncoreFreqOut, err := pt.GetCurrentUncoreFrequency(packageID, dieID)
 
-		temOut, err := pt.GetCPUTemperature(cpuID)
-		require.Equal(t, tempExp, temOut)
-		require.ErrorContains(t, err, mError.Error())
-		m.AssertExpectations(t)
+			require.Equal(t, uncoreFreqExp, uncoreFreqOut)
+			require.NoError(t, err)
+			mMsr.AssertExpectations(t)
+		})
 	})
 
-	t.Run("ModelNotSupported", func(t *testing.T) {
-		cpuID := 0
-		// CPU temp metric not supported by this model.
-		cpuModel := cpumodel.INTEL_FAM6_CORE_YONAH
-
-		tempExp := uint64(0)
+	t.Run("FromMsrFailed", func(t *testing.T) {
+		t.Run("MsrIsNil", func(t *testing.T) {
+			packageID := 0
+			dieID := 1
+			uncoreFreqExp := 0.0
 
-		m := &msrMock{}
+			pt := &PowerTelemetry{}
 
-		pt := &PowerTelemetry{
-			msr: m,
-			topology: &topologyData{
-				model: cpuModel,
-			},
-		}
+			uncoreFreqOut, err := pt.GetCurrentUncoreFrequency(packageID, dieID)
 
-		tempOut, err := pt.GetCPUTemperature(cpuID)
-		require.Equal(t, tempExp, tempOut)
-		require.ErrorContains(t, err, fmt.Sprintf("cpu temperature metric not supported by CPU model: 0x%X", cpuModel))
-		m.AssertExpectations(t)
-	})
+			require.ErrorContains(t, err, "\"msr\" is not initialized")
+			require.Equal(t, uncoreFreqExp, uncoreFreqOut)
+		})
 
-	t.Run("FailedToReadThermalStatus", func(t *testing.T) {
-		cpuID := 0
-		tempTargetValue := uint64(0x680a00)
-		mError := errors.New("mock error")
+		t.Run("PackageIDNotFound", func(t *testing.T) {
+			packageID := 2
+			dieID := 1
+			uncoreFreqExp := 0.0
 
-		tempExp := uint64(0)
+			mMsr := &msrMock{}
+			mUncoreFreq := &uncoreFreqMock{}
 
-		m := &msrMock{}
-		m.On("read", uint32(temperatureTarget), cpuID).Return(tempTargetValue, nil).Once()
-		m.On("read", uint32(thermalStatus), cpuID).Return(uint64(0), mError).Once()
+			// mock getting current uncore frequency
+			mUncoreFreq.On("getUncoreFrequencyMhz", packageID, dieID, "current").Return(0.0, errors.New("failed to read current uncore frequency file")).Once()
 
-		pt := &PowerTelemetry{
-			topology: &topologyData{
-				model: cpumodel.INTEL_FAM6_SAPPHIRERAPIDS_X,
-			},
-			msr: m,
-		}
+			pt := &PowerTelemetry{
+				topology:   newTopology,
+				uncoreFreq: mUncoreFreq,
+				msr:        mMsr,
 
-		temOut, err := pt.GetCPUTemperature(cpuID)
-		require.Equal(t, tempExp, temOut)
-		require.ErrorContains(t, err, mError.Error())
-		m.AssertExpectations(t)
-	})
+				cpus: []int{0, 1, 2},
+			}
 
-	t.Run("Temp23Celsius", func(t *testing.T) {
-		cpuID := 0
-		tempTargetValue := uint64(0x680a00)
-		thermalStatusValue := uint64(0x88510000)
+			uncoreFreqOut, err := pt.GetCurrentUncoreFrequency(packageID, dieID)
+			require.Equal(t, uncoreFreqExp, uncoreFreqOut)
+			require.ErrorContains(t, err, "unable to get CPU ID for package ID: 2")
+			mUncoreFreq.AssertExpectations(t)
+			mMsr.AssertExpectations(t)
+		})
 
-		tempExp := uint64(23)
+		t.Run("ReadMsrError", func(t *testing.T) {
+			packageID := 1
+			dieID := 0
+			uncoreFreqExp := 0.0
+			errMsg := "error reading msr file"
 
-		m := &msrMock{}
-		m.On("read", uint32(temperatureTarget), cpuID).Return(tempTargetValue, nil).Once()
-		m.On("read", uint32(thermalStatus), cpuID).Return(thermalStatusValue, nil).Once()
+			mUncoreFreq := &uncoreFreqMock{}
 
-		pt := &PowerTelemetry{
-			topology: &topologyData{
-				model: cpumodel.INTEL_FAM6_EMERALDRAPIDS_X,
-			},
-			msr: m,
-		}
+			// mock getting current uncore frequency
+			mUncoreFreq.On("getUncoreFrequencyMhz", packageID, dieID, "current").Return(0.0, errors.New("failed to read current uncore frequency file")).Once()
 
-		temOut, err := pt.GetCPUTemperature(cpuID)
-		require.Equal(t, tempExp, temOut)
-		require.NoError(t, err)
-		m.AssertExpectations(t)
-	})
+			mMsr := &msrMock{}
 
-	t.Run("Temp36Celsius", func(t *testing.T) {
-		cpuID := 0
-		tempTargetValue := uint64(0x630a00)
-		thermalStatusValue := uint64(0x883f0800)
+			// mock reading msr offset UNCORE_PERF_STATUS of CPU ID 1
+			mMsr.On("read", uint32(uncorePerfStatus), 1).Return(uint64(0), errors.New(errMsg)).Once()
 
-		tempExp := uint64(36)
+			pt := &PowerTelemetry{
+				topology:   newTopology,
+				uncoreFreq: mUncoreFreq,
+				msr:        mMsr,
 
-		m := &msrMock{}
-		m.On("read", uint32(temperatureTarget), cpuID).Return(tempTargetValue, nil).Once()
-		m.On("read", uint32(thermalStatus), cpuID).Return(thermalStatusValue, nil).Once()
+				cpus: []int{0, 1, 2},
+			}
 
-		pt := &PowerTelemetry{
-			topology: &topologyData{
-				model: cpumodel.INTEL_FAM6_EMERALDRAPIDS_X,
-			},
-			msr: m,
-		}
+			uncoreFreqOut, err := pt.GetCurrentUncoreFrequency(packageID, dieID)
 
-		temOut, err := pt.GetCPUTemperature(cpuID)
-		require.Equal(t, tempExp, temOut)
-		require.NoError(t, err)
-		m.AssertExpectations(t)
+			require.Equal(t, uncoreFreqExp, uncoreFreqOut)
+			require.ErrorContains(t, err, errMsg)
+			mUncoreFreq.AssertExpectations(t)
+			mMsr.AssertExpectations(t)
+		})
 	})
 }
 
-func TestGetCPUBaseFrequency(t *testing.T) {
-	t.Run("MsrIsNil", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
+func TestGetCPUFrequency(t *testing.T) {
+	pt := &PowerTelemetry{
+		cpuFreq: &cpuFreqData{
+			cpuFrequencyFilePath: "testdata/cpu-freq",
+		},
+	}
 
-		// expected output
-		expectedFreq := uint64(0)
+	t.Run("CPUFreqIsNil", func(t *testing.T) {
+		expectedFreq := 0.0
 
-		// power telemetry instance definition
-		pt := PowerTelemetry{
-			topology: &topologyData{},
-		}
+		ptel := &PowerTelemetry{}
 
-		actualFreq, err := pt.GetCPUBaseFrequency(cpuID)
-		require.ErrorContains(t, err, "\"msr\" is not initialized")
+		actualFreq, err := ptel.GetCPUFrequency(0)
 		require.Equal(t, expectedFreq, actualFreq)
+		require.ErrorContains(t, err, "\"cpu_frequency\" is not initialized")
 	})
 
-	t.Run("NoCPUIDsAvailable", func(t *testing.T) {
-		// input arguments for test case
-		packageID := 0
-		busClk := 100.0
-
-		// expected output
-		expectedFreq := uint64(0)
-
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {
-					packageID: 0,
-				},
-				1: {
-					packageID: 0,
-				},
-				2: {
-					packageID: 0,
-				},
-				3: {
-					packageID: 0,
-				},
-				4: {
-					packageID: 0,
-				},
-			},
-			model: cpumodel.INTEL_FAM6_EMERALDRAPIDS_X,
-		}
-
-		// msr definition
-		m := &msrMock{}
-
-		// power telemetry instance definition
-		pt := PowerTelemetry{
-			topology: topo,
-			msr:      m,
-			cpus:     []int{}, // no cpus available
-			busClock: busClk,
-		}
-
-		actualFreq, err := pt.GetCPUBaseFrequency(packageID)
-		require.ErrorContains(t, err, fmt.Sprintf("could not find CPU ID for package ID %v", packageID))
+	t.Run("Valid", func(t *testing.T) {
+		expectedFreq := 888.888
+		actualFreq, err := pt.GetCPUFrequency(0)
 		require.Equal(t, expectedFreq, actualFreq)
-		m.AssertExpectations(t)
+		require.NoError(t, err)
 	})
 
-	t.Run("CPUIDNotAvailable", func(t *testing.T) {
-		// input arguments for test case
-		packageID := 1
-		busClk := 100.0
+	t.Run("Invalid", func(t *testing.T) {
+		expectedFreq := 0.0
+		expectedError := "error reading file"
+		actualFreq, err := pt.GetCPUFrequency(1)
+		require.Equal(t, expectedFreq, actualFreq)
+		require.ErrorContains(t, err, expectedError)
+	})
+}
 
-		// expected output
-		expectedFreq := uint64(0)
+func TestGetBusClock(t *testing.T) {
+	type msrFreqTuple struct {
+		msrValue uint64
+		freq     float64
+	}
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {
-					packageID: 0,
-				},
-				1: {
-					packageID: 1,
-				},
-				2: {
-					packageID: 0,
-				},
-				3: {
+	models100 := []int{
+		0x2A, // INTEL_FAM6_SANDYBRIDGE
+		0x2D, // INTEL_FAM6_SANDYBRIDGE_X
+		0x3A, // INTEL_FAM6_IVYBRIDGE
+		0x3E, // INTEL_FAM6_IVYBRIDGE_X
+		0x3C, // INTEL_FAM6_HASWELL
+		0x3F, // INTEL_FAM6_HASWELL_X
+		0x45, // INTEL_FAM6_HASWELL_L
+		0x46, // INTEL_FAM6_HASWELL_G
+		0x3D, // INTEL_FAM6_BROADWELL
+		0x47, // INTEL_FAM6_BROADWELL_G
+		0x4F, // INTEL_FAM6_BROADWELL_X
+		0x56, // INTEL_FAM6_BROADWELL_D
+		0x4E, // INTEL_FAM6_SKYLAKE_L
+		0x5E, // INTEL_FAM6_SKYLAKE
+		0x55, // INTEL_FAM6_SKYLAKE_X
+		0x8E, // INTEL_FAM6_KABYLAKE_L
+		0x9E, // INTEL_FAM6_KABYLAKE
+		0xA5, // INTEL_FAM6_COMETLAKE
+		0xA6, // INTEL_FAM6_COMETLAKE_L
+		0x66, // INTEL_FAM6_CANNONLAKE_L
+		0x6A, // INTEL_FAM6_ICELAKE_X
+		0x6C, // INTEL_FAM6_ICELAKE_D
+		0x7D, // INTEL_FAM6_ICELAKE
+		0x7E, // INTEL_FAM6_ICELAKE_L
+		0x9D, // INTEL_FAM6_ICELAKE_NNPI
+		0xA7, // INTEL_FAM6_ROCKETLAKE
+		0x8C, // INTEL_FAM6_TIGERLAKE_L
+		0x8D, // INTEL_FAM6_TIGERLAKE
+		0x8F, // INTEL_FAM6_SAPPHIRERAPIDS_X
+		0xCF, // INTEL_FAM6_EMERALDRAPIDS_X
+		0xAD, // INTEL_FAM6_GRANITERAPIDS_X
+		0xAE, // INTEL_FAM6_GRANITERAPIDS_D
+		0x8A, // INTEL_FAM6_LAKEFIELD
+		0x97, // INTEL_FAM6_ALDERLAKE
+		0x9A, // INTEL_FAM6_ALDERLAKE_L
+		0xB7, // INTEL_FAM6_RAPTORLAKE
+		0xBA, // INTEL_FAM6_RAPTORLAKE_P
+		0xBF, // INTEL_FAM6_RAPTORLAKE_S
+		0xAC, // INTEL_FAM6_METEORLAKE
+		0xAA, // INTEL_FAM6_METEORLAKE_L
+		0xC6, // INTEL_FAM6_ARROWLAKE
+		0xBD, // INTEL_FAM6_LUNARLAKE_M
+		0x5C, // INTEL_FAM6_ATOM_GOLDMONT
+		0x5F, // INTEL_FAM6_ATOM_GOLDMONT_D
+		0x7A, // INTEL_FAM6_ATOM_GOLDMONT_PLUS
+		0x86, // INTEL_FAM6_ATOM_TREMONT_D
+		0x96, // INTEL_FAM6_ATOM_TREMONT
+		0x9C, // INTEL_FAM6_ATOM_TREMONT_L
+		0xBE, // INTEL_FAM6_ATOM_GRACEMONT
+		0xAF, // INTEL_FAM6_ATOM_CRESTMONT_X
+		0xB6, // INTEL_FAM6_ATOM_CRESTMONT
+		0x57, // INTEL_FAM6_XEON_PHI_KNL
+		0x85, // INTEL_FAM6_XEON_PHI_KNM
+	}
+
+	models133 := []int{
+		0x1E, // INTEL_FAM6_NEHALEM
+		0x1F, // INTEL_FAM6_NEHALEM_G
+		0x1A, // INTEL_FAM6_NEHALEM_EP
+		0x2E, // INTEL_FAM6_NEHALEM_EX
+		0x25, // INTEL_FAM6_WESTMERE
+		0x2C, // INTEL_FAM6_WESTMERE_EP
+		0x2F, // INTEL_FAM6_WESTMERE_EX
+	}
+
+	modelsSilvermont := []int{
+		0x37, // INTEL_FAM6_ATOM_SILVERMONT
+		0x4D, // INTEL_FAM6_ATOM_SILVERMONT_D
+		0x4A, // INTEL_FAM6_ATOM_SILVERMONT_MID
+		0x5A, // INTEL_FAM6_ATOM_SILVERMONT_SMARTPHONE
+	}
+
+	modelsAirmont := []int{
+		0x4C, // INTEL_FAM6_ATOM_AIRMONT
+	}
+
+	supportedModels := make([]int, 0)
+	supportedModels = append(supportedModels, models100...)
+	supportedModels = append(supportedModels, models133...)
+	supportedModels = append(supportedModels, modelsSilvermont...)
+	supportedModels = append(supportedModels, modelsAirmont...)
+
+	supportedModelMap := map[int]interface{}{}
+	for _, m := range supportedModels {
+		supportedModelMap[m] = struct{}{}
+	}
+
+	t.Run("BusClockSilvermont", func(t *testing.T) {
+		t.Run("NoCPUsAvailable", func(t *testing.T) {
+			busClockExp := 0.0
+
+			for _, model := range modelsSilvermont {
+				pt := &PowerTelemetry{
+					cpus: []int{}, // no CPU IDs available
+				}
+
+				busClockOut, err := pt.getBusClock(model)
+				require.Equal(t, busClockExp, busClockOut)
+				require.ErrorContains(t, err, "no available CPUs were found")
+			}
+		})
+
+		t.Run("MsrIsNil", func(t *testing.T) {
+			busClockExp := 0.0
+
+			for _, model := range modelsSilvermont {
+				pt := &PowerTelemetry{
+					cpus: []int{0},
+				}
+
+				busClockOut, err := pt.getBusClock(model)
+				require.Equal(t, busClockExp, busClockOut)
+				require.ErrorContains(t, err, "\"msr\" is not initialized")
+			}
+		})
+
+		t.Run("FailedToReadMsr", func(t *testing.T) {
+			cpuID := 0
+			busClockExp := 0.0
+			mError := errors.New("mock error")
+
+			for _, model := range modelsSilvermont {
+				mMsr := msrMock{}
+				mMsr.On("read", uint32(fsbFreq), cpuID).Return(uint64(0), mError).Once()
+
+				pt := &PowerTelemetry{
+					msr:  &mMsr,
+					cpus: []int{cpuID},
+				}
+
+				busClockOut, err := pt.getBusClock(model)
+				require.Equal(t, busClockExp, busClockOut)
+				require.ErrorContains(t, err, mError.Error())
+				mMsr.AssertExpectations(t)
+			}
+		})
+
+		t.Run("InvalidFrequencyIndex", func(t *testing.T) {
+			cpuID := 0
+			busClockExp := 0.0
+
+			for _, model := range modelsSilvermont {
+				mMsr := msrMock{}
+				mMsrValue := uint64(0xF5)
+				mMsr.On("read", uint32(fsbFreq), cpuID).Return(mMsrValue, nil).Once()
+
+				pt := &PowerTelemetry{
+					msr:  &mMsr,
+					cpus: []int{cpuID},
+				}
+
+				busClockOut, err := pt.getBusClock(model)
+				require.Equal(t, busClockExp, busClockOut)
+				require.ErrorContains(t, err, fmt.Sprintf("error while getting bus clock: index %d is outside of bounds", 5))
+				mMsr.AssertExpectations(t)
+			}
+		})
+
+		t.Run("Ok", func(t *testing.T) {
+			cpuID := 0
+			silvermontTuples := []msrFreqTuple{
+				{0x00, 83.3},
+				{0x01, 100.0},
+				{0x02, 133.3},
+				{0x03, 116.7},
+				{0x04, 80.0},
+			}
+			for _, model := range modelsSilvermont {
+				for _, tuple := range silvermontTuples {
+					mMsr := msrMock{}
+					mMsrValue := tuple.msrValue
+					mMsr.On("read", uint32(fsbFreq), cpuID).Return(mMsrValue, nil).Once()
+
+					pt := &PowerTelemetry{
+						msr:  &mMsr,
+						cpus: []int{cpuID},
+					}
+
+					busClockExp := tuple.freq
+					busClockOut, err := pt.getBusClock(model)
+					require.Equal(t, busClockExp, busClockOut)
+					require.NoError(t, err)
+					mMsr.AssertExpectations(t)
+				}
+			}
+		})
+	})
+
+	t.Run("BusClockAirmont", func(t *testing.T) {
+		t.Run("NoCPUsAvailable", func(t *testing.T) {
+			busClockExp := 0.0
+
+			for _, model := range modelsAirmont {
+				pt := &PowerTelemetry{
+					cpus: []int{}, // no CPU IDs available
+				}
+
+				busClockOut, err := pt.getBusClock(model)
+				require.Equal(t, busClockExp, busClockOut)
+				require.ErrorContains(t, err, "no available CPUs were found")
+			}
+		})
+
+		t.Run("MsrIsNil", func(t *testing.T) {
+			busClockExp := 0.0
+
+			for _, model := range modelsAirmont {
+				pt := &PowerTelemetry{
+					cpus: []int{0},
+				}
+
+				busClockOut, err := pt.getBusClock(model)
+				require.Equal(t, busClockExp, busClockOut)
+				require.ErrorContains(t, err, "\"msr\" is not initialized")
+			}
+		})
+
+		t.Run("FailedToReadMsr", func(t *testing.T) {
+			cpuID := 0
+			busClockExp := 0.0
+			mError := errors.New("mock error")
+
+			for _, model := range modelsAirmont {
+				mMsr := msrMock{}
+				mMsr.On("read", uint32(fsbFreq), cpuID).Return(uint64(0), mError).Once()
+
+				pt := &PowerTelemetry{
+					msr:  &mMsr,
+					cpus: []int{cpuID},
+				}
+
+				busClockOut, err := pt.getBusClock(model)
+				require.Equal(t, busClockExp, busClockOut)
+				require.ErrorContains(t, err, mError.Error())
+				mMsr.AssertExpectations(t)
+			}
+		})
+
+		t.Run("InvalidFrequencyIndex", func(t *testing.T) {
+			cpuID := 0
+			busClockExp := 0.0
+
+			for _, model := range modelsAirmont {
+				mMsr := msrMock{}
+				mMsrValue := uint64(0xF9)
+				mMsr.On("read", uint32(fsbFreq), cpuID).Return(mMsrValue, nil).Once()
+
+				pt := &PowerTelemetry{
+					msr:  &mMsr,
+					cpus: []int{cpuID},
+				}
+
+				busClockOut, err := pt.getBusClock(model)
+				require.Equal(t, busClockExp, busClockOut)
+				require.ErrorContains(t, err, fmt.Sprintf("error while getting bus clock: index %d is outside of bounds", 9))
+				mMsr.AssertExpectations(t)
+			}
+		})
+
+		t.Run("Ok", func(t *testing.T) {
+			cpuID := 0
+			airmontTuples := []msrFreqTuple{
+				{0x00, 83.3},
+				{0x01, 100.0},
+				{0x02, 133.3},
+				{0x03, 116.7},
+				{0x04, 80.0},
+				{0x05, 93.3},
+				{0x06, 90.0},
+				{0x07, 88.9},
+				{0x08, 87.5},
+			}
+			for _, model := range modelsAirmont {
+				for _, tuple := range airmontTuples {
+					mMsr := msrMock{}
+					mMsrValue := tuple.msrValue
+					mMsr.On("read", uint32(fsbFreq), cpuID).Return(mMsrValue, nil).Once()
+
+					pt := &PowerTelemetry{
+						msr:  &mMsr,
+						cpus: []int{cpuID},
+					}
+
+					busClockExp := tuple.freq
+					busClockOut, err := pt.getBusClock(model)
+					require.Equal(t, busClockExp, busClockOut)
+					require.NoError(t, err)
+					mMsr.AssertExpectations(t)
+				}
+			}
+		})
+	})
+
+	t.Run("BusClock100.0", func(t *testing.T) {
+		busClockExp := 100.0
+		pt := &PowerTelemetry{}
+
+		for _, model := range models100 {
+			busClockOut, err := pt.getBusClock(model)
+			require.NoError(t, err)
+			require.Equalf(t, busClockExp, busClockOut, "Model 0x%X", model)
+		}
+	})
+
+	t.Run("BusClock133.0", func(t *testing.T) {
+		busClockExp := 133.0
+		pt := &PowerTelemetry{}
+
+		for _, model := range models133 {
+			busClockOut, err := pt.getBusClock(model)
+			require.NoError(t, err)
+			require.Equalf(t, busClockExp, busClockOut, "Model 0x%X", model)
+		}
+	})
+
+	t.Run("UnsupportedModels", func(t *testing.T) {
+		busClockExp := 0.0
+		pt := &PowerTelemetry{}
+
+		for model := 0; model < 0xFF; model++ {
+			if supportedModelMap[model] == nil {
+				busClockOut, err := pt.getBusClock(model)
+				require.Equalf(t, busClockExp, busClockOut, "Model 0x%X", model)
+				require.ErrorContains(t, err, fmt.Sprintf("busClock is not supported by the CPU model: %v", model))
+			}
+		}
+	})
+}
+
+func TestGetCPUTemperature(t *testing.T) {
+	t.Run("MsrIsNil", func(t *testing.T) {
+		cpuID := 0
+
+		tempExp := uint64(0)
+
+		pt := &PowerTelemetry{}
+
+		tempOut, err := pt.GetCPUTemperature(cpuID)
+		require.Equal(t, tempExp, tempOut)
+		require.ErrorContains(t, err, "\"msr\" is not initialized")
+	})
+
+	t.Run("CoreTemp", func(t *testing.T) {
+		cpuID := 3
+		packageID := 1
+		coreID := 2
+
+		t.Run("FailedToGetPackageID", func(t *testing.T) {
+			mTopology := &topologyMock{}
+			mTopology.On("getCPUPackageID", cpuID).Return(0, errors.New("mock error")).Once()
+
+			pt := &PowerTelemetry{
+				topology: mTopology,
+				coreTemp: &coreTempMock{},
+			}
+
+			tempOut, err := pt.GetCPUTemperature(cpuID)
+			require.ErrorContains(t, err, fmt.Sprintf("error retrieving package ID for CPU ID %v", cpuID))
+			require.Zero(t, tempOut)
+			mTopology.AssertExpectations(t)
+		})
+
+		t.Run("FailedToGetCoreID", func(t *testing.T) {
+			mTopology := &topologyMock{}
+			mTopology.On("getCPUPackageID", cpuID).Return(packageID, nil).Once()
+			mTopology.On("getCPUCoreID", cpuID).Return(0, errors.New("mock error")).Once()
+
+			pt := &PowerTelemetry{
+				topology: mTopology,
+				coreTemp: &coreTempMock{},
+			}
+
+			tempOut, err := pt.GetCPUTemperature(cpuID)
+			require.ErrorContains(t, err, fmt.Sprintf("error retrieving core ID for CPU ID %v", cpuID))
+			require.Zero(t, tempOut)
+			mTopology.AssertExpectations(t)
+		})
+
+		t.Run("FailedToGetTemperature", func(t *testing.T) {
+			mError := errors.New("mock error")
+
+			mTopology := &topologyMock{}
+			mTopology.On("getCPUPackageID", cpuID).Return(packageID, nil).Once()
+			mTopology.On("getCPUCoreID", cpuID).Return(coreID, nil).Once()
+
+			mCoreTemp := &coreTempMock{}
+			mCoreTemp.On("getCoreTemperature", packageID, coreID, tempInput).Return(uint64(0), mError).Once()
+
+			pt := &PowerTelemetry{
+				topology: mTopology,
+				coreTemp: mCoreTemp,
+			}
+
+			tempOut, err := pt.GetCPUTemperature(cpuID)
+			require.ErrorIs(t, err, mError)
+			require.Zero(t, tempOut)
+			mTopology.AssertExpectations(t)
+			mCoreTemp.AssertExpectations(t)
+		})
+
+		t.Run("Valid", func(t *testing.T) {
+			mTopology := &topologyMock{}
+			mTopology.On("getCPUPackageID", cpuID).Return(packageID, nil).Once()
+			mTopology.On("getCPUCoreID", cpuID).Return(coreID, nil).Once()
+
+			mCoreTemp := &coreTempMock{}
+			mCoreTemp.On("getCoreTemperature", packageID, coreID, tempInput).Return(uint64(45), nil).Once()
+
+			pt := &PowerTelemetry{
+				topology: mTopology,
+				coreTemp: mCoreTemp,
+			}
+
+			tempOut, err := pt.GetCPUTemperature(cpuID)
+			require.NoError(t, err)
+			require.Equal(t, uint64(45), tempOut)
+			mTopology.AssertExpectations(t)
+			mCoreTemp.AssertExpectations(t)
+		})
+	})
+
+	t.Run("FailedToReadTemperatureTarget", func(t *testing.T) {
+		cpuID := 0
+		mError := errors.New("mock error")
+
+		tempExp := uint64(0)
+
+		m := &msrMock{}
+		m.On("read", uint32(temperatureTarget), cpuID).Return(uint64(0), mError).Once()
+
+		pt := &PowerTelemetry{
+			topology: &topologyData{
+				model: cpumodel.INTEL_FAM6_SAPPHIRERAPIDS_X,
+			},
+			msr: m,
+		}
+
+		temOut, err := pt.GetCPUTemperature(cpuID)
+		require.Equal(t, tempExp, temOut)
+		require.ErrorContains(t, err, mError.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("ModelNotSupported", func(t *testing.T) {
+		cpuID := 0
+		// CPU temp metric not supported by this model.
+		cpuModel := cpumodel.INTEL_FAM6_CORE_YONAH
+
+		tempExp := uint64(0)
+
+		m := &msrMock{}
+
+		pt := &PowerTelemetry{
+			msr: m,
+			topology: &topologyData{
+				model: cpuModel,
+			},
+		}
+
+		tempOut, err := pt.GetCPUTemperature(cpuID)
+		require.Equal(t, tempExp, tempOut)
+		require.ErrorContains(t, err, fmt.Sprintf("cpu temperature metric not supported by CPU model: 0x%X", cpuModel))
+		m.AssertExpectations(t)
+	})
+
+	t.Run("FailedToReadThermalStatus", func(t *testing.T) {
+		cpuID := 0
+		tempTargetValue := uint64(0x680a00)
+		mError := errors.New("mock error")
+
+		tempExp := uint64(0)
+
+		m := &msrMock{}
+		m.On("read", uint32(temperatureTarget), cpuID).Return(tempTargetValue, nil).Once()
+		m.On("read", uint32(thermalStatus), cpuID).Return(uint64(0), mError).Once()
+
+		pt := &PowerTelemetry{
+			topology: &topologyData{
+				model: cpumodel.INTEL_FAM6_SAPPHIRERAPIDS_X,
+			},
+			msr: m,
+		}
+
+		temOut, err := pt.GetCPUTemperature(cpuID)
+		require.Equal(t, tempExp, temOut)
+		require.ErrorContains(t, err, mError.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("Temp23Celsius", func(t *testing.T) {
+		cpuID := 0
+		tempTargetValue := uint64(0x680a00)
+		thermalStatusValue := uint64(0x88510000)
+
+		tempExp := uint64(23)
+
+		m := &msrMock{}
+		m.On("read", uint32(temperatureTarget), cpuID).Return(tempTargetValue, nil).Once()
+		m.On("read", uint32(thermalStatus), cpuID).Return(thermalStatusValue, nil).Once()
+
+		pt := &PowerTelemetry{
+			topology: &topologyData{
+				model: cpumodel.INTEL_FAM6_EMERALDRAPIDS_X,
+			},
+			msr: m,
+		}
+
+		temOut, err := pt.GetCPUTemperature(cpuID)
+		require.Equal(t, tempExp, temOut)
+		require.NoError(t, err)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("Temp36Celsius", func(t *testing.T) {
+		cpuID := 0
+		tempTargetValue := uint64(0x630a00)
+		thermalStatusValue := uint64(0x883f0800)
+
+		tempExp := uint64(36)
+
+		m := &msrMock{}
+		m.On("read", uint32(temperatureTarget), cpuID).Return(tempTargetValue, nil).Once()
+		m.On("read", uint32(thermalStatus), cpuID).Return(thermalStatusValue, nil).Once()
+
+		pt := &PowerTelemetry{
+			topology: &topologyData{
+				model: cpumodel.INTEL_FAM6_EMERALDRAPIDS_X,
+			},
+			msr: m,
+		}
+
+		temOut, err := pt.GetCPUTemperature(cpuID)
+		require.Equal(t, tempExp, temOut)
+		require.NoError(t, err)
+		m.AssertExpectations(t)
+	})
+}
+
+func TestGetCPUBaseFrequency(t *testing.T) {
+	t.Run("MsrIsNil", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+
+		// expected output
+		expectedFreq := uint64(0)
+
+		// power telemetry instance definition
+		pt := PowerTelemetry{
+			topology: &topologyData{},
+		}
+
+		actualFreq, err := pt.GetCPUBaseFrequency(cpuID)
+		require.ErrorContains(t, err, "\"msr\" is not initialized")
+		require.Equal(t, expectedFreq, actualFreq)
+	})
+
+	t.Run("NoCPUIDsAvailable", func(t *testing.T) {
+		// input arguments for test case
+		packageID := 0
+		busClk := 100.0
+
+		// expected output
+		expectedFreq := uint64(0)
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {
+					packageID: 0,
+				},
+				1: {
+					packageID: 0,
+				},
+				2: {
+					packageID: 0,
+				},
+				3: {
+					packageID: 0,
+				},
+				4: {
+					packageID: 0,
+				},
+			},
+			model: cpumodel.INTEL_FAM6_EMERALDRAPIDS_X,
+		}
+
+		// msr definition
+		m := &msrMock{}
+
+		// power telemetry instance definition
+		pt := PowerTelemetry{
+			topology: topo,
+			msr:      m,
+			cpus:     []int{}, // no cpus available
+			busClock: busClk,
+		}
+
+		actualFreq, err := pt.GetCPUBaseFrequency(packageID)
+		require.ErrorContains(t, err, fmt.Sprintf("could not find CPU ID for package ID %v", packageID))
+		require.Equal(t, expectedFreq, actualFreq)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("CPUIDNotAvailable", func(t *testing.T) {
+		// input arguments for test case
+		packageID := 1
+		busClk := 100.0
+
+		// expected output
+		expectedFreq := uint64(0)
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {
+					packageID: 0,
+				},
+				1: {
+					packageID: 1,
+				},
+				2: {
+					packageID: 0,
+				},
+				3: {
 					packageID: 1,
 				},
-				4: {
+				4: {
+					packageID: 0,
+				},
+			},
+			model: cpumodel.INTEL_FAM6_EMERALDRAPIDS_X,
+		}
+
+		// msr definition
+		m := &msrMock{}
+
+		// power telemetry instance definition
+		pt := PowerTelemetry{
+			topology: topo,
+			msr:      m,
+			cpus:     []int{0, 2, 4},
+			busClock: busClk,
+		}
+
+		actualFreq, err := pt.GetCPUBaseFrequency(packageID)
+		require.ErrorContains(t, err, fmt.Sprintf("could not find CPU ID for package ID %v", packageID))
+		require.Equal(t, expectedFreq, actualFreq)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("BaseFreq", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 2
+		packageID := 1
+		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
+		msrValue := uint64(0x1234)
+		busClk := 100.0
+
+		// expected output
+		expectedFreq := uint64(1800)
+
+		// msr definition
+		m := &msrMock{}
+		m.On("read", uint32(platformInfo), cpuID).Return(msrValue, nil)
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {
+					packageID: 0,
+				},
+				1: {
+					packageID: 0,
+				},
+				2: {
+					packageID: packageID,
+				},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := PowerTelemetry{
+			msr:      m,
+			topology: topo,
+			cpus:     []int{0, 1, 2},
+			busClock: busClk,
+		}
+
+		actualFreq, err := pt.GetCPUBaseFrequency(packageID)
+		require.NoError(t, err)
+		require.Equal(t, expectedFreq, actualFreq)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("BaseFreqFractional", func(t *testing.T) {
+		// input arguments for test case
+		packageID := 0
+		cpuID := 0
+		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
+		msrValue := uint64(0x1234)
+		busClk := 116.7
+
+		// expected output
+		expectedFreq := uint64(2100)
+
+		// msr definition
+		m := &msrMock{}
+		m.On("read", uint32(platformInfo), cpuID).Return(msrValue, nil)
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {
+					packageID: packageID,
+				},
+				1: {
+					packageID: packageID,
+				},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := PowerTelemetry{
+			msr:      m,
+			topology: topo,
+			busClock: busClk,
+			cpus:     []int{0, 1},
+		}
+
+		actualFreq, err := pt.GetCPUBaseFrequency(packageID)
+		require.NoError(t, err)
+		require.Equal(t, expectedFreq, actualFreq)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("UnsupportedModel", func(t *testing.T) {
+		// input arguments for test case
+		packageID := 1
+		cpuModel := cpumodel.INTEL_FAM6_CORE2_MEROM
+
+		// expected output
+		expectedFreq := uint64(0)
+		expectedErr := fmt.Errorf("base frequency metric not supported by CPU model: 0x%X", cpuModel)
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {
+					packageID: 0,
+				},
+				1: {
 					packageID: 0,
 				},
+				2: {
+					packageID: packageID,
+				},
+			},
+			model: cpuModel,
+		}
+
+		// msr definition
+		m := &msrMock{}
+
+		// power telemetry instance definition
+		pt := PowerTelemetry{
+			topology: topo,
+			msr:      m,
+			cpus:     []int{0, 1, 2},
+		}
+
+		actualFreq, err := pt.GetCPUBaseFrequency(packageID)
+		require.Equal(t, expectedFreq, actualFreq)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("ErrorReadingMsr", func(t *testing.T) {
+		// input arguments for test case
+		packageID := 0
+		cpuID := 0
+		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
+		msrValue := uint64(0x1234)
+
+		// expected output
+		expectedFreq := uint64(0)
+		expectedErr := errors.New("error reading msr")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("read", uint32(platformInfo), cpuID).Return(msrValue, errors.New("error reading msr"))
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {
+					packageID: packageID,
+				},
+				1: {
+					packageID: packageID,
+				},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := PowerTelemetry{
+			msr:      m,
+			topology: topo,
+			cpus:     []int{0, 1},
+		}
+
+		actualFreq, err := pt.GetCPUBaseFrequency(cpuID)
+		require.ErrorContains(t, err, expectedErr.Error())
+		require.Equal(t, expectedFreq, actualFreq)
+		m.AssertExpectations(t)
+	})
+}
+
+func TestGetPackagePPIN(t *testing.T) {
+	packageID := 1
+	cpuID := 2
+
+	// topology definition
+	topo := &topologyData{
+		topologyMap: map[int]*cpuInfo{
+			0: {
+				packageID: 0,
+			},
+			1: {
+				packageID: 0,
+			},
+			2: {
+				packageID: 1,
+			},
+			3: {
+				packageID: 1,
+			},
+		},
+		model: cpumodel.INTEL_FAM6_SAPPHIRERAPIDS_X,
+	}
+
+	t.Run("MsrIsNil", func(t *testing.T) {
+		pt := &PowerTelemetry{}
+
+		ppinOut, err := pt.GetPackagePPIN(packageID)
+		require.ErrorContains(t, err, "\"msr\" is not initialized")
+		require.Equal(t, uint64(0), ppinOut)
+	})
+
+	t.Run("ModelNotSupported", func(t *testing.T) {
+		cpuModel := cpumodel.INTEL_FAM6_ALDERLAKE
+
+		m := &msrMock{}
+
+		pt := &PowerTelemetry{
+			topology: &topologyData{
+				model: cpuModel,
+			},
+			msr: m,
+		}
+
+		ppinOut, err := pt.GetPackagePPIN(packageID)
+		require.ErrorContains(t, err, fmt.Sprintf("package PPIN metric not supported by CPU model: 0x%X", cpuModel))
+		require.Equal(t, uint64(0), ppinOut)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("CPUIDNotAvailable", func(t *testing.T) {
+		m := &msrMock{}
+
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+			cpus:     []int{0, 1},
+		}
+
+		ppinOut, err := pt.GetPackagePPIN(packageID)
+		require.ErrorContains(t, err, fmt.Sprintf("could not find CPU ID for package ID %v", packageID))
+		require.Equal(t, uint64(0), ppinOut)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("FailedToReadPPINCtl", func(t *testing.T) {
+		mError := errors.New("mock error")
+
+		m := &msrMock{}
+		m.On("read", uint32(ppinCtl), cpuID).Return(uint64(0), mError).Once()
+
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+			cpus:     []int{0, 1, 2, 3},
+		}
+
+		ppinOut, err := pt.GetPackagePPIN(packageID)
+		require.ErrorContains(t, err, mError.Error())
+		require.Equal(t, uint64(0), ppinOut)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("PPINDisabled", func(t *testing.T) {
+		m := &msrMock{}
+		m.On("read", uint32(ppinCtl), cpuID).Return(uint64(0x0), nil).Once()
+
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+			cpus:     []int{0, 1, 2, 3},
+		}
+
+		ppinOut, err := pt.GetPackagePPIN(packageID)
+		require.ErrorContains(t, err, fmt.Sprintf("PPIN is disabled for package ID: %v", packageID))
+		require.Equal(t, uint64(0), ppinOut)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("PPINDisabledAndLocked", func(t *testing.T) {
+		m := &msrMock{}
+		m.On("read", uint32(ppinCtl), cpuID).Return(uint64(0x1), nil).Once()
+
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+			cpus:     []int{0, 1, 2, 3},
+		}
+
+		ppinOut, err := pt.GetPackagePPIN(packageID)
+		require.ErrorContains(t, err, fmt.Sprintf("PPIN is disabled and locked for package ID: %v", packageID))
+		require.Equal(t, uint64(0), ppinOut)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("FailedToReadPPIN", func(t *testing.T) {
+		mError := errors.New("mock error")
+
+		m := &msrMock{}
+		m.On("read", uint32(ppinCtl), cpuID).Return(uint64(0x3), nil).Once()
+		m.On("read", uint32(ppin), cpuID).Return(uint64(0), mError).Once()
+
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+			cpus:     []int{0, 1, 2, 3},
+		}
+
+		ppinOut, err := pt.GetPackagePPIN(packageID)
+		require.ErrorContains(t, err, mError.Error())
+		require.Equal(t, uint64(0), ppinOut)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		ppinExp := uint64(0x1B2C3D4E5F607182)
+
+		m := &msrMock{}
+		m.On("read", uint32(ppinCtl), cpuID).Return(uint64(0x2), nil).Once()
+		m.On("read", uint32(ppin), cpuID).Return(ppinExp, nil).Once()
+
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+			cpus:     []int{0, 1, 2, 3},
+		}
+
+		ppinOut, err := pt.GetPackagePPIN(packageID)
+		require.NoError(t, err)
+		require.Equal(t, ppinExp, ppinOut)
+		m.AssertExpectations(t)
+	})
+}
+
+func TestGetPackagePPINs(t *testing.T) {
+	topo := &topologyData{
+		topologyMap: map[int]*cpuInfo{
+			0: {
+				packageID: 0,
+			},
+			1: {
+				packageID: 1,
+			},
+		},
+		packageIDs: []int{0, 1},
+		model:      cpumodel.INTEL_FAM6_SAPPHIRERAPIDS_X,
+	}
+
+	t.Run("MsrIsNil", func(t *testing.T) {
+		pt := &PowerTelemetry{}
+
+		ppins, err := pt.GetPackagePPINs()
+		require.ErrorContains(t, err, "\"msr\" is not initialized")
+		require.Nil(t, ppins)
+	})
+
+	t.Run("ModelNotSupported", func(t *testing.T) {
+		cpuModel := cpumodel.INTEL_FAM6_ALDERLAKE
+
+		pt := &PowerTelemetry{
+			topology: &topologyData{
+				model: cpuModel,
+			},
+			msr: &msrMock{},
+		}
+
+		ppins, err := pt.GetPackagePPINs()
+		require.ErrorContains(t, err, fmt.Sprintf("package PPIN metric not supported by CPU model: 0x%X", cpuModel))
+		require.Nil(t, ppins)
+	})
+
+	t.Run("PartialResults", func(t *testing.T) {
+		m := &msrMock{}
+		m.On("read", uint32(ppinCtl), 0).Return(uint64(0x2), nil).Once()
+		m.On("read", uint32(ppin), 0).Return(uint64(0xABCD), nil).Once()
+		m.On("read", uint32(ppinCtl), 1).Return(uint64(0x0), nil).Once()
+
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+			cpus:     []int{0, 1},
+		}
+
+		ppins, err := pt.GetPackagePPINs()
+		require.ErrorContains(t, err, "error retrieving PPIN for package ID 1: PPIN is disabled for package ID: 1")
+		require.Equal(t, map[int]uint64{0: 0xABCD}, ppins)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		m := &msrMock{}
+		m.On("read", uint32(ppinCtl), 0).Return(uint64(0x2), nil).Once()
+		m.On("read", uint32(ppin), 0).Return(uint64(0xABCD), nil).Once()
+		m.On("read", uint32(ppinCtl), 1).Return(uint64(0x3), nil).Once()
+		m.On("read", uint32(ppin), 1).Return(uint64(0x1234), nil).Once()
+
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+			cpus:     []int{0, 1},
+		}
+
+		ppins, err := pt.GetPackagePPINs()
+		require.NoError(t, err)
+		require.Equal(t, map[int]uint64{0: 0xABCD, 1: 0x1234}, ppins)
+		m.AssertExpectations(t)
+	})
+}
+
+type msrGetOffsetDeltasResult struct {
+	values map[uint32]uint64
+	err    error
+}
+
+type msrGetTimestampDeltaResult struct {
+	value time.Duration
+	err   error
+}
+
+func TestGetCPUC0StateResidency(t *testing.T) {
+	t.Run("MsrIsNil", func(t *testing.T) {
+		cpuID := 1
+		c0Exp := 0.0
+
+		pt := &PowerTelemetry{}
+
+		c0Out, err := pt.GetCPUC0StateResidency(cpuID)
+		require.Equal(t, c0Exp, c0Out)
+		require.ErrorContains(t, err, "\"msr\" is not initialized")
+	})
+
+	t.Run("InvalidCPUID", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 1
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: nil,
+			err:    errors.New("CPU ID 1 not found"),
+		}
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("error retrieving offset deltas for CPU ID")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			msr: m,
+		}
+
+		out, err := pt.GetCPUC0StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("TSCDeltaZero", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				maxFreqClockCount: 200,
+				timestampCounter:  0,
+			},
+			err: nil,
+		}
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("timestamp counter offset delta is zero for CPU ID")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			msr: m,
+		}
+
+		out, err := pt.GetCPUC0StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("MperfOffsetDeltaNotFound", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				timestampCounter: 5000,
+			},
+			err: nil,
+		}
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("mperf offset delta not found for CPU ID")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			msr: m,
+		}
+
+		out, err := pt.GetCPUC0StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("TSCOffsetDeltaNotFound", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				maxFreqClockCount: 200,
+			},
+			err: nil,
+		}
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("timestamp counter offset delta not found for CPU ID")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			msr: m,
+		}
+
+		out, err := pt.GetCPUC0StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("C0State2Per", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				maxFreqClockCount: 100000000,
+				timestampCounter:  5000000000,
+			},
+			err: nil,
+		}
+
+		// expected output
+		expectedResult := 2.0
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			msr: m,
+		}
+
+		out, err := pt.GetCPUC0StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.NoError(t, err)
+		m.AssertExpectations(t)
+	})
+}
+
+func TestGetCPUC1StateResidency(t *testing.T) {
+	t.Run("MsrIsNil", func(t *testing.T) {
+		cpuID := 1
+		c1Exp := 0.0
+
+		pt := PowerTelemetry{
+			topology: &topologyData{},
+		}
+
+		c1Out, err := pt.GetCPUC1StateResidency(cpuID)
+		require.Equal(t, c1Exp, c1Out)
+		require.ErrorContains(t, err, "\"msr\" is not initialized")
+	})
+
+	t.Run("InvalidCPUID", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 1
+		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: nil,
+			err:    errors.New("CPU ID 1 not found"),
+		}
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("error retrieving offset deltas for CPU ID")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC1StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("MperfOffsetDeltaNotFound", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				timestampCounter: 5000,
+			},
+			err: nil,
+		}
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("mperf offset delta not found for CPU ID")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC1StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("C3OffsetDeltaNotFound", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				timestampCounter:  5000,
+				maxFreqClockCount: 100,
+			},
+			err: nil,
+		}
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("c3 state residency offset delta not found for CPU ID")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC1StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("C6OffsetDeltaNotFound", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				timestampCounter:  5000,
+				maxFreqClockCount: 100,
+				c3Residency:       100,
+			},
+			err: nil,
+		}
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("c6 state residency offset delta not found for CPU ID")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC1StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("C7OffsetDeltaNotFound", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				timestampCounter:  5000,
+				maxFreqClockCount: 100,
+				c3Residency:       100,
+				c6Residency:       100,
+			},
+			err: nil,
+		}
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("c7 state residency offset delta not found for CPU ID")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC1StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("TSCOffsetDeltaNotFound", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				maxFreqClockCount: 100,
+				c3Residency:       100,
+				c6Residency:       100,
+				c7Residency:       100,
+			},
+			err: nil,
+		}
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("timestamp counter offset delta not found for CPU ID")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC1StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("TSCDeltaZero", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				timestampCounter:  0,
+				maxFreqClockCount: 100,
+				c3Residency:       100,
+				c6Residency:       100,
+				c7Residency:       100,
+			},
+			err: nil,
+		}
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("timestamp counter offset delta is zero for CPU ID")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC1StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("C1Status20Per", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				timestampCounter:  500,
+				maxFreqClockCount: 100,
+				c3Residency:       100,
+				c6Residency:       100,
+				c7Residency:       100,
+			},
+			err: nil,
+		}
+
+		// expected output
+		expectedResult := 20.0
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC1StateResidency(cpuID)
+		require.InDelta(t, expectedResult, out, 0.1)
+		require.NoError(t, err)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("UnsupportedModel", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		cpuModel := cpumodel.INTEL_FAM6_CORE2_MEROM
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := fmt.Errorf("c1 state residency metric not supported by CPU model: 0x%X", cpuModel)
+
+		// msr definition
+		m := &msrMock{}
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC1StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("InvalidCPUModel", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("c1 state residency metric not supported by CPU model: 0x0")
+
+		// msr definition
+		m := &msrMock{}
+
+		// topology definition
+		topo := &topologyData{}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC1StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+}
+
+func TestGetCPUC3StateResidency(t *testing.T) {
+	t.Run("MsrIsNil", func(t *testing.T) {
+		cpuID := 1
+		c3Exp := 0.0
+
+		pt := PowerTelemetry{
+			topology: &topologyData{},
+		}
+
+		c3Out, err := pt.GetCPUC3StateResidency(cpuID)
+		require.Equal(t, c3Exp, c3Out)
+		require.ErrorContains(t, err, "\"msr\" is not initialized")
+	})
+
+	t.Run("InvalidCPUID", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 1
+		cpuModel := cpumodel.INTEL_FAM6_HASWELL
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: nil,
+			err:    errors.New("CPU ID 1 not found"),
+		}
+
+		// expected output
+		expectedErr := errors.New("error retrieving offset deltas for CPU ID")
+		expectedResult := 0.0
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC3StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("C3OffsetDeltaNotFound", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		cpuModel := cpumodel.INTEL_FAM6_HASWELL
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				timestampCounter: 5000,
+			},
+			err: nil,
+		}
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("c3 state residency offset delta not found for CPU ID")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC3StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("C3State4Per", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		cpuModel := cpumodel.INTEL_FAM6_HASWELL
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				c3Residency:      200000000,
+				timestampCounter: 5000000000,
+			},
+			err: nil,
+		}
+
+		// expected output
+		expectedResult := 4.0
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC3StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.NoError(t, err)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("TSCOffsetDeltaNotFound", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		cpuModel := cpumodel.INTEL_FAM6_HASWELL
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				c3Residency: 200000000,
+			},
+			err: nil,
+		}
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("timestamp counter offset delta not found for CPU ID")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC3StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("TSCDeltaZero", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		cpuModel := cpumodel.INTEL_FAM6_HASWELL
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				c3Residency:      200,
+				timestampCounter: 0,
+			},
+			err: nil,
+		}
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("timestamp counter offset delta is zero for CPU ID")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC3StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("UnsupportedModel", func(t *testing.T) {
+		// input arguments for test case
+		cpuModel := cpumodel.INTEL_FAM6_CORE2_MEROM
+		cpuID := 0
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := fmt.Errorf("c3 state residency metric not supported by CPU model: 0x%X", cpuModel)
+
+		// msr definition
+		m := &msrMock{}
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC3StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("InvalidCPUModel", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("c3 state residency metric not supported by CPU model: 0x0")
+
+		// msr definition
+		m := &msrMock{}
+
+		// topology definition
+		topo := &topologyData{}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC3StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+}
+
+func TestGetCPUC6StateResidency(t *testing.T) {
+	t.Run("MsrIsNil", func(t *testing.T) {
+		cpuID := 1
+		c6Exp := 0.0
+
+		pt := PowerTelemetry{
+			topology: &topologyData{},
+		}
+
+		c6Out, err := pt.GetCPUC6StateResidency(cpuID)
+		require.Equal(t, c6Exp, c6Out)
+		require.ErrorContains(t, err, "\"msr\" is not initialized")
+	})
+
+	t.Run("InvalidCPUID", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 1
+		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: nil,
+			err:    errors.New("CPU ID 1 not found"),
+		}
+
+		// expected output
+		expectedErr := errors.New("error retrieving offset deltas for CPU ID")
+		expectedResult := 0.0
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC6StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("C6OffsetDeltaNotFound", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				timestampCounter: 5000,
+			},
+			err: nil,
+		}
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("c6 state residency offset delta not found for CPU ID")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC6StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("C6State4Per", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				c6Residency:      200000000,
+				timestampCounter: 5000000000,
+			},
+			err: nil,
+		}
+
+		// expected output
+		expectedResult := 4.0
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC6StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.NoError(t, err)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("TSCOffsetDeltaNotFound", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				c6Residency: 200000000,
+			},
+			err: nil,
+		}
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("timestamp counter offset delta not found for CPU ID")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC6StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("TSCDeltaZero", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				c6Residency:      200,
+				timestampCounter: 0,
+			},
+			err: nil,
+		}
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("timestamp counter offset delta is zero for CPU ID")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC6StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("UnsupportedModel", func(t *testing.T) {
+		// input arguments for test case
+		cpuModel := cpumodel.INTEL_FAM6_CORE2_MEROM
+		cpuID := 0
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := fmt.Errorf("c6 state residency metric not supported by CPU model: 0x%X", cpuModel)
+
+		// msr definition
+		m := &msrMock{}
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC6StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("InvalidCPUModel", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("c6 state residency metric not supported by CPU model: 0x0")
+
+		// msr definition
+		m := &msrMock{}
+
+		// topology definition
+		topo := &topologyData{}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC6StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+}
+
+func TestGetCPUC7StateResidency(t *testing.T) {
+	t.Run("MsrIsNil", func(t *testing.T) {
+		cpuID := 1
+		c7Exp := 0.0
+
+		pt := PowerTelemetry{
+			topology: &topologyData{},
+		}
+
+		c7Out, err := pt.GetCPUC7StateResidency(cpuID)
+		require.Equal(t, c7Exp, c7Out)
+		require.ErrorContains(t, err, "\"msr\" is not initialized")
+	})
+
+	t.Run("InvalidCPUID", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 1
+		cpuModel := cpumodel.INTEL_FAM6_SKYLAKE
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: nil,
+			err:    errors.New("CPU ID 1 not found"),
+		}
+
+		// expected output
+		expectedErr := errors.New("error retrieving offset deltas for CPU ID")
+		expectedResult := 0.0
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC7StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("C7OffsetDeltaNotFound", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		cpuModel := cpumodel.INTEL_FAM6_SKYLAKE
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				timestampCounter: 5000,
+			},
+			err: nil,
+		}
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("c7 state residency offset delta not found for CPU ID")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC7StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("C7State4Per", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		cpuModel := cpumodel.INTEL_FAM6_SKYLAKE
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				c7Residency:      200000000,
+				timestampCounter: 5000000000,
+			},
+			err: nil,
+		}
+
+		// expected output
+		expectedResult := 4.0
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC7StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.NoError(t, err)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("TSCOffsetDeltaNotFound", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		cpuModel := cpumodel.INTEL_FAM6_SKYLAKE
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				c7Residency: 200000000,
+			},
+			err: nil,
+		}
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("timestamp counter offset delta not found for CPU ID")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC7StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("TSCDeltaZero", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		cpuModel := cpumodel.INTEL_FAM6_SKYLAKE
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				c7Residency:      200,
+				timestampCounter: 0,
+			},
+			err: nil,
+		}
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("timestamp counter offset delta is zero for CPU ID")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
+			},
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC7StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("UnsupportedModel", func(t *testing.T) {
+		// input arguments for test case
+		cpuModel := cpumodel.INTEL_FAM6_CORE2_MEROM
+		cpuID := 0
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := fmt.Errorf("c7 state residency metric not supported by CPU model: 0x%X", cpuModel)
+
+		// msr definition
+		m := &msrMock{}
+
+		// topology definition
+		topo := &topologyData{
+			topologyMap: map[int]*cpuInfo{
+				0: {},
 			},
-			model: cpumodel.INTEL_FAM6_EMERALDRAPIDS_X,
+			model: cpuModel,
+		}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC7StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("InvalidCPUModel", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("c7 state residency metric not supported by CPU model")
+
+		// msr definition
+		m := &msrMock{}
+
+		// topology definition
+		topo := &topologyData{}
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			topology: topo,
+			msr:      m,
+		}
+
+		out, err := pt.GetCPUC7StateResidency(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+}
+
+func TestGetCPUBusyFrequencyMhz(t *testing.T) {
+	t.Run("MsrIsNil", func(t *testing.T) {
+		cpuID := 1
+		busyFreqExp := 0.0
+
+		pt := &PowerTelemetry{}
+
+		busyFreqOut, err := pt.GetCPUBusyFrequencyMhz(cpuID)
+		require.Equal(t, busyFreqExp, busyFreqOut)
+		require.ErrorContains(t, err, "\"msr\" is not initialized")
+	})
+
+	t.Run("DeltasInvalidCPUID", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 1
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: nil,
+			err:    errors.New("CPU ID 1 not found"),
+		}
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("error retrieving offset deltas for CPU ID")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			msr: m,
+		}
+
+		out, err := pt.GetCPUBusyFrequencyMhz(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("TimestampDeltaInvalidCPUID", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 1
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				actualFreqClockCount: 100000000000,
+				maxFreqClockCount:    200000000,
+				timestampCounter:     1000000,
+			},
+			err: nil,
+		}
+		mockTimestampDelta := &msrGetTimestampDeltaResult{
+			value: time.Duration(0),
+			err:   errors.New("CPU ID 1 not found"),
+		}
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("error retrieving timestamp delta for CPU ID")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+		m.On("getTimestampDelta", cpuID).Return(mockTimestampDelta.value, mockTimestampDelta.err).Once()
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			msr: m,
+		}
+
+		out, err := pt.GetCPUBusyFrequencyMhz(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("TimestampDeltaInvalidCPUID", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				actualFreqClockCount: 100000000000,
+				maxFreqClockCount:    200000000,
+				timestampCounter:     1000000,
+			},
+			err: nil,
+		}
+		mockTimestampDelta := &msrGetTimestampDeltaResult{
+			value: time.Duration(0),
+			err:   errors.New("CPU ID 1 not found"),
+		}
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("error retrieving timestamp delta for CPU ID")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+		m.On("getTimestampDelta", cpuID).Return(mockTimestampDelta.value, mockTimestampDelta.err).Once()
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			msr: m,
+		}
+
+		out, err := pt.GetCPUBusyFrequencyMhz(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("InvalidTimeInterval", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				actualFreqClockCount: 100000000000,
+				maxFreqClockCount:    200000000,
+				timestampCounter:     1000000,
+			},
+			err: nil,
+		}
+		mockTimestampDelta := &msrGetTimestampDeltaResult{
+			value: -100,
+			err:   nil,
+		}
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("timestamp delta must be greater than zero")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+		m.On("getTimestampDelta", cpuID).Return(mockTimestampDelta.value, mockTimestampDelta.err).Once()
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			msr: m,
+		}
+
+		out, err := pt.GetCPUBusyFrequencyMhz(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("MperfOffsetDeltaNotFound", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				timestampCounter: 5000,
+			},
+			err: nil,
+		}
+		mockTimestampDelta := &msrGetTimestampDeltaResult{
+			value: 1,
+			err:   nil,
+		}
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("mperf offset delta not found for CPU ID")
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+		m.On("getTimestampDelta", cpuID).Return(mockTimestampDelta.value, mockTimestampDelta.err).Once()
+
+		// power telemetry instance definition
+		pt := &PowerTelemetry{
+			msr: m,
+		}
+
+		out, err := pt.GetCPUBusyFrequencyMhz(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("MperfOffsetDeltaZero", func(t *testing.T) {
+		// input arguments for test case
+		cpuID := 0
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				timestampCounter:  5000,
+				maxFreqClockCount: 0,
+			},
+			err: nil,
 		}
+		mockTimestampDelta := &msrGetTimestampDeltaResult{
+			value: 1,
+			err:   nil,
+		}
+
+		// expected output
+		expectedResult := 0.0
+		expectedErr := errors.New("mperf offset delta is zero for CPU ID")
 
 		// msr definition
 		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+		m.On("getTimestampDelta", cpuID).Return(mockTimestampDelta.value, mockTimestampDelta.err).Once()
 
 		// power telemetry instance definition
-		pt := PowerTelemetry{
-			topology: topo,
-			msr:      m,
-			cpus:     []int{0, 2, 4},
-			busClock: busClk,
+		pt := &PowerTelemetry{
+			msr: m,
 		}
 
-		actualFreq, err := pt.GetCPUBaseFrequency(packageID)
-		require.ErrorContains(t, err, fmt.Sprintf("could not find CPU ID for package ID %v", packageID))
-		require.Equal(t, expectedFreq, actualFreq)
+		out, err := pt.GetCPUBusyFrequencyMhz(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
 		m.AssertExpectations(t)
 	})
 
-	t.Run("BaseFreq", func(t *testing.T) {
+	t.Run("AperfOffsetDeltaNotFound", func(t *testing.T) {
 		// input arguments for test case
-		cpuID := 2
-		packageID := 1
-		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
-		msrValue := uint64(0x1234)
-		busClk := 100.0
+		cpuID := 0
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				timestampCounter:  5000,
+				maxFreqClockCount: 100,
+			},
+			err: nil,
+		}
+		mockTimestampDelta := &msrGetTimestampDeltaResult{
+			value: 1,
+			err:   nil,
+		}
 
 		// expected output
-		expectedFreq := uint64(1800)
+		expectedResult := 0.0
+		expectedErr := errors.New("aperf offset delta not found for CPU ID")
 
 		// msr definition
 		m := &msrMock{}
-		m.On("read", uint32(platformInfo), cpuID).Return(msrValue, nil)
-
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {
-					packageID: 0,
-				},
-				1: {
-					packageID: 0,
-				},
-				2: {
-					packageID: packageID,
-				},
-			},
-			model: cpuModel,
-		}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+		m.On("getTimestampDelta", cpuID).Return(mockTimestampDelta.value, mockTimestampDelta.err).Once()
 
 		// power telemetry instance definition
-		pt := PowerTelemetry{
-			msr:      m,
-			topology: topo,
-			cpus:     []int{0, 1, 2},
-			busClock: busClk,
+		pt := &PowerTelemetry{
+			msr: m,
 		}
 
-		actualFreq, err := pt.GetCPUBaseFrequency(packageID)
-		require.NoError(t, err)
-		require.Equal(t, expectedFreq, actualFreq)
+		out, err := pt.GetCPUBusyFrequencyMhz(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
 		m.AssertExpectations(t)
 	})
 
-	t.Run("BaseFreqFractional", func(t *testing.T) {
+	t.Run("TSCOffsetDeltaNotFound", func(t *testing.T) {
 		// input arguments for test case
-		packageID := 0
 		cpuID := 0
-		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
-		msrValue := uint64(0x1234)
-		busClk := 116.7
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				actualFreqClockCount: 100000000000,
+				maxFreqClockCount:    200000000,
+			},
+			err: nil,
+		}
+		mockTimestampDelta := &msrGetTimestampDeltaResult{
+			value: 1,
+			err:   nil,
+		}
 
 		// expected output
-		expectedFreq := uint64(2100)
+		expectedResult := 0.0
+		expectedErr := errors.New("timestamp counter offset delta not found for CPU ID")
 
 		// msr definition
 		m := &msrMock{}
-		m.On("read", uint32(platformInfo), cpuID).Return(msrValue, nil)
-
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {
-					packageID: packageID,
-				},
-				1: {
-					packageID: packageID,
-				},
-			},
-			model: cpuModel,
-		}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+		m.On("getTimestampDelta", cpuID).Return(mockTimestampDelta.value, mockTimestampDelta.err).Once()
 
 		// power telemetry instance definition
-		pt := PowerTelemetry{
-			msr:      m,
-			topology: topo,
-			busClock: busClk,
-			cpus:     []int{0, 1},
+		pt := &PowerTelemetry{
+			msr: m,
 		}
 
-		actualFreq, err := pt.GetCPUBaseFrequency(packageID)
-		require.NoError(t, err)
-		require.Equal(t, expectedFreq, actualFreq)
+		out, err := pt.GetCPUBusyFrequencyMhz(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.ErrorContains(t, err, expectedErr.Error())
 		m.AssertExpectations(t)
 	})
 
-	t.Run("UnsupportedModel", func(t *testing.T) {
+	t.Run("BusyFreq", func(t *testing.T) {
 		// input arguments for test case
-		packageID := 1
-		cpuModel := cpumodel.INTEL_FAM6_CORE2_MEROM
+		cpuID := 0
+		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				actualFreqClockCount: 100000000000,
+				maxFreqClockCount:    200000000,
+				timestampCounter:     1000000,
+			},
+			err: nil,
+		}
+		mockTimestampDelta := &msrGetTimestampDeltaResult{
+			value: 1000000000,
+			err:   nil,
+		}
 
 		// expected output
-		expectedFreq := uint64(0)
-		expectedErr := fmt.Errorf("base frequency metric not supported by CPU model: 0x%X", cpuModel)
+		expectedResult := 500.0
+
+		// msr definition
+		m := &msrMock{}
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+		m.On("getTimestampDelta", cpuID).Return(mockTimestampDelta.value, mockTimestampDelta.err).Once()
 
 		// topology definition
 		topo := &topologyData{
 			topologyMap: map[int]*cpuInfo{
-				0: {
-					packageID: 0,
-				},
-				1: {
-					packageID: 0,
-				},
-				2: {
-					packageID: packageID,
-				},
+				0: {},
 			},
 			model: cpuModel,
 		}
 
-		// msr definition
-		m := &msrMock{}
-
 		// power telemetry instance definition
-		pt := PowerTelemetry{
+		pt := &PowerTelemetry{
 			topology: topo,
 			msr:      m,
-			cpus:     []int{0, 1, 2},
 		}
 
-		actualFreq, err := pt.GetCPUBaseFrequency(packageID)
-		require.Equal(t, expectedFreq, actualFreq)
-		require.ErrorContains(t, err, expectedErr.Error())
+		out, err := pt.GetCPUBusyFrequencyMhz(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.NoError(t, err)
 		m.AssertExpectations(t)
 	})
 
-	t.Run("ErrorReadingMsr", func(t *testing.T) {
+	t.Run("UnsupportedModel", func(t *testing.T) {
 		// input arguments for test case
-		packageID := 0
 		cpuID := 0
-		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
-		msrValue := uint64(0x1234)
+		cpuModel := cpumodel.INTEL_FAM6_CORE2_MEROM
+		mockOffsetDeltas := &msrGetOffsetDeltasResult{
+			values: map[uint32]uint64{
+				actualFreqClockCount: 100000000000,
+				maxFreqClockCount:    200000000,
+				timestampCounter:     1000000,
+			},
+			err: nil,
+		}
+		mockTimestampDelta := &msrGetTimestampDeltaResult{
+			value: 1000000000,
+			err:   nil,
+		}
 
 		// expected output
-		expectedFreq := uint64(0)
-		expectedErr := errors.New("error reading msr")
+		expectedResult := 500.0
 
 		// msr definition
 		m := &msrMock{}
-		m.On("read", uint32(platformInfo), cpuID).Return(msrValue, errors.New("error reading msr"))
+		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+		m.On("getTimestampDelta", cpuID).Return(mockTimestampDelta.value, mockTimestampDelta.err).Once()
 
 		// topology definition
 		topo := &topologyData{
 			topologyMap: map[int]*cpuInfo{
-				0: {
-					packageID: packageID,
-				},
-				1: {
-					packageID: packageID,
-				},
+				0: {},
 			},
 			model: cpuModel,
 		}
 
 		// power telemetry instance definition
-		pt := PowerTelemetry{
-			msr:      m,
+		pt := &PowerTelemetry{
 			topology: topo,
-			cpus:     []int{0, 1},
+			msr:      m,
 		}
 
-		actualFreq, err := pt.GetCPUBaseFrequency(cpuID)
-		require.ErrorContains(t, err, expectedErr.Error())
-		require.Equal(t, expectedFreq, actualFreq)
+		out, err := pt.GetCPUBusyFrequencyMhz(cpuID)
+		require.Equal(t, expectedResult, out)
+		require.NoError(t, err)
 		m.AssertExpectations(t)
 	})
 }
 
-type msrGetOffsetDeltasResult struct {
-	values map[uint32]uint64
-	err    error
-}
+func TestGetPerfCStateResidency(t *testing.T) {
+	cpuID := 1
+	interval := time.Second
+	deltas := map[pmuCounter]uint64{
+		{pmu: cstateCorePMU, event: "c1-residency"}: 200_000_000,
+		{pmu: cstateCorePMU, event: "c3-residency"}: 100_000_000,
+		{pmu: cstateCorePMU, event: "c6-residency"}: 1_000_000_000,
+		{pmu: cstateCorePMU, event: "c7-residency"}: 0,
+		{pmu: cstatePkgPMU, event: "c6-residency"}:  2_200_000_000,
+	}
 
-type msrGetTimestampDeltaResult struct {
-	value time.Duration
-	err   error
-}
+	setTSCFrequency := func(t *testing.T, hz uint64) {
+		t.Helper()
 
-func TestGetCPUC0StateResidency(t *testing.T) {
-	t.Run("MsrIsNil", func(t *testing.T) {
-		cpuID := 1
-		c0Exp := 0.0
+		orig := tscFrequency
+		tscFrequency = func() uint64 {
+			return hz
+		}
+		t.Cleanup(func() {
+			tscFrequency = orig
+		})
+	}
 
-		pt := &PowerTelemetry{}
+	getters := map[string]func(*PowerTelemetry, int) (float64, error){
+		"C1": (*PowerTelemetry).GetCPUC1StateResidency,
+		"C3": (*PowerTelemetry).GetCPUC3StateResidency,
+		"C6": (*PowerTelemetry).GetCPUC6StateResidency,
+		"C7": (*PowerTelemetry).GetCPUC7StateResidency,
+	}
 
-		c0Out, err := pt.GetCPUC0StateResidency(cpuID)
-		require.Equal(t, c0Exp, c0Out)
-		require.ErrorContains(t, err, "\"msr\" is not initialized")
+	t.Run("FailedToGetTimestampDelta", func(t *testing.T) {
+		m := &pmuCounterMock{}
+		m.On("getTimestampDelta", cpuID).Return(time.Duration(0), errors.New("mock error")).Once()
+
+		pt := &PowerTelemetry{
+			cstatePerf: m,
+		}
+
+		residency, err := pt.GetCPUC3StateResidency(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("error retrieving timestamp delta for CPU ID %v", cpuID))
+		require.Zero(t, residency)
+		m.AssertExpectations(t)
 	})
 
-	t.Run("InvalidCPUID", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 1
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: nil,
-			err:    errors.New("CPU ID 1 not found"),
+	t.Run("ZeroTimestampDelta", func(t *testing.T) {
+		m := &pmuCounterMock{}
+		m.On("getTimestampDelta", cpuID).Return(time.Duration(0), nil).Once()
+
+		pt := &PowerTelemetry{
+			cstatePerf: m,
 		}
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("error retrieving offset deltas for CPU ID")
+		residency, err := pt.GetCPUC7StateResidency(cpuID)
+		require.ErrorContains(t, err, "timestamp delta must be greater than zero")
+		require.Zero(t, residency)
+		m.AssertExpectations(t)
+	})
 
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+	t.Run("FailedToGetCounterDeltas", func(t *testing.T) {
+		m := &pmuCounterMock{}
+		m.On("getTimestampDelta", cpuID).Return(interval, nil).Once()
+		m.On("getCounterDeltas", cpuID).Return(map[pmuCounter]uint64(nil), errors.New("mock error")).Once()
+
+		pt := &PowerTelemetry{
+			cstatePerf: m,
+		}
+
+		residency, err := pt.GetCPUC6StateResidency(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("error retrieving counter deltas for CPU ID %v", cpuID))
+		require.Zero(t, residency)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("CounterNotFound", func(t *testing.T) {
+		m := &pmuCounterMock{}
+		m.On("getTimestampDelta", cpuID).Return(interval, nil).Once()
+		m.On("getCounterDeltas", cpuID).Return(map[pmuCounter]uint64{}, nil).Once()
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			msr: m,
+			cstatePerf: m,
 		}
 
-		out, err := pt.GetCPUC0StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		residency, err := pt.GetCPUC1StateResidency(cpuID)
+		require.ErrorIs(t, err, errPMUCounterNotFound)
+		require.ErrorContains(t, err, "event \"c1-residency\" of cstate_core PMU")
+		require.Zero(t, residency)
 		m.AssertExpectations(t)
 	})
 
-	t.Run("TSCDeltaZero", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				maxFreqClockCount: 200,
-				timestampCounter:  0,
-			},
-			err: nil,
+	t.Run("CounterNotFoundFallbackToCPUIdle", func(t *testing.T) {
+		mPerf := &pmuCounterMock{}
+		mPerf.On("getTimestampDelta", cpuID).Return(interval, nil).Once()
+		mPerf.On("getCounterDeltas", cpuID).Return(map[pmuCounter]uint64{}, nil).Once()
+
+		mCPUIdle := &cpuIdleMock{}
+		mCPUIdle.On("getTimestampDelta", cpuID).Return(interval, nil).Once()
+		mCPUIdle.On("getStateDeltas", cpuID).Return(map[string]cpuIdleStateDelta{"C1E": {usage: 10, time: 250000}}, nil).Once()
+
+		pt := &PowerTelemetry{
+			cstatePerf: mPerf,
+			cpuIdle:    mCPUIdle,
 		}
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("timestamp counter offset delta is zero for CPU ID")
 
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+		residency, err := pt.GetCPUC1StateResidency(cpuID)
+		require.NoError(t, err)
+		require.InDelta(t, 25.0, residency, 1e-9)
+		mPerf.AssertExpectations(t)
+		mCPUIdle.AssertExpectations(t)
+	})
+
+	t.Run("TSCFrequencyNotAvailable", func(t *testing.T) {
+		setTSCFrequency(t, 0)
+
+		m := &pmuCounterMock{}
+		m.On("getTimestampDelta", cpuID).Return(interval, nil).Once()
+		m.On("getCounterDeltas", cpuID).Return(deltas, nil).Once()
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			msr: m,
+			cstatePerf: m,
 		}
 
-		out, err := pt.GetCPUC0StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		residency, err := pt.GetCPUC6StateResidency(cpuID)
+		require.ErrorContains(t, err, "nominal frequency of the time stamp counter is not available")
+		require.Zero(t, residency)
 		m.AssertExpectations(t)
 	})
 
-	t.Run("MperfOffsetDeltaNotFound", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				timestampCounter: 5000,
-			},
-			err: nil,
+	t.Run("Ok", func(t *testing.T) {
+		setTSCFrequency(t, 2_000_000_000)
+
+		expected := map[string]float64{
+			"C1": 10.0,
+			"C3": 5.0,
+			"C6": 50.0,
+			"C7": 0.0,
 		}
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("mperf offset delta not found for CPU ID")
+		for state, getter := range getters {
+			t.Run(state, func(t *testing.T) {
+				m := &pmuCounterMock{}
+				m.On("getTimestampDelta", cpuID).Return(interval, nil).Once()
+				m.On("getCounterDeltas", cpuID).Return(deltas, nil).Once()
 
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+				// cpuidle is not used while the counter of cstate_core PMU is available.
+				pt := &PowerTelemetry{
+					cstatePerf: m,
+					cpuIdle:    &cpuIdleMock{},
+				}
 
-		// power telemetry instance definition
-		pt := &PowerTelemetry{
-			msr: m,
+				residency, err := getter(pt, cpuID)
+				require.NoError(t, err)
+				require.InDelta(t, expected[state], residency, 1e-9)
+				m.AssertExpectations(t)
+			})
 		}
+	})
+}
 
-		out, err := pt.GetCPUC0StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
-		m.AssertExpectations(t)
+func TestGetPerfMsrMetrics(t *testing.T) {
+	cpuID := 1
+	deltas := map[pmuCounter]uint64{
+		{pmu: msrPMU, event: msrPMUTsc}:   2_000_000_000,
+		{pmu: msrPMU, event: msrPMUAperf}: 900_000_000,
+		{pmu: msrPMU, event: msrPMUMperf}: 600_000_000,
+	}
+
+	t.Run("C0StateResidency", func(t *testing.T) {
+		t.Run("FailedToGetCounterDeltas", func(t *testing.T) {
+			m := &pmuCounterMock{}
+			m.On("getCounterDeltas", cpuID).Return(map[pmuCounter]uint64(nil), errors.New("mock error")).Once()
+
+			pt := &PowerTelemetry{
+				msrPerf: m,
+			}
+
+			c0, err := pt.GetCPUC0StateResidency(cpuID)
+			require.ErrorContains(t, err, fmt.Sprintf("error retrieving counter deltas for CPU ID %v", cpuID))
+			require.Zero(t, c0)
+			m.AssertExpectations(t)
+		})
+
+		t.Run("CounterNotFound", func(t *testing.T) {
+			m := &pmuCounterMock{}
+			m.On("getCounterDeltas", cpuID).Return(map[pmuCounter]uint64{
+				{pmu: msrPMU, event: msrPMUTsc}: 1000,
+			}, nil).Once()
+
+			pt := &PowerTelemetry{
+				msrPerf: m,
+			}
+
+			c0, err := pt.GetCPUC0StateResidency(cpuID)
+			require.ErrorIs(t, err, errPMUCounterNotFound)
+			require.ErrorContains(t, err, "event \"mperf\" of msr PMU")
+			require.Zero(t, c0)
+			m.AssertExpectations(t)
+		})
+
+		t.Run("ZeroTscDelta", func(t *testing.T) {
+			m := &pmuCounterMock{}
+			m.On("getCounterDeltas", cpuID).Return(map[pmuCounter]uint64{
+				{pmu: msrPMU, event: msrPMUTsc}:   0,
+				{pmu: msrPMU, event: msrPMUMperf}: 0,
+			}, nil).Once()
+
+			pt := &PowerTelemetry{
+				msrPerf: m,
+			}
+
+			c0, err := pt.GetCPUC0StateResidency(cpuID)
+			require.ErrorContains(t, err, fmt.Sprintf("tsc counter delta is zero for CPU ID: %v", cpuID))
+			require.Zero(t, c0)
+			m.AssertExpectations(t)
+		})
+
+		t.Run("Ok", func(t *testing.T) {
+			m := &pmuCounterMock{}
+			m.On("getCounterDeltas", cpuID).Return(deltas, nil).Once()
+
+			pt := &PowerTelemetry{
+				msrPerf: m,
+			}
+
+			c0, err := pt.GetCPUC0StateResidency(cpuID)
+			require.NoError(t, err)
+			require.InDelta(t, 30.0, c0, 1e-9)
+			m.AssertExpectations(t)
+		})
 	})
 
-	t.Run("TSCOffsetDeltaNotFound", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				maxFreqClockCount: 200,
-			},
-			err: nil,
-		}
+	t.Run("BusyFrequencyMhz", func(t *testing.T) {
+		t.Run("FailedToGetTimestampDelta", func(t *testing.T) {
+			m := &pmuCounterMock{}
+			m.On("getTimestampDelta", cpuID).Return(time.Duration(0), errors.New("mock error")).Once()
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("timestamp counter offset delta not found for CPU ID")
+			pt := &PowerTelemetry{
+				msrPerf: m,
+			}
 
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+			freq, err := pt.GetCPUBusyFrequencyMhz(cpuID)
+			require.ErrorContains(t, err, fmt.Sprintf("error retrieving timestamp delta for CPU ID %v", cpuID))
+			require.Zero(t, freq)
+			m.AssertExpectations(t)
+		})
+
+		t.Run("ZeroTimestampDelta", func(t *testing.T) {
+			m := &pmuCounterMock{}
+			m.On("getTimestampDelta", cpuID).Return(time.Duration(0), nil).Once()
+
+			pt := &PowerTelemetry{
+				msrPerf: m,
+			}
+
+			freq, err := pt.GetCPUBusyFrequencyMhz(cpuID)
+			require.ErrorContains(t, err, "timestamp delta must be greater than zero")
+			require.Zero(t, freq)
+			m.AssertExpectations(t)
+		})
+
+		t.Run("CounterNotFound", func(t *testing.T) {
+			m := &pmuCounterMock{}
+			m.On("getTimestampDelta", cpuID).Return(time.Second, nil).Once()
+			m.On("getCounterDeltas", cpuID).Return(map[pmuCounter]uint64{
+				{pmu: msrPMU, event: msrPMUTsc}: 1000,
+			}, nil).Once()
+
+			pt := &PowerTelemetry{
+				msrPerf: m,
+			}
+
+			freq, err := pt.GetCPUBusyFrequencyMhz(cpuID)
+			require.ErrorIs(t, err, errPMUCounterNotFound)
+			require.ErrorContains(t, err, "event \"aperf\" of msr PMU")
+			require.Zero(t, freq)
+			m.AssertExpectations(t)
+		})
+
+		t.Run("ZeroMperfDelta", func(t *testing.T) {
+			m := &pmuCounterMock{}
+			m.On("getTimestampDelta", cpuID).Return(time.Second, nil).Once()
+			m.On("getCounterDeltas", cpuID).Return(map[pmuCounter]uint64{
+				{pmu: msrPMU, event: msrPMUTsc}:   1000,
+				{pmu: msrPMU, event: msrPMUAperf}: 0,
+				{pmu: msrPMU, event: msrPMUMperf}: 0,
+			}, nil).Once()
+
+			pt := &PowerTelemetry{
+				msrPerf: m,
+			}
+
+			freq, err := pt.GetCPUBusyFrequencyMhz(cpuID)
+			require.ErrorContains(t, err, fmt.Sprintf("mperf counter delta is zero for CPU ID: %v", cpuID))
+			require.Zero(t, freq)
+			m.AssertExpectations(t)
+		})
+
+		t.Run("Ok", func(t *testing.T) {
+			m := &pmuCounterMock{}
+			m.On("getTimestampDelta", cpuID).Return(time.Second, nil).Once()
+			m.On("getCounterDeltas", cpuID).Return(deltas, nil).Once()
+
+			pt := &PowerTelemetry{
+				msrPerf: m,
+			}
+
+			// 2000 MHz of TSC scaled by aperf/mperf ratio of 1.5.
+			freq, err := pt.GetCPUBusyFrequencyMhz(cpuID)
+			require.NoError(t, err)
+			require.InDelta(t, 3000.0, freq, 1e-6)
+			m.AssertExpectations(t)
+		})
+	})
+}
+
+func TestGetPackageCStateResidency(t *testing.T) {
+	packageID := 0
+	cpuID := 2
+
+	t.Run("PerfCStatesIsNil", func(t *testing.T) {
+		pt := &PowerTelemetry{}
+
+		residency, err := pt.GetPackageCStateResidency(packageID, "C6")
+		require.ErrorContains(t, err, "\"perf_cstates\" is not initialized")
+		require.Zero(t, residency)
+	})
+
+	t.Run("CPUIDNotFound", func(t *testing.T) {
+		mTopology := &topologyMock{}
+		mTopology.On("getCPUPackageID", cpuID).Return(1, nil).Once()
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			msr: m,
+			topology:   mTopology,
+			cstatePerf: &pmuCounterMock{},
+			cpus:       []int{cpuID},
 		}
 
-		out, err := pt.GetCPUC0StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
-		m.AssertExpectations(t)
+		residency, err := pt.GetPackageCStateResidency(packageID, "C6")
+		require.ErrorContains(t, err, fmt.Sprintf("could not find CPU ID for package ID %v", packageID))
+		require.Zero(t, residency)
+		mTopology.AssertExpectations(t)
 	})
 
-	t.Run("C0State2Per", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				maxFreqClockCount: 100000000,
-				timestampCounter:  5000000000,
-			},
-			err: nil,
+	t.Run("Ok", func(t *testing.T) {
+		orig := tscFrequency
+		tscFrequency = func() uint64 {
+			return 2_000_000_000
 		}
+		t.Cleanup(func() {
+			tscFrequency = orig
+		})
 
-		// expected output
-		expectedResult := 2.0
+		mTopology := &topologyMock{}
+		mTopology.On("getCPUPackageID", cpuID).Return(packageID, nil).Once()
 
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+		m := &pmuCounterMock{}
+		m.On("getTimestampDelta", cpuID).Return(time.Second, nil).Once()
+		m.On("getCounterDeltas", cpuID).Return(map[pmuCounter]uint64{
+			{pmu: cstateCorePMU, event: "c6-residency"}: 1_000_000_000,
+			{pmu: cstatePkgPMU, event: "c6-residency"}:  500_000_000,
+		}, nil).Once()
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			msr: m,
+			topology:   mTopology,
+			cstatePerf: m,
+			cpus:       []int{cpuID},
 		}
 
-		out, err := pt.GetCPUC0StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
+		residency, err := pt.GetPackageCStateResidency(packageID, "C6")
 		require.NoError(t, err)
+		require.InDelta(t, 25.0, residency, 1e-9)
+		mTopology.AssertExpectations(t)
 		m.AssertExpectations(t)
 	})
 }
 
-func TestGetCPUC1StateResidency(t *testing.T) {
+func TestUpdatePerCPUMetrics(t *testing.T) {
 	t.Run("MsrIsNil", func(t *testing.T) {
-		cpuID := 1
-		c1Exp := 0.0
+		cpuID := 0
 
-		pt := PowerTelemetry{
-			topology: &topologyData{},
-		}
+		pt := &PowerTelemetry{}
 
-		c1Out, err := pt.GetCPUC1StateResidency(cpuID)
-		require.Equal(t, c1Exp, c1Out)
+		err := pt.UpdatePerCPUMetrics(cpuID)
 		require.ErrorContains(t, err, "\"msr\" is not initialized")
 	})
 
-	t.Run("InvalidCPUID", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 1
-		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: nil,
-			err:    errors.New("CPU ID 1 not found"),
-		}
-
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("error retrieving offset deltas for CPU ID")
-
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+	t.Run("ThermalThrottle", func(t *testing.T) {
+		cpuID := 0
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
-		}
+		m := &thermalThrottleMock{}
+		m.On("update", cpuID).Return(errors.New("mock error")).Once()
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			throttle: m,
 		}
 
-		out, err := pt.GetCPUC1StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		err := pt.UpdatePerCPUMetrics(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("error updating thermal throttle for CPU ID %v", cpuID))
 		m.AssertExpectations(t)
 	})
 
-	t.Run("MperfOffsetDeltaNotFound", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				timestampCounter: 5000,
-			},
-			err: nil,
-		}
+	t.Run("PerfCStates", func(t *testing.T) {
+		t.Run("FailedToUpdate", func(t *testing.T) {
+			cpuID := 0
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("mperf offset delta not found for CPU ID")
+			m := &pmuCounterMock{}
+			m.On("update", cpuID).Return(errors.New("mock error")).Once()
 
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+			pt := &PowerTelemetry{
+				cstatePerf: m,
+			}
+
+			err := pt.UpdatePerCPUMetrics(cpuID)
+			require.ErrorContains(t, err, fmt.Sprintf("error updating C-state residency counters for CPU ID %v", cpuID))
+			m.AssertExpectations(t)
+		})
+
+		t.Run("MsrIsNil", func(t *testing.T) {
+			cpuID := 0
+
+			m := &pmuCounterMock{}
+			m.On("update", cpuID).Return(nil).Once()
+
+			pt := &PowerTelemetry{
+				cstatePerf: m,
+			}
+
+			require.NoError(t, pt.UpdatePerCPUMetrics(cpuID))
+			m.AssertExpectations(t)
+		})
+	})
+
+	t.Run("PerfMsr", func(t *testing.T) {
+		t.Run("FailedToUpdate", func(t *testing.T) {
+			cpuID := 0
+
+			m := &pmuCounterMock{}
+			m.On("update", cpuID).Return(errors.New("mock error")).Once()
+
+			pt := &PowerTelemetry{
+				msrPerf: m,
+			}
+
+			err := pt.UpdatePerCPUMetrics(cpuID)
+			require.ErrorContains(t, err, fmt.Sprintf("error updating msr PMU counters for CPU ID %v", cpuID))
+			m.AssertExpectations(t)
+		})
+
+		t.Run("MsrIsNil", func(t *testing.T) {
+			cpuID := 0
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
-		}
+			m := &pmuCounterMock{}
+			m.On("update", cpuID).Return(nil).Once()
 
-		// power telemetry instance definition
-		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
-		}
+			pt := &PowerTelemetry{
+				msrPerf: m,
+			}
 
-		out, err := pt.GetCPUC1StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
-		m.AssertExpectations(t)
+			require.NoError(t, pt.UpdatePerCPUMetrics(cpuID))
+			m.AssertExpectations(t)
+		})
 	})
 
-	t.Run("C3OffsetDeltaNotFound", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				timestampCounter:  5000,
-				maxFreqClockCount: 100,
-			},
-			err: nil,
-		}
+	t.Run("CPUIdle", func(t *testing.T) {
+		t.Run("FailedToUpdate", func(t *testing.T) {
+			cpuID := 0
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("c3 state residency offset delta not found for CPU ID")
+			m := &cpuIdleMock{}
+			m.On("update", cpuID).Return(errors.New("mock error")).Once()
 
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+			pt := &PowerTelemetry{
+				cpuIdle: m,
+			}
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
-		}
+			err := pt.UpdatePerCPUMetrics(cpuID)
+			require.ErrorContains(t, err, fmt.Sprintf("error updating idle states for CPU ID %v", cpuID))
+			m.AssertExpectations(t)
+		})
 
-		// power telemetry instance definition
-		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
-		}
+		t.Run("MsrIsNil", func(t *testing.T) {
+			cpuID := 0
 
-		out, err := pt.GetCPUC1StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
-		m.AssertExpectations(t)
-	})
+			m := &cpuIdleMock{}
+			m.On("update", cpuID).Return(nil).Once()
 
-	t.Run("C6OffsetDeltaNotFound", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				timestampCounter:  5000,
-				maxFreqClockCount: 100,
-				c3Residency:       100,
-			},
-			err: nil,
-		}
+			pt := &PowerTelemetry{
+				cpuIdle: m,
+			}
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("c6 state residency offset delta not found for CPU ID")
+			require.NoError(t, pt.UpdatePerCPUMetrics(cpuID))
+			m.AssertExpectations(t)
+		})
 
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+		t.Run("WithThermalThrottle", func(t *testing.T) {
+			cpuID := 0
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
-		}
+			mCPUIdle := &cpuIdleMock{}
+			mCPUIdle.On("update", cpuID).Return(nil).Once()
 
-		// power telemetry instance definition
-		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
-		}
+			mThrottle := &thermalThrottleMock{}
+			mThrottle.On("update", cpuID).Return(nil).Once()
 
-		out, err := pt.GetCPUC1StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
-		m.AssertExpectations(t)
-	})
+			pt := &PowerTelemetry{
+				cpuIdle:  mCPUIdle,
+				throttle: mThrottle,
+			}
 
-	t.Run("C7OffsetDeltaNotFound", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				timestampCounter:  5000,
-				maxFreqClockCount: 100,
-				c3Residency:       100,
-				c6Residency:       100,
-			},
-			err: nil,
-		}
+			require.NoError(t, pt.UpdatePerCPUMetrics(cpuID))
+			mCPUIdle.AssertExpectations(t)
+			mThrottle.AssertExpectations(t)
+		})
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("c7 state residency offset delta not found for CPU ID")
+		t.Run("WithMsr", func(t *testing.T) {
+			cpuID := 0
 
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+			mCPUIdle := &cpuIdleMock{}
+			mCPUIdle.On("update", cpuID).Return(nil).Once()
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
-		}
+			mMsr := &msrMock{}
+			mMsr.On("update", cpuID).Return(nil).Once()
+			mMsr.On("getOffsetDeltas", cpuID).Return(
+				map[uint32]uint64{
+					timestampCounter:  300,
+					maxFreqClockCount: 100,
+					c3Residency:       0,
+					c6Residency:       100,
+					c7Residency:       0,
+				}, nil,
+			).Once()
 
-		// power telemetry instance definition
-		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
-		}
+			pt := &PowerTelemetry{
+				msr:     mMsr,
+				cpuIdle: mCPUIdle,
+			}
 
-		out, err := pt.GetCPUC1StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
-		m.AssertExpectations(t)
-	})
+			require.NoError(t, pt.UpdatePerCPUMetrics(cpuID))
+			mCPUIdle.AssertExpectations(t)
+			mMsr.AssertExpectations(t)
+		})
 
-	t.Run("TSCOffsetDeltaNotFound", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				maxFreqClockCount: 100,
-				c3Residency:       100,
-				c6Residency:       100,
-				c7Residency:       100,
-			},
-			err: nil,
-		}
+		t.Run("FailedToUpdateWithMsr", func(t *testing.T) {
+			cpuID := 0
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("timestamp counter offset delta not found for CPU ID")
+			mCPUIdle := &cpuIdleMock{}
+			mCPUIdle.On("update", cpuID).Return(errors.New("mock error")).Once()
 
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+			mThrottle := &thermalThrottleMock{}
+			mThrottle.On("update", cpuID).Return(errors.New("mock error")).Once()
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
-		}
+			mMsr := &msrMock{}
+			mMsr.On("update", cpuID).Return(nil).Once()
+			mMsr.On("getOffsetDeltas", cpuID).Return(
+				map[uint32]uint64{
+					timestampCounter:  300,
+					maxFreqClockCount: 100,
+					c3Residency:       0,
+					c6Residency:       100,
+					c7Residency:       0,
+				}, nil,
+			).Once()
 
-		// power telemetry instance definition
-		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
-		}
+			pt := &PowerTelemetry{
+				msr:      mMsr,
+				cpuIdle:  mCPUIdle,
+				throttle: mThrottle,
+			}
 
-		out, err := pt.GetCPUC1StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
-		m.AssertExpectations(t)
+			// msr is updated despite the errors of the other subsystems, which are joined.
+			err := pt.UpdatePerCPUMetrics(cpuID)
+			require.ErrorContains(t, err, fmt.Sprintf("error updating idle states for CPU ID %v", cpuID))
+			require.ErrorContains(t, err, fmt.Sprintf("error updating thermal throttle for CPU ID %v", cpuID))
+			mCPUIdle.AssertExpectations(t)
+			mThrottle.AssertExpectations(t)
+			mMsr.AssertExpectations(t)
+		})
 	})
 
-	t.Run("TSCDeltaZero", func(t *testing.T) {
-		// input arguments for test case
+	t.Run("FailedToUpdate", func(t *testing.T) {
 		cpuID := 0
-		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				timestampCounter:  0,
-				maxFreqClockCount: 100,
-				c3Residency:       100,
-				c6Residency:       100,
-				c7Residency:       100,
-			},
-			err: nil,
-		}
-
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("timestamp counter offset delta is zero for CPU ID")
+		errExpected := errors.New("error while updating storage")
 
-		// msr definition
 		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
-		}
+		// mock updating offset deltas.
+		m.On("update", cpuID).Return(errExpected).Once()
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			msr: m,
 		}
 
-		out, err := pt.GetCPUC1StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		err := pt.UpdatePerCPUMetrics(cpuID)
+		require.ErrorContains(t, err, errExpected.Error())
 		m.AssertExpectations(t)
 	})
 
-	t.Run("C1Status20Per", func(t *testing.T) {
-		// input arguments for test case
+	t.Run("FailedToGetDeltas", func(t *testing.T) {
 		cpuID := 0
-		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				timestampCounter:  500,
-				maxFreqClockCount: 100,
-				c3Residency:       100,
-				c6Residency:       100,
-				c7Residency:       100,
-			},
-			err: nil,
-		}
-
-		// expected output
-		expectedResult := 20.0
-
-		// msr definition
 		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
-		}
+		// mock updating offset deltas.
+		m.On("update", cpuID).Return(nil).Once()
+
+		// mock getting offset deltas.
+		m.On("getOffsetDeltas", cpuID).Return(nil, errors.New("mock error")).Once()
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			msr: m,
 		}
 
-		out, err := pt.GetCPUC1StateResidency(cpuID)
-		require.InDelta(t, expectedResult, out, 0.1)
-		require.NoError(t, err)
+		err := pt.UpdatePerCPUMetrics(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("error retrieving offset deltas for CPU ID %v", cpuID))
 		m.AssertExpectations(t)
 	})
 
-	t.Run("UnsupportedModel", func(t *testing.T) {
-		// input arguments for test case
+	t.Run("SumDeltasOverflow", func(t *testing.T) {
 		cpuID := 0
-		cpuModel := cpumodel.INTEL_FAM6_CORE2_MEROM
-
-		// expected output
-		expectedResult := 0.0
-		expectedErr := fmt.Errorf("c1 state residency metric not supported by CPU model: 0x%X", cpuModel)
-
-		// msr definition
 		m := &msrMock{}
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
-		}
+		// mock updating offset deltas.
+		m.On("update", cpuID).Return(nil).Once()
+
+		// mock getting offset deltas.
+		m.On("getOffsetDeltas", cpuID).Return(
+			map[uint32]uint64{
+				timestampCounter:  300,
+				maxFreqClockCount: math.MaxUint64,
+				c3Residency:       1,
+				c6Residency:       0,
+				c7Residency:       0,
+			}, nil,
+		).Once()
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			msr: m,
 		}
 
-		out, err := pt.GetCPUC1StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		err := pt.UpdatePerCPUMetrics(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("sum of deltas caused overflow for CPU ID %v", cpuID))
 		m.AssertExpectations(t)
 	})
 
-	t.Run("InvalidCPUModel", func(t *testing.T) {
-		// input arguments for test case
+	t.Run("FailedToScale", func(t *testing.T) {
 		cpuID := 0
+		m := &msrMock{}
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("c1 state residency metric not supported by CPU model: 0x0")
+		// mock updating offset deltas.
+		m.On("update", cpuID).Return(nil).Once()
 
-		// msr definition
-		m := &msrMock{}
+		// mock getting offset deltas.
+		m.On("getOffsetDeltas", cpuID).Return(
+			map[uint32]uint64{
+				timestampCounter:  300,
+				maxFreqClockCount: 300,
+				c3Residency:       100,
+				c6Residency:       100,
+				c7Residency:       100,
+			}, nil,
+		).Once()
 
-		// topology definition
-		topo := &topologyData{}
+		sumDeltaBig := new(big.Float).SetUint64(600)
+		tscDeltaBig := new(big.Float).SetUint64(300)
+		fBig := new(big.Float).Quo(tscDeltaBig, sumDeltaBig)
+
+		// mock scaling offset deltas.
+		m.On("scaleOffsetDeltas", cpuID, []uint32{
+			maxFreqClockCount,
+			c3Residency,
+			c6Residency,
+			c7Residency,
+		}, fBig).Return(errors.New("mock error")).Once()
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			msr: m,
 		}
 
-		out, err := pt.GetCPUC1StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		err := pt.UpdatePerCPUMetrics(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("error scaling offset deltas for CPU ID %v", cpuID))
 		m.AssertExpectations(t)
 	})
-}
 
-func TestGetCPUC3StateResidency(t *testing.T) {
-	t.Run("MsrIsNil", func(t *testing.T) {
-		cpuID := 1
-		c3Exp := 0.0
+	t.Run("CounterReset", func(t *testing.T) {
+		cpuID := 0
+		resetErr := &CounterResetError{CPUID: cpuID, Offsets: []uint32{c6Residency}}
 
-		pt := PowerTelemetry{
-			topology: &topologyData{},
+		m := &msrMock{}
+		m.On("update", cpuID).Return(resetErr).Once()
+		m.On("getOffsetDeltas", cpuID).Return(map[uint32]uint64{
+			timestampCounter:  500,
+			maxFreqClockCount: 100,
+			c3Residency:       100,
+			c6Residency:       0,
+			c7Residency:       100,
+		}, nil).Once()
+
+		pt := &PowerTelemetry{
+			msr: m,
 		}
 
-		c3Out, err := pt.GetCPUC3StateResidency(cpuID)
-		require.Equal(t, c3Exp, c3Out)
-		require.ErrorContains(t, err, "\"msr\" is not initialized")
+		err := pt.UpdatePerCPUMetrics(cpuID)
+		var errOut *CounterResetError
+		require.ErrorAs(t, err, &errOut)
+		require.Equal(t, resetErr, errOut)
+		m.AssertExpectations(t)
 	})
 
-	t.Run("InvalidCPUID", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 1
-		cpuModel := cpumodel.INTEL_FAM6_HASWELL
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: nil,
-			err:    errors.New("CPU ID 1 not found"),
+	t.Run("WithoutScalingDeltas", func(t *testing.T) {
+		cpuID := 0
+		msrOffsets := []uint32{
+			timestampCounter,
+			maxFreqClockCount,
+			c3Residency,
+			c6Residency,
+			c7Residency,
 		}
 
-		// expected output
-		expectedErr := errors.New("error retrieving offset deltas for CPU ID")
-		expectedResult := 0.0
+		offsetDeltas := map[uint32]uint64{
+			timestampCounter:  500,
+			maxFreqClockCount: 100,
+			c3Residency:       100,
+			c6Residency:       100,
+			c7Residency:       100,
+		}
 
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+		m := &msrRegMock{}
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
-		}
+		// mock reading all msr offset values.
+		m.On("readAll", msrOffsets).Return(offsetDeltas, nil).Once()
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			msr: &msrDataWithStorage{
+				msrMap: map[int]msrRegWithStorage{
+					cpuID: &msrWithStorage{
+						msrReg: m,
+
+						offsets:      msrOffsets,
+						offsetValues: map[uint32]uint64{},
+						offsetDeltas: map[uint32]uint64{},
+					},
+				},
+			},
 		}
 
-		out, err := pt.GetCPUC3StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		require.NoError(t, pt.UpdatePerCPUMetrics(cpuID))
+
+		offsetDeltasOut, err := pt.msr.getOffsetDeltas(cpuID)
+		require.NoError(t, err)
+		require.Equal(t, offsetDeltas, offsetDeltasOut)
 		m.AssertExpectations(t)
 	})
 
-	t.Run("C3OffsetDeltaNotFound", func(t *testing.T) {
-		// input arguments for test case
+	t.Run("WithScaledDeltas", func(t *testing.T) {
 		cpuID := 0
-		cpuModel := cpumodel.INTEL_FAM6_HASWELL
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				timestampCounter: 5000,
-			},
-			err: nil,
+		msrOffsets := []uint32{
+			timestampCounter,
+			maxFreqClockCount,
+			c3Residency,
+			c6Residency,
+			c7Residency,
 		}
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("c3 state residency offset delta not found for CPU ID")
+		offsetDeltas := map[uint32]uint64{
+			timestampCounter:  300,
+			maxFreqClockCount: 300,
+			c3Residency:       100,
+			c6Residency:       100,
+			c7Residency:       100,
+		}
 
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+		m := &msrRegMock{}
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
-		}
+		// mock reading all msr offset values.
+		m.On("readAll", msrOffsets).Return(offsetDeltas, nil).Once()
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			msr: &msrDataWithStorage{
+				msrMap: map[int]msrRegWithStorage{
+					cpuID: &msrWithStorage{
+						msrReg: m,
+
+						offsets:      msrOffsets,
+						offsetValues: map[uint32]uint64{},
+						offsetDeltas: map[uint32]uint64{},
+					},
+				},
+			},
 		}
 
-		out, err := pt.GetCPUC3StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		require.NoError(t, pt.UpdatePerCPUMetrics(cpuID))
+
+		offsetDeltasOut, err := pt.msr.getOffsetDeltas(cpuID)
+		require.NoError(t, err)
+		require.Equal(t, map[uint32]uint64{
+			timestampCounter:  300,
+			maxFreqClockCount: 150,
+			c3Residency:       50,
+			c6Residency:       50,
+			c7Residency:       50,
+		}, offsetDeltasOut)
+
 		m.AssertExpectations(t)
 	})
+}
 
-	t.Run("C3State4Per", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		cpuModel := cpumodel.INTEL_FAM6_HASWELL
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				c3Residency:      200000000,
-				timestampCounter: 5000000000,
-			},
-			err: nil,
+func TestUpdateAllCPUMetrics(t *testing.T) {
+	t.Run("ModulesNotInitialized", func(t *testing.T) {
+		pt := &PowerTelemetry{
+			cpus: []int{0, 1},
 		}
 
-		// expected output
-		expectedResult := 4.0
+		err := pt.UpdateAllCPUMetrics(context.Background())
+		require.ErrorContains(t, err, "\"msr\" is not initialized")
+	})
 
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+	t.Run("Valid", func(t *testing.T) {
+		cpus := []int{0, 1, 2, 3, 4, 5, 6, 7}
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
+		m := &cpuIdleMock{}
+		for _, cpuID := range cpus {
+			m.On("update", cpuID).Return(nil).Once()
 		}
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			cpuIdle:       m,
+			cpus:          cpus,
+			updateWorkers: 3,
 		}
 
-		out, err := pt.GetCPUC3StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.NoError(t, err)
+		require.NoError(t, pt.UpdateAllCPUMetrics(context.Background()))
 		m.AssertExpectations(t)
 	})
 
-	t.Run("TSCOffsetDeltaNotFound", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		cpuModel := cpumodel.INTEL_FAM6_HASWELL
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				c3Residency: 200000000,
-			},
-			err: nil,
+	t.Run("BoundedWorkers", func(t *testing.T) {
+		cpus := make([]int, 64)
+		for i := range cpus {
+			cpus[i] = i
 		}
+		workers := 4
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("timestamp counter offset delta not found for CPU ID")
+		var mu sync.Mutex
+		running, maxRunning := 0, 0
+		m := &cpuIdleMock{}
+		m.On("update", mock.AnythingOfType("int")).Run(func(mock.Arguments) {
+			mu.Lock()
+			running++
+			maxRunning = max(maxRunning, running)
+			mu.Unlock()
 
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+			time.Sleep(time.Millisecond)
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
-		}
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}).Return(nil).Times(len(cpus))
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			cpuIdle:       m,
+			cpus:          cpus,
+			updateWorkers: workers,
 		}
 
-		out, err := pt.GetCPUC3StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		require.NoError(t, pt.UpdateAllCPUMetrics(context.Background()))
+		require.LessOrEqual(t, maxRunning, workers)
 		m.AssertExpectations(t)
 	})
 
-	t.Run("TSCDeltaZero", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		cpuModel := cpumodel.INTEL_FAM6_HASWELL
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				c3Residency:      200,
-				timestampCounter: 0,
-			},
-			err: nil,
-		}
-
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("timestamp counter offset delta is zero for CPU ID")
-
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+	t.Run("FailedToUpdateSomeCPUs", func(t *testing.T) {
+		cpus := []int{0, 1, 2, 3}
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
-		}
+		m := &cpuIdleMock{}
+		m.On("update", 0).Return(nil).Once()
+		m.On("update", 1).Return(errors.New("mock error 1")).Once()
+		m.On("update", 2).Return(nil).Once()
+		m.On("update", 3).Return(errors.New("mock error 3")).Once()
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			cpuIdle: m,
+			cpus:    cpus,
 		}
 
-		out, err := pt.GetCPUC3StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		err := pt.UpdateAllCPUMetrics(context.Background())
+		require.ErrorContains(t, err, "failed to update metrics for 2 out of 4 CPU IDs")
+
+		var updateErr *UpdateError
+		require.ErrorAs(t, err, &updateErr)
+		require.Len(t, updateErr.Errors, 2)
+		require.Equal(t, 1, updateErr.Errors[0].CPUID)
+		require.ErrorContains(t, updateErr.Errors[0].Err, "mock error 1")
+		require.Equal(t, 3, updateErr.Errors[1].CPUID)
+		require.ErrorContains(t, updateErr.Errors[1].Err, "mock error 3")
+
+		var cpuErr *CPUUpdateError
+		require.ErrorAs(t, err, &cpuErr)
+		require.Equal(t, 1, cpuErr.CPUID)
 		m.AssertExpectations(t)
 	})
 
-	t.Run("UnsupportedModel", func(t *testing.T) {
-		// input arguments for test case
-		cpuModel := cpumodel.INTEL_FAM6_CORE2_MEROM
-		cpuID := 0
-
-		// expected output
-		expectedResult := 0.0
-		expectedErr := fmt.Errorf("c3 state residency metric not supported by CPU model: 0x%X", cpuModel)
+	t.Run("WithMsr", func(t *testing.T) {
+		cpus := []int{0, 1, 2, 3}
+		deltas := map[uint32]uint64{
+			timestampCounter:  300,
+			maxFreqClockCount: 100,
+			c3Residency:       0,
+			c6Residency:       100,
+			c7Residency:       0,
+		}
 
-		// msr definition
-		m := &msrMock{}
+		mCPUIdle := &cpuIdleMock{}
+		mMsr := &msrMock{}
+		mMsr.On("isBatchAvailable").Return(false).Once()
+		for _, cpuID := range cpus {
+			mCPUIdle.On("update", cpuID).Return(nil).Once()
+		}
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
+		// msr storage is updated per CPU ID by the workers.
+		mMsr.On("update", 0).Return(nil).Once()
+		mMsr.On("update", 1).Return(errors.New("mock error 1")).Once()
+		mMsr.On("update", 2).Return(&CounterResetError{CPUID: 2, Offsets: []uint32{c6Residency}}).Once()
+		mMsr.On("update", 3).Return(nil).Once()
+		for _, cpuID := range []int{0, 2, 3} {
+			mMsr.On("getOffsetDeltas", cpuID).Return(deltas, nil).Once()
 		}
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			msr:           mMsr,
+			cpuIdle:       mCPUIdle,
+			cpus:          cpus,
+			updateWorkers: 2,
 		}
 
-		out, err := pt.GetCPUC3StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
-		m.AssertExpectations(t)
-	})
+		err := pt.UpdateAllCPUMetrics(context.Background())
 
-	t.Run("InvalidCPUModel", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
+		var updateErr *UpdateError
+		require.ErrorAs(t, err, &updateErr)
+		require.Len(t, updateErr.Errors, 2)
+		require.Equal(t, 1, updateErr.Errors[0].CPUID)
+		require.ErrorContains(t, updateErr.Errors[0].Err, "mock error 1")
+		require.Equal(t, 2, updateErr.Errors[1].CPUID)
+		var resetErr *CounterResetError
+		require.ErrorAs(t, updateErr.Errors[1].Err, &resetErr)
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("c3 state residency metric not supported by CPU model: 0x0")
+		mCPUIdle.AssertExpectations(t)
+		mMsr.AssertExpectations(t)
+		mMsr.AssertNotCalled(t, "updateAll")
+	})
 
-		// msr definition
-		m := &msrMock{}
+	t.Run("WithMsrBatch", func(t *testing.T) {
+		cpus := []int{0, 1, 2, 3}
+		deltas := map[uint32]uint64{
+			timestampCounter:  300,
+			maxFreqClockCount: 100,
+			c3Residency:       0,
+			c6Residency:       100,
+			c7Residency:       0,
+		}
 
-		// topology definition
-		topo := &topologyData{}
+		mCPUIdle := &cpuIdleMock{}
+		mMsr := &msrMock{}
+		mMsr.On("isBatchAvailable").Return(true).Once()
+		for _, cpuID := range cpus {
+			mCPUIdle.On("update", cpuID).Return(nil).Once()
+		}
+
+		// msr storage of all CPU IDs is updated with a single batch call, instead of one call per CPU ID.
+		mMsr.On("updateAll").Return(map[int]error{
+			1: errors.New("mock error 1"),
+			2: &CounterResetError{CPUID: 2, Offsets: []uint32{c6Residency}},
+		}).Once()
+		for _, cpuID := range []int{0, 2, 3} {
+			mMsr.On("getOffsetDeltas", cpuID).Return(deltas, nil).Once()
+		}
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			msr:           mMsr,
+			cpuIdle:       mCPUIdle,
+			cpus:          cpus,
+			updateWorkers: 2,
 		}
 
-		out, err := pt.GetCPUC3StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
-		m.AssertExpectations(t)
+		err := pt.UpdateAllCPUMetrics(context.Background())
+
+		var updateErr *UpdateError
+		require.ErrorAs(t, err, &updateErr)
+		require.Len(t, updateErr.Errors, 2)
+		require.Equal(t, 1, updateErr.Errors[0].CPUID)
+		require.ErrorContains(t, updateErr.Errors[0].Err, "mock error 1")
+		require.Equal(t, 2, updateErr.Errors[1].CPUID)
+		var resetErr *CounterResetError
+		require.ErrorAs(t, updateErr.Errors[1].Err, &resetErr)
+
+		mCPUIdle.AssertExpectations(t)
+		mMsr.AssertExpectations(t)
+		mMsr.AssertNotCalled(t, "update", mock.Anything)
 	})
-}
 
-func TestGetCPUC6StateResidency(t *testing.T) {
-	t.Run("MsrIsNil", func(t *testing.T) {
-		cpuID := 1
-		c6Exp := 0.0
+	t.Run("ContextCanceledBetweenCPUs", func(t *testing.T) {
+		cpus := []int{0, 1, 2}
 
-		pt := PowerTelemetry{
-			topology: &topologyData{},
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mMsr := &msrMock{}
+		mMsr.On("isBatchAvailable").Return(false).Once()
+		mMsr.On("update", 0).Return(nil).Run(func(mock.Arguments) { cancel() }).Once()
+		mMsr.On("getOffsetDeltas", 0).Return(map[uint32]uint64{timestampCounter: 100}, nil).Once()
+
+		pt := &PowerTelemetry{
+			msr:           mMsr,
+			cpus:          cpus,
+			updateWorkers: 1,
 		}
 
-		c6Out, err := pt.GetCPUC6StateResidency(cpuID)
-		require.Equal(t, c6Exp, c6Out)
-		require.ErrorContains(t, err, "\"msr\" is not initialized")
+		err := pt.UpdateAllCPUMetrics(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+
+		var updateErr *UpdateError
+		require.ErrorAs(t, err, &updateErr)
+		require.Len(t, updateErr.Errors, 2)
+		require.Equal(t, 1, updateErr.Errors[0].CPUID)
+		require.Equal(t, 2, updateErr.Errors[1].CPUID)
+		mMsr.AssertExpectations(t)
+		mMsr.AssertNotCalled(t, "update", 1)
+		mMsr.AssertNotCalled(t, "update", 2)
 	})
 
-	t.Run("InvalidCPUID", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 1
-		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: nil,
-			err:    errors.New("CPU ID 1 not found"),
+	t.Run("ContextCanceled", func(t *testing.T) {
+		cpus := []int{0, 1, 2}
+
+		m := &cpuIdleMock{}
+
+		pt := &PowerTelemetry{
+			cpuIdle: m,
+			cpus:    cpus,
 		}
 
-		// expected output
-		expectedErr := errors.New("error retrieving offset deltas for CPU ID")
-		expectedResult := 0.0
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
 
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+		err := pt.UpdateAllCPUMetrics(ctx)
+		require.ErrorIs(t, err, context.Canceled)
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
+		var updateErr *UpdateError
+		require.ErrorAs(t, err, &updateErr)
+		require.Len(t, updateErr.Errors, len(cpus))
+		for i, cpuErr := range updateErr.Errors {
+			require.Equal(t, cpus[i], cpuErr.CPUID)
+			require.ErrorIs(t, cpuErr, context.Canceled)
 		}
+		m.AssertNotCalled(t, "update", mock.Anything)
+	})
+}
 
-		// power telemetry instance definition
-		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
-		}
+func TestIsCPUSupported(t *testing.T) {
+	t.Run("True", func(t *testing.T) {
+		mTopology := &topologyMock{}
+		mTopology.On("getCPUFamily", 0).Return("6", nil).Once()
+		mTopology.On("getCPUVendor", 0).Return("GenuineIntel", nil).Once()
 
-		out, err := pt.GetCPUC6StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
-		m.AssertExpectations(t)
+		isSupported, err := isCPUSupported(mTopology)
+		require.True(t, isSupported)
+		require.NoError(t, err)
+		mTopology.AssertExpectations(t)
 	})
 
-	t.Run("C6OffsetDeltaNotFound", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				timestampCounter: 5000,
-			},
-			err: nil,
-		}
+	t.Run("False", func(t *testing.T) {
+		t.Run("FamilyNotIntel6", func(t *testing.T) {
+			mTopology := &topologyMock{}
+			mTopology.On("getCPUFamily", 0).Return("", nil).Once()
+			mTopology.On("getCPUVendor", 0).Return("GenuineIntel", nil).Once()
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("c6 state residency offset delta not found for CPU ID")
+			isSupported, err := isCPUSupported(mTopology)
+			require.False(t, isSupported)
+			require.NoError(t, err)
+			mTopology.AssertExpectations(t)
+		})
 
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+		t.Run("VendorNotGenuineIntel", func(t *testing.T) {
+			mTopology := &topologyMock{}
+			mTopology.On("getCPUFamily", 0).Return("6", nil).Once()
+			mTopology.On("getCPUVendor", 0).Return("AuthenticAMD", nil).Once()
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
-		}
+			isSupported, err := isCPUSupported(mTopology)
+			require.False(t, isSupported)
+			require.NoError(t, err)
+			mTopology.AssertExpectations(t)
+		})
+
+		t.Run("FailedToGetFamily", func(t *testing.T) {
+			mError := errors.New("mock error")
+			mTopology := &topologyMock{}
+			mTopology.On("getCPUFamily", 0).Return("", mError).Once()
+
+			isSupported, err := isCPUSupported(mTopology)
+			require.False(t, isSupported)
+			require.ErrorContains(t, err, mError.Error())
+			mTopology.AssertExpectations(t)
+		})
+
+		t.Run("FailedToGetVendor", func(t *testing.T) {
+			mError := errors.New("mock error")
+			mTopology := &topologyMock{}
+			mTopology.On("getCPUFamily", 0).Return("6", nil).Once()
+			mTopology.On("getCPUVendor", 0).Return("", mError).Once()
+
+			isSupported, err := isCPUSupported(mTopology)
+			require.False(t, isSupported)
+			require.ErrorContains(t, err, mError.Error())
+			mTopology.AssertExpectations(t)
+		})
+	})
+}
 
-		// power telemetry instance definition
-		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
-		}
+func TestGetMsrCounterDelta(t *testing.T) {
+	t.Run("MsrIsNil", func(t *testing.T) {
+		pt := &PowerTelemetry{}
 
-		out, err := pt.GetCPUC6StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
-		m.AssertExpectations(t)
+		delta, err := pt.GetMsrCounterDelta(0, "fixed_ctr0")
+		require.ErrorContains(t, err, "\"msr\" is not initialized")
+		require.Zero(t, delta)
 	})
 
-	t.Run("C6State4Per", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				c6Residency:      200000000,
-				timestampCounter: 5000000000,
-			},
-			err: nil,
-		}
-
-		// expected output
-		expectedResult := 4.0
+	t.Run("FailedToGetCounterDelta", func(t *testing.T) {
+		cpuID := 1
 
-		// msr definition
 		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
-
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
-		}
+		m.On("getCounterDelta", cpuID, "fixed_ctr0").Return(uint64(0), errors.New("mock error")).Once()
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			msr: m,
 		}
 
-		out, err := pt.GetCPUC6StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.NoError(t, err)
+		delta, err := pt.GetMsrCounterDelta(cpuID, "fixed_ctr0")
+		require.ErrorContains(t, err, fmt.Sprintf("error retrieving MSR counter delta for CPU ID %v: mock error", cpuID))
+		require.Zero(t, delta)
 		m.AssertExpectations(t)
 	})
 
-	t.Run("TSCOffsetDeltaNotFound", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				c6Residency: 200000000,
-			},
-			err: nil,
-		}
-
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("timestamp counter offset delta not found for CPU ID")
+	t.Run("Valid", func(t *testing.T) {
+		cpuID := 1
 
-		// msr definition
 		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
-
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
-		}
+		m.On("getCounterDelta", cpuID, "fixed_ctr0").Return(uint64(1000), nil).Once()
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			msr: m,
 		}
 
-		out, err := pt.GetCPUC6StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		delta, err := pt.GetMsrCounterDelta(cpuID, "fixed_ctr0")
+		require.NoError(t, err)
+		require.Equal(t, uint64(1000), delta)
 		m.AssertExpectations(t)
 	})
+}
 
-	t.Run("TSCDeltaZero", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				c6Residency:      200,
-				timestampCounter: 0,
-			},
-			err: nil,
-		}
+func TestGetMsrCounterRate(t *testing.T) {
+	t.Run("MsrIsNil", func(t *testing.T) {
+		pt := &PowerTelemetry{}
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("timestamp counter offset delta is zero for CPU ID")
+		rate, err := pt.GetMsrCounterRate(0, "fixed_ctr0")
+		require.ErrorContains(t, err, "\"msr\" is not initialized")
+		require.Zero(t, rate)
+	})
 
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+	t.Run("FailedToGetTimestampDelta", func(t *testing.T) {
+		cpuID := 1
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
-		}
+		m := &msrMock{}
+		m.On("getCounterDelta", cpuID, "fixed_ctr0").Return(uint64(1000), nil).Once()
+		m.On("getTimestampDelta", cpuID).Return(time.Duration(0), errors.New("mock error")).Once()
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			msr: m,
 		}
 
-		out, err := pt.GetCPUC6StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		rate, err := pt.GetMsrCounterRate(cpuID, "fixed_ctr0")
+		require.ErrorContains(t, err, fmt.Sprintf("error retrieving timestamp delta for CPU ID %v", cpuID))
+		require.Zero(t, rate)
 		m.AssertExpectations(t)
 	})
 
-	t.Run("UnsupportedModel", func(t *testing.T) {
-		// input arguments for test case
-		cpuModel := cpumodel.INTEL_FAM6_CORE2_MEROM
-		cpuID := 0
-
-		// expected output
-		expectedResult := 0.0
-		expectedErr := fmt.Errorf("c6 state residency metric not supported by CPU model: 0x%X", cpuModel)
+	t.Run("ZeroTimestampDelta", func(t *testing.T) {
+		cpuID := 1
 
-		// msr definition
 		m := &msrMock{}
+		m.On("getCounterDelta", cpuID, "fixed_ctr0").Return(uint64(1000), nil).Once()
+		m.On("getTimestampDelta", cpuID).Return(time.Duration(0), nil).Once()
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
-		}
-
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			msr: m,
 		}
 
-		out, err := pt.GetCPUC6StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		rate, err := pt.GetMsrCounterRate(cpuID, "fixed_ctr0")
+		require.ErrorContains(t, err, "timestamp delta must be greater than zero")
+		require.Zero(t, rate)
 		m.AssertExpectations(t)
 	})
 
-	t.Run("InvalidCPUModel", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("c6 state residency metric not supported by CPU model: 0x0")
+	t.Run("Valid", func(t *testing.T) {
+		cpuID := 1
 
-		// msr definition
 		m := &msrMock{}
+		m.On("getCounterDelta", cpuID, "fixed_ctr0").Return(uint64(1000), nil).Once()
+		m.On("getTimestampDelta", cpuID).Return(2*time.Second, nil).Once()
 
-		// topology definition
-		topo := &topologyData{}
-
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			msr: m,
 		}
 
-		out, err := pt.GetCPUC6StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		rate, err := pt.GetMsrCounterRate(cpuID, "fixed_ctr0")
+		require.NoError(t, err)
+		require.InDelta(t, 500.0, rate, 1e-9)
 		m.AssertExpectations(t)
 	})
 }
 
-func TestGetCPUC7StateResidency(t *testing.T) {
-	t.Run("MsrIsNil", func(t *testing.T) {
-		cpuID := 1
-		c7Exp := 0.0
+func TestGetCPUIdleStates(t *testing.T) {
+	cpuID := 1
 
-		pt := PowerTelemetry{
-			topology: &topologyData{},
-		}
+	t.Run("CPUIdleIsNil", func(t *testing.T) {
+		pt := &PowerTelemetry{}
 
-		c7Out, err := pt.GetCPUC7StateResidency(cpuID)
-		require.Equal(t, c7Exp, c7Out)
-		require.ErrorContains(t, err, "\"msr\" is not initialized")
+		states, err := pt.GetCPUIdleStates(cpuID)
+		require.ErrorContains(t, err, "\"cpuidle\" is not initialized")
+		require.Nil(t, states)
 	})
 
-	t.Run("InvalidCPUID", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 1
-		cpuModel := cpumodel.INTEL_FAM6_SKYLAKE
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: nil,
-			err:    errors.New("CPU ID 1 not found"),
-		}
-
-		// expected output
-		expectedErr := errors.New("error retrieving offset deltas for CPU ID")
-		expectedResult := 0.0
-
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+	t.Run("FailedToGetStates", func(t *testing.T) {
+		mError := errors.New("mock error")
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
-		}
+		m := &cpuIdleMock{}
+		m.On("getStates", cpuID).Return(nil, mError).Once()
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			cpuIdle: m,
 		}
 
-		out, err := pt.GetCPUC7StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		states, err := pt.GetCPUIdleStates(cpuID)
+		require.ErrorIs(t, err, mError)
+		require.Nil(t, states)
 		m.AssertExpectations(t)
 	})
 
-	t.Run("C7OffsetDeltaNotFound", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		cpuModel := cpumodel.INTEL_FAM6_SKYLAKE
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				timestampCounter: 5000,
-			},
-			err: nil,
+	t.Run("Valid", func(t *testing.T) {
+		exp := []CPUIdleState{
+			{Name: "POLL", Description: "CPUIDLE CORE POLL IDLE"},
+			{Name: "C1", Description: "MWAIT 0x00", ExitLatencyUs: 2, TargetResidencyUs: 2},
 		}
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("c7 state residency offset delta not found for CPU ID")
-
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
-
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
-		}
+		m := &cpuIdleMock{}
+		m.On("getStates", cpuID).Return(exp, nil).Once()
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			cpuIdle: m,
 		}
 
-		out, err := pt.GetCPUC7StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		states, err := pt.GetCPUIdleStates(cpuID)
+		require.NoError(t, err)
+		require.Equal(t, exp, states)
 		m.AssertExpectations(t)
 	})
+}
 
-	t.Run("C7State4Per", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		cpuModel := cpumodel.INTEL_FAM6_SKYLAKE
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				c7Residency:      200000000,
-				timestampCounter: 5000000000,
-			},
-			err: nil,
+func TestGetCPUIdleStateMetrics(t *testing.T) {
+	cpuID := 1
+	deltas := map[string]cpuIdleStateDelta{
+		"POLL": {usage: 10, time: 1000},
+		"C1":   {usage: 400, time: 250000},
+		"C6":   {usage: 20, time: 500000},
+	}
+
+	t.Run("CPUIdleIsNil", func(t *testing.T) {
+		pt := &PowerTelemetry{}
+
+		residency, err := pt.GetCPUIdleStateResidencyPercent(cpuID, "C1")
+		require.ErrorContains(t, err, "\"cpuidle\" is not initialized")
+		require.Zero(t, residency)
+
+		rate, err := pt.GetCPUIdleStateEntryRate(cpuID, "C1")
+		require.ErrorContains(t, err, "\"cpuidle\" is not initialized")
+		require.Zero(t, rate)
+	})
+
+	t.Run("FailedToGetTimestampDelta", func(t *testing.T) {
+		m := &cpuIdleMock{}
+		m.On("getTimestampDelta", cpuID).Return(time.Duration(0), errors.New("mock error")).Once()
+
+		pt := &PowerTelemetry{
+			cpuIdle: m,
 		}
 
-		// expected output
-		expectedResult := 4.0
+		residency, err := pt.GetCPUIdleStateResidencyPercent(cpuID, "C1")
+		require.ErrorContains(t, err, fmt.Sprintf("error retrieving timestamp delta for CPU ID %v", cpuID))
+		require.Zero(t, residency)
+		m.AssertExpectations(t)
+	})
 
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+	t.Run("ZeroTimestampDelta", func(t *testing.T) {
+		m := &cpuIdleMock{}
+		m.On("getTimestampDelta", cpuID).Return(time.Duration(0), nil).Once()
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
+		pt := &PowerTelemetry{
+			cpuIdle: m,
 		}
 
-		// power telemetry instance definition
+		rate, err := pt.GetCPUIdleStateEntryRate(cpuID, "C1")
+		require.ErrorContains(t, err, "elapsed interval is not available, idle state counters must be read at least twice")
+		require.Zero(t, rate)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("TimestampDeltaBelowMicrosecond", func(t *testing.T) {
+		m := &cpuIdleMock{}
+		m.On("getTimestampDelta", cpuID).Return(500*time.Nanosecond, nil).Once()
+
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			cpuIdle: m,
 		}
 
-		out, err := pt.GetCPUC7StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.NoError(t, err)
+		residency, err := pt.GetCPUIdleStateResidencyPercent(cpuID, "C1")
+		require.ErrorContains(t, err, "elapsed interval 500ns is shorter than 1 microsecond")
+		require.Zero(t, residency)
 		m.AssertExpectations(t)
 	})
 
-	t.Run("TSCOffsetDeltaNotFound", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		cpuModel := cpumodel.INTEL_FAM6_SKYLAKE
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				c7Residency: 200000000,
-			},
-			err: nil,
+	t.Run("FailedToGetStateDeltas", func(t *testing.T) {
+		m := &cpuIdleMock{}
+		m.On("getTimestampDelta", cpuID).Return(time.Second, nil).Once()
+		m.On("getStateDeltas", cpuID).Return(nil, errors.New("mock error")).Once()
+
+		pt := &PowerTelemetry{
+			cpuIdle: m,
 		}
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("timestamp counter offset delta not found for CPU ID")
+		residency, err := pt.GetCPUIdleStateResidencyPercent(cpuID, "C1")
+		require.ErrorContains(t, err, fmt.Sprintf("error retrieving idle state deltas for CPU ID %v", cpuID))
+		require.Zero(t, residency)
+		m.AssertExpectations(t)
+	})
 
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+	t.Run("StateNotFound", func(t *testing.T) {
+		m := &cpuIdleMock{}
+		m.On("getTimestampDelta", cpuID).Return(time.Second, nil).Twice()
+		m.On("getStateDeltas", cpuID).Return(deltas, nil).Twice()
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
+		pt := &PowerTelemetry{
+			cpuIdle: m,
 		}
 
-		// power telemetry instance definition
+		residency, err := pt.GetCPUIdleStateResidencyPercent(cpuID, "C3")
+		require.ErrorContains(t, err, fmt.Sprintf("idle state \"C3\" not found for CPU ID: %v", cpuID))
+		require.Zero(t, residency)
+
+		rate, err := pt.GetCPUIdleStateEntryRate(cpuID, "C3")
+		require.ErrorContains(t, err, fmt.Sprintf("idle state \"C3\" not found for CPU ID: %v", cpuID))
+		require.Zero(t, rate)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("CStateNotFound", func(t *testing.T) {
+		m := &cpuIdleMock{}
+		m.On("getTimestampDelta", cpuID).Return(time.Second, nil).Once()
+		m.On("getStateDeltas", cpuID).Return(map[string]cpuIdleStateDelta{"POLL": {}}, nil).Once()
+
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			cpuIdle: m,
 		}
 
-		out, err := pt.GetCPUC7StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		residency, err := pt.GetCPUC6StateResidency(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("no idle states of C6 found for CPU ID: %v", cpuID))
+		require.Zero(t, residency)
 		m.AssertExpectations(t)
 	})
 
-	t.Run("TSCDeltaZero", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		cpuModel := cpumodel.INTEL_FAM6_SKYLAKE
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				c7Residency:      200,
-				timestampCounter: 0,
-			},
-			err: nil,
+	t.Run("Valid", func(t *testing.T) {
+		m := &cpuIdleMock{}
+		m.On("getTimestampDelta", cpuID).Return(500*time.Millisecond, nil).Twice()
+		m.On("getStateDeltas", cpuID).Return(deltas, nil).Twice()
+
+		pt := &PowerTelemetry{
+			cpuIdle: m,
 		}
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("timestamp counter offset delta is zero for CPU ID")
+		residency, err := pt.GetCPUIdleStateResidencyPercent(cpuID, "C1")
+		require.NoError(t, err)
+		require.InDelta(t, 50.0, residency, 1e-9)
 
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+		rate, err := pt.GetCPUIdleStateEntryRate(cpuID, "C1")
+		require.NoError(t, err)
+		require.InDelta(t, 800.0, rate, 1e-9)
+		m.AssertExpectations(t)
+	})
+}
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
+func TestSetIdleStateDisabled(t *testing.T) {
+	cpuID := 1
+	stateName := "C6"
+
+	t.Run("CPUIdleIsNil", func(t *testing.T) {
+		pt := &PowerTelemetry{}
+
+		err := pt.SetIdleStateDisabled(cpuID, stateName, true)
+		require.ErrorContains(t, err, "\"cpuidle\" is not initialized")
+
+		disabled, err := pt.IsIdleStateDisabled(cpuID, stateName)
+		require.ErrorContains(t, err, "\"cpuidle\" is not initialized")
+		require.False(t, disabled)
+	})
+
+	t.Run("FailedToSet", func(t *testing.T) {
+		mError := errors.New("mock error")
+
+		m := &cpuIdleMock{}
+		m.On("setStateDisabled", cpuID, stateName, true).Return(mError).Once()
+
+		pt := &PowerTelemetry{
+			cpuIdle: m,
 		}
 
-		// power telemetry instance definition
+		require.ErrorIs(t, pt.SetIdleStateDisabled(cpuID, stateName, true), mError)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		m := &cpuIdleMock{}
+		m.On("setStateDisabled", cpuID, stateName, true).Return(nil).Once()
+		m.On("isStateDisabled", cpuID, stateName).Return(true, nil).Once()
+
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			cpuIdle: m,
 		}
 
-		out, err := pt.GetCPUC7StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		require.NoError(t, pt.SetIdleStateDisabled(cpuID, stateName, true))
+
+		disabled, err := pt.IsIdleStateDisabled(cpuID, stateName)
+		require.NoError(t, err)
+		require.True(t, disabled)
 		m.AssertExpectations(t)
 	})
+}
 
-	t.Run("UnsupportedModel", func(t *testing.T) {
-		// input arguments for test case
-		cpuModel := cpumodel.INTEL_FAM6_CORE2_MEROM
-		cpuID := 0
+func TestGetIntelIdleParams(t *testing.T) {
+	t.Run("CPUIdleIsNil", func(t *testing.T) {
+		pt := &PowerTelemetry{}
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := fmt.Errorf("c7 state residency metric not supported by CPU model: 0x%X", cpuModel)
+		maxCState, err := pt.GetIntelIdleMaxCState()
+		require.ErrorContains(t, err, "\"cpuidle\" is not initialized")
+		require.Zero(t, maxCState)
 
-		// msr definition
-		m := &msrMock{}
+		statesOff, err := pt.GetIntelIdleStatesOff()
+		require.ErrorContains(t, err, "\"cpuidle\" is not initialized")
+		require.Zero(t, statesOff)
+	})
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
-		}
+	t.Run("FailedToGetParam", func(t *testing.T) {
+		m := &cpuIdleMock{}
+		m.On("getIntelIdleParam", intelIdleMaxCStateFile).Return("", errors.New("mock error")).Once()
+		m.On("getIntelIdleParam", intelIdleStatesOffFile).Return("", errors.New("mock error")).Once()
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			cpuIdle: m,
 		}
 
-		out, err := pt.GetCPUC7StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		maxCState, err := pt.GetIntelIdleMaxCState()
+		require.ErrorContains(t, err, "error retrieving intel_idle parameter \"max_cstate\"")
+		require.Zero(t, maxCState)
+
+		statesOff, err := pt.GetIntelIdleStatesOff()
+		require.ErrorContains(t, err, "error retrieving intel_idle parameter \"states_off\"")
+		require.Zero(t, statesOff)
 		m.AssertExpectations(t)
 	})
 
-	t.Run("InvalidCPUModel", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
+	t.Run("InvalidParam", func(t *testing.T) {
+		m := &cpuIdleMock{}
+		m.On("getIntelIdleParam", intelIdleMaxCStateFile).Return("invalid", nil).Once()
+		m.On("getIntelIdleParam", intelIdleStatesOffFile).Return("-1", nil).Once()
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("c7 state residency metric not supported by CPU model")
+		pt := &PowerTelemetry{
+			cpuIdle: m,
+		}
 
-		// msr definition
-		m := &msrMock{}
+		maxCState, err := pt.GetIntelIdleMaxCState()
+		require.ErrorContains(t, err, "error converting intel_idle parameter \"max_cstate\"")
+		require.Zero(t, maxCState)
 
-		// topology definition
-		topo := &topologyData{}
+		statesOff, err := pt.GetIntelIdleStatesOff()
+		require.ErrorContains(t, err, "error converting intel_idle parameter \"states_off\"")
+		require.Zero(t, statesOff)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		m := &cpuIdleMock{}
+		m.On("getIntelIdleParam", intelIdleMaxCStateFile).Return("9", nil).Once()
+		m.On("getIntelIdleParam", intelIdleStatesOffFile).Return("12", nil).Once()
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			cpuIdle: m,
 		}
 
-		out, err := pt.GetCPUC7StateResidency(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		maxCState, err := pt.GetIntelIdleMaxCState()
+		require.NoError(t, err)
+		require.Equal(t, 9, maxCState)
+
+		statesOff, err := pt.GetIntelIdleStatesOff()
+		require.NoError(t, err)
+		require.Equal(t, uint64(12), statesOff)
 		m.AssertExpectations(t)
 	})
 }
 
-func TestGetCPUBusyFrequencyMhz(t *testing.T) {
-	t.Run("MsrIsNil", func(t *testing.T) {
-		cpuID := 1
-		busyFreqExp := 0.0
+func TestReadMSR(t *testing.T) {
+	offset := uint32(0x10)
 
+	t.Run("MsrIsNil", func(t *testing.T) {
 		pt := &PowerTelemetry{}
 
-		busyFreqOut, err := pt.GetCPUBusyFrequencyMhz(cpuID)
-		require.Equal(t, busyFreqExp, busyFreqOut)
+		value, err := pt.ReadMSR(0, offset)
 		require.ErrorContains(t, err, "\"msr\" is not initialized")
+		require.Zero(t, value)
 	})
 
-	t.Run("DeltasInvalidCPUID", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 1
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: nil,
-			err:    errors.New("CPU ID 1 not found"),
+	t.Run("CPUNotAvailable", func(t *testing.T) {
+		m := &msrMock{}
+		pt := &PowerTelemetry{
+			msr:  m,
+			cpus: []int{0, 1},
 		}
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("error retrieving offset deltas for CPU ID")
+		value, err := pt.ReadMSR(2, offset)
+		var cpuErr *CPUNotAvailableError
+		require.ErrorAs(t, err, &cpuErr)
+		require.Equal(t, 2, cpuErr.CPUID)
+		require.EqualError(t, err, "CPU ID 2 is not available")
+		require.Zero(t, value)
+		m.AssertExpectations(t)
+	})
 
-		// msr definition
+	t.Run("FailedToRead", func(t *testing.T) {
+		mErr := errors.New("mock error")
 		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
+		m.On("read", offset, 1).Return(uint64(0), mErr).Once()
+		pt := &PowerTelemetry{
+			msr:  m,
+			cpus: []int{0, 1},
+		}
+
+		value, err := pt.ReadMSR(1, offset)
+		var readErr *MsrReadError
+		require.ErrorAs(t, err, &readErr)
+		require.Equal(t, 1, readErr.CPUID)
+		require.Equal(t, offset, readErr.Offset)
+		require.ErrorIs(t, err, mErr)
+		require.EqualError(t, err, "error reading MSR offset 0x10 for CPU ID 1: mock error")
+		require.Zero(t, value)
+		m.AssertExpectations(t)
+	})
 
-		// power telemetry instance definition
+	t.Run("Valid", func(t *testing.T) {
+		m := &msrMock{}
+		m.On("read", offset, 1).Return(uint64(0xFFAA), nil).Once()
 		pt := &PowerTelemetry{
-			msr: m,
+			msr:  m,
+			cpus: []int{0, 1},
 		}
 
-		out, err := pt.GetCPUBusyFrequencyMhz(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		value, err := pt.ReadMSR(1, offset)
+		require.NoError(t, err)
+		require.Equal(t, uint64(0xFFAA), value)
 		m.AssertExpectations(t)
 	})
+}
 
-	t.Run("TimestampDeltaInvalidCPUID", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 1
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				actualFreqClockCount: 100000000000,
-				maxFreqClockCount:    200000000,
-				timestampCounter:     1000000,
-			},
-			err: nil,
-		}
-		mockTimestampDelta := &msrGetTimestampDeltaResult{
-			value: time.Duration(0),
-			err:   errors.New("CPU ID 1 not found"),
-		}
+func TestReadMSRs(t *testing.T) {
+	offsets := []uint32{0x10, 0xE7}
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("error retrieving timestamp delta for CPU ID")
+	t.Run("MsrIsNil", func(t *testing.T) {
+		pt := &PowerTelemetry{}
 
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
-		m.On("getTimestampDelta", cpuID).Return(mockTimestampDelta.value, mockTimestampDelta.err).Once()
+		values, err := pt.ReadMSRs([]int{0}, offsets)
+		require.ErrorContains(t, err, "\"msr\" is not initialized")
+		require.Nil(t, values)
+	})
 
-		// power telemetry instance definition
+	t.Run("OffsetsEmpty", func(t *testing.T) {
 		pt := &PowerTelemetry{
-			msr: m,
+			msr:  &msrMock{},
+			cpus: []int{0, 1},
 		}
 
-		out, err := pt.GetCPUBusyFrequencyMhz(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
-		m.AssertExpectations(t)
+		values, err := pt.ReadMSRs([]int{0}, nil)
+		require.ErrorContains(t, err, "MSR offsets cannot be empty")
+		require.Nil(t, values)
 	})
 
-	t.Run("TimestampDeltaInvalidCPUID", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				actualFreqClockCount: 100000000000,
-				maxFreqClockCount:    200000000,
-				timestampCounter:     1000000,
-			},
-			err: nil,
-		}
-		mockTimestampDelta := &msrGetTimestampDeltaResult{
-			value: time.Duration(0),
-			err:   errors.New("CPU ID 1 not found"),
+	t.Run("CPUNotAvailable", func(t *testing.T) {
+		m := &msrMock{}
+		pt := &PowerTelemetry{
+			msr:  m,
+			cpus: []int{0, 1},
 		}
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("error retrieving timestamp delta for CPU ID")
-
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
-		m.On("getTimestampDelta", cpuID).Return(mockTimestampDelta.value, mockTimestampDelta.err).Once()
+		values, err := pt.ReadMSRs([]int{0, 3}, offsets)
+		var cpuErr *CPUNotAvailableError
+		require.ErrorAs(t, err, &cpuErr)
+		require.Equal(t, 3, cpuErr.CPUID)
+		require.Nil(t, values)
+		m.AssertExpectations(t)
+	})
 
-		// power telemetry instance definition
+	t.Run("FailedToRead", func(t *testing.T) {
+		m := &msrMock{}
+		m.On("read", uint32(0x10), 0).Return(uint64(0x1), nil).Once()
+		m.On("read", uint32(0xE7), 0).Return(uint64(0), errors.New("mock error")).Once()
 		pt := &PowerTelemetry{
-			msr: m,
+			msr:  m,
+			cpus: []int{0, 1},
 		}
 
-		out, err := pt.GetCPUBusyFrequencyMhz(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		values, err := pt.ReadMSRs([]int{0, 1}, offsets)
+		var readErr *MsrReadError
+		require.ErrorAs(t, err, &readErr)
+		require.Equal(t, 0, readErr.CPUID)
+		require.Equal(t, uint32(0xE7), readErr.Offset)
+		require.Nil(t, values)
 		m.AssertExpectations(t)
 	})
 
-	t.Run("InvalidTimeInterval", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				actualFreqClockCount: 100000000000,
-				maxFreqClockCount:    200000000,
-				timestampCounter:     1000000,
-			},
-			err: nil,
-		}
-		mockTimestampDelta := &msrGetTimestampDeltaResult{
-			value: -100,
-			err:   nil,
+	t.Run("Valid", func(t *testing.T) {
+		m := &msrMock{}
+		m.On("read", uint32(0x10), 1).Return(uint64(0x100), nil).Once()
+		m.On("read", uint32(0xE7), 1).Return(uint64(0x200), nil).Once()
+		pt := &PowerTelemetry{
+			msr:  m,
+			cpus: []int{0, 1},
 		}
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("timestamp delta must be greater than zero")
+		values, err := pt.ReadMSRs([]int{1}, offsets)
+		require.NoError(t, err)
+		require.Equal(t, map[int]map[uint32]uint64{
+			1: {0x10: 0x100, 0xE7: 0x200},
+		}, values)
+		m.AssertExpectations(t)
+	})
 
-		// msr definition
+	t.Run("AllCPUs", func(t *testing.T) {
 		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
-		m.On("getTimestampDelta", cpuID).Return(mockTimestampDelta.value, mockTimestampDelta.err).Once()
-
-		// power telemetry instance definition
+		m.On("read", uint32(0x10), 0).Return(uint64(0x100), nil).Once()
+		m.On("read", uint32(0xE7), 0).Return(uint64(0x200), nil).Once()
+		m.On("read", uint32(0x10), 1).Return(uint64(0x300), nil).Once()
+		m.On("read", uint32(0xE7), 1).Return(uint64(0x400), nil).Once()
 		pt := &PowerTelemetry{
-			msr: m,
+			msr:  m,
+			cpus: []int{0, 1},
 		}
 
-		out, err := pt.GetCPUBusyFrequencyMhz(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		values, err := pt.ReadMSRs(nil, offsets)
+		require.NoError(t, err)
+		require.Equal(t, map[int]map[uint32]uint64{
+			0: {0x10: 0x100, 0xE7: 0x200},
+			1: {0x10: 0x300, 0xE7: 0x400},
+		}, values)
 		m.AssertExpectations(t)
 	})
+}
 
-	t.Run("MperfOffsetDeltaNotFound", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				timestampCounter: 5000,
-			},
-			err: nil,
-		}
-		mockTimestampDelta := &msrGetTimestampDeltaResult{
-			value: 1,
-			err:   nil,
-		}
+func TestWriteMSR(t *testing.T) {
+	cpuID := 0
+	offset := uint32(0x1A0)
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("mperf offset delta not found for CPU ID")
+	t.Run("MsrIsNil", func(t *testing.T) {
+		pt := &PowerTelemetry{}
 
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
-		m.On("getTimestampDelta", cpuID).Return(mockTimestampDelta.value, mockTimestampDelta.err).Once()
+		require.ErrorContains(t, pt.WriteMSR(cpuID, offset, 0x1, 0x1), "\"msr\" is not initialized")
+	})
 
-		// power telemetry instance definition
+	t.Run("MsrWriteIsNil", func(t *testing.T) {
 		pt := &PowerTelemetry{
-			msr: m,
+			msr: &msrMock{},
 		}
 
-		out, err := pt.GetCPUBusyFrequencyMhz(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
-		m.AssertExpectations(t)
+		require.ErrorContains(t, pt.WriteMSR(cpuID, offset, 0x1, 0x1), "\"msr_write\" is not initialized")
 	})
 
-	t.Run("MperfOffsetDeltaZero", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				timestampCounter:  5000,
-				maxFreqClockCount: 0,
-			},
-			err: nil,
-		}
-		mockTimestampDelta := &msrGetTimestampDeltaResult{
-			value: 1,
-			err:   nil,
+	t.Run("NotAllowed", func(t *testing.T) {
+		pt := &PowerTelemetry{
+			msr:      &msrMock{},
+			msrWrite: newTestMsrWriteGuard(t, map[uint32]uint64{offset: 0x1}, "on"),
 		}
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("mperf offset delta is zero for CPU ID")
+		err := pt.WriteMSR(cpuID, offset, 0x2, 0x2)
+		var notAllowedErr *MsrWriteNotAllowedError
+		require.ErrorAs(t, err, &notAllowedErr)
+	})
 
-		// msr definition
+	t.Run("Valid", func(t *testing.T) {
 		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
-		m.On("getTimestampDelta", cpuID).Return(mockTimestampDelta.value, mockTimestampDelta.err).Once()
+		m.On("read", offset, cpuID).Return(uint64(0x850088), nil).Once()
+		m.On("write", offset, cpuID, uint64(0x850089)).Return(nil).Once()
+		m.On("read", offset, cpuID).Return(uint64(0x850089), nil).Once()
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			msr: m,
+			msr:      m,
+			msrWrite: newTestMsrWriteGuard(t, map[uint32]uint64{offset: 0x1}, "on"),
 		}
 
-		out, err := pt.GetCPUBusyFrequencyMhz(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		require.NoError(t, pt.WriteMSR(cpuID, offset, 0x1, 0x1))
 		m.AssertExpectations(t)
 	})
+}
 
-	t.Run("AperfOffsetDeltaNotFound", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				timestampCounter:  5000,
-				maxFreqClockCount: 100,
-			},
-			err: nil,
-		}
-		mockTimestampDelta := &msrGetTimestampDeltaResult{
-			value: 1,
-			err:   nil,
+func TestReplayMsrJournal(t *testing.T) {
+	cpuID := 0
+	offset := uint32(0x1A0)
+
+	t.Run("MsrIsNil", func(t *testing.T) {
+		pt := &PowerTelemetry{}
+
+		require.ErrorContains(t, pt.ReplayMsrJournal(), "\"msr\" is not initialized")
+	})
+
+	t.Run("MsrWriteIsNil", func(t *testing.T) {
+		pt := &PowerTelemetry{
+			msr: &msrMock{},
 		}
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("aperf offset delta not found for CPU ID")
+		require.ErrorContains(t, pt.ReplayMsrJournal(), "\"msr_write\" is not initialized")
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		g := newTestMsrWriteGuard(t, map[uint32]uint64{offset: 0x1}, "on")
+		require.NoError(t, g.journal.record(msrJournalEntry{CPUID: cpuID, Offset: offset, Mask: 0x1, Original: 0x0, Value: 0x1}))
 
-		// msr definition
 		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
-		m.On("getTimestampDelta", cpuID).Return(mockTimestampDelta.value, mockTimestampDelta.err).Once()
+		m.On("read", offset, cpuID).Return(uint64(0x850089), nil).Once()
+		m.On("write", offset, cpuID, uint64(0x850088)).Return(nil).Once()
+		m.On("read", offset, cpuID).Return(uint64(0x850088), nil).Once()
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			msr: m,
+			msr:      m,
+			msrWrite: g,
 		}
 
-		out, err := pt.GetCPUBusyFrequencyMhz(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		require.NoError(t, pt.ReplayMsrJournal())
+		entries, err := g.journal.entries()
+		require.NoError(t, err)
+		require.Empty(t, entries)
 		m.AssertExpectations(t)
 	})
+}
 
-	t.Run("TSCOffsetDeltaNotFound", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				actualFreqClockCount: 100000000000,
-				maxFreqClockCount:    200000000,
-			},
-			err: nil,
-		}
-		mockTimestampDelta := &msrGetTimestampDeltaResult{
-			value: 1,
-			err:   nil,
-		}
+func TestRestore(t *testing.T) {
+	t.Run("CPUIdleIsNil", func(t *testing.T) {
+		pt := &PowerTelemetry{}
 
-		// expected output
-		expectedResult := 0.0
-		expectedErr := errors.New("timestamp counter offset delta not found for CPU ID")
+		require.NoError(t, pt.Restore())
+		require.NoError(t, pt.Close())
+	})
 
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
-		m.On("getTimestampDelta", cpuID).Return(mockTimestampDelta.value, mockTimestampDelta.err).Once()
+	t.Run("FailedToRestoreIdleStates", func(t *testing.T) {
+		m := &cpuIdleMock{}
+		m.On("restore").Return(errors.New("mock error")).Once()
 
-		// power telemetry instance definition
 		pt := &PowerTelemetry{
-			msr: m,
+			cpuIdle: m,
 		}
 
-		out, err := pt.GetCPUBusyFrequencyMhz(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.ErrorContains(t, err, expectedErr.Error())
+		require.ErrorContains(t, pt.Close(), "failed to restore idle states")
 		m.AssertExpectations(t)
 	})
 
-	t.Run("BusyFreq", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		cpuModel := cpumodel.INTEL_FAM6_ATOM_SILVERMONT
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				actualFreqClockCount: 100000000000,
-				maxFreqClockCount:    200000000,
-				timestampCounter:     1000000,
-			},
-			err: nil,
-		}
-		mockTimestampDelta := &msrGetTimestampDeltaResult{
-			value: 1000000000,
-			err:   nil,
-		}
+	t.Run("FailedToRestoreMsrOffsets", func(t *testing.T) {
+		g := newTestMsrWriteGuard(t, map[uint32]uint64{0x1A0: 0x1}, "on")
+		require.NoError(t, g.journal.record(msrJournalEntry{CPUID: 0, Offset: 0x1A0, Mask: 0x1, Original: 0x0, Value: 0x1}))
 
-		// expected output
-		expectedResult := 500.0
+		mCPUIdle := &cpuIdleMock{}
+		mCPUIdle.On("restore").Return(errors.New("mock error")).Once()
 
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
-		m.On("getTimestampDelta", cpuID).Return(mockTimestampDelta.value, mockTimestampDelta.err).Once()
+		mMsr := &msrMock{}
+		mMsr.On("read", uint32(0x1A0), 0).Return(uint64(0), errors.New("mock error")).Once()
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
+		pt := &PowerTelemetry{
+			cpuIdle:  mCPUIdle,
+			msr:      mMsr,
+			msrWrite: g,
 		}
 
-		// power telemetry instance definition
+		err := pt.Restore()
+		require.ErrorContains(t, err, "failed to restore idle states")
+		require.ErrorContains(t, err, "failed to restore MSR offsets")
+		mCPUIdle.AssertExpectations(t)
+		mMsr.AssertExpectations(t)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		m := &cpuIdleMock{}
+		m.On("restore").Return(nil).Once()
+
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			cpuIdle: m,
 		}
 
-		out, err := pt.GetCPUBusyFrequencyMhz(cpuID)
-		require.Equal(t, expectedResult, out)
-		require.NoError(t, err)
+		require.NoError(t, pt.Restore())
 		m.AssertExpectations(t)
 	})
+}
 
-	t.Run("UnsupportedModel", func(t *testing.T) {
-		// input arguments for test case
-		cpuID := 0
-		cpuModel := cpumodel.INTEL_FAM6_CORE2_MEROM
-		mockOffsetDeltas := &msrGetOffsetDeltasResult{
-			values: map[uint32]uint64{
-				actualFreqClockCount: 100000000000,
-				maxFreqClockCount:    200000000,
-				timestampCounter:     1000000,
-			},
-			err: nil,
+func TestClose(t *testing.T) {
+	t.Run("FailedToCloseMsr", func(t *testing.T) {
+		mCPUIdle := &cpuIdleMock{}
+		mCPUIdle.On("restore").Return(errors.New("mock error")).Once()
+
+		mMsr := &msrMock{}
+		mMsr.On("close").Return(errors.New("mock error")).Once()
+
+		pt := &PowerTelemetry{
+			cpuIdle: mCPUIdle,
+			msr:     mMsr,
 		}
-		mockTimestampDelta := &msrGetTimestampDeltaResult{
-			value: 1000000000,
-			err:   nil,
+
+		err := pt.Close()
+		require.ErrorContains(t, err, "failed to restore idle states")
+		require.ErrorContains(t, err, "failed to close msr")
+		mCPUIdle.AssertExpectations(t)
+		mMsr.AssertExpectations(t)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		mCPUIdle := &cpuIdleMock{}
+		mCPUIdle.On("restore").Return(nil).Once()
+
+		mMsr := &msrMock{}
+		mMsr.On("close").Return(nil).Once()
+
+		pt := &PowerTelemetry{
+			cpuIdle: mCPUIdle,
+			msr:     mMsr,
 		}
 
-		// expected output
-		expectedResult := 500.0
+		require.NoError(t, pt.Close())
+		mCPUIdle.AssertExpectations(t)
+		mMsr.AssertExpectations(t)
+	})
+}
 
-		// msr definition
-		m := &msrMock{}
-		m.On("getOffsetDeltas", cpuID).Return(mockOffsetDeltas.values, mockOffsetDeltas.err).Once()
-		m.On("getTimestampDelta", cpuID).Return(mockTimestampDelta.value, mockTimestampDelta.err).Once()
+func TestGetPackageTemperature(t *testing.T) {
+	packageID := 1
 
-		// topology definition
-		topo := &topologyData{
-			topologyMap: map[int]*cpuInfo{
-				0: {},
-			},
-			model: cpuModel,
+	t.Run("CoreTempIsNil", func(t *testing.T) {
+		pt := &PowerTelemetry{}
+
+		temp, err := pt.GetPackageTemperature(packageID)
+		require.ErrorContains(t, err, "\"coretemp\" is not initialized")
+		require.Zero(t, temp)
+	})
+
+	t.Run("FailedToGetTemperature", func(t *testing.T) {
+		mError := errors.New("mock error")
+
+		m := &coreTempMock{}
+		m.On("getPackageTemperature", packageID, tempInput).Return(uint64(0), mError).Once()
+
+		pt := &PowerTelemetry{
+			coreTemp: m,
 		}
 
-		// power telemetry instance definition
+		temp, err := pt.GetPackageTemperature(packageID)
+		require.ErrorIs(t, err, mError)
+		require.Zero(t, temp)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		m := &coreTempMock{}
+		m.On("getPackageTemperature", packageID, tempInput).Return(uint64(52), nil).Once()
+
 		pt := &PowerTelemetry{
-			topology: topo,
-			msr:      m,
+			coreTemp: m,
 		}
 
-		out, err := pt.GetCPUBusyFrequencyMhz(cpuID)
-		require.Equal(t, expectedResult, out)
+		temp, err := pt.GetPackageTemperature(packageID)
 		require.NoError(t, err)
+		require.Equal(t, uint64(52), temp)
 		m.AssertExpectations(t)
 	})
 }
 
-func TestUpdatePerCPUMetrics(t *testing.T) {
-	t.Run("MsrIsNil", func(t *testing.T) {
-		cpuID := 0
+func TestGetTemperatureThresholds(t *testing.T) {
+	cpuID := 3
+	packageID := 1
+	coreID := 2
 
+	t.Run("CoreTempIsNil", func(t *testing.T) {
 		pt := &PowerTelemetry{}
 
-		err := pt.UpdatePerCPUMetrics(cpuID)
-		require.ErrorContains(t, err, "\"msr\" is not initialized")
+		thresholds, err := pt.GetCPUTemperatureThresholds(cpuID)
+		require.ErrorContains(t, err, "\"coretemp\" is not initialized")
+		require.Equal(t, TemperatureThresholds{}, thresholds)
+
+		thresholds, err = pt.GetPackageTemperatureThresholds(packageID)
+		require.ErrorContains(t, err, "\"coretemp\" is not initialized")
+		require.Equal(t, TemperatureThresholds{}, thresholds)
 	})
 
-	t.Run("FailedToUpdate", func(t *testing.T) {
-		cpuID := 0
-		errExpected := errors.New("error while updating storage")
+	t.Run("FailedToGetMaxTemperature", func(t *testing.T) {
+		mError := errors.New("mock error")
 
-		m := &msrMock{}
+		mTopology := &topologyMock{}
+		mTopology.On("getCPUPackageID", cpuID).Return(packageID, nil).Once()
+		mTopology.On("getCPUCoreID", cpuID).Return(coreID, nil).Once()
 
-		// mock updating offset deltas.
-		m.On("update", cpuID).Return(errExpected).Once()
+		mCoreTemp := &coreTempMock{}
+		mCoreTemp.On("getCoreTemperature", packageID, coreID, tempMax).Return(uint64(0), mError).Once()
+		mCoreTemp.On("getPackageTemperature", packageID, tempMax).Return(uint64(0), mError).Once()
 
 		pt := &PowerTelemetry{
-			msr: m,
+			topology: mTopology,
+			coreTemp: mCoreTemp,
 		}
 
-		err := pt.UpdatePerCPUMetrics(cpuID)
-		require.ErrorContains(t, err, errExpected.Error())
-		m.AssertExpectations(t)
+		thresholds, err := pt.GetCPUTemperatureThresholds(cpuID)
+		require.ErrorIs(t, err, mError)
+		require.Equal(t, TemperatureThresholds{}, thresholds)
+
+		thresholds, err = pt.GetPackageTemperatureThresholds(packageID)
+		require.ErrorIs(t, err, mError)
+		require.Equal(t, TemperatureThresholds{}, thresholds)
+		mTopology.AssertExpectations(t)
+		mCoreTemp.AssertExpectations(t)
 	})
 
-	t.Run("FailedToGetDeltas", func(t *testing.T) {
-		cpuID := 0
-		m := &msrMock{}
+	t.Run("FailedToGetCriticalTemperature", func(t *testing.T) {
+		mError := errors.New("mock error")
 
-		// mock updating offset deltas.
-		m.On("update", cpuID).Return(nil).Once()
+		mTopology := &topologyMock{}
+		mTopology.On("getCPUPackageID", cpuID).Return(packageID, nil).Twice()
+		mTopology.On("getCPUCoreID", cpuID).Return(coreID, nil).Twice()
 
-		// mock getting offset deltas.
-		m.On("getOffsetDeltas", cpuID).Return(nil, errors.New("mock error")).Once()
+		mCoreTemp := &coreTempMock{}
+		mCoreTemp.On("getCoreTemperature", packageID, coreID, tempMax).Return(uint64(90), nil).Once()
+		mCoreTemp.On("getCoreTemperature", packageID, coreID, tempCrit).Return(uint64(0), mError).Once()
+		mCoreTemp.On("getPackageTemperature", packageID, tempMax).Return(uint64(90), nil).Once()
+		mCoreTemp.On("getPackageTemperature", packageID, tempCrit).Return(uint64(0), mError).Once()
 
 		pt := &PowerTelemetry{
-			msr: m,
+			topology: mTopology,
+			coreTemp: mCoreTemp,
 		}
 
-		err := pt.UpdatePerCPUMetrics(cpuID)
-		require.ErrorContains(t, err, fmt.Sprintf("error retrieving offset deltas for CPU ID %v", cpuID))
-		m.AssertExpectations(t)
-	})
+		thresholds, err := pt.GetCPUTemperatureThresholds(cpuID)
+		require.ErrorIs(t, err, mError)
+		require.Equal(t, TemperatureThresholds{}, thresholds)
 
-	t.Run("SumDeltasOverflow", func(t *testing.T) {
-		cpuID := 0
-		m := &msrMock{}
+		thresholds, err = pt.GetPackageTemperatureThresholds(packageID)
+		require.ErrorIs(t, err, mError)
+		require.Equal(t, TemperatureThresholds{}, thresholds)
+		mTopology.AssertExpectations(t)
+		mCoreTemp.AssertExpectations(t)
+	})
 
-		// mock updating offset deltas.
-		m.On("update", cpuID).Return(nil).Once()
+	t.Run("Valid", func(t *testing.T) {
+		mTopology := &topologyMock{}
+		mTopology.On("getCPUPackageID", cpuID).Return(packageID, nil).Twice()
+		mTopology.On("getCPUCoreID", cpuID).Return(coreID, nil).Twice()
 
-		// mock getting offset deltas.
-		m.On("getOffsetDeltas", cpuID).Return(
-			map[uint32]uint64{
-				timestampCounter:  300,
-				maxFreqClockCount: math.MaxUint64,
-				c3Residency:       1,
-				c6Residency:       0,
-				c7Residency:       0,
-			}, nil,
-		).Once()
+		mCoreTemp := &coreTempMock{}
+		mCoreTemp.On("getCoreTemperature", packageID, coreID, tempMax).Return(uint64(90), nil).Once()
+		mCoreTemp.On("getCoreTemperature", packageID, coreID, tempCrit).Return(uint64(100), nil).Once()
+		mCoreTemp.On("getPackageTemperature", packageID, tempMax).Return(uint64(91), nil).Once()
+		mCoreTemp.On("getPackageTemperature", packageID, tempCrit).Return(uint64(101), nil).Once()
 
 		pt := &PowerTelemetry{
-			msr: m,
+			topology: mTopology,
+			coreTemp: mCoreTemp,
 		}
 
-		err := pt.UpdatePerCPUMetrics(cpuID)
-		require.ErrorContains(t, err, fmt.Sprintf("sum of deltas caused overflow for CPU ID %v", cpuID))
-		m.AssertExpectations(t)
+		thresholds, err := pt.GetCPUTemperatureThresholds(cpuID)
+		require.NoError(t, err)
+		require.Equal(t, TemperatureThresholds{Max: 90, Critical: 100}, thresholds)
+
+		thresholds, err = pt.GetPackageTemperatureThresholds(packageID)
+		require.NoError(t, err)
+		require.Equal(t, TemperatureThresholds{Max: 91, Critical: 101}, thresholds)
+		mTopology.AssertExpectations(t)
+		mCoreTemp.AssertExpectations(t)
 	})
+}
 
-	t.Run("FailedToScale", func(t *testing.T) {
-		cpuID := 0
-		m := &msrMock{}
+func TestGetThermalZones(t *testing.T) {
+	t.Run("ThermalZonesIsNil", func(t *testing.T) {
+		pt := &PowerTelemetry{}
 
-		// mock updating offset deltas.
-		m.On("update", cpuID).Return(nil).Once()
+		zones, err := pt.GetThermalZones()
+		require.ErrorContains(t, err, "\"thermal_zone\" is not initialized")
+		require.Nil(t, zones)
 
-		// mock getting offset deltas.
-		m.On("getOffsetDeltas", cpuID).Return(
-			map[uint32]uint64{
-				timestampCounter:  300,
-				maxFreqClockCount: 300,
-				c3Residency:       100,
-				c6Residency:       100,
-				c7Residency:       100,
-			}, nil,
-		).Once()
+		devices, err := pt.GetCoolingDevices()
+		require.ErrorContains(t, err, "\"thermal_zone\" is not initialized")
+		require.Nil(t, devices)
+	})
 
-		sumDeltaBig := new(big.Float).SetUint64(600)
-		tscDeltaBig := new(big.Float).SetUint64(300)
-		fBig := new(big.Float).Quo(tscDeltaBig, sumDeltaBig)
+	t.Run("FailedToGetValues", func(t *testing.T) {
+		mError := errors.New("mock error")
 
-		// mock scaling offset deltas.
-		m.On("scaleOffsetDeltas", cpuID, []uint32{
-			maxFreqClockCount,
-			c3Residency,
-			c6Residency,
-			c7Residency,
-		}, fBig).Return(errors.New("mock error")).Once()
+		m := &thermalZoneMock{}
+		m.On("getThermalZones").Return(nil, mError).Once()
+		m.On("getCoolingDevices").Return(nil, mError).Once()
 
 		pt := &PowerTelemetry{
-			msr: m,
+			thermal: m,
 		}
 
-		err := pt.UpdatePerCPUMetrics(cpuID)
-		require.ErrorContains(t, err, fmt.Sprintf("error scaling offset deltas for CPU ID %v", cpuID))
+		zones, err := pt.GetThermalZones()
+		require.ErrorIs(t, err, mError)
+		require.Nil(t, zones)
+
+		devices, err := pt.GetCoolingDevices()
+		require.ErrorIs(t, err, mError)
+		require.Nil(t, devices)
 		m.AssertExpectations(t)
 	})
 
-	t.Run("WithoutScalingDeltas", func(t *testing.T) {
-		cpuID := 0
-		msrOffsets := []uint32{
-			timestampCounter,
-			maxFreqClockCount,
-			c3Residency,
-			c6Residency,
-			c7Residency,
+	t.Run("Valid", func(t *testing.T) {
+		zonesExp := []ThermalZone{
+			{
+				ID:          0,
+				Type:        "x86_pkg_temp",
+				Temperature: 45,
+				Policy:      "step_wise",
+				TripPoints: []ThermalTripPoint{
+					{Index: 0, Type: "passive", Temperature: 95, Hysteresis: 2},
+				},
+				CoolingDevices: []int{0},
+			},
 		}
-
-		offsetDeltas := map[uint32]uint64{
-			timestampCounter:  500,
-			maxFreqClockCount: 100,
-			c3Residency:       100,
-			c6Residency:       100,
-			c7Residency:       100,
+		devicesExp := []CoolingDevice{
+			{ID: 0, Type: "Processor", CurState: 1, MaxState: 10},
 		}
 
-		m := &msrRegMock{}
-
-		// mock reading all msr offset values.
-		m.On("readAll", msrOffsets).Return(offsetDeltas, nil).Once()
+		m := &thermalZoneMock{}
+		m.On("getThermalZones").Return(zonesExp, nil).Once()
+		m.On("getCoolingDevices").Return(devicesExp, nil).Once()
 
 		pt := &PowerTelemetry{
-			msr: &msrDataWithStorage{
-				msrMap: map[int]msrRegWithStorage{
-					cpuID: &msrWithStorage{
-						msrReg: m,
-
-						offsets:      msrOffsets,
-						offsetValues: map[uint32]uint64{},
-						offsetDeltas: map[uint32]uint64{},
-					},
-				},
-			},
+			thermal: m,
 		}
 
-		require.NoError(t, pt.UpdatePerCPUMetrics(cpuID))
+		zones, err := pt.GetThermalZones()
+		require.NoError(t, err)
+		require.Equal(t, zonesExp, zones)
 
-		offsetDeltasOut, err := pt.msr.getOffsetDeltas(cpuID)
+		devices, err := pt.GetCoolingDevices()
 		require.NoError(t, err)
-		require.Equal(t, offsetDeltas, offsetDeltasOut)
+		require.Equal(t, devicesExp, devices)
 		m.AssertExpectations(t)
 	})
+}
 
-	t.Run("WithScaledDeltas", func(t *testing.T) {
-		cpuID := 0
-		msrOffsets := []uint32{
-			timestampCounter,
-			maxFreqClockCount,
-			c3Residency,
-			c6Residency,
-			c7Residency,
-		}
+func TestGetThermalThrottleStats(t *testing.T) {
+	cpuID := 2
+	packageID := 1
 
-		offsetDeltas := map[uint32]uint64{
-			timestampCounter:  300,
-			maxFreqClockCount: 300,
-			c3Residency:       100,
-			c6Residency:       100,
-			c7Residency:       100,
-		}
+	// topology definition
+	topo := &topologyData{
+		topologyMap: map[int]*cpuInfo{
+			0: {
+				packageID: 0,
+			},
+			1: {
+				packageID: 0,
+			},
+			2: {
+				packageID: 1,
+			},
+			3: {
+				packageID: 1,
+			},
+		},
+	}
 
-		m := &msrRegMock{}
+	t.Run("ThermalThrottleIsNil", func(t *testing.T) {
+		pt := &PowerTelemetry{}
 
-		// mock reading all msr offset values.
-		m.On("readAll", msrOffsets).Return(offsetDeltas, nil).Once()
+		stats, err := pt.GetCPUThermalThrottleStats(cpuID)
+		require.ErrorContains(t, err, "\"thermal_throttle\" is not initialized")
+		require.Equal(t, ThermalThrottleStats{}, stats)
 
-		pt := &PowerTelemetry{
-			msr: &msrDataWithStorage{
-				msrMap: map[int]msrRegWithStorage{
-					cpuID: &msrWithStorage{
-						msrReg: m,
+		stats, err = pt.GetPackageThermalThrottleStats(packageID)
+		require.ErrorContains(t, err, "\"thermal_throttle\" is not initialized")
+		require.Equal(t, ThermalThrottleStats{}, stats)
+	})
 
-						offsets:      msrOffsets,
-						offsetValues: map[uint32]uint64{},
-						offsetDeltas: map[uint32]uint64{},
-					},
-				},
-			},
+	t.Run("CPUIDNotAvailable", func(t *testing.T) {
+		m := &thermalThrottleMock{}
+
+		pt := &PowerTelemetry{
+			topology: topo,
+			throttle: m,
+			cpus:     []int{0, 1},
 		}
 
-		require.NoError(t, pt.UpdatePerCPUMetrics(cpuID))
+		stats, err := pt.GetPackageThermalThrottleStats(packageID)
+		require.ErrorContains(t, err, fmt.Sprintf("could not find CPU ID for package ID %v", packageID))
+		require.Equal(t, ThermalThrottleStats{}, stats)
+		m.AssertExpectations(t)
+	})
 
-		offsetDeltasOut, err := pt.msr.getOffsetDeltas(cpuID)
-		require.NoError(t, err)
-		require.Equal(t, map[uint32]uint64{
-			timestampCounter:  300,
-			maxFreqClockCount: 150,
-			c3Residency:       50,
-			c6Residency:       50,
-			c7Residency:       50,
-		}, offsetDeltasOut)
+	t.Run("FailedToGetTimestampDelta", func(t *testing.T) {
+		m := &thermalThrottleMock{}
+		m.On("getTimestampDelta", cpuID).Return(time.Duration(0), errors.New("mock error")).Once()
+
+		pt := &PowerTelemetry{
+			throttle: m,
+		}
 
+		stats, err := pt.GetCPUThermalThrottleStats(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("error retrieving timestamp delta for CPU ID %v", cpuID))
+		require.Equal(t, ThermalThrottleStats{}, stats)
 		m.AssertExpectations(t)
 	})
-}
 
-func TestIsCPUSupported(t *testing.T) {
-	t.Run("True", func(t *testing.T) {
-		mTopology := &topologyMock{}
-		mTopology.On("getCPUFamily", 0).Return("6", nil).Once()
-		mTopology.On("getCPUVendor", 0).Return("GenuineIntel", nil).Once()
+	t.Run("ZeroTimestampDelta", func(t *testing.T) {
+		m := &thermalThrottleMock{}
+		m.On("getTimestampDelta", cpuID).Return(time.Duration(0), nil).Once()
 
-		isSupported, err := isCPUSupported(mTopology)
-		require.True(t, isSupported)
-		require.NoError(t, err)
-		mTopology.AssertExpectations(t)
+		pt := &PowerTelemetry{
+			throttle: m,
+		}
+
+		stats, err := pt.GetCPUThermalThrottleStats(cpuID)
+		require.ErrorContains(t, err, "elapsed interval is not available, thermal throttle counters must be read at least twice")
+		require.Equal(t, ThermalThrottleStats{}, stats)
+		m.AssertExpectations(t)
 	})
 
-	t.Run("False", func(t *testing.T) {
-		t.Run("FamilyNotIntel6", func(t *testing.T) {
-			mTopology := &topologyMock{}
-			mTopology.On("getCPUFamily", 0).Return("", nil).Once()
-			mTopology.On("getCPUVendor", 0).Return("GenuineIntel", nil).Once()
+	t.Run("FailedToGetDeltas", func(t *testing.T) {
+		m := &thermalThrottleMock{}
+		m.On("getTimestampDelta", cpuID).Return(time.Second, nil).Once()
+		m.On("getThrottleDeltas", cpuID, packageThrottle).Return(throttleCounters{}, errors.New("mock error")).Once()
 
-			isSupported, err := isCPUSupported(mTopology)
-			require.False(t, isSupported)
-			require.NoError(t, err)
-			mTopology.AssertExpectations(t)
-		})
+		pt := &PowerTelemetry{
+			topology: topo,
+			throttle: m,
+			cpus:     []int{0, 1, 2, 3},
+		}
 
-		t.Run("VendorNotGenuineIntel", func(t *testing.T) {
-			mTopology := &topologyMock{}
-			mTopology.On("getCPUFamily", 0).Return("6", nil).Once()
-			mTopology.On("getCPUVendor", 0).Return("AuthenticAMD", nil).Once()
+		stats, err := pt.GetPackageThermalThrottleStats(packageID)
+		require.ErrorContains(t, err, fmt.Sprintf("error retrieving thermal throttle deltas for CPU ID %v", cpuID))
+		require.Equal(t, ThermalThrottleStats{}, stats)
+		m.AssertExpectations(t)
+	})
 
-			isSupported, err := isCPUSupported(mTopology)
-			require.False(t, isSupported)
-			require.NoError(t, err)
-			mTopology.AssertExpectations(t)
-		})
+	t.Run("FailedToGetMaxTime", func(t *testing.T) {
+		m := &thermalThrottleMock{}
+		m.On("getTimestampDelta", cpuID).Return(time.Second, nil).Once()
+		m.On("getThrottleDeltas", cpuID, coreThrottle).Return(throttleCounters{count: 1, timeMs: 2}, nil).Once()
+		m.On("getThrottleMaxTimeMs", cpuID, coreThrottle).Return(uint64(0), errors.New("mock error")).Once()
 
-		t.Run("FailedToGetFamily", func(t *testing.T) {
-			mError := errors.New("mock error")
-			mTopology := &topologyMock{}
-			mTopology.On("getCPUFamily", 0).Return("", mError).Once()
+		pt := &PowerTelemetry{
+			throttle: m,
+		}
+
+		stats, err := pt.GetCPUThermalThrottleStats(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("error retrieving thermal throttle max time for CPU ID %v", cpuID))
+		require.Equal(t, ThermalThrottleStats{}, stats)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		m := &thermalThrottleMock{}
+		m.On("getTimestampDelta", cpuID).Return(5*time.Second, nil).Twice()
+		m.On("getThrottleDeltas", cpuID, coreThrottle).Return(throttleCounters{count: 3, timeMs: 120}, nil).Once()
+		m.On("getThrottleMaxTimeMs", cpuID, coreThrottle).Return(uint64(80), nil).Once()
+		m.On("getThrottleDeltas", cpuID, packageThrottle).Return(throttleCounters{count: 1, timeMs: 40}, nil).Once()
+		m.On("getThrottleMaxTimeMs", cpuID, packageThrottle).Return(uint64(90), nil).Once()
 
-			isSupported, err := isCPUSupported(mTopology)
-			require.False(t, isSupported)
-			require.ErrorContains(t, err, mError.Error())
-			mTopology.AssertExpectations(t)
-		})
+		pt := &PowerTelemetry{
+			topology: topo,
+			throttle: m,
+			cpus:     []int{0, 1, 2, 3},
+		}
 
-		t.Run("FailedToGetVendor", func(t *testing.T) {
-			mError := errors.New("mock error")
-			mTopology := &topologyMock{}
-			mTopology.On("getCPUFamily", 0).Return("6", nil).Once()
-			mTopology.On("getCPUVendor", 0).Return("", mError).Once()
+		stats, err := pt.GetCPUThermalThrottleStats(cpuID)
+		require.NoError(t, err)
+		require.Equal(t, ThermalThrottleStats{Count: 3, TimeMs: 120, MaxTimeMs: 80, Interval: 5 * time.Second}, stats)
 
-			isSupported, err := isCPUSupported(mTopology)
-			require.False(t, isSupported)
-			require.ErrorContains(t, err, mError.Error())
-			mTopology.AssertExpectations(t)
-		})
+		stats, err = pt.GetPackageThermalThrottleStats(packageID)
+		require.NoError(t, err)
+		require.Equal(t, ThermalThrottleStats{Count: 1, TimeMs: 40, MaxTimeMs: 90, Interval: 5 * time.Second}, stats)
+		m.AssertExpectations(t)
 	})
 }
 
@@ -3371,306 +6201,748 @@ func TestGetCPUCoreID(t *testing.T) {
 		},
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// power telemetry instance definition
-			pt := &PowerTelemetry{
-				topology: tt.topology,
-			}
-			// actual output
-			actual, err := pt.GetCPUCoreID(0)
-			require.Equal(t, tt.expected, actual)
-			if tt.err != nil {
-				require.ErrorContains(t, err, tt.err.Error())
-			} else {
-				require.NoError(t, err)
-			}
-		})
-	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// power telemetry instance definition
+			pt := &PowerTelemetry{
+				topology: tt.topology,
+			}
+			// actual output
+			actual, err := pt.GetCPUCoreID(0)
+			require.Equal(t, tt.expected, actual)
+			if tt.err != nil {
+				require.ErrorContains(t, err, tt.err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetPackageDieIDs(t *testing.T) {
+	tests := []struct {
+		name     string
+		topology topologyReader
+		err      error
+		expected []int
+	}{
+		{
+			name:     "InvalidPackageIDNoDies",
+			topology: &topologyData{},
+			err:      errors.New("error retrieving dies"),
+			expected: nil,
+		},
+		{
+			name: "InvalidPackageIDPackageDoesNotExist",
+			topology: &topologyData{
+				packageDies: map[int][]int{
+					1: {
+						0, 1,
+					},
+				},
+			},
+			err:      errors.New("error retrieving dies"),
+			expected: nil,
+		},
+		{
+			name: "ValidPackageID",
+			topology: &topologyData{
+				packageDies: map[int][]int{
+					0: {
+						1, 2,
+					},
+				},
+			},
+			err:      nil,
+			expected: []int{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// power telemetry instance definition
+			pt := &PowerTelemetry{
+				topology: tt.topology,
+			}
+			// actual output
+			actual, err := pt.GetPackageDieIDs(0)
+			require.Equal(t, tt.expected, actual)
+			if tt.err != nil {
+				require.ErrorContains(t, err, tt.err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+type raplMock struct {
+	mock.Mock
+}
+
+func (m *raplMock) initZoneMap() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *raplMock) getPackageIDs() []int {
+	args := m.Called()
+	return args.Get(0).([]int)
+}
+
+func (m *raplMock) isRaplLoaded(modulesPath string) (bool, error) {
+	args := m.Called(modulesPath)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *raplMock) getCurrentPowerConsumptionWatts(packageID int, domain string) (float64, error) {
+	args := m.Called(packageID, domain)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *raplMock) getMaxPowerConstraintWatts(packageID int) (float64, error) {
+	args := m.Called(packageID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func TestPower_GetCurrentPackagePowerConsumptionWatts(t *testing.T) {
+	t.Run("RaplIsNil", func(t *testing.T) {
+		packageID := 0
+		currPowerExp := 0.0
+
+		pt := &PowerTelemetry{}
+		currPowerOut, err := pt.GetCurrentPackagePowerConsumptionWatts(packageID)
+		require.Equal(t, currPowerExp, currPowerOut)
+		require.ErrorContains(t, err, "\"rapl\" is not initialized")
+	})
+
+	t.Run("FailedToGetCurrentPower", func(t *testing.T) {
+		packageID := 0
+		currPowerExp := 0.0
+
+		mError := errors.New("mock error")
+		mRapl := &raplMock{}
+		mRapl.On("getCurrentPowerConsumptionWatts", packageID, packageDomain.String()).Return(currPowerExp, mError).Once()
+
+		pt := &PowerTelemetry{
+			rapl: mRapl,
+		}
+
+		currPowerOut, err := pt.GetCurrentPackagePowerConsumptionWatts(packageID)
+		require.Equal(t, currPowerExp, currPowerOut)
+		require.ErrorContains(t, err, mError.Error())
+		mRapl.AssertExpectations(t)
+	})
+
+	t.Run("Ok", func(t *testing.T) {
+		packageID := 0
+		currPowerExp := 30.0
+
+		mRapl := &raplMock{}
+		mRapl.On("getCurrentPowerConsumptionWatts", packageID, packageDomain.String()).Return(currPowerExp, nil).Once()
+
+		pt := &PowerTelemetry{
+			rapl: mRapl,
+		}
+
+		currPowerOut, err := pt.GetCurrentPackagePowerConsumptionWatts(packageID)
+		require.Equal(t, currPowerExp, currPowerOut)
+		require.NoError(t, err)
+		mRapl.AssertExpectations(t)
+	})
+}
+
+func TestPower_GetCurrentDramPowerConsumptionWatts(t *testing.T) {
+	t.Run("RaplIsNil", func(t *testing.T) {
+		packageID := 0
+		currPowerExp := 0.0
+
+		pt := &PowerTelemetry{}
+		currPowerOut, err := pt.GetCurrentDramPowerConsumptionWatts(packageID)
+		require.Equal(t, currPowerExp, currPowerOut)
+		require.ErrorContains(t, err, "\"rapl\" is not initialized")
+	})
+
+	t.Run("FailedToGetCurrentPower", func(t *testing.T) {
+		packageID := 0
+		currPowerExp := 0.0
+
+		mError := errors.New("mock error")
+		mRapl := &raplMock{}
+		mRapl.On("getCurrentPowerConsumptionWatts", packageID, dramDomain.String()).Return(currPowerExp, mError).Once()
+
+		pt := &PowerTelemetry{
+			rapl: mRapl,
+		}
+
+		currPowerOut, err := pt.GetCurrentDramPowerConsumptionWatts(packageID)
+		require.Equal(t, currPowerExp, currPowerOut)
+		require.ErrorContains(t, err, mError.Error())
+		mRapl.AssertExpectations(t)
+	})
+
+	t.Run("Ok", func(t *testing.T) {
+		packageID := 0
+		currPowerExp := 30.0
+
+		mRapl := &raplMock{}
+		mRapl.On("getCurrentPowerConsumptionWatts", packageID, dramDomain.String()).Return(currPowerExp, nil).Once()
+
+		pt := &PowerTelemetry{
+			rapl: mRapl,
+		}
+
+		currPowerOut, err := pt.GetCurrentDramPowerConsumptionWatts(packageID)
+		require.Equal(t, currPowerExp, currPowerOut)
+		require.NoError(t, err)
+		mRapl.AssertExpectations(t)
+	})
+}
+
+func TestPower_GetPackageThermalDesignPowerWatts(t *testing.T) {
+	t.Run("RaplIsNil", func(t *testing.T) {
+		packageID := 0
+		currPowerExp := 0.0
+
+		pt := &PowerTelemetry{}
+		currPowerOut, err := pt.GetPackageThermalDesignPowerWatts(packageID)
+		require.Equal(t, currPowerExp, currPowerOut)
+		require.ErrorContains(t, err, "\"rapl\" is not initialized")
+	})
+
+	t.Run("FailedToGetMaxPower", func(t *testing.T) {
+		packageID := 0
+		maxPowerExp := 0.0
+
+		mError := errors.New("mock error")
+		mRapl := &raplMock{}
+		mRapl.On("getMaxPowerConstraintWatts", packageID).Return(maxPowerExp, mError).Once()
+
+		pt := &PowerTelemetry{
+			rapl: mRapl,
+		}
+
+		maxPowerOut, err := pt.GetPackageThermalDesignPowerWatts(packageID)
+		require.Equal(t, maxPowerExp, maxPowerOut)
+		require.ErrorContains(t, err, mError.Error())
+		mRapl.AssertExpectations(t)
+	})
+
+	t.Run("Ok", func(t *testing.T) {
+		packageID := 0
+		maxPowerExp := 30.0
+
+		mRapl := &raplMock{}
+		mRapl.On("getMaxPowerConstraintWatts", packageID).Return(maxPowerExp, nil).Once()
+
+		pt := &PowerTelemetry{
+			rapl: mRapl,
+		}
+
+		maxPowerOut, err := pt.GetPackageThermalDesignPowerWatts(packageID)
+		require.Equal(t, maxPowerExp, maxPowerOut)
+		require.NoError(t, err)
+		mRapl.AssertExpectations(t)
+	})
 }
 
-func TestGetPackageDieIDs(t *testing.T) {
-	tests := []struct {
-		name     string
-		topology topologyReader
-		err      error
-		expected []int
-	}{
-		{
-			name:     "InvalidPackageIDNoDies",
-			topology: &topologyData{},
-			err:      errors.New("error retrieving dies"),
-			expected: nil,
-		},
-		{
-			name: "InvalidPackageIDPackageDoesNotExist",
-			topology: &topologyData{
-				packageDies: map[int][]int{
-					1: {
-						0, 1,
-					},
-				},
-			},
-			err:      errors.New("error retrieving dies"),
-			expected: nil,
-		},
-		{
-			name: "ValidPackageID",
-			topology: &topologyData{
-				packageDies: map[int][]int{
-					0: {
-						1, 2,
-					},
-				},
-			},
-			err:      nil,
-			expected: []int{1, 2},
-		},
-	}
+type perfMock struct {
+	mock.Mock
+}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// power telemetry instance definition
-			pt := &PowerTelemetry{
-				topology: tt.topology,
-			}
-			// actual output
-			actual, err := pt.GetPackageDieIDs(0)
-			require.Equal(t, tt.expected, actual)
-			if tt.err != nil {
-				require.ErrorContains(t, err, tt.err.Error())
-			} else {
-				require.NoError(t, err)
-			}
-		})
-	}
+func (m *perfMock) activate(events []string, cores []int) error {
+	args := m.Called(events, cores)
+	return args.Error(0)
 }
 
-type raplMock struct {
-	mock.Mock
+func (m *perfMock) read() ([]coreMetric, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]coreMetric), args.Error(1)
 }
 
-func (m *raplMock) initZoneMap() error {
+func (m *perfMock) deactivate() error {
 	args := m.Called()
 	return args.Error(0)
 }
 
-func (m *raplMock) getPackageIDs() []int {
+func (m *perfMock) initResolver(jsonFile string) error {
+	args := m.Called(jsonFile)
+	return args.Error(0)
+}
+
+func (m *perfMock) initHybridResolvers(jsonFiles map[CoreType]string) error {
+	args := m.Called(jsonFiles)
+	return args.Error(0)
+}
+
+func (m *perfMock) activeCPUIDs() []int {
 	args := m.Called()
+	if args.Get(0) == nil {
+		return nil
+	}
 	return args.Get(0).([]int)
 }
 
-func (m *raplMock) isRaplLoaded(modulesPath string) (bool, error) {
-	args := m.Called(modulesPath)
-	return args.Bool(0), args.Error(1)
+func (m *perfMock) update() error {
+	args := m.Called()
+	return args.Error(0)
 }
 
-func (m *raplMock) getCurrentPowerConsumptionWatts(packageID int, domain string) (float64, error) {
-	args := m.Called(packageID, domain)
-	return args.Get(0).(float64), args.Error(1)
+func (m *perfMock) getCoreMetrics(cpuID int) []coreMetric {
+	args := m.Called(cpuID)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).([]coreMetric)
+}
+
+type perfUncoreMock struct {
+	mockUncorePerf
+}
+
+func (m *perfUncoreMock) update() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *perfUncoreMock) getPackageMetrics(packageID int) []uncoreMetric {
+	args := m.Called(packageID)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).([]uncoreMetric)
+}
+
+func (m *perfUncoreMock) getTimestampDelta() time.Duration {
+	args := m.Called()
+	return args.Get(0).(time.Duration)
 }
 
-func (m *raplMock) getMaxPowerConstraintWatts(packageID int) (float64, error) {
-	args := m.Called(packageID)
-	return args.Get(0).(float64), args.Error(1)
-}
+func TestPower_GetCPUC0SubstateC01Percent(t *testing.T) {
+	t.Run("PerfIsNil", func(t *testing.T) {
+		cpuID := 0
+		c01Exp := 0.0
+
+		pt := &PowerTelemetry{}
+
+		c01Out, err := pt.GetCPUC0SubstateC01Percent(cpuID)
+		require.ErrorContains(t, err, "\"perf\" is not initialized")
+		require.Equal(t, c01Exp, c01Out)
+	})
+
+	t.Run("GetCoreMetricsError", func(t *testing.T) {
+		cpuID := 0
+		c01Exp := 0.0
+
+		mPerf := &perfMock{}
+		mMetrics := []coreMetric{}
+		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
+
+		pt := &PowerTelemetry{
+			perf: mPerf,
+		}
+
+		c01Out, err := pt.GetCPUC0SubstateC01Percent(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("no core metrics found for CPU ID: %v", cpuID))
+		require.Equal(t, c01Exp, c01Out)
+		mPerf.AssertExpectations(t)
+	})
+
+	t.Run("C01MetricNotFound", func(t *testing.T) {
+		cpuID := 0
+		c01Exp := 0.0
+
+		mPerf := &perfMock{}
+		mMetrics := []coreMetric{
+			{
+				name:  c02.String(),
+				cpuID: cpuID,
+				delta: 100,
+			},
+			{
+				name:  thread.String(),
+				cpuID: cpuID,
+				delta: 5000,
+			},
+		}
+		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
+
+		pt := &PowerTelemetry{
+			perf: mPerf,
+		}
+
+		c01Out, err := pt.GetCPUC0SubstateC01Percent(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("could not find metric: %q", c01.String()))
+		require.Equal(t, c01Exp, c01Out)
+		mPerf.AssertExpectations(t)
+	})
+
+	t.Run("ThreadMetricNotFound", func(t *testing.T) {
+		cpuID := 0
+		c01Exp := 0.0
+
+		mPerf := &perfMock{}
+		mMetrics := []coreMetric{
+			{
+				name:  c01.String(),
+				cpuID: cpuID,
+				delta: 100,
+			},
+		}
+		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
+
+		pt := &PowerTelemetry{
+			perf: mPerf,
+		}
+
+		c01Out, err := pt.GetCPUC0SubstateC01Percent(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("could not find metric: %q", thread.String()))
+		require.Equal(t, c01Exp, c01Out)
+		mPerf.AssertExpectations(t)
+	})
+
+	t.Run("ThreadMetricIsZero", func(t *testing.T) {
+		cpuID := 0
+		c01Exp := 0.0
+
+		mPerf := &perfMock{}
+		mMetrics := []coreMetric{
+			{
+				name:  c01.String(),
+				cpuID: cpuID,
+				delta: 100,
+			},
+			{
+				name:  thread.String(),
+				cpuID: cpuID,
+				delta: 0,
+			},
+		}
+		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
+
+		pt := &PowerTelemetry{
+			perf: mPerf,
+		}
+
+		c01Out, err := pt.GetCPUC0SubstateC01Percent(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("zero delta for reference metric: %q", thread.String()))
+		require.Equal(t, c01Exp, c01Out)
+		mPerf.AssertExpectations(t)
+	})
+
+	t.Run("C01State2Per", func(t *testing.T) {
+		cpuID := 0
+		c01Exp := 2.0
+
+		mPerf := &perfMock{}
+		mMetrics := []coreMetric{
+			{
+				name:  c01.String(),
+				cpuID: cpuID,
+				delta: 100,
+			},
+			{
+				name:  thread.String(),
+				cpuID: cpuID,
+				delta: 5000,
+			},
+		}
+		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
+
+		pt := &PowerTelemetry{
+			perf: mPerf,
+		}
+
+		c01Out, err := pt.GetCPUC0SubstateC01Percent(cpuID)
+		require.NoError(t, err)
+		require.Equal(t, c01Exp, c01Out)
+		mPerf.AssertExpectations(t)
+	})
+
+	t.Run("ExactMetricName", func(t *testing.T) {
+		cpuID := 0
+		c01Exp := 2.0
+
+		mPerf := &perfMock{}
+		mMetrics := []coreMetric{
+			{
+				name:  c01.String(),
+				cpuID: cpuID,
+				delta: 100,
+			},
+			{
+				name:  thread.String() + "_P",
+				cpuID: cpuID,
+				delta: 1000,
+			},
+			{
+				name:  strings.ToLower(thread.String()),
+				cpuID: cpuID,
+				delta: 5000,
+			},
+		}
+		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
 
-func TestPower_GetCurrentPackagePowerConsumptionWatts(t *testing.T) {
-	t.Run("RaplIsNil", func(t *testing.T) {
-		packageID := 0
-		currPowerExp := 0.0
+		pt := &PowerTelemetry{
+			perf: mPerf,
+		}
 
-		pt := &PowerTelemetry{}
-		currPowerOut, err := pt.GetCurrentPackagePowerConsumptionWatts(packageID)
-		require.Equal(t, currPowerExp, currPowerOut)
-		require.ErrorContains(t, err, "\"rapl\" is not initialized")
+		c01Out, err := pt.GetCPUC0SubstateC01Percent(cpuID)
+		require.NoError(t, err)
+		require.Equal(t, c01Exp, c01Out)
+		mPerf.AssertExpectations(t)
 	})
 
-	t.Run("FailedToGetCurrentPower", func(t *testing.T) {
-		packageID := 0
-		currPowerExp := 0.0
+	t.Run("MetricNameIsPrefix", func(t *testing.T) {
+		cpuID := 0
+		c01Exp := 0.0
 
-		mError := errors.New("mock error")
-		mRapl := &raplMock{}
-		mRapl.On("getCurrentPowerConsumptionWatts", packageID, packageDomain.String()).Return(currPowerExp, mError).Once()
+		mPerf := &perfMock{}
+		mMetrics := []coreMetric{
+			{
+				name:  c01.String(),
+				cpuID: cpuID,
+				delta: 100,
+			},
+			{
+				name:  thread.String() + "_P",
+				cpuID: cpuID,
+				delta: 1000,
+			},
+		}
+		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
 
 		pt := &PowerTelemetry{
-			rapl: mRapl,
+			perf: mPerf,
 		}
 
-		currPowerOut, err := pt.GetCurrentPackagePowerConsumptionWatts(packageID)
-		require.Equal(t, currPowerExp, currPowerOut)
-		require.ErrorContains(t, err, mError.Error())
-		mRapl.AssertExpectations(t)
+		c01Out, err := pt.GetCPUC0SubstateC01Percent(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("could not find metric: %q", thread.String()))
+		require.Equal(t, c01Exp, c01Out)
+		mPerf.AssertExpectations(t)
 	})
 
-	t.Run("Ok", func(t *testing.T) {
-		packageID := 0
-		currPowerExp := 30.0
+	t.Run("C01State2PerCumulative", func(t *testing.T) {
+		cpuID := 0
+		c01Exp := 2.0
 
-		mRapl := &raplMock{}
-		mRapl.On("getCurrentPowerConsumptionWatts", packageID, packageDomain.String()).Return(currPowerExp, nil).Once()
+		mPerf := &perfMock{}
+		mMetrics := []coreMetric{
+			{
+				name:   c01.String(),
+				cpuID:  cpuID,
+				scaled: 100,
+			},
+			{
+				name:   thread.String(),
+				cpuID:  cpuID,
+				scaled: 5000,
+			},
+		}
+		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
 
 		pt := &PowerTelemetry{
-			rapl: mRapl,
+			perf:           mPerf,
+			perfCumulative: true,
 		}
 
-		currPowerOut, err := pt.GetCurrentPackagePowerConsumptionWatts(packageID)
-		require.Equal(t, currPowerExp, currPowerOut)
+		c01Out, err := pt.GetCPUC0SubstateC01Percent(cpuID)
 		require.NoError(t, err)
-		mRapl.AssertExpectations(t)
+		require.Equal(t, c01Exp, c01Out)
+		mPerf.AssertExpectations(t)
 	})
 }
 
-func TestPower_GetCurrentDramPowerConsumptionWatts(t *testing.T) {
-	t.Run("RaplIsNil", func(t *testing.T) {
-		packageID := 0
-		currPowerExp := 0.0
+func TestPower_GetCPUC0SubstateC02Percent(t *testing.T) {
+	t.Run("PerfIsNil", func(t *testing.T) {
+		cpuID := 0
+		c02Exp := 0.0
 
 		pt := &PowerTelemetry{}
-		currPowerOut, err := pt.GetCurrentDramPowerConsumptionWatts(packageID)
-		require.Equal(t, currPowerExp, currPowerOut)
-		require.ErrorContains(t, err, "\"rapl\" is not initialized")
+
+		c02Out, err := pt.GetCPUC0SubstateC02Percent(cpuID)
+		require.ErrorContains(t, err, "\"perf\" is not initialized")
+		require.Equal(t, c02Exp, c02Out)
 	})
 
-	t.Run("FailedToGetCurrentPower", func(t *testing.T) {
-		packageID := 0
-		currPowerExp := 0.0
+	t.Run("GetCoreMetricsError", func(t *testing.T) {
+		cpuID := 0
+		c02Exp := 0.0
 
-		mError := errors.New("mock error")
-		mRapl := &raplMock{}
-		mRapl.On("getCurrentPowerConsumptionWatts", packageID, dramDomain.String()).Return(currPowerExp, mError).Once()
+		mPerf := &perfMock{}
+		mMetrics := []coreMetric{}
+		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
 
 		pt := &PowerTelemetry{
-			rapl: mRapl,
+			perf: mPerf,
 		}
 
-		currPowerOut, err := pt.GetCurrentDramPowerConsumptionWatts(packageID)
-		require.Equal(t, currPowerExp, currPowerOut)
-		require.ErrorContains(t, err, mError.Error())
-		mRapl.AssertExpectations(t)
+		c02Out, err := pt.GetCPUC0SubstateC02Percent(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("no core metrics found for CPU ID: %v", cpuID))
+		require.Equal(t, c02Exp, c02Out)
+		mPerf.AssertExpectations(t)
 	})
 
-	t.Run("Ok", func(t *testing.T) {
-		packageID := 0
-		currPowerExp := 30.0
+	t.Run("C02MetricNotFound", func(t *testing.T) {
+		cpuID := 0
+		c02Exp := 0.0
 
-		mRapl := &raplMock{}
-		mRapl.On("getCurrentPowerConsumptionWatts", packageID, dramDomain.String()).Return(currPowerExp, nil).Once()
+		mPerf := &perfMock{}
+		mMetrics := []coreMetric{
+			{
+				name:  c01.String(),
+				cpuID: cpuID,
+				delta: 100,
+			},
+			{
+				name:  thread.String(),
+				cpuID: cpuID,
+				delta: 5000,
+			},
+		}
+		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
 
 		pt := &PowerTelemetry{
-			rapl: mRapl,
+			perf: mPerf,
 		}
 
-		currPowerOut, err := pt.GetCurrentDramPowerConsumptionWatts(packageID)
-		require.Equal(t, currPowerExp, currPowerOut)
-		require.NoError(t, err)
-		mRapl.AssertExpectations(t)
+		c02Out, err := pt.GetCPUC0SubstateC02Percent(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("could not find metric: %q", c02.String()))
+		require.Equal(t, c02Exp, c02Out)
+		mPerf.AssertExpectations(t)
 	})
-}
 
-func TestPower_GetPackageThermalDesignPowerWatts(t *testing.T) {
-	t.Run("RaplIsNil", func(t *testing.T) {
-		packageID := 0
-		currPowerExp := 0.0
+	t.Run("ThreadMetricNotFound", func(t *testing.T) {
+		cpuID := 0
+		c02Exp := 0.0
 
-		pt := &PowerTelemetry{}
-		currPowerOut, err := pt.GetPackageThermalDesignPowerWatts(packageID)
-		require.Equal(t, currPowerExp, currPowerOut)
-		require.ErrorContains(t, err, "\"rapl\" is not initialized")
+		mPerf := &perfMock{}
+		mMetrics := []coreMetric{
+			{
+				name:  c02.String(),
+				cpuID: cpuID,
+				delta: 100,
+			},
+		}
+		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
+
+		pt := &PowerTelemetry{
+			perf: mPerf,
+		}
+
+		c02Out, err := pt.GetCPUC0SubstateC02Percent(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("could not find metric: %q", thread.String()))
+		require.Equal(t, c02Exp, c02Out)
+		mPerf.AssertExpectations(t)
 	})
 
-	t.Run("FailedToGetMaxPower", func(t *testing.T) {
-		packageID := 0
-		maxPowerExp := 0.0
+	t.Run("ThreadMetricIsZero", func(t *testing.T) {
+		cpuID := 0
+		c02Exp := 0.0
 
-		mError := errors.New("mock error")
-		mRapl := &raplMock{}
-		mRapl.On("getMaxPowerConstraintWatts", packageID).Return(maxPowerExp, mError).Once()
+		mPerf := &perfMock{}
+		mMetrics := []coreMetric{
+			{
+				name:  c02.String(),
+				cpuID: cpuID,
+				delta: 100,
+			},
+			{
+				name:  thread.String(),
+				cpuID: cpuID,
+				delta: 0,
+			},
+		}
+		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
 
 		pt := &PowerTelemetry{
-			rapl: mRapl,
+			perf: mPerf,
 		}
 
-		maxPowerOut, err := pt.GetPackageThermalDesignPowerWatts(packageID)
-		require.Equal(t, maxPowerExp, maxPowerOut)
-		require.ErrorContains(t, err, mError.Error())
-		mRapl.AssertExpectations(t)
+		c02Out, err := pt.GetCPUC0SubstateC02Percent(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("zero delta for reference metric: %q", thread.String()))
+		require.Equal(t, c02Exp, c02Out)
+		mPerf.AssertExpectations(t)
 	})
 
-	t.Run("Ok", func(t *testing.T) {
-		packageID := 0
-		maxPowerExp := 30.0
+	t.Run("C02State4Per", func(t *testing.T) {
+		cpuID := 0
+		c02Exp := 4.0
 
-		mRapl := &raplMock{}
-		mRapl.On("getMaxPowerConstraintWatts", packageID).Return(maxPowerExp, nil).Once()
+		mPerf := &perfMock{}
+		mMetrics := []coreMetric{
+			{
+				name:  c02.String(),
+				cpuID: cpuID,
+				delta: 200,
+			},
+			{
+				name:  thread.String(),
+				cpuID: cpuID,
+				delta: 5000,
+			},
+		}
+		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
 
 		pt := &PowerTelemetry{
-			rapl: mRapl,
+			perf: mPerf,
 		}
 
-		maxPowerOut, err := pt.GetPackageThermalDesignPowerWatts(packageID)
-		require.Equal(t, maxPowerExp, maxPowerOut)
+		c02Out, err := pt.GetCPUC0SubstateC02Percent(cpuID)
 		require.NoError(t, err)
-		mRapl.AssertExpectations(t)
+		require.Equal(t, c02Exp, c02Out)
+		mPerf.AssertExpectations(t)
 	})
-}
-
-type perfMock struct {
-	mock.Mock
-}
-
-func (m *perfMock) activate(events []string, cores []int) error {
-	args := m.Called(events, cores)
-	return args.Error(0)
-}
-
-func (m *perfMock) read() ([]coreMetric, error) {
-	args := m.Called()
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).([]coreMetric), args.Error(1)
-}
 
-func (m *perfMock) deactivate() error {
-	args := m.Called()
-	return args.Error(0)
-}
+	t.Run("C02State4PerCumulative", func(t *testing.T) {
+		cpuID := 0
+		c02Exp := 4.0
 
-func (m *perfMock) initResolver(jsonFile string) error {
-	args := m.Called(jsonFile)
-	return args.Error(0)
-}
+		mPerf := &perfMock{}
+		mMetrics := []coreMetric{
+			{
+				name:   c02.String(),
+				cpuID:  cpuID,
+				scaled: 200,
+			},
+			{
+				name:   thread.String(),
+				cpuID:  cpuID,
+				scaled: 5000,
+			},
+		}
+		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
 
-func (m *perfMock) update() error {
-	args := m.Called()
-	return args.Error(0)
-}
+		pt := &PowerTelemetry{
+			perf:           mPerf,
+			perfCumulative: true,
+		}
 
-func (m *perfMock) getCoreMetrics(cpuID int) []coreMetric {
-	args := m.Called(cpuID)
-	if args.Get(0) == nil {
-		return nil
-	}
-	return args.Get(0).([]coreMetric)
+		c02Out, err := pt.GetCPUC0SubstateC02Percent(cpuID)
+		require.NoError(t, err)
+		require.Equal(t, c02Exp, c02Out)
+		mPerf.AssertExpectations(t)
+	})
 }
 
-func TestPower_GetCPUC0SubstateC01Percent(t *testing.T) {
+func TestPower_GetCPUC0SubstateC0WaitPercent(t *testing.T) {
 	t.Run("PerfIsNil", func(t *testing.T) {
 		cpuID := 0
-		c01Exp := 0.0
+		c0WaitExp := 0.0
 
 		pt := &PowerTelemetry{}
 
-		c01Out, err := pt.GetCPUC0SubstateC01Percent(cpuID)
+		c0WaitOut, err := pt.GetCPUC0SubstateC0WaitPercent(cpuID)
 		require.ErrorContains(t, err, "\"perf\" is not initialized")
-		require.Equal(t, c01Exp, c01Out)
+		require.Equal(t, c0WaitExp, c0WaitOut)
 	})
 
 	t.Run("GetCoreMetricsError", func(t *testing.T) {
 		cpuID := 0
-		c01Exp := 0.0
+		c0WaitExp := 0.0
 
 		mPerf := &perfMock{}
 		mMetrics := []coreMetric{}
@@ -3680,27 +6952,27 @@ func TestPower_GetCPUC0SubstateC01Percent(t *testing.T) {
 			perf: mPerf,
 		}
 
-		c01Out, err := pt.GetCPUC0SubstateC01Percent(cpuID)
+		c0WaitOut, err := pt.GetCPUC0SubstateC0WaitPercent(cpuID)
 		require.ErrorContains(t, err, fmt.Sprintf("no core metrics found for CPU ID: %v", cpuID))
-		require.Equal(t, c01Exp, c01Out)
+		require.Equal(t, c0WaitExp, c0WaitOut)
 		mPerf.AssertExpectations(t)
 	})
 
-	t.Run("C01MetricNotFound", func(t *testing.T) {
+	t.Run("C0WaitMetricNotFound", func(t *testing.T) {
 		cpuID := 0
-		c01Exp := 0.0
+		c0WaitExp := 0.0
 
 		mPerf := &perfMock{}
 		mMetrics := []coreMetric{
 			{
-				name:   c02.String(),
-				cpuID:  cpuID,
-				scaled: 100,
+				name:  c01.String(),
+				cpuID: cpuID,
+				delta: 100,
 			},
 			{
-				name:   thread.String(),
-				cpuID:  cpuID,
-				scaled: 5000,
+				name:  thread.String(),
+				cpuID: cpuID,
+				delta: 5000,
 			},
 		}
 		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
@@ -3709,22 +6981,22 @@ func TestPower_GetCPUC0SubstateC01Percent(t *testing.T) {
 			perf: mPerf,
 		}
 
-		c01Out, err := pt.GetCPUC0SubstateC01Percent(cpuID)
-		require.ErrorContains(t, err, fmt.Sprintf("could not find metric: %q", c01.String()))
-		require.Equal(t, c01Exp, c01Out)
+		c0WaitOut, err := pt.GetCPUC0SubstateC0WaitPercent(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("could not find metric: %q", c0Wait.String()))
+		require.Equal(t, c0WaitExp, c0WaitOut)
 		mPerf.AssertExpectations(t)
 	})
 
 	t.Run("ThreadMetricNotFound", func(t *testing.T) {
 		cpuID := 0
-		c01Exp := 0.0
+		c0WaitExp := 0.0
 
 		mPerf := &perfMock{}
 		mMetrics := []coreMetric{
 			{
-				name:   c01.String(),
-				cpuID:  cpuID,
-				scaled: 100,
+				name:  c0Wait.String(),
+				cpuID: cpuID,
+				delta: 100,
 			},
 		}
 		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
@@ -3733,27 +7005,27 @@ func TestPower_GetCPUC0SubstateC01Percent(t *testing.T) {
 			perf: mPerf,
 		}
 
-		c01Out, err := pt.GetCPUC0SubstateC01Percent(cpuID)
+		c0WaitOut, err := pt.GetCPUC0SubstateC0WaitPercent(cpuID)
 		require.ErrorContains(t, err, fmt.Sprintf("could not find metric: %q", thread.String()))
-		require.Equal(t, c01Exp, c01Out)
+		require.Equal(t, c0WaitExp, c0WaitOut)
 		mPerf.AssertExpectations(t)
 	})
 
 	t.Run("ThreadMetricIsZero", func(t *testing.T) {
 		cpuID := 0
-		c01Exp := 0.0
+		c0WaitExp := 0.0
 
 		mPerf := &perfMock{}
 		mMetrics := []coreMetric{
 			{
-				name:   c01.String(),
-				cpuID:  cpuID,
-				scaled: 100,
+				name:  c0Wait.String(),
+				cpuID: cpuID,
+				delta: 100,
 			},
 			{
-				name:   thread.String(),
-				cpuID:  cpuID,
-				scaled: 0,
+				name:  thread.String(),
+				cpuID: cpuID,
+				delta: 0,
 			},
 		}
 		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
@@ -3762,22 +7034,51 @@ func TestPower_GetCPUC0SubstateC01Percent(t *testing.T) {
 			perf: mPerf,
 		}
 
-		c01Out, err := pt.GetCPUC0SubstateC01Percent(cpuID)
-		require.ErrorContains(t, err, fmt.Sprintf("zero scaled value for reference metric: %q", thread.String()))
-		require.Equal(t, c01Exp, c01Out)
+		c0WaitOut, err := pt.GetCPUC0SubstateC0WaitPercent(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("zero delta for reference metric: %q", thread.String()))
+		require.Equal(t, c0WaitExp, c0WaitOut)
 		mPerf.AssertExpectations(t)
 	})
 
-	t.Run("C01State2Per", func(t *testing.T) {
+	t.Run("C0WaitState10Per", func(t *testing.T) {
 		cpuID := 0
-		c01Exp := 2.0
+		c0WaitExp := 10.0
 
 		mPerf := &perfMock{}
 		mMetrics := []coreMetric{
 			{
-				name:   c01.String(),
+				name:  c0Wait.String(),
+				cpuID: cpuID,
+				delta: 500,
+			},
+			{
+				name:  thread.String(),
+				cpuID: cpuID,
+				delta: 5000,
+			},
+		}
+		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
+
+		pt := &PowerTelemetry{
+			perf: mPerf,
+		}
+
+		c0WaitOut, err := pt.GetCPUC0SubstateC0WaitPercent(cpuID)
+		require.NoError(t, err)
+		require.Equal(t, c0WaitExp, c0WaitOut)
+		mPerf.AssertExpectations(t)
+	})
+
+	t.Run("C0WaitState10PerCumulative", func(t *testing.T) {
+		cpuID := 0
+		c0WaitExp := 10.0
+
+		mPerf := &perfMock{}
+		mMetrics := []coreMetric{
+			{
+				name:   c0Wait.String(),
 				cpuID:  cpuID,
-				scaled: 100,
+				scaled: 500,
 			},
 			{
 				name:   thread.String(),
@@ -3788,296 +7089,477 @@ func TestPower_GetCPUC0SubstateC01Percent(t *testing.T) {
 		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
 
 		pt := &PowerTelemetry{
-			perf: mPerf,
+			perf:           mPerf,
+			perfCumulative: true,
 		}
 
-		c01Out, err := pt.GetCPUC0SubstateC01Percent(cpuID)
+		c0WaitOut, err := pt.GetCPUC0SubstateC0WaitPercent(cpuID)
 		require.NoError(t, err)
-		require.Equal(t, c01Exp, c01Out)
+		require.Equal(t, c0WaitExp, c0WaitOut)
 		mPerf.AssertExpectations(t)
 	})
 }
 
-func TestPower_GetCPUC0SubstateC02Percent(t *testing.T) {
-	t.Run("PerfIsNil", func(t *testing.T) {
-		cpuID := 0
-		c02Exp := 0.0
+func TestPower_GetCPUIPC(t *testing.T) {
+	cpuID := 2
 
+	t.Run("PerfIsNil", func(t *testing.T) {
 		pt := &PowerTelemetry{}
 
-		c02Out, err := pt.GetCPUC0SubstateC02Percent(cpuID)
+		ipc, err := pt.GetCPUIPC(cpuID)
 		require.ErrorContains(t, err, "\"perf\" is not initialized")
-		require.Equal(t, c02Exp, c02Out)
+		require.Zero(t, ipc)
 	})
 
-	t.Run("GetCoreMetricsError", func(t *testing.T) {
-		cpuID := 0
-		c02Exp := 0.0
+	t.Run("NoCoreMetrics", func(t *testing.T) {
+		mPerf := &perfMock{}
+		mPerf.On("getCoreMetrics", cpuID).Return([]coreMetric{}).Once()
+
+		pt := &PowerTelemetry{
+			perf: mPerf,
+		}
 
+		ipc, err := pt.GetCPUIPC(cpuID)
+		require.ErrorContains(t, err, "no core metrics found for CPU ID: 2")
+		require.Zero(t, ipc)
+		mPerf.AssertExpectations(t)
+	})
+
+	t.Run("InstRetiredNotFound", func(t *testing.T) {
 		mPerf := &perfMock{}
-		mMetrics := []coreMetric{}
-		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
+		mPerf.On("getCoreMetrics", cpuID).Return([]coreMetric{
+			{name: thread.String(), cpuID: cpuID, delta: 1000},
+		}).Once()
 
 		pt := &PowerTelemetry{
 			perf: mPerf,
 		}
 
-		c02Out, err := pt.GetCPUC0SubstateC02Percent(cpuID)
-		require.ErrorContains(t, err, fmt.Sprintf("no core metrics found for CPU ID: %v", cpuID))
-		require.Equal(t, c02Exp, c02Out)
+		ipc, err := pt.GetCPUIPC(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("could not find metric: %q", instRetired))
+		require.Zero(t, ipc)
 		mPerf.AssertExpectations(t)
 	})
 
-	t.Run("C02MetricNotFound", func(t *testing.T) {
-		cpuID := 0
-		c02Exp := 0.0
+	t.Run("ZeroCycles", func(t *testing.T) {
+		mPerf := &perfMock{}
+		mPerf.On("getCoreMetrics", cpuID).Return([]coreMetric{
+			{name: instRetired, cpuID: cpuID, delta: 1000},
+			{name: thread.String(), cpuID: cpuID, delta: 0},
+		}).Once()
+
+		pt := &PowerTelemetry{
+			perf: mPerf,
+		}
 
+		ipc, err := pt.GetCPUIPC(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("zero delta for reference metric: %q", thread.String()))
+		require.Zero(t, ipc)
+		mPerf.AssertExpectations(t)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
 		mPerf := &perfMock{}
-		mMetrics := []coreMetric{
-			{
-				name:   c01.String(),
-				cpuID:  cpuID,
-				scaled: 100,
-			},
-			{
-				name:   thread.String(),
-				cpuID:  cpuID,
-				scaled: 5000,
-			},
+		mPerf.On("getCoreMetrics", cpuID).Return([]coreMetric{
+			{name: instRetired, cpuID: cpuID, scaled: 10_000, delta: 3000},
+			{name: thread.String(), cpuID: cpuID, scaled: 20_000, delta: 2000},
+		}).Once()
+
+		pt := &PowerTelemetry{
+			perf: mPerf,
 		}
-		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
+
+		ipc, err := pt.GetCPUIPC(cpuID)
+		require.NoError(t, err)
+		require.InDelta(t, 1.5, ipc, 1e-9)
+		mPerf.AssertExpectations(t)
+	})
+}
+
+func TestPower_GetCPUCPI(t *testing.T) {
+	cpuID := 2
+
+	t.Run("ZeroInstructions", func(t *testing.T) {
+		mPerf := &perfMock{}
+		mPerf.On("getCoreMetrics", cpuID).Return([]coreMetric{
+			{name: instRetired, cpuID: cpuID, delta: 0},
+			{name: thread.String(), cpuID: cpuID, delta: 1000},
+		}).Once()
+
+		pt := &PowerTelemetry{
+			perf: mPerf,
+		}
+
+		cpi, err := pt.GetCPUCPI(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("zero delta for reference metric: %q", instRetired))
+		require.Zero(t, cpi)
+		mPerf.AssertExpectations(t)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		mPerf := &perfMock{}
+		mPerf.On("getCoreMetrics", cpuID).Return([]coreMetric{
+			{name: instRetired, cpuID: cpuID, delta: 4000},
+			{name: thread.String(), cpuID: cpuID, delta: 1000},
+		}).Once()
+
+		pt := &PowerTelemetry{
+			perf: mPerf,
+		}
+
+		cpi, err := pt.GetCPUCPI(cpuID)
+		require.NoError(t, err)
+		require.InDelta(t, 0.25, cpi, 1e-9)
+		mPerf.AssertExpectations(t)
+	})
+}
+
+func TestPower_GetCPUUnhaltedFrequencyMhz(t *testing.T) {
+	cpuID := 2
+
+	setTSCFrequency := func(t *testing.T, hz uint64) {
+		t.Helper()
+
+		orig := tscFrequency
+		tscFrequency = func() uint64 {
+			return hz
+		}
+		t.Cleanup(func() {
+			tscFrequency = orig
+		})
+	}
+
+	t.Run("RefCyclesNotFound", func(t *testing.T) {
+		mPerf := &perfMock{}
+		mPerf.On("getCoreMetrics", cpuID).Return([]coreMetric{
+			{name: thread.String(), cpuID: cpuID, delta: 1000},
+		}).Once()
+
+		pt := &PowerTelemetry{
+			perf: mPerf,
+		}
+
+		freq, err := pt.GetCPUUnhaltedFrequencyMhz(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("could not find metric: %q", refCycles))
+		require.Zero(t, freq)
+		mPerf.AssertExpectations(t)
+	})
+
+	t.Run("TSCFrequencyNotAvailable", func(t *testing.T) {
+		setTSCFrequency(t, 0)
+
+		mPerf := &perfMock{}
+		mPerf.On("getCoreMetrics", cpuID).Return([]coreMetric{
+			{name: thread.String(), cpuID: cpuID, delta: 3000},
+			{name: refCycles, cpuID: cpuID, delta: 2000},
+		}).Once()
+
+		pt := &PowerTelemetry{
+			perf: mPerf,
+		}
+
+		freq, err := pt.GetCPUUnhaltedFrequencyMhz(cpuID)
+		require.ErrorContains(t, err, "nominal frequency of the time stamp counter is not available")
+		require.Zero(t, freq)
+		mPerf.AssertExpectations(t)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		setTSCFrequency(t, 2_000_000_000)
+
+		mPerf := &perfMock{}
+		mPerf.On("getCoreMetrics", cpuID).Return([]coreMetric{
+			{name: thread.String(), cpuID: cpuID, delta: 3000},
+			{name: refCycles, cpuID: cpuID, delta: 2000},
+		}).Once()
+
+		pt := &PowerTelemetry{
+			perf: mPerf,
+		}
+
+		freq, err := pt.GetCPUUnhaltedFrequencyMhz(cpuID)
+		require.NoError(t, err)
+		require.InDelta(t, 3000.0, freq, 1e-9)
+		mPerf.AssertExpectations(t)
+	})
+}
+
+func TestPower_GetCPUTopdownPercent(t *testing.T) {
+	cpuID := 1
+
+	metrics := []coreMetric{
+		{name: topdownSlots, cpuID: cpuID, delta: 8000},
+		{name: topdownRetiring, cpuID: cpuID, delta: 2000},
+		{name: topdownBadSpeculation, cpuID: cpuID, delta: 400},
+		{name: topdownFrontendBound, cpuID: cpuID, delta: 1600},
+		{name: topdownBackendBound, cpuID: cpuID, delta: 4000},
+	}
+
+	testCases := []struct {
+		name     string
+		getter   func(pt *PowerTelemetry, cpuID int) (float64, error)
+		expected float64
+	}{
+		{
+			name:     "Retiring",
+			getter:   (*PowerTelemetry).GetCPUTopdownRetiringPercent,
+			expected: 25.0,
+		},
+		{
+			name:     "BadSpeculation",
+			getter:   (*PowerTelemetry).GetCPUTopdownBadSpeculationPercent,
+			expected: 5.0,
+		},
+		{
+			name:     "FrontendBound",
+			getter:   (*PowerTelemetry).GetCPUTopdownFrontendBoundPercent,
+			expected: 20.0,
+		},
+		{
+			name:     "BackendBound",
+			getter:   (*PowerTelemetry).GetCPUTopdownBackendBoundPercent,
+			expected: 50.0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mPerf := &perfMock{}
+			mPerf.On("getCoreMetrics", cpuID).Return(metrics).Once()
+
+			pt := &PowerTelemetry{
+				perf: mPerf,
+			}
+
+			percent, err := tc.getter(pt, cpuID)
+			require.NoError(t, err)
+			require.InDelta(t, tc.expected, percent, 1e-9)
+			mPerf.AssertExpectations(t)
+		})
+	}
+
+	t.Run("PerfIsNil", func(t *testing.T) {
+		pt := &PowerTelemetry{}
+
+		percent, err := pt.GetCPUTopdownRetiringPercent(cpuID)
+		require.ErrorContains(t, err, "\"perf\" is not initialized")
+		require.Zero(t, percent)
+	})
+
+	t.Run("SlotsNotFound", func(t *testing.T) {
+		mPerf := &perfMock{}
+		mPerf.On("getCoreMetrics", cpuID).Return(metrics[1:]).Once()
 
 		pt := &PowerTelemetry{
 			perf: mPerf,
 		}
 
-		c02Out, err := pt.GetCPUC0SubstateC02Percent(cpuID)
-		require.ErrorContains(t, err, fmt.Sprintf("could not find metric: %q", c02.String()))
-		require.Equal(t, c02Exp, c02Out)
+		percent, err := pt.GetCPUTopdownBackendBoundPercent(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("could not find metric: %q", topdownSlots))
+		require.Zero(t, percent)
 		mPerf.AssertExpectations(t)
 	})
 
-	t.Run("ThreadMetricNotFound", func(t *testing.T) {
-		cpuID := 0
-		c02Exp := 0.0
-
+	t.Run("ZeroSlots", func(t *testing.T) {
 		mPerf := &perfMock{}
-		mMetrics := []coreMetric{
-			{
-				name:   c02.String(),
-				cpuID:  cpuID,
-				scaled: 100,
-			},
-		}
-		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
+		mPerf.On("getCoreMetrics", cpuID).Return([]coreMetric{
+			{name: topdownSlots, cpuID: cpuID},
+			{name: topdownFrontendBound, cpuID: cpuID},
+		}).Once()
 
 		pt := &PowerTelemetry{
 			perf: mPerf,
 		}
 
-		c02Out, err := pt.GetCPUC0SubstateC02Percent(cpuID)
-		require.ErrorContains(t, err, fmt.Sprintf("could not find metric: %q", thread.String()))
-		require.Equal(t, c02Exp, c02Out)
+		percent, err := pt.GetCPUTopdownFrontendBoundPercent(cpuID)
+		require.ErrorContains(t, err, fmt.Sprintf("zero delta for reference metric: %q", topdownSlots))
+		require.Zero(t, percent)
 		mPerf.AssertExpectations(t)
 	})
+}
 
-	t.Run("ThreadMetricIsZero", func(t *testing.T) {
-		cpuID := 0
-		c02Exp := 0.0
+func TestPower_GetCPUCoreType(t *testing.T) {
+	t.Run("PerfIsNil", func(t *testing.T) {
+		pt := &PowerTelemetry{}
+
+		coreType, err := pt.GetCPUCoreType(0)
+		require.ErrorContains(t, err, "\"perf\" is not initialized")
+		require.Empty(t, coreType)
+	})
 
+	t.Run("NoCoreMetrics", func(t *testing.T) {
 		mPerf := &perfMock{}
-		mMetrics := []coreMetric{
-			{
-				name:   c02.String(),
-				cpuID:  cpuID,
-				scaled: 100,
-			},
-			{
-				name:   thread.String(),
-				cpuID:  cpuID,
-				scaled: 0,
-			},
-		}
-		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
+		mPerf.On("getCoreMetrics", 3).Return([]coreMetric{}).Once()
 
 		pt := &PowerTelemetry{
 			perf: mPerf,
 		}
 
-		c02Out, err := pt.GetCPUC0SubstateC02Percent(cpuID)
-		require.ErrorContains(t, err, fmt.Sprintf("zero scaled value for reference metric: %q", thread.String()))
-		require.Equal(t, c02Exp, c02Out)
+		coreType, err := pt.GetCPUCoreType(3)
+		require.ErrorContains(t, err, "no core metrics found for CPU ID: 3")
+		require.Empty(t, coreType)
 		mPerf.AssertExpectations(t)
 	})
 
-	t.Run("C02State4Per", func(t *testing.T) {
-		cpuID := 0
-		c02Exp := 4.0
-
+	t.Run("Valid", func(t *testing.T) {
 		mPerf := &perfMock{}
-		mMetrics := []coreMetric{
-			{
-				name:   c02.String(),
-				cpuID:  cpuID,
-				scaled: 200,
-			},
-			{
-				name:   thread.String(),
-				cpuID:  cpuID,
-				scaled: 5000,
-			},
-		}
-		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
+		mPerf.On("getCoreMetrics", 8).Return([]coreMetric{
+			{name: instRetired, cpuID: 8, coreType: CoreTypeAtom},
+		}).Once()
 
 		pt := &PowerTelemetry{
 			perf: mPerf,
 		}
 
-		c02Out, err := pt.GetCPUC0SubstateC02Percent(cpuID)
+		coreType, err := pt.GetCPUCoreType(8)
 		require.NoError(t, err)
-		require.Equal(t, c02Exp, c02Out)
+		require.Equal(t, CoreTypeAtom, coreType)
 		mPerf.AssertExpectations(t)
 	})
 }
 
-func TestPower_GetCPUC0SubstateC0WaitPercent(t *testing.T) {
-	t.Run("PerfIsNil", func(t *testing.T) {
-		cpuID := 0
-		c0WaitExp := 0.0
+func TestPower_GetPerfEventValue(t *testing.T) {
+	cpuID := 1
 
+	t.Run("PerfIsNil", func(t *testing.T) {
 		pt := &PowerTelemetry{}
 
-		c0WaitOut, err := pt.GetCPUC0SubstateC0WaitPercent(cpuID)
+		value, err := pt.GetPerfEventValue(cpuID, "INST_RETIRED.ANY")
 		require.ErrorContains(t, err, "\"perf\" is not initialized")
-		require.Equal(t, c0WaitExp, c0WaitOut)
+		require.Equal(t, PerfEventValue{}, value)
 	})
 
-	t.Run("GetCoreMetricsError", func(t *testing.T) {
-		cpuID := 0
-		c0WaitExp := 0.0
-
+	t.Run("EventNotFound", func(t *testing.T) {
 		mPerf := &perfMock{}
-		mMetrics := []coreMetric{}
-		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
+		mPerf.On("getCoreMetrics", cpuID).Return([]coreMetric{
+			{name: "CPU_CLK_UNHALTED.THREAD", cpuID: cpuID, scaled: 100},
+		}).Once()
 
 		pt := &PowerTelemetry{
 			perf: mPerf,
 		}
 
-		c0WaitOut, err := pt.GetCPUC0SubstateC0WaitPercent(cpuID)
-		require.ErrorContains(t, err, fmt.Sprintf("no core metrics found for CPU ID: %v", cpuID))
-		require.Equal(t, c0WaitExp, c0WaitOut)
+		value, err := pt.GetPerfEventValue(cpuID, "INST_RETIRED.ANY")
+		require.ErrorContains(t, err, "could not find perf event \"INST_RETIRED.ANY\" for CPU ID 1")
+		require.Equal(t, PerfEventValue{}, value)
 		mPerf.AssertExpectations(t)
 	})
 
-	t.Run("C0WaitMetricNotFound", func(t *testing.T) {
-		cpuID := 0
-		c0WaitExp := 0.0
-
+	t.Run("Valid", func(t *testing.T) {
 		mPerf := &perfMock{}
-		mMetrics := []coreMetric{
-			{
-				name:   c01.String(),
-				cpuID:  cpuID,
-				scaled: 100,
-			},
+		mPerf.On("getCoreMetrics", cpuID).Return([]coreMetric{
+			{name: "CPU_CLK_UNHALTED.THREAD", cpuID: cpuID, scaled: 100},
 			{
-				name:   thread.String(),
+				name:   "INST_RETIRED.ANY",
 				cpuID:  cpuID,
-				scaled: 5000,
+				values: ev.CounterValue{Raw: 500, Enabled: 2000, Running: 1000},
+				scaled: 1000,
+				delta:  400,
 			},
-		}
-		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
+		}).Once()
 
 		pt := &PowerTelemetry{
 			perf: mPerf,
 		}
 
-		c0WaitOut, err := pt.GetCPUC0SubstateC0WaitPercent(cpuID)
-		require.ErrorContains(t, err, fmt.Sprintf("could not find metric: %q", c0Wait.String()))
-		require.Equal(t, c0WaitExp, c0WaitOut)
+		value, err := pt.GetPerfEventValue(cpuID, "inst_retired.any")
+		require.NoError(t, err)
+		require.Equal(t, PerfEventValue{Raw: 500, Enabled: 2000, Running: 1000, Scaled: 1000, Delta: 400, LowConfidence: true}, value)
 		mPerf.AssertExpectations(t)
 	})
 
-	t.Run("ThreadMetricNotFound", func(t *testing.T) {
-		cpuID := 0
-		c0WaitExp := 0.0
+	t.Run("Multiplexed", func(t *testing.T) {
+		testCases := []struct {
+			name          string
+			ratio         float64
+			lowConfidence bool
+		}{
+			{name: "NotMultiplexed", ratio: 1},
+			{name: "AtThreshold", ratio: LowConfidenceMultiplexRatio},
+			{name: "LowConfidence", ratio: 0.2, lowConfidence: true},
+		}
 
-		mPerf := &perfMock{}
-		mMetrics := []coreMetric{
-			{
-				name:   c0Wait.String(),
-				cpuID:  cpuID,
-				scaled: 100,
-			},
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				mPerf := &perfMock{}
+				mPerf.On("getCoreMetrics", cpuID).Return([]coreMetric{
+					{name: "INST_RETIRED.ANY", cpuID: cpuID, scaled: 1000, multiplexRatio: tc.ratio},
+				}).Once()
+
+				pt := &PowerTelemetry{
+					perf: mPerf,
+				}
+
+				value, err := pt.GetPerfEventValue(cpuID, "INST_RETIRED.ANY")
+				require.NoError(t, err)
+				require.Equal(t, PerfEventValue{Scaled: 1000, MultiplexRatio: tc.ratio, LowConfidence: tc.lowConfidence}, value)
+				mPerf.AssertExpectations(t)
+			})
 		}
-		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
+	})
+}
+
+func TestPower_GetCPUPerfMultiplexing(t *testing.T) {
+	cpuID := 1
+
+	t.Run("PerfIsNil", func(t *testing.T) {
+		pt := &PowerTelemetry{}
+
+		multiplexing, err := pt.GetCPUPerfMultiplexing(cpuID)
+		require.ErrorContains(t, err, "\"perf\" is not initialized")
+		require.Equal(t, PerfMultiplexing{}, multiplexing)
+	})
+
+	t.Run("NoCoreMetrics", func(t *testing.T) {
+		mPerf := &perfMock{}
+		mPerf.On("getCoreMetrics", cpuID).Return([]coreMetric{}).Once()
 
 		pt := &PowerTelemetry{
 			perf: mPerf,
 		}
 
-		c0WaitOut, err := pt.GetCPUC0SubstateC0WaitPercent(cpuID)
-		require.ErrorContains(t, err, fmt.Sprintf("could not find metric: %q", thread.String()))
-		require.Equal(t, c0WaitExp, c0WaitOut)
+		multiplexing, err := pt.GetCPUPerfMultiplexing(cpuID)
+		require.ErrorContains(t, err, "no core metrics found for CPU ID: 1")
+		require.Equal(t, PerfMultiplexing{}, multiplexing)
 		mPerf.AssertExpectations(t)
 	})
 
-	t.Run("ThreadMetricIsZero", func(t *testing.T) {
-		cpuID := 0
-		c0WaitExp := 0.0
-
+	t.Run("NotMultiplexed", func(t *testing.T) {
 		mPerf := &perfMock{}
-		mMetrics := []coreMetric{
-			{
-				name:   c0Wait.String(),
-				cpuID:  cpuID,
-				scaled: 100,
-			},
-			{
-				name:   thread.String(),
-				cpuID:  cpuID,
-				scaled: 0,
-			},
-		}
-		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
+		mPerf.On("getCoreMetrics", cpuID).Return([]coreMetric{
+			{name: "CPU_CLK_UNHALTED.THREAD", cpuID: cpuID, multiplexRatio: 1},
+			{name: "INST_RETIRED.ANY", cpuID: cpuID, multiplexRatio: 1},
+		}).Once()
 
 		pt := &PowerTelemetry{
 			perf: mPerf,
 		}
 
-		c0WaitOut, err := pt.GetCPUC0SubstateC0WaitPercent(cpuID)
-		require.ErrorContains(t, err, fmt.Sprintf("zero scaled value for reference metric: %q", thread.String()))
-		require.Equal(t, c0WaitExp, c0WaitOut)
+		multiplexing, err := pt.GetCPUPerfMultiplexing(cpuID)
+		require.NoError(t, err)
+		require.Equal(t, PerfMultiplexing{MinRatio: 1, MeanRatio: 1}, multiplexing)
 		mPerf.AssertExpectations(t)
 	})
 
-	t.Run("C0WaitState10Per", func(t *testing.T) {
-		cpuID := 0
-		c0WaitExp := 10.0
-
+	t.Run("LowConfidence", func(t *testing.T) {
 		mPerf := &perfMock{}
-		mMetrics := []coreMetric{
-			{
-				name:   c0Wait.String(),
-				cpuID:  cpuID,
-				scaled: 500,
-			},
-			{
-				name:   thread.String(),
-				cpuID:  cpuID,
-				scaled: 5000,
-			},
-		}
-		mPerf.On("getCoreMetrics", cpuID).Return(mMetrics).Once()
+		mPerf.On("getCoreMetrics", cpuID).Return([]coreMetric{
+			{name: "CPU_CLK_UNHALTED.THREAD", cpuID: cpuID, multiplexRatio: 1},
+			{name: "CPU_CLK_UNHALTED.C01", cpuID: cpuID, multiplexRatio: 0.6},
+			{name: "CPU_CLK_UNHALTED.C02", cpuID: cpuID, multiplexRatio: 0.3},
+			{name: "CPU_CLK_UNHALTED.C0_WAIT", cpuID: cpuID, multiplexRatio: 0.1},
+		}).Once()
 
 		pt := &PowerTelemetry{
 			perf: mPerf,
 		}
 
-		c0WaitOut, err := pt.GetCPUC0SubstateC0WaitPercent(cpuID)
+		multiplexing, err := pt.GetCPUPerfMultiplexing(cpuID)
 		require.NoError(t, err)
-		require.Equal(t, c0WaitExp, c0WaitOut)
+		require.InDelta(t, 0.1, multiplexing.MinRatio, 1e-9)
+		require.InDelta(t, 0.5, multiplexing.MeanRatio, 1e-9)
+		require.True(t, multiplexing.LowConfidence)
+		require.Equal(t, []string{"CPU_CLK_UNHALTED.C02", "CPU_CLK_UNHALTED.C0_WAIT"}, multiplexing.LowConfidenceEvents)
 		mPerf.AssertExpectations(t)
 	})
 }
@@ -4116,6 +7598,36 @@ func TestPower_ReadPerfEvents(t *testing.T) {
 		require.NoError(t, err)
 		mPerf.AssertExpectations(t)
 	})
+
+	t.Run("FailedToReadUncore", func(t *testing.T) {
+		mPerf := &perfMock{}
+		mPerf.On("update").Return(nil).Once()
+
+		mPerfUncore := &perfUncoreMock{}
+		mPerfUncore.On("update").Return(errors.New("mock error")).Once()
+
+		pt := &PowerTelemetry{
+			perf:       mPerf,
+			perfUncore: mPerfUncore,
+		}
+
+		err := pt.ReadPerfEvents()
+		require.ErrorContains(t, err, "error reading uncore perf events: mock error")
+		mPerf.AssertExpectations(t)
+		mPerfUncore.AssertExpectations(t)
+	})
+
+	t.Run("SuccessfulReadUncoreOnly", func(t *testing.T) {
+		mPerfUncore := &perfUncoreMock{}
+		mPerfUncore.On("update").Return(nil).Once()
+
+		pt := &PowerTelemetry{
+			perfUncore: mPerfUncore,
+		}
+
+		require.NoError(t, pt.ReadPerfEvents())
+		mPerfUncore.AssertExpectations(t)
+	})
 }
 
 func TestPower_DeactivatePerfEvents(t *testing.T) {
@@ -4152,6 +7664,153 @@ func TestPower_DeactivatePerfEvents(t *testing.T) {
 		require.NoError(t, err)
 		mPerf.AssertExpectations(t)
 	})
+
+	t.Run("SuccessfulDeactivationWithUncore", func(t *testing.T) {
+		mPerf := &perfMock{}
+		mPerf.On("deactivate").Return(nil).Once()
+
+		mPerfUncore := &perfUncoreMock{}
+		mPerfUncore.On("deactivate").Return(nil).Once()
+
+		pt := &PowerTelemetry{
+			perf:       mPerf,
+			perfUncore: mPerfUncore,
+		}
+
+		require.NoError(t, pt.DeactivatePerfEvents())
+		mPerf.AssertExpectations(t)
+		mPerfUncore.AssertExpectations(t)
+	})
+
+	t.Run("PerfCStates", func(t *testing.T) {
+		t.Run("FailedToDeactivate", func(t *testing.T) {
+			m := &pmuCounterMock{}
+			m.On("deactivate").Return(errors.New("mock error")).Once()
+
+			pt := &PowerTelemetry{
+				cstatePerf: m,
+			}
+
+			err := pt.DeactivatePerfEvents()
+			require.ErrorContains(t, err, "error deactivating C-state residency events")
+			m.AssertExpectations(t)
+		})
+
+		t.Run("SuccessfulDeactivation", func(t *testing.T) {
+			m := &pmuCounterMock{}
+			m.On("deactivate").Return(nil).Once()
+
+			pt := &PowerTelemetry{
+				cstatePerf: m,
+			}
+
+			require.NoError(t, pt.DeactivatePerfEvents())
+			m.AssertExpectations(t)
+		})
+	})
+
+	t.Run("PerfMsr", func(t *testing.T) {
+		t.Run("FailedToDeactivate", func(t *testing.T) {
+			m := &pmuCounterMock{}
+			m.On("deactivate").Return(errors.New("mock error")).Once()
+
+			pt := &PowerTelemetry{
+				msrPerf: m,
+			}
+
+			err := pt.DeactivatePerfEvents()
+			require.ErrorContains(t, err, "error deactivating msr PMU events")
+			m.AssertExpectations(t)
+		})
+
+		t.Run("SuccessfulDeactivation", func(t *testing.T) {
+			m := &pmuCounterMock{}
+			m.On("deactivate").Return(nil).Once()
+
+			pt := &PowerTelemetry{
+				msrPerf: m,
+			}
+
+			require.NoError(t, pt.DeactivatePerfEvents())
+			m.AssertExpectations(t)
+		})
+	})
+}
+
+func TestPower_GetPackageMemoryBandwidth(t *testing.T) {
+	packageID := 1
+
+	t.Run("PerfUncoreIsNil", func(t *testing.T) {
+		pt := &PowerTelemetry{}
+
+		bandwidth, err := pt.GetPackageMemoryBandwidth(packageID)
+		require.ErrorContains(t, err, "\"perf_uncore\" is not initialized")
+		require.Equal(t, MemoryBandwidth{}, bandwidth)
+	})
+
+	t.Run("IntervalNotAvailable", func(t *testing.T) {
+		mPerfUncore := &perfUncoreMock{}
+		mPerfUncore.On("getTimestampDelta").Return(time.Duration(0)).Once()
+
+		pt := &PowerTelemetry{
+			perfUncore: mPerfUncore,
+		}
+
+		bandwidth, err := pt.GetPackageMemoryBandwidth(packageID)
+		require.ErrorContains(t, err, "elapsed interval is not available")
+		require.Equal(t, MemoryBandwidth{}, bandwidth)
+		mPerfUncore.AssertExpectations(t)
+	})
+
+	t.Run("NoMetrics", func(t *testing.T) {
+		mPerfUncore := &perfUncoreMock{}
+		mPerfUncore.On("getTimestampDelta").Return(time.Second).Once()
+		mPerfUncore.On("getPackageMetrics", packageID).Return([]uncoreMetric{}).Once()
+
+		pt := &PowerTelemetry{
+			perfUncore: mPerfUncore,
+		}
+
+		bandwidth, err := pt.GetPackageMemoryBandwidth(packageID)
+		require.ErrorContains(t, err, "no uncore metrics found for package ID: 1")
+		require.Equal(t, MemoryBandwidth{}, bandwidth)
+		mPerfUncore.AssertExpectations(t)
+	})
+
+	t.Run("WriteMetricNotFound", func(t *testing.T) {
+		mPerfUncore := &perfUncoreMock{}
+		mPerfUncore.On("getTimestampDelta").Return(time.Second).Once()
+		mPerfUncore.On("getPackageMetrics", packageID).Return([]uncoreMetric{
+			{name: imcCASCountRead, packageID: packageID, delta: 100},
+		}).Once()
+
+		pt := &PowerTelemetry{
+			perfUncore: mPerfUncore,
+		}
+
+		bandwidth, err := pt.GetPackageMemoryBandwidth(packageID)
+		require.ErrorContains(t, err, fmt.Sprintf("could not find uncore metric: %q", imcCASCountWrite))
+		require.Equal(t, MemoryBandwidth{}, bandwidth)
+		mPerfUncore.AssertExpectations(t)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		mPerfUncore := &perfUncoreMock{}
+		mPerfUncore.On("getTimestampDelta").Return(2 * time.Second).Once()
+		mPerfUncore.On("getPackageMetrics", packageID).Return([]uncoreMetric{
+			{name: imcCASCountRead, packageID: packageID, delta: 312_500_000},
+			{name: imcCASCountWrite, packageID: packageID, delta: 62_500_000},
+		}).Once()
+
+		pt := &PowerTelemetry{
+			perfUncore: mPerfUncore,
+		}
+
+		bandwidth, err := pt.GetPackageMemoryBandwidth(packageID)
+		require.NoError(t, err)
+		require.Equal(t, MemoryBandwidth{Read: 10, Write: 2}, bandwidth)
+		mPerfUncore.AssertExpectations(t)
+	})
 }
 
 func TestPower_GetPackageIDs(t *testing.T) {
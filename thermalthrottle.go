@@ -0,0 +1,238 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/intel/powertelemetry/internal/log"
+)
+
+const (
+	// Path to folder with a collection of both global and individual CPU attributes.
+	defaultThermalThrottleBasePath = "/sys/devices/system/cpu"
+
+	// Pattern of the path to a folder with thermal throttle counters of a CPU.
+	thermalThrottlePathPattern = "cpu%d/thermal_throttle"
+
+	// Patterns of the names of the files with thermal throttle counters of a domain.
+	throttleCountFilePattern     = "%s_throttle_count"
+	throttleTotalTimeFilePattern = "%s_throttle_total_time_ms"
+	throttleMaxTimeFilePattern   = "%s_throttle_max_time_ms"
+)
+
+// throttleDomain is an enum type to identify the domains of thermal throttle counters.
+type throttleDomain int
+
+// throttleDomain enum defines supported thermal throttle domains.
+const (
+	coreThrottle throttleDomain = iota
+	packageThrottle
+)
+
+// Helper function to return a string representation of throttleDomain.
+func (d throttleDomain) String() string {
+	switch d {
+	case coreThrottle:
+		return "core"
+	case packageThrottle:
+		return "package"
+	default:
+		return ""
+	}
+}
+
+// ThermalThrottleStats holds thermal throttling statistics of a CPU or a package within a sampling interval.
+type ThermalThrottleStats struct {
+	Count     uint64        // Number of thermal throttling events within the interval
+	TimeMs    uint64        // Time spent thermally throttled within the interval, in milliseconds
+	MaxTimeMs uint64        // Duration of the longest thermal throttling event since boot, in milliseconds
+	Interval  time.Duration // Sampling interval
+}
+
+// throttleCounters holds the cumulative thermal throttle counters of a domain.
+type throttleCounters struct {
+	count  uint64
+	timeMs uint64
+}
+
+// thermalThrottleReader represents a mechanism for reading thermal throttle counters exposed via filesystem.
+type thermalThrottleReader interface {
+	// initThrottleMap takes a slice of CPU IDs and initializes the thermal throttle storage for each of them.
+	initThrottleMap(cpus []int) error
+
+	// update takes a CPU ID and reads its thermal throttle counters.
+	update(cpuID int) error
+
+	// getThrottleDeltas takes a CPU ID and a domain, and returns the thermal throttle counter deltas
+	// between the latest and the previous reading operation.
+	getThrottleDeltas(cpuID int, domain throttleDomain) (throttleCounters, error)
+
+	// getThrottleMaxTimeMs takes a CPU ID and a domain, and returns the duration of the longest
+	// thermal throttling event, in milliseconds.
+	getThrottleMaxTimeMs(cpuID int, domain throttleDomain) (uint64, error)
+
+	// getTimestampDelta takes a CPU ID and returns the time interval between the latest and
+	// the previous reading operation.
+	getTimestampDelta(cpuID int) (time.Duration, error)
+}
+
+// cpuThrottleStorage holds thermal throttle counters of a CPU and the timestamps of its reading operations.
+type cpuThrottleStorage struct {
+	cpuID          int
+	path           string
+	domains        []throttleDomain                    // domains exposed for the CPU
+	values         map[throttleDomain]throttleCounters // counter values from the last read operation
+	deltas         map[throttleDomain]throttleCounters // counter deltas between the latest and its previous reading operation
+	timestamp      time.Time                           // timestamp of the last reading operation
+	timestampDelta time.Duration                       // timestamp delta between the last read and its previous reading operation
+}
+
+// thermalThrottleData allows to get thermal throttle counters exposed via filesystem. Implements
+// thermalThrottleReader interface.
+type thermalThrottleData struct {
+	basePath string
+	cpuMap   map[int]*cpuThrottleStorage
+}
+
+// initThrottleMap takes a slice of CPU IDs and initializes the thermal throttle storage for each of them, with
+// an initial reading of its counters. Core domain counters are required, while package domain counters are
+// optional, since they depend on processor support for package thermal management.
+func (t *thermalThrottleData) initThrottleMap(cpus []int) error {
+	if len(t.basePath) == 0 {
+		return errors.New("base path of thermal throttle cannot be empty")
+	}
+	if err := checkFile(t.basePath); err != nil {
+		return fmt.Errorf("invalid base path of thermal throttle: %w", err)
+	}
+
+	cpuMap := make(map[int]*cpuThrottleStorage, len(cpus))
+	for _, cpuID := range cpus {
+		path := filepath.Join(t.basePath, fmt.Sprintf(thermalThrottlePathPattern, cpuID))
+		if err := checkFile(path); err != nil {
+			return fmt.Errorf("error initializing thermal throttle for CPU ID %v: %w", cpuID, err)
+		}
+
+		domains := []throttleDomain{coreThrottle}
+		if ok, _ := fileExists(filepath.Join(path, fmt.Sprintf(throttleCountFilePattern, packageThrottle))); ok {
+			domains = append(domains, packageThrottle)
+		}
+
+		storage := &cpuThrottleStorage{
+			cpuID:   cpuID,
+			path:    path,
+			domains: domains,
+			values:  make(map[throttleDomain]throttleCounters, len(domains)),
+			deltas:  make(map[throttleDomain]throttleCounters, len(domains)),
+		}
+		if err := storage.update(); err != nil {
+			return fmt.Errorf("error reading thermal throttle for CPU ID %v: %w", cpuID, err)
+		}
+		cpuMap[cpuID] = storage
+	}
+	t.cpuMap = cpuMap
+	return nil
+}
+
+// update takes a CPU ID and updates the storage with the latest values of its thermal throttle counters.
+func (t *thermalThrottleData) update(cpuID int) error {
+	storage, err := t.getStorage(cpuID)
+	if err != nil {
+		return err
+	}
+	return storage.update()
+}
+
+// getThrottleDeltas takes a CPU ID and a domain, and returns the thermal throttle counter deltas between
+// the latest and the previous reading operation.
+func (t *thermalThrottleData) getThrottleDeltas(cpuID int, domain throttleDomain) (throttleCounters, error) {
+	storage, err := t.getStorage(cpuID)
+	if err != nil {
+		return throttleCounters{}, err
+	}
+
+	deltas, ok := storage.deltas[domain]
+	if !ok {
+		return throttleCounters{}, fmt.Errorf("%s thermal throttle counters not found for CPU ID: %v", domain, cpuID)
+	}
+	return deltas, nil
+}
+
+// getThrottleMaxTimeMs takes a CPU ID and a domain, and returns the duration of the longest thermal throttling
+// event of the domain, in milliseconds.
+func (t *thermalThrottleData) getThrottleMaxTimeMs(cpuID int, domain throttleDomain) (uint64, error) {
+	storage, err := t.getStorage(cpuID)
+	if err != nil {
+		return 0, err
+	}
+	return readSysfsUint(storage.path, fmt.Sprintf(throttleMaxTimeFilePattern, domain))
+}
+
+// getTimestampDelta takes a CPU ID and returns the time interval between the latest and the previous
+// reading operation of its thermal throttle counters.
+func (t *thermalThrottleData) getTimestampDelta(cpuID int) (time.Duration, error) {
+	storage, err := t.getStorage(cpuID)
+	if err != nil {
+		return 0, err
+	}
+	return storage.timestampDelta, nil
+}
+
+// getStorage takes a CPU ID and returns its thermal throttle storage.
+func (t *thermalThrottleData) getStorage(cpuID int) (*cpuThrottleStorage, error) {
+	storage, ok := t.cpuMap[cpuID]
+	if !ok {
+		return nil, fmt.Errorf("could not find thermal throttle for CPU ID: %v", cpuID)
+	}
+	return storage, nil
+}
+
+// update reads the thermal throttle counters of all domains of the receiver, and updates both the last
+// read values and the deltas with respect to the previous reading operation. Deltas and timestamp delta
+// of the first reading operation are zero.
+func (s *cpuThrottleStorage) update() error {
+	latest := make(map[throttleDomain]throttleCounters, len(s.domains))
+	for _, domain := range s.domains {
+		count, err := readSysfsUint(s.path, fmt.Sprintf(throttleCountFilePattern, domain))
+		if err != nil {
+			return err
+		}
+		timeMs, err := readSysfsUint(s.path, fmt.Sprintf(throttleTotalTimeFilePattern, domain))
+		if err != nil {
+			return err
+		}
+		latest[domain] = throttleCounters{count: count, timeMs: timeMs}
+	}
+
+	// Deltas of the first reading operation are not available, since there is no previous one.
+	newTimestamp := timeNowFn()
+	firstRead := s.timestamp.IsZero()
+	if !firstRead {
+		s.timestampDelta = newTimestamp.Sub(s.timestamp)
+	}
+	s.timestamp = newTimestamp
+
+	for domain, curr := range latest {
+		prev := s.values[domain]
+		switch {
+		case firstRead:
+			s.deltas[domain] = throttleCounters{}
+		case curr.count < prev.count || curr.timeMs < prev.timeMs:
+			s.deltas[domain] = throttleCounters{}
+			log.Warnf("A negative delta for the %s thermal throttle counters and CPU ID %v", domain, s.cpuID)
+		default:
+			s.deltas[domain] = throttleCounters{
+				count:  curr.count - prev.count,
+				timeMs: curr.timeMs - prev.timeMs,
+			}
+		}
+		s.values[domain] = curr
+	}
+	return nil
+}
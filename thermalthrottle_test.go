@@ -0,0 +1,193 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeThermalThrottle takes a base path, a CPU ID, a domain and counter values, and writes the thermal
+// throttle files of the domain to the corresponding thermal_throttle directory.
+func writeThermalThrottle(t *testing.T, basePath string, cpuID int, domain throttleDomain, count, timeMs, maxTimeMs uint64) {
+	t.Helper()
+
+	path := filepath.Join(basePath, fmt.Sprintf(thermalThrottlePathPattern, cpuID))
+	require.NoError(t, os.MkdirAll(path, 0750))
+
+	files := map[string]uint64{
+		fmt.Sprintf(throttleCountFilePattern, domain):     count,
+		fmt.Sprintf(throttleTotalTimeFilePattern, domain): timeMs,
+		fmt.Sprintf(throttleMaxTimeFilePattern, domain):   maxTimeMs,
+	}
+	for name, value := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(path, name), []byte(strconv.FormatUint(value, 10)+"\n"), 0640))
+	}
+}
+
+func TestThrottleDomainToString(t *testing.T) {
+	require.Equal(t, "core", coreThrottle.String())
+	require.Equal(t, "package", packageThrottle.String())
+	require.Equal(t, "", throttleDomain(2).String())
+}
+
+func TestThermalThrottleData_InitThrottleMap(t *testing.T) {
+	t.Run("BasePathEmpty", func(t *testing.T) {
+		th := &thermalThrottleData{}
+
+		err := th.initThrottleMap([]int{0})
+		require.ErrorContains(t, err, "base path of thermal throttle cannot be empty")
+	})
+
+	t.Run("BasePathNotExist", func(t *testing.T) {
+		th := &thermalThrottleData{
+			basePath: "/dummy/path",
+		}
+
+		err := th.initThrottleMap([]int{0})
+		require.ErrorContains(t, err, "invalid base path of thermal throttle")
+	})
+
+	t.Run("ThermalThrottleDirNotExist", func(t *testing.T) {
+		th := &thermalThrottleData{
+			basePath: t.TempDir(),
+		}
+
+		err := th.initThrottleMap([]int{0})
+		require.ErrorContains(t, err, "error initializing thermal throttle for CPU ID 0")
+	})
+
+	t.Run("CoreCountersNotExist", func(t *testing.T) {
+		basePath := t.TempDir()
+		writeThermalThrottle(t, basePath, 0, packageThrottle, 0, 0, 0)
+
+		th := &thermalThrottleData{
+			basePath: basePath,
+		}
+
+		err := th.initThrottleMap([]int{0})
+		require.ErrorContains(t, err, "error reading thermal throttle for CPU ID 0")
+		require.ErrorContains(t, err, "core_throttle_count")
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		basePath := t.TempDir()
+		writeThermalThrottle(t, basePath, 0, coreThrottle, 1, 2, 3)
+		writeThermalThrottle(t, basePath, 0, packageThrottle, 4, 5, 6)
+		writeThermalThrottle(t, basePath, 1, coreThrottle, 7, 8, 9)
+
+		th := &thermalThrottleData{
+			basePath: basePath,
+		}
+
+		require.NoError(t, th.initThrottleMap([]int{0, 1}))
+		require.Len(t, th.cpuMap, 2)
+		require.Equal(t, []throttleDomain{coreThrottle, packageThrottle}, th.cpuMap[0].domains)
+		require.Equal(t, map[throttleDomain]throttleCounters{
+			coreThrottle:    {count: 1, timeMs: 2},
+			packageThrottle: {count: 4, timeMs: 5},
+		}, th.cpuMap[0].values)
+		require.Equal(t, []throttleDomain{coreThrottle}, th.cpuMap[1].domains)
+	})
+}
+
+func TestThermalThrottleData_Update(t *testing.T) {
+	setFakeClock()
+	defer unsetFakeClock()
+	fakeClock.Set(time.Now())
+
+	cpuID := 0
+	basePath := t.TempDir()
+	writeThermalThrottle(t, basePath, cpuID, coreThrottle, 10, 1000, 50)
+	writeThermalThrottle(t, basePath, cpuID, packageThrottle, 20, 2000, 60)
+	writeThermalThrottle(t, basePath, 1, coreThrottle, 10, 1000, 50)
+
+	th := &thermalThrottleData{
+		basePath: basePath,
+	}
+	require.NoError(t, th.initThrottleMap([]int{cpuID, 1}))
+
+	t.Run("CPUIDNotFound", func(t *testing.T) {
+		require.ErrorContains(t, th.update(2), "could not find thermal throttle for CPU ID: 2")
+
+		_, err := th.getThrottleDeltas(2, coreThrottle)
+		require.ErrorContains(t, err, "could not find thermal throttle for CPU ID: 2")
+
+		_, err = th.getThrottleMaxTimeMs(2, coreThrottle)
+		require.ErrorContains(t, err, "could not find thermal throttle for CPU ID: 2")
+
+		_, err = th.getTimestampDelta(2)
+		require.ErrorContains(t, err, "could not find thermal throttle for CPU ID: 2")
+	})
+
+	t.Run("FirstSample", func(t *testing.T) {
+		// counters since boot are not reported as deltas of the initial reading operation
+		deltas, err := th.getThrottleDeltas(cpuID, coreThrottle)
+		require.NoError(t, err)
+		require.Equal(t, throttleCounters{}, deltas)
+
+		deltas, err = th.getThrottleDeltas(cpuID, packageThrottle)
+		require.NoError(t, err)
+		require.Equal(t, throttleCounters{}, deltas)
+
+		interval, err := th.getTimestampDelta(cpuID)
+		require.NoError(t, err)
+		require.Zero(t, interval)
+
+		pt := &PowerTelemetry{
+			throttle: th,
+		}
+		stats, err := pt.GetCPUThermalThrottleStats(cpuID)
+		require.ErrorContains(t, err, "elapsed interval is not available, thermal throttle counters must be read at least twice")
+		require.Equal(t, ThermalThrottleStats{}, stats)
+	})
+
+	t.Run("DomainNotFound", func(t *testing.T) {
+		_, err := th.getThrottleDeltas(1, packageThrottle)
+		require.ErrorContains(t, err, "package thermal throttle counters not found for CPU ID: 1")
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		writeThermalThrottle(t, basePath, cpuID, coreThrottle, 15, 1300, 80)
+		writeThermalThrottle(t, basePath, cpuID, packageThrottle, 22, 2100, 60)
+		fakeClock.Add(5 * time.Second)
+
+		require.NoError(t, th.update(cpuID))
+
+		deltas, err := th.getThrottleDeltas(cpuID, coreThrottle)
+		require.NoError(t, err)
+		require.Equal(t, throttleCounters{count: 5, timeMs: 300}, deltas)
+
+		deltas, err = th.getThrottleDeltas(cpuID, packageThrottle)
+		require.NoError(t, err)
+		require.Equal(t, throttleCounters{count: 2, timeMs: 100}, deltas)
+
+		maxTimeMs, err := th.getThrottleMaxTimeMs(cpuID, coreThrottle)
+		require.NoError(t, err)
+		require.Equal(t, uint64(80), maxTimeMs)
+
+		interval, err := th.getTimestampDelta(cpuID)
+		require.NoError(t, err)
+		require.Equal(t, 5*time.Second, interval)
+	})
+
+	t.Run("NegativeDelta", func(t *testing.T) {
+		writeThermalThrottle(t, basePath, cpuID, coreThrottle, 0, 0, 0)
+		fakeClock.Add(time.Second)
+
+		require.NoError(t, th.update(cpuID))
+
+		deltas, err := th.getThrottleDeltas(cpuID, coreThrottle)
+		require.NoError(t, err)
+		require.Equal(t, throttleCounters{}, deltas)
+	})
+}
@@ -0,0 +1,307 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+const (
+	// Path to folder with thermal zones and cooling devices exposed by the thermal subsystem.
+	defaultThermalBasePath = "/sys/class/thermal"
+
+	// Patterns of the names of the folders of thermal zones and cooling devices.
+	thermalZoneDirPattern   = "thermal_zone%d"
+	coolingDeviceDirPattern = "cooling_device%d"
+
+	// Patterns of the names of the files with trip point attributes of a thermal zone.
+	tripPointTempFilePattern = "trip_point_%d_temp"
+	tripPointTypeFilePattern = "trip_point_%d_type"
+	tripPointHystFilePattern = "trip_point_%d_hyst"
+)
+
+// Names of the files exposed by the kernel for each thermal zone and cooling device.
+const (
+	thermalTypeFile     = "type"
+	thermalTempFile     = "temp"
+	thermalPolicyFile   = "policy"
+	coolingCurStateFile = "cur_state"
+	coolingMaxStateFile = "max_state"
+)
+
+var (
+	// thermalZoneRegex is a regular expression matching thermal zone directory names.
+	thermalZoneRegex = regexp.MustCompile(`^thermal_zone(\d+)$`)
+
+	// coolingDeviceRegex is a regular expression matching cooling device directory names.
+	coolingDeviceRegex = regexp.MustCompile(`^cooling_device(\d+)$`)
+
+	// tripPointTempRegex is a regular expression matching trip point temperature file names.
+	tripPointTempRegex = regexp.MustCompile(`^trip_point_(\d+)_temp$`)
+
+	// zoneCoolingDeviceRegex is a regular expression matching the names of links to cooling devices bound to a thermal zone.
+	zoneCoolingDeviceRegex = regexp.MustCompile(`^cdev(\d+)$`)
+)
+
+// ThermalZone represents a thermal zone exposed by the thermal subsystem.
+type ThermalZone struct {
+	ID             int                // ID of the thermal zone, e.g. 0 for thermal_zone0
+	Type           string             // Type of the thermal zone, e.g. "x86_pkg_temp", "acpitz"
+	Temperature    float64            // Current temperature of the thermal zone, in degrees Celsius
+	Policy         string             // Thermal governor of the thermal zone, e.g. "step_wise"
+	TripPoints     []ThermalTripPoint // Trip points of the thermal zone, ordered by index
+	CoolingDevices []int              // IDs of cooling devices bound to the thermal zone
+}
+
+// ThermalTripPoint represents a trip point of a thermal zone.
+type ThermalTripPoint struct {
+	Index       int     // Index of the trip point within the thermal zone
+	Type        string  // Type of the trip point, e.g. "passive", "active", "hot", "critical"
+	Temperature float64 // Temperature of the trip point, in degrees Celsius
+	Hysteresis  float64 // Hysteresis of the trip point, in degrees Celsius. Zero if not exposed
+}
+
+// CoolingDevice represents a cooling device exposed by the thermal subsystem.
+type CoolingDevice struct {
+	ID       int    // ID of the cooling device, e.g. 0 for cooling_device0
+	Type     string // Type of the cooling device, e.g. "Processor", "intel_powerclamp"
+	CurState uint64 // Current cooling state of the device
+	MaxState uint64 // Maximum cooling state of the device
+}
+
+// thermalZoneReader represents a mechanism for reading thermal zones and cooling devices exposed via filesystem.
+type thermalZoneReader interface {
+	// initThermalZones discovers thermal zones, their trip points and cooling devices.
+	initThermalZones() error
+
+	// getThermalZones returns the thermal zones with their current attribute values, ordered by ID, skipping
+	// the ones which cannot be read.
+	getThermalZones() ([]ThermalZone, error)
+
+	// getCoolingDevices returns the cooling devices with their current attribute values, ordered by ID, skipping
+	// the ones which cannot be read.
+	getCoolingDevices() ([]CoolingDevice, error)
+}
+
+// thermalZoneEntry holds the discovered layout of a thermal zone.
+type thermalZoneEntry struct {
+	id             int
+	path           string
+	tripPoints     []int
+	coolingDevices []int
+}
+
+// thermalZoneData allows to get thermal zones and cooling devices exposed via filesystem. Implements
+// thermalZoneReader interface.
+type thermalZoneData struct {
+	basePath       string
+	zones          []*thermalZoneEntry
+	coolingDevices []int
+}
+
+// initThermalZones discovers thermal zones, their trip points and bound cooling devices, together with
+// all cooling devices exposed by the thermal subsystem.
+func (t *thermalZoneData) initThermalZones() error {
+	if len(t.basePath) == 0 {
+		return errors.New("base path of thermal subsystem cannot be empty")
+	}
+	if err := checkFile(t.basePath); err != nil {
+		return fmt.Errorf("invalid base path of thermal subsystem: %w", err)
+	}
+
+	entries, err := os.ReadDir(t.basePath)
+	if err != nil {
+		return fmt.Errorf("error reading thermal subsystem directory %q: %w", t.basePath, err)
+	}
+
+	zones := make([]*thermalZoneEntry, 0)
+	coolingDevices := make([]int, 0)
+	for _, entry := range entries {
+		if id, ok := matchIndex(thermalZoneRegex, entry.Name()); ok {
+			zone, err := newThermalZoneEntry(id, filepath.Join(t.basePath, entry.Name()))
+			if err != nil {
+				return fmt.Errorf("error initializing thermal zone %v: %w", id, err)
+			}
+			zones = append(zones, zone)
+			continue
+		}
+		if id, ok := matchIndex(coolingDeviceRegex, entry.Name()); ok {
+			coolingDevices = append(coolingDevices, id)
+		}
+	}
+	if len(zones) == 0 {
+		return fmt.Errorf("no thermal zones found in %q", t.basePath)
+	}
+
+	slices.SortFunc(zones, func(a, b *thermalZoneEntry) int {
+		return a.id - b.id
+	})
+	slices.Sort(coolingDevices)
+
+	t.zones = zones
+	t.coolingDevices = coolingDevices
+	return nil
+}
+
+// getThermalZones returns the thermal zones with their current attribute values, ordered by ID. Thermal zones which
+// cannot be read are skipped, and the returned error joins their errors.
+func (t *thermalZoneData) getThermalZones() ([]ThermalZone, error) {
+	zones := make([]ThermalZone, 0, len(t.zones))
+	var errs []error
+	for _, entry := range t.zones {
+		zone, err := entry.read()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error reading thermal zone %v: %w", entry.id, err))
+			continue
+		}
+		zones = append(zones, zone)
+	}
+	return zones, errors.Join(errs...)
+}
+
+// getCoolingDevices returns the cooling devices with their current attribute values, ordered by ID. Cooling devices
+// which cannot be read are skipped, and the returned error joins their errors.
+func (t *thermalZoneData) getCoolingDevices() ([]CoolingDevice, error) {
+	devices := make([]CoolingDevice, 0, len(t.coolingDevices))
+	var errs []error
+	for _, id := range t.coolingDevices {
+		device, err := readCoolingDevice(id, filepath.Join(t.basePath, fmt.Sprintf(coolingDeviceDirPattern, id)))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error reading cooling device %v: %w", id, err))
+			continue
+		}
+		devices = append(devices, device)
+	}
+	return devices, errors.Join(errs...)
+}
+
+// readCoolingDevice takes a cooling device ID and its path, and returns the cooling device with its current
+// attribute values.
+func readCoolingDevice(id int, path string) (CoolingDevice, error) {
+	devType, err := readSysfsString(path, thermalTypeFile)
+	if err != nil {
+		return CoolingDevice{}, err
+	}
+	curState, err := readSysfsUint(path, coolingCurStateFile)
+	if err != nil {
+		return CoolingDevice{}, err
+	}
+	maxState, err := readSysfsUint(path, coolingMaxStateFile)
+	if err != nil {
+		return CoolingDevice{}, err
+	}
+
+	return CoolingDevice{
+		ID:       id,
+		Type:     devType,
+		CurState: curState,
+		MaxState: maxState,
+	}, nil
+}
+
+// newThermalZoneEntry takes a thermal zone ID and its path, and returns the thermal zone layout with the indexes
+// of its trip points and the IDs of its bound cooling devices.
+func newThermalZoneEntry(id int, path string) (*thermalZoneEntry, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading thermal zone directory %q: %w", path, err)
+	}
+
+	zone := &thermalZoneEntry{
+		id:             id,
+		path:           path,
+		tripPoints:     make([]int, 0),
+		coolingDevices: make([]int, 0),
+	}
+	for _, entry := range entries {
+		if index, ok := matchIndex(tripPointTempRegex, entry.Name()); ok {
+			zone.tripPoints = append(zone.tripPoints, index)
+			continue
+		}
+		if _, ok := matchIndex(zoneCoolingDeviceRegex, entry.Name()); ok {
+			target, err := os.Readlink(filepath.Join(path, entry.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("error resolving cooling device link %q: %w", entry.Name(), err)
+			}
+			if devID, ok := matchIndex(coolingDeviceRegex, filepath.Base(target)); ok {
+				zone.coolingDevices = append(zone.coolingDevices, devID)
+			}
+		}
+	}
+	slices.Sort(zone.tripPoints)
+	slices.Sort(zone.coolingDevices)
+	return zone, nil
+}
+
+// read returns the thermal zone with the current values of its attributes and trip points.
+func (z *thermalZoneEntry) read() (ThermalZone, error) {
+	zoneType, err := readSysfsString(z.path, thermalTypeFile)
+	if err != nil {
+		return ThermalZone{}, err
+	}
+	temp, err := readSysfsInt(z.path, thermalTempFile)
+	if err != nil {
+		return ThermalZone{}, err
+	}
+	// policy file is not exposed by all kernel versions.
+	policy, _ := readSysfsString(z.path, thermalPolicyFile)
+
+	tripPoints := make([]ThermalTripPoint, 0, len(z.tripPoints))
+	for _, index := range z.tripPoints {
+		tripType, err := readSysfsString(z.path, fmt.Sprintf(tripPointTypeFilePattern, index))
+		if err != nil {
+			return ThermalZone{}, err
+		}
+		tripTemp, err := readSysfsInt(z.path, fmt.Sprintf(tripPointTempFilePattern, index))
+		if err != nil {
+			return ThermalZone{}, err
+		}
+		// hysteresis file is optional, it is only exposed if the thermal zone driver supports it.
+		var tripHyst int64
+		if ok, _ := fileExists(filepath.Join(z.path, fmt.Sprintf(tripPointHystFilePattern, index))); ok {
+			tripHyst, err = readSysfsInt(z.path, fmt.Sprintf(tripPointHystFilePattern, index))
+			if err != nil {
+				return ThermalZone{}, err
+			}
+		}
+
+		tripPoints = append(tripPoints, ThermalTripPoint{
+			Index:       index,
+			Type:        tripType,
+			Temperature: float64(tripTemp) / milliDegreesPerDegree,
+			Hysteresis:  float64(tripHyst) / milliDegreesPerDegree,
+		})
+	}
+
+	return ThermalZone{
+		ID:             z.id,
+		Type:           zoneType,
+		Temperature:    float64(temp) / milliDegreesPerDegree,
+		Policy:         policy,
+		TripPoints:     tripPoints,
+		CoolingDevices: slices.Clone(z.coolingDevices),
+	}, nil
+}
+
+// matchIndex takes a regular expression with a single numeric capture group and a name, and returns
+// the captured index if the name matches the expression.
+func matchIndex(re *regexp.Regexp, name string) (int, bool) {
+	match := re.FindStringSubmatch(name)
+	if match == nil {
+		return 0, false
+	}
+	index, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, false
+	}
+	return index, true
+}
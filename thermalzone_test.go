@@ -0,0 +1,225 @@
+// Copyright (C) 2023 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && amd64
+
+package powertelemetry
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeSysfsFiles takes a directory path and a map of file names and contents, and writes the files
+// to the directory.
+func writeSysfsFiles(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(path, 0750))
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(path, name), []byte(content+"\n"), 0640))
+	}
+}
+
+// writeThermalTree takes a base path and writes a thermal subsystem tree with two thermal zones
+// and two cooling devices, where cooling device 1 is bound to thermal zone 1.
+func writeThermalTree(t *testing.T, basePath string) {
+	t.Helper()
+
+	zone0 := filepath.Join(basePath, fmt.Sprintf(thermalZoneDirPattern, 0))
+	writeSysfsFiles(t, zone0, map[string]string{
+		thermalTypeFile:                          "acpitz",
+		thermalTempFile:                          "27800",
+		thermalPolicyFile:                        "step_wise",
+		fmt.Sprintf(tripPointTypeFilePattern, 0): "critical",
+		fmt.Sprintf(tripPointTempFilePattern, 0): "105000",
+	})
+
+	zone1 := filepath.Join(basePath, fmt.Sprintf(thermalZoneDirPattern, 1))
+	writeSysfsFiles(t, zone1, map[string]string{
+		thermalTypeFile:                          "x86_pkg_temp",
+		thermalTempFile:                          "45000",
+		thermalPolicyFile:                        "step_wise",
+		fmt.Sprintf(tripPointTypeFilePattern, 0): "passive",
+		fmt.Sprintf(tripPointTempFilePattern, 0): "95000",
+		fmt.Sprintf(tripPointHystFilePattern, 0): "2000",
+		fmt.Sprintf(tripPointTypeFilePattern, 1): "passive",
+		fmt.Sprintf(tripPointTempFilePattern, 1): "-273200",
+		fmt.Sprintf(tripPointHystFilePattern, 1): "0",
+	})
+
+	for id, devType := range map[int]string{0: "Processor", 1: "intel_powerclamp"} {
+		writeSysfsFiles(t, filepath.Join(basePath, fmt.Sprintf(coolingDeviceDirPattern, id)), map[string]string{
+			thermalTypeFile:     devType,
+			coolingCurStateFile: fmt.Sprintf("%d", id),
+			coolingMaxStateFile: "10",
+		})
+	}
+	require.NoError(t, os.Symlink(filepath.Join("..", fmt.Sprintf(coolingDeviceDirPattern, 1)), filepath.Join(zone1, "cdev0")))
+}
+
+func TestThermalZoneData_InitThermalZones(t *testing.T) {
+	t.Run("BasePathEmpty", func(t *testing.T) {
+		th := &thermalZoneData{}
+
+		require.ErrorContains(t, th.initThermalZones(), "base path of thermal subsystem cannot be empty")
+	})
+
+	t.Run("BasePathNotExist", func(t *testing.T) {
+		th := &thermalZoneData{
+			basePath: "/dummy/path",
+		}
+
+		require.ErrorContains(t, th.initThermalZones(), "invalid base path of thermal subsystem")
+	})
+
+	t.Run("NoThermalZones", func(t *testing.T) {
+		basePath := t.TempDir()
+		writeSysfsFiles(t, filepath.Join(basePath, fmt.Sprintf(coolingDeviceDirPattern, 0)), map[string]string{
+			thermalTypeFile: "Processor",
+		})
+
+		th := &thermalZoneData{
+			basePath: basePath,
+		}
+
+		require.ErrorContains(t, th.initThermalZones(), "no thermal zones found")
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		basePath := t.TempDir()
+		writeThermalTree(t, basePath)
+
+		th := &thermalZoneData{
+			basePath: basePath,
+		}
+
+		require.NoError(t, th.initThermalZones())
+		require.Equal(t, []*thermalZoneEntry{
+			{
+				id:             0,
+				path:           filepath.Join(basePath, "thermal_zone0"),
+				tripPoints:     []int{0},
+				coolingDevices: []int{},
+			},
+			{
+				id:             1,
+				path:           filepath.Join(basePath, "thermal_zone1"),
+				tripPoints:     []int{0, 1},
+				coolingDevices: []int{1},
+			},
+		}, th.zones)
+		require.Equal(t, []int{0, 1}, th.coolingDevices)
+	})
+}
+
+func TestThermalZoneData_GetThermalZones(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		basePath := t.TempDir()
+		writeThermalTree(t, basePath)
+
+		th := &thermalZoneData{
+			basePath: basePath,
+		}
+		require.NoError(t, th.initThermalZones())
+
+		zones, err := th.getThermalZones()
+		require.NoError(t, err)
+		require.Equal(t, []ThermalZone{
+			{
+				ID:          0,
+				Type:        "acpitz",
+				Temperature: 27.8,
+				Policy:      "step_wise",
+				TripPoints: []ThermalTripPoint{
+					{Index: 0, Type: "critical", Temperature: 105},
+				},
+				CoolingDevices: []int{},
+			},
+			{
+				ID:          1,
+				Type:        "x86_pkg_temp",
+				Temperature: 45,
+				Policy:      "step_wise",
+				TripPoints: []ThermalTripPoint{
+					{Index: 0, Type: "passive", Temperature: 95, Hysteresis: 2},
+					{Index: 1, Type: "passive", Temperature: -273.2},
+				},
+				CoolingDevices: []int{1},
+			},
+		}, zones)
+	})
+
+	t.Run("FailedToReadTemperature", func(t *testing.T) {
+		basePath := t.TempDir()
+		writeThermalTree(t, basePath)
+
+		th := &thermalZoneData{
+			basePath: basePath,
+		}
+		require.NoError(t, th.initThermalZones())
+		require.NoError(t, os.Remove(filepath.Join(basePath, "thermal_zone1", thermalTempFile)))
+
+		zones, err := th.getThermalZones()
+		require.ErrorContains(t, err, "error reading thermal zone 1")
+		require.Len(t, zones, 1)
+		require.Equal(t, 0, zones[0].ID)
+	})
+
+	t.Run("FailedToReadTripPoint", func(t *testing.T) {
+		basePath := t.TempDir()
+		writeThermalTree(t, basePath)
+
+		th := &thermalZoneData{
+			basePath: basePath,
+		}
+		require.NoError(t, th.initThermalZones())
+		require.NoError(t, os.WriteFile(filepath.Join(basePath, "thermal_zone0", "trip_point_0_temp"), []byte("invalid\n"), 0640))
+
+		zones, err := th.getThermalZones()
+		require.ErrorContains(t, err, "error reading thermal zone 0")
+		require.ErrorContains(t, err, "error while converting value from file")
+		require.Len(t, zones, 1)
+		require.Equal(t, 1, zones[0].ID)
+	})
+}
+
+func TestThermalZoneData_GetCoolingDevices(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		basePath := t.TempDir()
+		writeThermalTree(t, basePath)
+
+		th := &thermalZoneData{
+			basePath: basePath,
+		}
+		require.NoError(t, th.initThermalZones())
+
+		devices, err := th.getCoolingDevices()
+		require.NoError(t, err)
+		require.Equal(t, []CoolingDevice{
+			{ID: 0, Type: "Processor", CurState: 0, MaxState: 10},
+			{ID: 1, Type: "intel_powerclamp", CurState: 1, MaxState: 10},
+		}, devices)
+	})
+
+	t.Run("FailedToReadMaxState", func(t *testing.T) {
+		basePath := t.TempDir()
+		writeThermalTree(t, basePath)
+
+		th := &thermalZoneData{
+			basePath: basePath,
+		}
+		require.NoError(t, th.initThermalZones())
+		require.NoError(t, os.Remove(filepath.Join(basePath, "cooling_device0", coolingMaxStateFile)))
+
+		devices, err := th.getCoolingDevices()
+		require.ErrorContains(t, err, "error reading cooling device 0")
+		require.Equal(t, []CoolingDevice{
+			{ID: 1, Type: "intel_powerclamp", CurState: 1, MaxState: 10},
+		}, devices)
+	})
+}
@@ -7,6 +7,7 @@ package powertelemetry
 
 const (
 	fromKiloHertzToMegaHertzRatio = 1e-3
+	fromHertzToMegaHertzRatio     = 1e-6
 	fromMicrojoulesToJoulesRatio  = 1e-6
 	fromMicrowattsToWatts         = 1e-6
 	fromProcessorCyclesToHertz    = 1e-6